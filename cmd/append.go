@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+var appendCmd = &cobra.Command{
+	Use:   "append <page>",
+	Short: "Append markdown from stdin to a page",
+	Long: `Append markdown read from stdin to the end of a page.
+
+The page is given by ID or by a URL copied from Notion. Headings, lists,
+to-dos, code, quotes, dividers, tables and images are converted to the
+matching Notion blocks. The ID and link of the first new block are printed.`,
+	Example: `  echo "- [ ] Call the bank" | notion-tui append <page>
+  notion-tui append <page> < meeting-notes.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAppend,
+}
+
+func init() {
+	appendCmd.Flags().Bool("json", false, "output as JSON")
+	rootCmd.AddCommand(appendCmd)
+}
+
+// appendInput contains parameters for appendMarkdown.
+type appendInput struct {
+	Appender notion.BlockAppender
+	Cache    *cache.PageCache // optional; the page's cached content is dropped
+	PageID   string
+	Markdown string
+}
+
+// createdOutput describes what `append` and `capture` created.
+type createdOutput struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Blocks int    `json:"blocks,omitempty"`
+}
+
+func runAppend(cmd *cobra.Command, args []string) error {
+	id, err := notion.ParseID(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	run := func(ctx context.Context, pc *cache.PageCache) error {
+		return appendMarkdown(ctx, cmd.OutOrStdout(), appendInput{
+			Appender: notion.NewClient(cfg.NotionToken),
+			Cache:    pc,
+			PageID:   id,
+			Markdown: string(data),
+		}, jsonOutput(cmd))
+	}
+
+	if cfg.CacheDir == "" {
+		return run(ctx, nil)
+	}
+	return withCache(ctx, cfg, true, run)
+}
+
+// appendMarkdown implements `append`.
+func appendMarkdown(ctx context.Context, w io.Writer, input appendInput, asJSON bool) error {
+	nodes := notion.ParseMarkdown(input.Markdown)
+	if len(nodes) == 0 {
+		return errors.New("nothing to append: stdin is empty")
+	}
+
+	created, err := notion.AppendBlockTree(ctx, input.Appender, input.PageID, nodes)
+	if input.Cache != nil && len(created) > 0 {
+		// The cached copy no longer matches the page
+		key := notion.FormatID(input.PageID)
+		_ = input.Cache.Delete(key)
+		_ = input.Cache.Delete(cache.MetaKey(key))
+	}
+	if err != nil {
+		return err
+	}
+
+	blockID := string(created[0].GetID())
+	return printCreated(w, createdOutput{
+		ID:     blockID,
+		URL:    blockURL(input.PageID, blockID),
+		Blocks: len(created),
+	}, asJSON)
+}
+
+// blockURL links to a block within a page.
+func blockURL(pageID, blockID string) string {
+	return "https://www.notion.so/" + notion.NormalizeID(pageID) + "#" + notion.NormalizeID(blockID)
+}
+
+// printCreated prints the ID and URL of a created object, tab-separated so
+// scripts can split them.
+func printCreated(w io.Writer, out createdOutput, asJSON bool) error {
+	if asJSON {
+		return writeJSON(w, out)
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\n", out.ID, out.URL)
+	return err
+}
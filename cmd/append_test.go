@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// newAppendClient returns a mock client that gives appended blocks IDs such
+// as "page-1-0", derived from the parent.
+func newAppendClient() *testhelpers.MockNotionClient {
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.AppendBlocksFunc = func(ctx context.Context, id string,
+		req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+		resp := &notionapi.AppendBlockChildrenResponse{Object: notionapi.ObjectTypeList}
+		for i := range req.Children {
+			block := testhelpers.NewParagraphBlock("")
+			block.ID = notionapi.BlockID(fmt.Sprintf("%s-%d", id, i))
+			resp.Results = append(resp.Results, block)
+		}
+		return resp, nil
+	}
+	return mockClient
+}
+
+func TestAppendMarkdown(t *testing.T) {
+	mockClient := newAppendClient()
+	pc := newTestCache(t)
+
+	var out bytes.Buffer
+	err := appendMarkdown(context.Background(), &out, appendInput{
+		Appender: mockClient,
+		Cache:    pc,
+		PageID:   "page-1",
+		Markdown: "## Notes\n\n- Call the bank\n  - Ask about fees\n",
+	}, false)
+	if err != nil {
+		t.Fatalf("appendMarkdown() error = %v", err)
+	}
+
+	if got, want := out.String(), "page-1-0\thttps://www.notion.so/page-1#page-1-0\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	calls := mockClient.AppendBlocksCalls
+	if len(calls) != 2 {
+		t.Fatalf("AppendBlocks called %d times, want 2", len(calls))
+	}
+	if calls[0].ID != "page-1" || len(calls[0].Request.Children) != 2 {
+		t.Errorf("first call = %s with %d blocks, want page-1 with 2", calls[0].ID, len(calls[0].Request.Children))
+	}
+	if calls[1].ID != "page-1-1" || len(calls[1].Request.Children) != 1 {
+		t.Errorf("second call = %s with %d blocks, want the nested item under page-1-1", calls[1].ID, len(calls[1].Request.Children))
+	}
+
+	for _, key := range []string{"page-1", cache.MetaKey("page-1")} {
+		if pc.Has(context.Background(), key) {
+			t.Errorf("cache entry %s was not dropped", key)
+		}
+	}
+}
+
+func TestAppendMarkdownJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := appendMarkdown(context.Background(), &out, appendInput{
+		Appender: newAppendClient(),
+		PageID:   "page-9",
+		Markdown: "one\n\ntwo\n\nthree",
+	}, true)
+	if err != nil {
+		t.Fatalf("appendMarkdown() error = %v", err)
+	}
+
+	var got createdOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	want := createdOutput{ID: "page-9-0", URL: "https://www.notion.so/page-9#page-9-0", Blocks: 3}
+	if got != want {
+		t.Errorf("output = %+v, want %+v", got, want)
+	}
+}
+
+func TestAppendMarkdownErrors(t *testing.T) {
+	t.Run("empty input", func(t *testing.T) {
+		mockClient := newAppendClient()
+		err := appendMarkdown(context.Background(), &bytes.Buffer{}, appendInput{
+			Appender: mockClient,
+			PageID:   "page-1",
+			Markdown: "\n  \n",
+		}, false)
+		if err == nil || !strings.Contains(err.Error(), "nothing to append") {
+			t.Errorf("appendMarkdown() error = %v, want nothing to append", err)
+		}
+		if mockClient.AppendBlocksCallCount() != 0 {
+			t.Error("AppendBlocks should not be called")
+		}
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		mockClient := testhelpers.NewMockNotionClient().WithError(testhelpers.ErrNotFound)
+		err := appendMarkdown(context.Background(), &bytes.Buffer{}, appendInput{
+			Appender: mockClient,
+			PageID:   "page-1",
+			Markdown: "text",
+		}, false)
+		if err == nil {
+			t.Error("appendMarkdown() error = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,669 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/prefetch"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the local page cache",
+	Long: `Inspect and manage the local page cache.
+
+The cache directory comes from cache_dir in the configuration. All
+subcommands except warm work without a Notion token. Use --json for
+machine-readable output.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size and health",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, false, func(ctx context.Context, pc *cache.PageCache) error {
+			return cacheStats(ctx, cmd.OutOrStdout(), pc, jsonOutput(cmd))
+		})
+	},
+}
+
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List cached pages",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, false, func(ctx context.Context, pc *cache.PageCache) error {
+			return cacheList(ctx, cmd.OutOrStdout(), pc, jsonOutput(cmd))
+		})
+	},
+}
+
+var cacheShowCmd = &cobra.Command{
+	Use:   "show <page-id>",
+	Short: "Show the cached content of a page",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, false, func(ctx context.Context, pc *cache.PageCache) error {
+			return cacheShow(ctx, cmd.OutOrStdout(), pc, args[0], jsonOutput(cmd))
+		})
+	},
+}
+
+var cacheRmCmd = &cobra.Command{
+	Use:   "rm <page-id>...",
+	Short: "Remove pages from the cache",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, true, func(ctx context.Context, pc *cache.PageCache) error {
+			return cacheRemove(ctx, cmd.OutOrStdout(), pc, args, jsonOutput(cmd))
+		})
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached page",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, true, func(ctx context.Context, pc *cache.PageCache) error {
+			return cacheClear(ctx, cmd.OutOrStdout(), pc, jsonOutput(cmd))
+		})
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove expired entries and quarantined files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, true, func(ctx context.Context, pc *cache.PageCache) error {
+			return cachePrune(ctx, cmd.OutOrStdout(), pc, jsonOutput(cmd))
+		})
+	},
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cache integrity and quarantine corrupt entries",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLocalCache(cmd, true, func(ctx context.Context, pc *cache.PageCache) error {
+			return cacheVerify(ctx, cmd.OutOrStdout(), pc, jsonOutput(cmd))
+		})
+	},
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm <database>",
+	Short: "Prefetch the recently edited pages of a database",
+	Long: `Prefetch the recently edited pages of a database into the cache.
+
+The database is given by ID or by its configured name. This runs the same
+prefetch logic as the TUI, without a terminal, so it can be scheduled.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCacheWarm,
+}
+
+func init() {
+	cacheCmd.PersistentFlags().Bool("json", false, "print machine-readable JSON")
+	cacheWarmCmd.Flags().Int("limit", 0, "number of recently edited pages to warm (default: prefetch.recent_pages)")
+
+	cacheCmd.AddCommand(
+		cacheStatsCmd,
+		cacheLsCmd,
+		cacheShowCmd,
+		cacheRmCmd,
+		cacheClearCmd,
+		cachePruneCmd,
+		cacheWarmCmd,
+		cacheVerifyCmd,
+	)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// commandContext returns the command's context, or a background context.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// jsonOutput reports whether --json was given.
+func jsonOutput(cmd *cobra.Command) bool {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	return asJSON
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	return nil
+}
+
+// withLocalCache opens the configured cache without requiring a Notion token and
+// runs fn with it. When followIndex is set the search index is kept in sync with
+// removals and saved afterwards.
+func withLocalCache(cmd *cobra.Command, followIndex bool, fn func(context.Context, *cache.PageCache) error) error {
+	cfg, err := config.LoadLocal()
+	if err != nil {
+		return err
+	}
+	return withCache(commandContext(cmd), cfg, followIndex, fn)
+}
+
+// withCache opens the cache described by cfg, runs fn and closes the cache.
+func withCache(ctx context.Context, cfg *config.Config, followIndex bool, fn func(context.Context, *cache.PageCache) error) error {
+	pc, err := openCache(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if pc == nil {
+		return errors.New("caching is disabled (cache_dir is empty)")
+	}
+	defer pc.Close()
+
+	var ix *index.Index
+	if followIndex {
+		if ix, err = index.Open(ctx, index.OpenInput{Cache: pc}); err != nil {
+			return fmt.Errorf("open search index: %w", err)
+		}
+	}
+
+	if err := fn(ctx, pc); err != nil {
+		return err
+	}
+
+	if ix != nil {
+		if err := ix.Save(); err != nil {
+			return fmt.Errorf("save search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// cacheStatsOutput is the --json output of `cache stats`.
+type cacheStatsOutput struct {
+	Dir       string `json:"dir"`
+	Encrypted bool   `json:"encrypted"`
+	cache.DiskUsage
+}
+
+// cacheStats implements `cache stats`.
+func cacheStats(ctx context.Context, w io.Writer, pc *cache.PageCache, asJSON bool) error {
+	usage, err := pc.DiskUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := cacheStatsOutput{Dir: pc.Dir(), Encrypted: pc.Encrypted(), DiskUsage: usage}
+	if asJSON {
+		return writeJSON(w, out)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Directory:\t%s\n", out.Dir)
+	fmt.Fprintf(tw, "Encrypted:\t%s\n", yesNo(out.Encrypted))
+	fmt.Fprintf(tw, "Entries:\t%d (%d expired)\n", usage.Entries, usage.Expired)
+	fmt.Fprintf(tw, "Size:\t%s\n", formatBytes(usage.Bytes))
+	fmt.Fprintf(tw, "Unreadable:\t%d\n", usage.Unreadable)
+	fmt.Fprintf(tw, "Quarantined:\t%d\n", usage.Quarantined)
+	return tw.Flush()
+}
+
+// cachedPage summarizes the cache entries stored for one page.
+type cachedPage struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	HasMeta   bool       `json:"has_meta"`
+	HasBlocks bool       `json:"has_blocks"`
+	CachedAt  time.Time  `json:"cached_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Expired   bool       `json:"expired"`
+	Bytes     int        `json:"bytes"`
+
+	meta   *cache.CacheEntry
+	blocks *cache.CacheEntry
+}
+
+// collectPages groups cache entries by page, most recently cached first.
+func collectPages(ctx context.Context, pc *cache.PageCache) ([]*cachedPage, error) {
+	entries, err := pc.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*cachedPage)
+	var pages []*cachedPage
+	for i := range entries {
+		entry := &entries[i]
+
+		id := entry.PageID
+		isMeta := cache.IsMetaKey(id)
+		if isMeta {
+			id = strings.TrimPrefix(id, cache.MetaKey(""))
+		}
+
+		key := notion.NormalizeID(id)
+		page, ok := byID[key]
+		if !ok {
+			page = &cachedPage{ID: id}
+			byID[key] = page
+			pages = append(pages, page)
+		}
+
+		if isMeta {
+			page.HasMeta = true
+			page.meta = entry
+			if decoded, err := notion.DecodePage(entry.Data); err == nil {
+				page.Title = notion.PageTitle(decoded)
+			}
+		} else {
+			page.HasBlocks = true
+			page.blocks = entry
+		}
+
+		page.Bytes += len(entry.Data)
+		if entry.Timestamp.After(page.CachedAt) {
+			page.CachedAt = entry.Timestamp
+		}
+		if entry.TTL > 0 {
+			expires := entry.Timestamp.Add(entry.TTL)
+			if page.ExpiresAt == nil || expires.Before(*page.ExpiresAt) {
+				page.ExpiresAt = &expires
+			}
+		}
+		if pc.IsExpired(entry) {
+			page.Expired = true
+		}
+	}
+
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].CachedAt.After(pages[j].CachedAt)
+	})
+	return pages, nil
+}
+
+// findPage returns the cached page with the given ID, in any ID format.
+func findPage(pages []*cachedPage, id string) (*cachedPage, bool) {
+	key := notion.NormalizeID(id)
+	for _, page := range pages {
+		if notion.NormalizeID(page.ID) == key {
+			return page, true
+		}
+	}
+	return nil, false
+}
+
+// cacheList implements `cache ls`.
+func cacheList(ctx context.Context, w io.Writer, pc *cache.PageCache, asJSON bool) error {
+	pages, err := collectPages(ctx, pc)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		if pages == nil {
+			pages = []*cachedPage{}
+		}
+		return writeJSON(w, pages)
+	}
+
+	if len(pages) == 0 {
+		fmt.Fprintln(w, "The cache is empty.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tCONTENT\tCACHED\tSTATUS\tSIZE")
+	for _, page := range pages {
+		status := "fresh"
+		if page.Expired {
+			status = "expired"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			page.ID,
+			orDash(page.Title),
+			pageContent(page),
+			formatAge(page.CachedAt),
+			status,
+			formatBytes(int64(page.Bytes)),
+		)
+	}
+	return tw.Flush()
+}
+
+// cacheShowOutput is the --json output of `cache show`.
+type cacheShowOutput struct {
+	*cachedPage
+	Meta   json.RawMessage `json:"meta,omitempty"`
+	Blocks json.RawMessage `json:"blocks,omitempty"`
+}
+
+// cacheShow implements `cache show`.
+func cacheShow(ctx context.Context, w io.Writer, pc *cache.PageCache, id string, asJSON bool) error {
+	pages, err := collectPages(ctx, pc)
+	if err != nil {
+		return err
+	}
+	page, ok := findPage(pages, id)
+	if !ok {
+		return fmt.Errorf("page %s is not cached", id)
+	}
+
+	if asJSON {
+		out := cacheShowOutput{cachedPage: page}
+		if page.meta != nil {
+			out.Meta = page.meta.Data
+		}
+		if page.blocks != nil {
+			out.Blocks = page.blocks.Data
+		}
+		return writeJSON(w, out)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Page:\t%s\n", page.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", orDash(page.Title))
+	fmt.Fprintf(tw, "Content:\t%s\n", pageContent(page))
+	fmt.Fprintf(tw, "Cached:\t%s (%s)\n", page.CachedAt.Format(time.RFC3339), formatAge(page.CachedAt))
+	if page.ExpiresAt != nil {
+		fmt.Fprintf(tw, "Expires:\t%s\n", page.ExpiresAt.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(tw, "Expires:\tnever\n")
+	}
+	fmt.Fprintf(tw, "Size:\t%s\n", formatBytes(int64(page.Bytes)))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if page.blocks == nil {
+		return nil
+	}
+
+	var response notionapi.GetChildrenResponse
+	if err := json.Unmarshal(page.blocks.Data, &response); err != nil {
+		return fmt.Errorf("decode cached blocks: %w", err)
+	}
+	markdown, err := notion.ConvertBlocksToMarkdown(response.Results)
+	if err != nil {
+		return fmt.Errorf("render cached blocks: %w", err)
+	}
+	fmt.Fprintf(w, "\n%s\n", strings.TrimRight(markdown, "\n"))
+	return nil
+}
+
+// cacheRemove implements `cache rm`.
+func cacheRemove(ctx context.Context, w io.Writer, pc *cache.PageCache, ids []string, asJSON bool) error {
+	pages, err := collectPages(ctx, pc)
+	if err != nil {
+		return err
+	}
+
+	removed := []string{}
+	var missing []string
+	for _, id := range ids {
+		page, ok := findPage(pages, id)
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		for _, entry := range []*cache.CacheEntry{page.meta, page.blocks} {
+			if entry == nil {
+				continue
+			}
+			if err := pc.Delete(entry.PageID); err != nil {
+				return err
+			}
+		}
+		removed = append(removed, page.ID)
+	}
+
+	if asJSON {
+		if err := writeJSON(w, map[string][]string{"removed": removed}); err != nil {
+			return err
+		}
+	} else {
+		for _, id := range removed {
+			fmt.Fprintf(w, "Removed %s\n", id)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("not cached: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// cacheClear implements `cache clear`.
+func cacheClear(ctx context.Context, w io.Writer, pc *cache.PageCache, asJSON bool) error {
+	pages, err := collectPages(ctx, pc)
+	if err != nil {
+		return err
+	}
+	if err := pc.Clear(); err != nil {
+		return err
+	}
+
+	if asJSON {
+		return writeJSON(w, map[string]int{"removed_pages": len(pages)})
+	}
+	fmt.Fprintf(w, "Removed %d cached pages.\n", len(pages))
+	return nil
+}
+
+// cachePrune implements `cache prune`.
+func cachePrune(ctx context.Context, w io.Writer, pc *cache.PageCache, asJSON bool) error {
+	pruned, err := pc.Prune(ctx)
+	if err != nil {
+		return err
+	}
+	purged, err := pc.PurgeQuarantine()
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		if pruned == nil {
+			pruned = []string{}
+		}
+		return writeJSON(w, struct {
+			Expired     []string `json:"expired"`
+			Quarantined int      `json:"quarantined"`
+		}{Expired: pruned, Quarantined: purged})
+	}
+	fmt.Fprintf(w, "Removed %d expired entries and %d quarantined files.\n", len(pruned), purged)
+	return nil
+}
+
+// cacheVerify implements `cache verify`. It fails when corrupt entries were found.
+func cacheVerify(ctx context.Context, w io.Writer, pc *cache.PageCache, asJSON bool) error {
+	quarantined, err := pc.Verify(ctx)
+	if err != nil {
+		return err
+	}
+	usage, err := pc.DiskUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		if quarantined == nil {
+			quarantined = []string{}
+		}
+		if err := writeJSON(w, struct {
+			Healthy     int      `json:"healthy"`
+			Quarantined []string `json:"quarantined"`
+		}{Healthy: usage.Entries, Quarantined: quarantined}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(w, "%d entries OK, %d quarantined.\n", usage.Entries, len(quarantined))
+		for _, key := range quarantined {
+			fmt.Fprintf(w, "  quarantined %s\n", key)
+		}
+	}
+
+	if len(quarantined) > 0 {
+		return fmt.Errorf("found %d corrupt cache entries", len(quarantined))
+	}
+	return nil
+}
+
+// runCacheWarm implements `cache warm`.
+func runCacheWarm(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	databaseID := resolveDatabase(cfg, args[0])
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit <= 0 {
+		limit = cfg.PrefetchRecentPages(databaseID)
+	}
+	if limit <= 0 {
+		limit = config.DefaultPrefetchRecentPages
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	return withCache(ctx, cfg, true, func(ctx context.Context, pc *cache.PageCache) error {
+		p, err := prefetch.NewPrefetcher(prefetch.NewPrefetcherInput{
+			Fetcher:     notion.NewClient(cfg.NotionToken),
+			Cache:       pc,
+			HistorySize: cfg.PrefetchHistorySize(),
+		})
+		if err != nil {
+			return err
+		}
+		return cacheWarm(ctx, cmd.OutOrStdout(), p, databaseID, limit, jsonOutput(cmd))
+	})
+}
+
+// cacheWarmOutput is the --json output of `cache warm`.
+type cacheWarmOutput struct {
+	Database string `json:"database"`
+	Warmed   int    `json:"warmed"`
+	Skipped  int    `json:"skipped"`
+	Failed   int    `json:"failed"`
+}
+
+// cacheWarm queues a database for prefetching and processes the queue.
+func cacheWarm(ctx context.Context, w io.Writer, p *prefetch.Prefetcher, databaseID string, limit int, asJSON bool) error {
+	p.QueueDatabase(databaseID, limit)
+	drainErr := p.Drain(ctx)
+
+	stats := p.Stats()
+	out := cacheWarmOutput{
+		Database: databaseID,
+		Warmed:   stats.Warmed,
+		Skipped:  stats.Skipped,
+		Failed:   stats.Failed,
+	}
+	if asJSON {
+		if err := writeJSON(w, out); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(w, "Warmed %d pages, %d already fresh, %d failed.\n", out.Warmed, out.Skipped, out.Failed)
+	}
+
+	if drainErr != nil {
+		return drainErr
+	}
+	if out.Failed > 0 {
+		return fmt.Errorf("failed to warm %d pages", out.Failed)
+	}
+	return nil
+}
+
+// resolveDatabase maps a configured database name to its ID. Anything else is
+// returned unchanged and treated as an ID.
+func resolveDatabase(cfg *config.Config, nameOrID string) string {
+	for _, db := range cfg.Databases {
+		if strings.EqualFold(db.Name, nameOrID) {
+			return db.ID
+		}
+	}
+	return nameOrID
+}
+
+// pageContent describes which entries are cached for a page.
+func pageContent(page *cachedPage) string {
+	switch {
+	case page.HasMeta && page.HasBlocks:
+		return "meta+blocks"
+	case page.HasBlocks:
+		return "blocks"
+	default:
+		return "meta"
+	}
+}
+
+// formatBytes renders a byte count for humans.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatAge renders how long ago t was.
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// orDash returns s, or "-" when it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// yesNo renders a boolean for humans.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+)
+
+var cacheRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Re-encrypt the cache under a new key",
+	Long: `Re-encrypt every cache entry under a new key.
+
+The current key is taken from the cache_encryption config section. The new key
+is given with exactly one of --key-file, --key-command or --passphrase-env.
+Update cache_encryption to the new key source afterwards.
+
+Use --decrypt to turn encryption off and store the cache in plaintext.
+
+The search index is re-encrypted along with the pages. If a rotation is
+interrupted, the cache opens with either key; run the command again with the
+same new key (or --decrypt) to finish it.`,
+	Args: cobra.NoArgs,
+	RunE: runCacheRotateKey,
+}
+
+func init() {
+	cacheRotateKeyCmd.Flags().String("key-file", "", "file holding the new key")
+	cacheRotateKeyCmd.Flags().String("key-command", "", "shell command printing the new key")
+	cacheRotateKeyCmd.Flags().String("passphrase-env", "", "environment variable holding the new passphrase")
+	cacheRotateKeyCmd.Flags().Bool("decrypt", false, "decrypt the cache instead of rotating to a new key")
+
+	cacheCmd.AddCommand(cacheRotateKeyCmd)
+}
+
+// runCacheRotateKey implements `notion-tui cache rotate-key`.
+func runCacheRotateKey(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadLocal()
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext(cmd)
+
+	newKey, err := newKeyFromFlags(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	pc, err := openCache(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if pc == nil {
+		return errors.New("cache_dir is not configured")
+	}
+	defer pc.Close()
+
+	if err := pc.Rotate(ctx, newKey); err != nil {
+		return fmt.Errorf("rotate cache key: %w", err)
+	}
+
+	if newKey == nil {
+		fmt.Fprintln(cmd.OutOrStdout(), "Cache decrypted. Remove cache_encryption from your config.")
+	} else {
+		fmt.Fprintln(cmd.OutOrStdout(), "Cache re-encrypted. Point cache_encryption at the new key.")
+	}
+	return nil
+}
+
+// newKeyFromFlags reads the rotation target key. It returns nil for --decrypt.
+func newKeyFromFlags(ctx context.Context, cmd *cobra.Command) (*cache.Key, error) {
+	key, err := readNewKey(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	redactKey(key)
+	return key, nil
+}
+
+// readNewKey reads the key the rotation flags select.
+func readNewKey(ctx context.Context, cmd *cobra.Command) (*cache.Key, error) {
+	keyFile, _ := cmd.Flags().GetString("key-file")
+	keyCommand, _ := cmd.Flags().GetString("key-command")
+	passphraseEnv, _ := cmd.Flags().GetString("passphrase-env")
+	decrypt, _ := cmd.Flags().GetBool("decrypt")
+
+	set := 0
+	for _, given := range []bool{keyFile != "", keyCommand != "", passphraseEnv != "", decrypt} {
+		if given {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("give exactly one of --key-file, --key-command, --passphrase-env or --decrypt")
+	}
+
+	switch {
+	case keyFile != "":
+		return cache.KeyFromFile(keyFile)
+	case keyCommand != "":
+		return cache.KeyFromCommand(ctx, keyCommand)
+	case passphraseEnv != "":
+		return cache.KeyFromPassphrase(os.Getenv(passphraseEnv))
+	default:
+		return nil, nil
+	}
+}
+
+// cacheKey returns the key selected by the cache_encryption config section,
+// or nil when the cache is not encrypted. The key material is kept out of the
+// debug log.
+func cacheKey(ctx context.Context, enc config.CacheEncryptionConfig) (*cache.Key, error) {
+	key, err := readCacheKey(ctx, enc)
+	if err != nil {
+		return nil, err
+	}
+	redactKey(key)
+	return key, nil
+}
+
+// readCacheKey reads the key the cache_encryption config section selects.
+func readCacheKey(ctx context.Context, enc config.CacheEncryptionConfig) (*cache.Key, error) {
+	switch {
+	case enc.Passphrase != "":
+		return cache.KeyFromPassphrase(enc.Passphrase)
+	case enc.KeyFile != "":
+		return cache.KeyFromFile(enc.KeyFile)
+	case enc.KeyCommand != "":
+		return cache.KeyFromCommand(ctx, enc.KeyCommand)
+	default:
+		return nil, nil
+	}
+}
+
+// openCache opens the configured cache, or returns nil when caching is disabled.
+func openCache(ctx context.Context, cfg *config.Config) (*cache.PageCache, error) {
+	if cfg.CacheDir == "" {
+		return nil, nil
+	}
+
+	key, err := cacheKey(ctx, cfg.CacheEncryption)
+	if err != nil {
+		return nil, fmt.Errorf("load cache key: %w", err)
+	}
+
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: cfg.CacheDir, Key: key})
+	if err != nil {
+		return nil, fmt.Errorf("open cache: %w", err)
+	}
+	return pc, nil
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/prefetch"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// newTestCache returns a cache holding one fully cached page ("page-1") and one
+// expired page with blocks only ("page-2").
+func newTestCache(t *testing.T) *cache.PageCache {
+	t.Helper()
+
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewPageCache() error = %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	ctx := context.Background()
+	inputs := []cache.SetInput{
+		{PageID: cache.MetaKey("page-1"), Data: testhelpers.NewTestPage("page-1", "Roadmap"), TTL: time.Hour},
+		{PageID: "page-1", Data: testhelpers.NewGetChildrenResponse([]notionapi.Block{
+			testhelpers.NewParagraphBlock("Ship the cache CLI"),
+		}), TTL: time.Hour},
+		{PageID: "page-2", Data: testhelpers.NewGetChildrenResponse(nil), TTL: time.Nanosecond},
+	}
+	for _, input := range inputs {
+		if err := pc.Set(ctx, input); err != nil {
+			t.Fatalf("Set(%s) error = %v", input.PageID, err)
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	return pc
+}
+
+// TestCacheSubcommandsRegistered verifies the cache command family is wired up.
+func TestCacheSubcommandsRegistered(t *testing.T) {
+	want := []string{"stats", "ls", "show", "rm", "clear", "prune", "warm", "verify", "rotate-key"}
+	for _, name := range want {
+		found := false
+		for _, c := range cacheCmd.Commands() {
+			if c.Name() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("cache %s subcommand not registered", name)
+		}
+	}
+	if cacheCmd.PersistentFlags().Lookup("json") == nil {
+		t.Error("--json flag not registered on cache")
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	var human bytes.Buffer
+	if err := cacheStats(ctx, &human, pc, false); err != nil {
+		t.Fatalf("cacheStats() error = %v", err)
+	}
+	if !strings.Contains(human.String(), "3 (1 expired)") {
+		t.Errorf("cacheStats() output = %q, want entry counts", human.String())
+	}
+
+	var raw bytes.Buffer
+	if err := cacheStats(ctx, &raw, pc, true); err != nil {
+		t.Fatalf("cacheStats() error = %v", err)
+	}
+	var out struct {
+		Dir       string `json:"dir"`
+		Encrypted bool   `json:"encrypted"`
+		Entries   int    `json:"entries"`
+		Expired   int    `json:"expired"`
+	}
+	if err := json.Unmarshal(raw.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", raw.String(), err)
+	}
+	if out.Dir != pc.Dir() || out.Encrypted || out.Entries != 3 || out.Expired != 1 {
+		t.Errorf("cacheStats() JSON = %+v", out)
+	}
+}
+
+func TestCacheList(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	var human bytes.Buffer
+	if err := cacheList(ctx, &human, pc, false); err != nil {
+		t.Fatalf("cacheList() error = %v", err)
+	}
+	for _, want := range []string{"Roadmap", "meta+blocks", "expired"} {
+		if !strings.Contains(human.String(), want) {
+			t.Errorf("cacheList() output missing %q:\n%s", want, human.String())
+		}
+	}
+
+	var raw bytes.Buffer
+	if err := cacheList(ctx, &raw, pc, true); err != nil {
+		t.Fatalf("cacheList() error = %v", err)
+	}
+	var pages []cachedPage
+	if err := json.Unmarshal(raw.Bytes(), &pages); err != nil {
+		t.Fatalf("invalid JSON %q: %v", raw.String(), err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("cacheList() listed %d pages, want 2", len(pages))
+	}
+	byID := map[string]cachedPage{pages[0].ID: pages[0], pages[1].ID: pages[1]}
+	if p := byID["page-1"]; p.Title != "Roadmap" || !p.HasMeta || !p.HasBlocks || p.Expired {
+		t.Errorf("page-1 = %+v", p)
+	}
+	if p := byID["page-2"]; p.HasMeta || !p.HasBlocks || !p.Expired {
+		t.Errorf("page-2 = %+v", p)
+	}
+}
+
+func TestCacheShow(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	var human bytes.Buffer
+	if err := cacheShow(ctx, &human, pc, "page-1", false); err != nil {
+		t.Fatalf("cacheShow() error = %v", err)
+	}
+	for _, want := range []string{"Roadmap", "Ship the cache CLI"} {
+		if !strings.Contains(human.String(), want) {
+			t.Errorf("cacheShow() output missing %q:\n%s", want, human.String())
+		}
+	}
+
+	var raw bytes.Buffer
+	if err := cacheShow(ctx, &raw, pc, "page-1", true); err != nil {
+		t.Fatalf("cacheShow() error = %v", err)
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(raw.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", raw.String(), err)
+	}
+	for _, key := range []string{"id", "title", "meta", "blocks"} {
+		if _, ok := out[key]; !ok {
+			t.Errorf("cacheShow() JSON missing %q", key)
+		}
+	}
+
+	if err := cacheShow(ctx, &bytes.Buffer{}, pc, "missing", false); err == nil {
+		t.Error("cacheShow() of an uncached page should fail")
+	}
+}
+
+func TestCacheRemove(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := cacheRemove(ctx, &out, pc, []string{"page-1"}, false); err != nil {
+		t.Fatalf("cacheRemove() error = %v", err)
+	}
+	if pc.Has(ctx, "page-1") || pc.Has(ctx, cache.MetaKey("page-1")) {
+		t.Error("cacheRemove() left entries for page-1")
+	}
+
+	err := cacheRemove(ctx, &bytes.Buffer{}, pc, []string{"page-2", "missing"}, false)
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("cacheRemove() error = %v, want it to name the missing page", err)
+	}
+}
+
+func TestCacheClearPruneVerify(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := cachePrune(ctx, &out, pc, false); err != nil {
+		t.Fatalf("cachePrune() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Removed 1 expired") {
+		t.Errorf("cachePrune() output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := cacheVerify(ctx, &out, pc, false); err != nil {
+		t.Fatalf("cacheVerify() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "2 entries OK, 0 quarantined") {
+		t.Errorf("cacheVerify() output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := cacheClear(ctx, &out, pc, true); err != nil {
+		t.Fatalf("cacheClear() error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != `{
+  "removed_pages": 1
+}` {
+		t.Errorf("cacheClear() JSON = %q", out.String())
+	}
+}
+
+func TestCacheWarm(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.DatabaseToReturn = testhelpers.NewTestDatabase("db-1")
+	mockClient.PageToReturn = testhelpers.NewTestPage("page-x", "Any")
+	mockClient.BlocksToReturn = testhelpers.NewGetChildrenResponse(nil)
+
+	p, err := prefetch.NewPrefetcher(prefetch.NewPrefetcherInput{Fetcher: mockClient, Cache: pc})
+	if err != nil {
+		t.Fatalf("NewPrefetcher() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cacheWarm(ctx, &out, p, "db-1", 5, true); err != nil {
+		t.Fatalf("cacheWarm() error = %v", err)
+	}
+
+	var stats cacheWarmOutput
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if stats.Database != "db-1" || stats.Warmed == 0 || stats.Failed != 0 {
+		t.Errorf("cacheWarm() stats = %+v", stats)
+	}
+	if !pc.Has(ctx, "page-3") {
+		t.Error("cacheWarm() did not cache database pages")
+	}
+}
+
+func TestResolveDatabase(t *testing.T) {
+	cfg := &config.Config{
+		Databases: []config.DatabaseConfig{{ID: "db-tasks", Name: "Tasks"}},
+	}
+
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{arg: "Tasks", want: "db-tasks"},
+		{arg: "tasks", want: "db-tasks"},
+		{arg: "db-tasks", want: "db-tasks"},
+		{arg: "db-other", want: "db-other"},
+	}
+	for _, tt := range tests {
+		if got := resolveDatabase(cfg, tt.arg); got != tt.want {
+			t.Errorf("resolveDatabase(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{n: 0, want: "0 B"},
+		{n: 1023, want: "1023 B"},
+		{n: 1536, want: "1.5 KB"},
+		{n: 5 * 1024 * 1024, want: "5.0 MB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture <text>",
+	Short: "Add a row to the inbox database",
+	Long: `Add a row titled <text> to the inbox database and print its ID and URL.
+
+The inbox is inbox_database from the config file (a configured name or an ID)
+and falls back to the default database; --database picks another one.
+Properties are set with --set name=value, using the same value formats as
+the query command's output: dates accept YYYY-MM-DD, today, tomorrow and so
+on, and multi-select values are separated by commas.
+
+With "-" as the text, the first line of stdin is the title and the rest is
+added to the new page as markdown.`,
+	Example: `  notion-tui capture "Renew passport" --set Status=Todo --set Due=tomorrow
+  pbpaste | notion-tui capture - --database Reading --set Tags=article,later`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCapture,
+}
+
+func init() {
+	captureCmd.Flags().StringArray("set", nil, "set a property, as name=value (repeatable)")
+	captureCmd.Flags().String("database", "", "database to add the row to (default: inbox_database)")
+	captureCmd.Flags().Bool("json", false, "output as JSON")
+	rootCmd.AddCommand(captureCmd)
+}
+
+// captureClient is the subset of the Notion client used to create a row.
+type captureClient interface {
+	notion.BlockAppender
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
+	CreatePage(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error)
+}
+
+// captureInput contains parameters for capture.
+type captureInput struct {
+	Client     captureClient
+	DatabaseID string
+	Title      string
+	Body       string // markdown added as page content
+	Set        []string
+	Now        time.Time
+}
+
+func runCapture(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	database, _ := cmd.Flags().GetString("database")
+	if database == "" {
+		database = cfg.GetInboxDatabase()
+	}
+	if database == "" {
+		return errors.New("no inbox database: set inbox_database in the config file or pass --database")
+	}
+	id, err := notion.ParseID(resolveDatabase(cfg, database))
+	if err != nil {
+		return err
+	}
+
+	title, body := strings.Join(args, " "), ""
+	if title == "-" {
+		if title, body, err = readCapture(cmd.InOrStdin()); err != nil {
+			return err
+		}
+	}
+
+	set, _ := cmd.Flags().GetStringArray("set")
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	return capture(ctx, cmd.OutOrStdout(), captureInput{
+		Client:     notion.NewClient(cfg.NotionToken),
+		DatabaseID: id,
+		Title:      title,
+		Body:       body,
+		Set:        set,
+		Now:        time.Now(),
+	}, jsonOutput(cmd))
+}
+
+// readCapture takes the title from the first non-blank line of stdin and the
+// body from the rest.
+func readCapture(r io.Reader) (string, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", "", fmt.Errorf("read stdin: %w", err)
+	}
+	text := strings.TrimLeft(string(data), " \t\r\n")
+	title, body, _ := strings.Cut(text, "\n")
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", "", errors.New("nothing to capture: stdin is empty")
+	}
+	return title, body, nil
+}
+
+// capture implements `capture`.
+func capture(ctx context.Context, w io.Writer, input captureInput, asJSON bool) error {
+	if strings.TrimSpace(input.Title) == "" {
+		return errors.New("nothing to capture: the title is empty")
+	}
+
+	db, err := input.Client.GetDatabase(ctx, input.DatabaseID)
+	if err != nil {
+		return err
+	}
+	props, err := captureProperties(db, input)
+	if err != nil {
+		return err
+	}
+
+	page, err := input.Client.CreatePage(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: notionapi.DatabaseID(input.DatabaseID)},
+		Properties: props,
+	})
+	if err != nil {
+		return err
+	}
+
+	if nodes := notion.ParseMarkdown(input.Body); len(nodes) > 0 {
+		if _, err := notion.AppendBlockTree(ctx, input.Client, string(page.ID), nodes); err != nil {
+			return fmt.Errorf("add content to %s: %w", page.ID, err)
+		}
+	}
+
+	return printCreated(w, createdOutput{ID: string(page.ID), URL: page.URL}, asJSON)
+}
+
+// captureProperties builds the properties of the new row from the title and
+// the --set flags.
+func captureProperties(db *notionapi.Database, input captureInput) (notionapi.Properties, error) {
+	props := notionapi.Properties{}
+	for name, prop := range db.Properties {
+		if prop.GetType() == notionapi.PropertyConfigTypeTitle {
+			title, err := notion.ParsePropertyValue(notionapi.PropertyConfigTypeTitle, input.Title, input.Now)
+			if err != nil {
+				return nil, err
+			}
+			props[name] = title
+		}
+	}
+	if len(props) == 0 {
+		return nil, fmt.Errorf("database %s has no title property", input.DatabaseID)
+	}
+
+	for _, assignment := range input.Set {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return nil, fmt.Errorf("--set %q: want name=value", assignment)
+		}
+		name, ok := findProperty(db.Properties, strings.TrimSpace(key))
+		if !ok {
+			return nil, fmt.Errorf("--set %q: unknown property %q", assignment, key)
+		}
+		prop, err := notion.ParsePropertyValue(db.Properties[name].GetType(), value, input.Now)
+		if err != nil {
+			return nil, fmt.Errorf("--set %q: %w", assignment, err)
+		}
+		props[name] = prop
+	}
+	return props, nil
+}
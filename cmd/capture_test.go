@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// newCaptureClient returns a mock client for an inbox database with a status
+// and a date property.
+func newCaptureClient() *testhelpers.MockNotionClient {
+	schema := testhelpers.NewTestDatabaseSchema("db-inbox")
+	schema.Properties["Status"] = &notionapi.StatusPropertyConfig{Type: notionapi.PropertyConfigStatus}
+	schema.Properties["Due"] = &notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate}
+
+	return newAppendClient().WithSchema(schema)
+}
+
+func TestCapture(t *testing.T) {
+	mockClient := newCaptureClient()
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	err := capture(context.Background(), &out, captureInput{
+		Client:     mockClient,
+		DatabaseID: "db-inbox",
+		Title:      "Renew passport",
+		Body:       "Bring the old one.\n",
+		Set:        []string{"status=Todo", "Due=tomorrow"},
+		Now:        now,
+	}, false)
+	if err != nil {
+		t.Fatalf("capture() error = %v", err)
+	}
+
+	if got, want := out.String(), "new-page\thttps://www.notion.so/new-page\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	req := mockClient.LastCreatePageCall().Request
+	if req.Parent.Type != notionapi.ParentTypeDatabaseID || req.Parent.DatabaseID != "db-inbox" {
+		t.Errorf("Parent = %+v, want database db-inbox", req.Parent)
+	}
+	want := map[string]string{"title": "Renew passport", "Status": "Todo", "Due": "2024-03-16"}
+	if len(req.Properties) != len(want) {
+		t.Errorf("Properties = %v, want %v", req.Properties, want)
+	}
+	for name, value := range want {
+		if got := notion.PropertyString(req.Properties[name]); got != value {
+			t.Errorf("Properties[%s] = %q, want %q", name, got, value)
+		}
+	}
+
+	call := mockClient.LastAppendBlocksCall()
+	if call == nil || call.ID != "new-page" || len(call.Request.Children) != 1 {
+		t.Errorf("body was not appended to the new page: %+v", call)
+	}
+}
+
+func TestCaptureErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   captureInput
+		wantErr string
+	}{
+		{
+			name:    "empty title",
+			input:   captureInput{Title: "  "},
+			wantErr: "the title is empty",
+		},
+		{
+			name:    "malformed set",
+			input:   captureInput{Title: "x", Set: []string{"Status"}},
+			wantErr: `--set "Status": want name=value`,
+		},
+		{
+			name:    "unknown property",
+			input:   captureInput{Title: "x", Set: []string{"Owner=me"}},
+			wantErr: `unknown property "Owner"`,
+		},
+		{
+			name:    "invalid value",
+			input:   captureInput{Title: "x", Set: []string{"Due=someday"}},
+			wantErr: `"someday" is not a date`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newCaptureClient()
+			tt.input.Client = mockClient
+			tt.input.DatabaseID = "db-inbox"
+
+			err := capture(context.Background(), &bytes.Buffer{}, tt.input, false)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("capture() error = %v, want %q", err, tt.wantErr)
+			}
+			if mockClient.CreatePageCallCount() != 0 {
+				t.Error("CreatePage should not be called")
+			}
+		})
+	}
+}
+
+func TestReadCapture(t *testing.T) {
+	title, body, err := readCapture(strings.NewReader("\n  Article to read  \nhttps://example.com\n\nNotes"))
+	if err != nil {
+		t.Fatalf("readCapture() error = %v", err)
+	}
+	if title != "Article to read" {
+		t.Errorf("title = %q", title)
+	}
+	if body != "https://example.com\n\nNotes" {
+		t.Errorf("body = %q", body)
+	}
+
+	if _, _, err := readCapture(strings.NewReader(" \n")); err == nil {
+		t.Error("readCapture() of blank input should fail")
+	}
+}
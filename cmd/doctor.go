@@ -0,0 +1,473 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+)
+
+const (
+	// notionAPIURL is requested for the server's clock; any response has a
+	// Date header, so no token is sent.
+	notionAPIURL = "https://api.notion.com/v1/users/me"
+	// maxClockSkew is the difference from Notion's clock that is reported.
+	maxClockSkew = time.Minute
+	// integrationsURL is where integration tokens are managed.
+	integrationsURL = "https://www.notion.so/my-integrations"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration, token, databases and terminal",
+	Long: `Run a series of checks and print a report with a fix for every problem:
+
+  - the config file can be read and is not readable by other users
+  - the settings are valid
+  - Notion accepts the token
+  - every configured database exists and is shared with the integration
+  - the cache directory is writable and private
+  - the terminal supports colors and Unicode
+  - the system clock agrees with Notion's
+
+The command fails when a check fails. Warnings do not stop notion-tui
+from working but may explain odd behaviour.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// checkStatus is the outcome of a doctor check.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+)
+
+// doctorCheck is one line of the doctor report.
+type doctorCheck struct {
+	Name   string
+	Status checkStatus
+	Detail string
+	Fix    string // how to resolve a warning or failure
+}
+
+// doctorClient is the subset of the Notion client used by doctor.
+type doctorClient interface {
+	Me(ctx context.Context) (*notionapi.User, error)
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
+}
+
+// doctorInput contains the environment doctor inspects.
+type doctorInput struct {
+	ConfigFile    string // the file read, if any
+	ConfigReadErr error  // from reading ConfigFile
+	Config        *config.Config
+	ConfigErr     error        // from validating the settings; Config is nil when set
+	TokenErr      error        // from notion_token_file or notion_token_command
+	Client        doctorClient // nil when there is no token
+	Getenv        func(key string) string
+	ColorProfile  string // "TrueColor", "ANSI256", "ANSI" or "Ascii"
+	ServerTime    func(ctx context.Context) (time.Time, error)
+	Now           func() time.Time
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	input := doctorInput{
+		Getenv:       os.Getenv,
+		ColorProfile: lipgloss.ColorProfile().Name(),
+		ServerTime:   notionServerTime,
+		Now:          time.Now,
+	}
+
+	// Read the file again to see the error initConfig ignores
+	input.ConfigReadErr = viper.ReadInConfig()
+	input.ConfigFile = viper.ConfigFileUsed()
+	var notFound viper.ConfigFileNotFoundError
+	if errors.As(input.ConfigReadErr, &notFound) {
+		input.ConfigFile = ""
+	}
+
+	input.Config, input.ConfigErr = config.LoadLocal()
+	if input.Config != nil {
+		input.TokenErr = input.Config.ResolveToken()
+		redactConfig(input.Config)
+	}
+	if input.Config != nil && input.Config.NotionToken != "" {
+		input.Client = notion.NewClient(input.Config.NotionToken)
+	}
+
+	checks := doctor(commandContext(cmd), input)
+	return printDoctor(cmd.OutOrStdout(), checks)
+}
+
+// doctor runs every check. Checks that depend on an earlier one which
+// failed, such as databases after the token, are reported as skipped.
+func doctor(ctx context.Context, input doctorInput) []doctorCheck {
+	checks := []doctorCheck{checkConfigFile(input)}
+
+	if input.ConfigErr != nil {
+		checks = append(checks, doctorCheck{
+			Name:   "Settings",
+			Status: checkFail,
+			Detail: input.ConfigErr.Error(),
+			Fix:    "correct the setting; config.example.yaml documents every option",
+		})
+		checks = append(checks, checkTerminal(input)...)
+		return append(checks, checkClock(ctx, input))
+	}
+	checks = append(checks, doctorCheck{Name: "Settings", Status: checkPass, Detail: "valid"})
+	checks = append(checks, checkKeys(input.Config))
+	checks = append(checks, checkTheme(input.Config))
+
+	token := checkToken(ctx, input)
+	checks = append(checks, token)
+	if token.Status == checkFail {
+		checks = append(checks, doctorCheck{
+			Name:   "Databases",
+			Status: checkWarn,
+			Detail: "skipped until the token works",
+		})
+	} else {
+		checks = append(checks, checkDatabases(ctx, input)...)
+	}
+
+	checks = append(checks, checkCacheDir(input.Config))
+	checks = append(checks, checkTerminal(input)...)
+	return append(checks, checkClock(ctx, input))
+}
+
+// checkKeys reports whether the key bindings load without conflicts, as the
+// TUI refuses to start otherwise.
+func checkKeys(cfg *config.Config) doctorCheck {
+	check := doctorCheck{Name: "Key bindings", Status: checkPass}
+	if _, err := keymap.New(keymap.NewInput{Preset: cfg.Keys.Preset, Overrides: cfg.Keys.Overrides()}); err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Fix = "change the keys section of the config file so each key has one action"
+		return check
+	}
+	check.Detail = "no conflicts"
+	if cfg.Keys.Preset != "" {
+		check.Detail = fmt.Sprintf("%s preset, no conflicts", cfg.Keys.Preset)
+	}
+	return check
+}
+
+// checkTheme reports whether the configured theme loads. It is checked even
+// when NO_COLOR hides it.
+func checkTheme(cfg *config.Config) doctorCheck {
+	check := doctorCheck{Name: "Theme", Status: checkPass}
+	t, err := loadTheme(cfg, false)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Fix = "fix the theme section of the config file or the theme file it names"
+		return check
+	}
+	check.Detail = t.Name
+	return check
+}
+
+// checkConfigFile reports which config file was read and whether it is
+// private. A missing file is fine when flags and the environment are used.
+func checkConfigFile(input doctorInput) doctorCheck {
+	check := doctorCheck{Name: "Config file"}
+	switch {
+	case input.ConfigFile == "":
+		check.Status = checkWarn
+		check.Detail = "no config file found; using flags and environment variables only"
+		check.Fix = "copy config.example.yaml to ~/.config/notion-tui/config.yaml"
+		return check
+	case input.ConfigReadErr != nil:
+		check.Status = checkFail
+		check.Detail = input.ConfigReadErr.Error()
+		check.Fix = "fix the YAML in " + input.ConfigFile
+		return check
+	}
+
+	check.Status = checkPass
+	check.Detail = input.ConfigFile
+	if runtime.GOOS == "windows" {
+		return check
+	}
+	info, err := os.Stat(input.ConfigFile)
+	if err == nil && info.Mode().Perm()&0077 != 0 {
+		check.Status = checkWarn
+		check.Detail = fmt.Sprintf("%s can be read by other users (mode %o) and may hold your token", input.ConfigFile, info.Mode().Perm())
+		check.Fix = "chmod 600 " + input.ConfigFile
+	}
+	return check
+}
+
+// checkToken asks Notion who the token belongs to.
+func checkToken(ctx context.Context, input doctorInput) doctorCheck {
+	check := doctorCheck{Name: "Token"}
+	token := input.Config.NotionToken
+	if input.TokenErr != nil {
+		check.Status = checkFail
+		check.Detail = input.TokenErr.Error()
+		check.Fix = "run the notion_token_command by hand, or check notion_token_file, until it prints the token"
+		return check
+	}
+	if token == "" || input.Client == nil {
+		check.Status = checkFail
+		check.Detail = "no token set"
+		check.Fix = "set notion_token, notion_token_file or notion_token_command in the config file, or NOTION_TUI_NOTION_TOKEN; create one at " + integrationsURL
+		return check
+	}
+
+	user, err := input.Client.Me(ctx)
+	if err != nil {
+		check.Status = checkFail
+		if notion.ClassifyError(err) == notion.ErrorClassAuth {
+			check.Detail = "Notion rejected the token"
+			check.Fix = "copy the Internal Integration Secret again from " + integrationsURL
+		} else {
+			check.Detail = err.Error()
+			check.Fix = "check your network connection and proxy settings"
+		}
+		return check
+	}
+
+	check.Status = checkPass
+	check.Detail = fmt.Sprintf("integration %q", user.Name)
+	if user.Bot != nil && user.Bot.WorkspaceName != "" {
+		check.Detail += fmt.Sprintf(" in workspace %q", user.Bot.WorkspaceName)
+	}
+	if !strings.HasPrefix(token, "secret_") && !strings.HasPrefix(token, "ntn_") {
+		check.Status = checkWarn
+		check.Detail += "; the token does not look like an integration secret"
+		check.Fix = "use an Internal Integration Secret (secret_... or ntn_...) rather than a browser session token"
+	}
+	return check
+}
+
+// checkDatabases opens every configured database.
+func checkDatabases(ctx context.Context, input doctorInput) []doctorCheck {
+	if len(input.Config.Databases) == 0 {
+		return []doctorCheck{{
+			Name:   "Databases",
+			Status: checkWarn,
+			Detail: "none configured; only search and links can open pages",
+			Fix:    "add your databases under databases: in the config file",
+		}}
+	}
+
+	checks := make([]doctorCheck, 0, len(input.Config.Databases))
+	for _, db := range input.Config.Databases {
+		check := doctorCheck{Name: "Database " + db.Name}
+		_, err := input.Client.GetDatabase(ctx, db.ID)
+		switch {
+		case err == nil:
+			check.Status = checkPass
+			check.Detail = "shared with the integration"
+		case notion.ClassifyError(err) == notion.ErrorClassNotFound:
+			check.Status = checkFail
+			check.Detail = db.ID + " was not found or is not shared with the integration"
+			check.Fix = "open the database in Notion, choose ... > Connections and add the integration; then check the ID"
+		case notion.ClassifyError(err) == notion.ErrorClassAuth:
+			check.Status = checkFail
+			check.Detail = "the integration may not read " + db.ID
+			check.Fix = "give the integration the Read content capability at " + integrationsURL
+		default:
+			check.Status = checkFail
+			check.Detail = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkCacheDir makes sure the cache directory, or the parent it will be
+// created in, is writable, and that cached pages are private.
+func checkCacheDir(cfg *config.Config) doctorCheck {
+	check := doctorCheck{Name: "Cache directory"}
+	if cfg.CacheDir == "" {
+		check.Status = checkPass
+		check.Detail = "caching is disabled"
+		return check
+	}
+
+	dir := cfg.CacheDir
+	info, err := os.Stat(dir)
+	for errors.Is(err, os.ErrNotExist) && filepath.Dir(dir) != dir {
+		dir = filepath.Dir(dir)
+		info, err = os.Stat(dir)
+	}
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Fix = "set cache_dir to a directory you can write to"
+		return check
+	}
+	if !info.IsDir() {
+		check.Status = checkFail
+		check.Detail = dir + " is not a directory"
+		check.Fix = "remove the file or set cache_dir to another path"
+		return check
+	}
+
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = dir + " is not writable"
+		check.Fix = "chmod u+rwx " + dir + ", or set cache_dir to a directory you can write to"
+		return check
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.Status = checkPass
+	check.Detail = cfg.CacheDir
+	if dir != cfg.CacheDir {
+		check.Detail += " (created on first use)"
+		return check
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		check.Status = checkWarn
+		check.Detail = fmt.Sprintf("%s can be read by other users (mode %o)", dir, info.Mode().Perm())
+		check.Fix = "chmod 700 " + dir + ", or enable cache_encryption"
+	}
+	return check
+}
+
+// checkTerminal reports color and Unicode support, which the TUI needs to
+// draw its borders, icons and highlights.
+func checkTerminal(input doctorInput) []doctorCheck {
+	colors := doctorCheck{Name: "Terminal colors", Status: checkPass}
+	switch input.ColorProfile {
+	case "TrueColor":
+		colors.Detail = "true color"
+	case "ANSI256":
+		colors.Detail = "256 colors"
+	case "ANSI":
+		colors.Detail = "16 colors; themes are approximated"
+	default:
+		colors.Status = checkWarn
+		colors.Detail = "no color support detected"
+		colors.Fix = "run in a terminal with TERM set (e.g. xterm-256color) and NO_COLOR unset"
+		if input.Getenv("NO_COLOR") != "" {
+			colors.Fix = "unset NO_COLOR"
+		}
+	}
+
+	unicode := doctorCheck{Name: "Unicode", Status: checkPass}
+	locale := firstEnv(input.Getenv, "LC_ALL", "LC_CTYPE", "LANG")
+	lower := strings.ToLower(locale)
+	switch {
+	case runtime.GOOS == "windows":
+		unicode.Detail = "assumed on Windows"
+	case strings.Contains(lower, "utf-8") || strings.Contains(lower, "utf8"):
+		unicode.Detail = locale
+	default:
+		unicode.Status = checkWarn
+		unicode.Detail = fmt.Sprintf("locale %q is not UTF-8; icons and borders may be garbled", locale)
+		unicode.Fix = "export LANG=en_US.UTF-8 (or another UTF-8 locale)"
+	}
+
+	return []doctorCheck{colors, unicode}
+}
+
+// checkClock compares the local clock with Notion's. A skewed clock makes
+// cached pages look newer or older than they are and shifts dates such as
+// "tomorrow".
+func checkClock(ctx context.Context, input doctorInput) doctorCheck {
+	check := doctorCheck{Name: "Clock"}
+	server, err := input.ServerTime(ctx)
+	if err != nil {
+		check.Status = checkWarn
+		check.Detail = "could not read Notion's clock: " + err.Error()
+		return check
+	}
+
+	skew := input.Now().Sub(server)
+	if skew < 0 {
+		skew = -skew
+	}
+	skew = skew.Round(time.Second)
+	if skew > maxClockSkew {
+		check.Status = checkWarn
+		check.Detail = fmt.Sprintf("off by %s from Notion", skew)
+		check.Fix = "turn on automatic time sync (NTP)"
+		return check
+	}
+	check.Status = checkPass
+	check.Detail = fmt.Sprintf("within %s of Notion", skew)
+	return check
+}
+
+// notionServerTime returns the time in the Date header of a Notion response.
+func notionServerTime(ctx context.Context) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, notionAPIURL, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse Date header: %w", err)
+	}
+	return date, nil
+}
+
+// firstEnv returns the first of the variables that is set.
+func firstEnv(getenv func(string) string, keys ...string) string {
+	for _, key := range keys {
+		if v := getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// printDoctor writes the report and fails when any check failed.
+func printDoctor(w io.Writer, checks []doctorCheck) error {
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+
+	counts := make(map[checkStatus]int)
+	for _, check := range checks {
+		counts[check.Status]++
+		fmt.Fprintf(w, "%s  %-*s  %s\n", check.Status, width, check.Name, check.Detail)
+		if check.Fix != "" {
+			fmt.Fprintf(w, "      %-*s  fix: %s\n", width, "", check.Fix)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[checkPass], counts[checkWarn], counts[checkFail])
+
+	if counts[checkFail] > 0 {
+		return fmt.Errorf("%d checks failed", counts[checkFail])
+	}
+	return nil
+}
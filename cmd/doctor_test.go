@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/config"
+)
+
+// fakeDoctorClient answers doctor's API calls.
+type fakeDoctorClient struct {
+	meErr     error
+	databases map[string]error // by ID; nil means shared
+}
+
+func (f *fakeDoctorClient) Me(ctx context.Context) (*notionapi.User, error) {
+	if f.meErr != nil {
+		return nil, f.meErr
+	}
+	return &notionapi.User{Name: "Notion TUI", Bot: &notionapi.Bot{WorkspaceName: "Acme"}}, nil
+}
+
+func (f *fakeDoctorClient) GetDatabase(ctx context.Context, id string) (*notionapi.Database, error) {
+	if err := f.databases[id]; err != nil {
+		return nil, err
+	}
+	return &notionapi.Database{ID: notionapi.ObjectID(id)}, nil
+}
+
+func newDoctorInput(t *testing.T, client *fakeDoctorClient) doctorInput {
+	t.Helper()
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte("notion_token: secret_x\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(dir, "cache")
+	if err := os.Mkdir(cacheDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	env := map[string]string{"LANG": "en_US.UTF-8"}
+	return doctorInput{
+		ConfigFile: configFile,
+		Config: &config.Config{
+			NotionToken: "secret_x",
+			CacheDir:    cacheDir,
+			Databases: []config.DatabaseConfig{
+				{ID: "db-tasks", Name: "Tasks"},
+				{ID: "db-notes", Name: "Notes"},
+			},
+		},
+		Client:       client,
+		Getenv:       func(key string) string { return env[key] },
+		ColorProfile: "TrueColor",
+		ServerTime:   func(ctx context.Context) (time.Time, error) { return now.Add(-2 * time.Second), nil },
+		Now:          func() time.Time { return now },
+	}
+}
+
+// checkByName returns the check with the given name.
+func checkByName(t *testing.T, checks []doctorCheck, name string) doctorCheck {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, checks)
+	return doctorCheck{}
+}
+
+func TestDoctorHealthy(t *testing.T) {
+	input := newDoctorInput(t, &fakeDoctorClient{})
+	checks := doctor(context.Background(), input)
+
+	var out bytes.Buffer
+	if err := printDoctor(&out, checks); err != nil {
+		t.Fatalf("printDoctor() error = %v\n%s", err, out.String())
+	}
+	for _, check := range checks {
+		if check.Status != checkPass {
+			t.Errorf("%s = %s: %s", check.Name, check.Status, check.Detail)
+		}
+	}
+	if !strings.Contains(out.String(), `integration "Notion TUI" in workspace "Acme"`) {
+		t.Errorf("report = %q", out.String())
+	}
+	if !strings.HasSuffix(out.String(), "11 passed, 0 warnings, 0 failed\n") {
+		t.Errorf("report = %q", out.String())
+	}
+}
+
+func TestDoctorProblems(t *testing.T) {
+	t.Run("database not shared", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{databases: map[string]error{
+			"db-notes": &notionapi.Error{Status: 404, Code: "object_not_found"},
+		}})
+		checks := doctor(context.Background(), input)
+
+		check := checkByName(t, checks, "Database Notes")
+		if check.Status != checkFail || !strings.Contains(check.Fix, "Connections") {
+			t.Errorf("Database Notes = %+v", check)
+		}
+		var out bytes.Buffer
+		if err := printDoctor(&out, checks); err == nil || err.Error() != "1 checks failed" {
+			t.Errorf("printDoctor() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "fix: open the database in Notion") {
+			t.Errorf("report = %q", out.String())
+		}
+	})
+
+	t.Run("invalid token skips databases", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{meErr: &notionapi.Error{Status: 401, Code: "unauthorized"}})
+		checks := doctor(context.Background(), input)
+
+		if check := checkByName(t, checks, "Token"); check.Status != checkFail || check.Detail != "Notion rejected the token" {
+			t.Errorf("Token = %+v", check)
+		}
+		if check := checkByName(t, checks, "Databases"); check.Status != checkWarn {
+			t.Errorf("Databases = %+v", check)
+		}
+	})
+
+	t.Run("token command fails", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{})
+		input.Config.NotionToken = ""
+		input.Client = nil
+		input.TokenErr = errors.New("run notion_token_command: exit status 1: item not found")
+		checks := doctor(context.Background(), input)
+
+		if check := checkByName(t, checks, "Token"); check.Status != checkFail || !strings.Contains(check.Detail, "item not found") {
+			t.Errorf("Token = %+v", check)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{})
+		input.ColorProfile = "Ascii"
+		input.Getenv = func(key string) string {
+			return map[string]string{"LANG": "C", "NO_COLOR": "1"}[key]
+		}
+		input.ServerTime = func(ctx context.Context) (time.Time, error) { return input.Now().Add(10 * time.Minute), nil }
+		checks := doctor(context.Background(), input)
+
+		if check := checkByName(t, checks, "Terminal colors"); check.Status != checkWarn || check.Fix != "unset NO_COLOR" {
+			t.Errorf("Terminal colors = %+v", check)
+		}
+		if check := checkByName(t, checks, "Clock"); check.Status != checkWarn || check.Detail != "off by 10m0s from Notion" {
+			t.Errorf("Clock = %+v", check)
+		}
+		if runtime.GOOS != "windows" {
+			if check := checkByName(t, checks, "Unicode"); check.Status != checkWarn {
+				t.Errorf("Unicode = %+v", check)
+			}
+		}
+	})
+
+	t.Run("conflicting keys", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{})
+		input.Config.Keys.Detail = map[string][]string{"edit": {"q"}}
+		checks := doctor(context.Background(), input)
+
+		check := checkByName(t, checks, "Key bindings")
+		if check.Status != checkFail || !strings.Contains(check.Detail, `"q" is bound to both global.quit and detail.edit`) {
+			t.Errorf("Key bindings = %+v", check)
+		}
+	})
+
+	t.Run("invalid theme color", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{})
+		input.Config.Theme.Colors = map[string]string{"accent": "teal"}
+		checks := doctor(context.Background(), input)
+
+		check := checkByName(t, checks, "Theme")
+		if check.Status != checkFail || !strings.Contains(check.Detail, `colors.accent: invalid color "teal"`) {
+			t.Errorf("Theme = %+v", check)
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		input := newDoctorInput(t, &fakeDoctorClient{})
+		input.Config = nil
+		input.ConfigErr = errors.New("database[0] is missing required field 'id'")
+		input.ServerTime = func(ctx context.Context) (time.Time, error) { return time.Time{}, errors.New("offline") }
+		checks := doctor(context.Background(), input)
+
+		if check := checkByName(t, checks, "Settings"); check.Status != checkFail {
+			t.Errorf("Settings = %+v", check)
+		}
+		if check := checkByName(t, checks, "Clock"); check.Status != checkWarn {
+			t.Errorf("Clock = %+v", check)
+		}
+	})
+}
+
+func TestCheckCacheDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not checked on Windows")
+	}
+
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		cacheDir   string
+		wantStatus checkStatus
+		wantDetail string
+	}{
+		{name: "disabled", cacheDir: "", wantStatus: checkPass, wantDetail: "caching is disabled"},
+		{name: "not created yet", cacheDir: filepath.Join(dir, "a", "b"), wantStatus: checkPass, wantDetail: "(created on first use)"},
+		{name: "readable by others", cacheDir: dir, wantStatus: checkWarn, wantDetail: "can be read by other users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := checkCacheDir(&config.Config{CacheDir: tt.cacheDir})
+			if check.Status != tt.wantStatus || !strings.Contains(check.Detail, tt.wantDetail) {
+				t.Errorf("checkCacheDir() = %+v", check)
+			}
+		})
+	}
+}
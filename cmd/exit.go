@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// Exit statuses of notion-tui. Notion API errors map to their own statuses
+// so scripts can tell them apart.
+const (
+	// ExitError is any failure without a more specific status.
+	ExitError = 1
+	// ExitAuth means the token is invalid or lacks permission.
+	ExitAuth = 2
+	// ExitNotFound means the object does not exist or is not shared with the integration.
+	ExitNotFound = 3
+	// ExitRateLimit means Notion's rate limit was hit and retries ran out.
+	ExitRateLimit = 4
+)
+
+// ExitCode returns the process exit status for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	switch notion.ClassifyError(err) {
+	case notion.ErrorClassAuth:
+		return ExitAuth
+	case notion.ErrorClassNotFound:
+		return ExitNotFound
+	case notion.ErrorClassRateLimit:
+		return ExitRateLimit
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+func TestExportTargetDatabase(t *testing.T) {
+	// Fixed rows, so the second run sees them unchanged
+	mockClient := testhelpers.NewMockNotionClient().WithDatabase(testhelpers.NewTestDatabase("db-1"))
+	dir := t.TempDir()
+
+	var out, progress bytes.Buffer
+	err := exportTarget(context.Background(), &out, &progress, exportInput{
+		Client: mockClient,
+		ID:     "db-1",
+		Dir:    dir,
+	})
+	if err != nil {
+		t.Fatalf("exportTarget() error = %v", err)
+	}
+
+	if got, want := out.String(), "Exported 5 pages to "+dir+" (0 unchanged, 0 files downloaded)\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(progress.String(), "[1/5] first-page-page1.md\n") {
+		t.Errorf("progress = %q", progress.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fifth-page-page5.md")); err != nil {
+		t.Errorf("row was not exported: %v", err)
+	}
+
+	progress.Reset()
+	if err := exportTarget(context.Background(), &out, &progress, exportInput{Client: mockClient, ID: "db-1", Dir: dir}); err != nil {
+		t.Fatalf("second exportTarget() error = %v", err)
+	}
+	if !strings.Contains(progress.String(), "[5/5] fifth-page-page5.md (unchanged)\n") {
+		t.Errorf("second run should skip unchanged pages, progress = %q", progress.String())
+	}
+}
+
+func TestExportTargetPage(t *testing.T) {
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.GetDatabaseFunc = func(ctx context.Context, id string) (*notionapi.Database, error) {
+		return nil, testhelpers.ErrNotFound
+	}
+	mockClient.GetPageFunc = func(ctx context.Context, id string) (*notionapi.Page, error) {
+		return testhelpers.NewTestPage(id, "Handbook"), nil
+	}
+	dir := t.TempDir()
+
+	var out bytes.Buffer
+	err := exportTarget(context.Background(), &out, &bytes.Buffer{}, exportInput{
+		Client: mockClient,
+		ID:     "page-1",
+		Dir:    dir,
+	})
+	if err != nil {
+		t.Fatalf("exportTarget() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "handbook-page1.md"))
+	if err != nil {
+		t.Fatalf("page was not exported: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "---\nid: page-1\ntitle: Handbook\n") {
+		t.Errorf("file = %q, want front matter", data)
+	}
+	if mockClient.QueryDatabaseCallCount() != 0 {
+		t.Error("QueryDatabase should not be called for a page")
+	}
+}
+
+func TestExportTargetInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := exportTarget(ctx, &bytes.Buffer{}, &bytes.Buffer{}, exportInput{
+		Client: testhelpers.NewMockNotionClient(),
+		ID:     "db-1",
+		Dir:    t.TempDir(),
+	})
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "run it again to resume") {
+		t.Errorf("exportTarget() error = %v, want an interruption that can be resumed", err)
+	}
+}
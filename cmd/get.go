@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/export"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// Output formats supported by `get page`.
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+	formatText     = "text"
+	formatHTML     = "html"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print Notion content without starting the TUI",
+	Long: `Print Notion content to stdout without starting the TUI.
+
+Errors exit with a status that scripts can check: 2 for an invalid token
+or missing permission, 3 when the page does not exist or is not shared
+with the integration, 4 when Notion's rate limit was hit, and 1 otherwise.`,
+}
+
+var getPageCmd = &cobra.Command{
+	Use:   "page <id-or-url>",
+	Short: "Print a page as markdown, JSON, text or HTML",
+	Long: `Print a page with all of its nested blocks.
+
+The page is given by ID or by a URL copied from Notion. Fresh page data is
+read from the cache unless --no-cache is given; pages fetched from Notion
+are written back so the TUI opens them instantly.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetPage,
+}
+
+func init() {
+	getPageCmd.Flags().String("format", formatMarkdown, "output format: markdown, json, text or html")
+	getPageCmd.Flags().Bool("with-properties", false, "include page properties (front matter for markdown)")
+	getPageCmd.Flags().Bool("no-cache", false, "always fetch from Notion and leave the cache untouched")
+	getPageCmd.Flags().Int("max-depth", 0, "levels of nested blocks to fetch (0 = all)")
+
+	getCmd.AddCommand(getPageCmd)
+	rootCmd.AddCommand(getCmd)
+}
+
+// pageFetcher is the subset of the Notion client used to read a page.
+type pageFetcher interface {
+	notion.BlockFetcher
+	GetPage(ctx context.Context, id string) (*notionapi.Page, error)
+}
+
+// getPageInput contains parameters for getPage.
+type getPageInput struct {
+	Fetcher        pageFetcher
+	Cache          *cache.PageCache // optional
+	ID             string
+	Format         string
+	WithProperties bool
+	MaxDepth       int
+}
+
+func runGetPage(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if err := validateFormat(format); err != nil {
+		return err
+	}
+	id, err := notion.ParseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	withProperties, _ := cmd.Flags().GetBool("with-properties")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	maxDepth, _ := cmd.Flags().GetInt("max-depth")
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	run := func(ctx context.Context, pc *cache.PageCache) error {
+		return getPage(ctx, cmd.OutOrStdout(), getPageInput{
+			Fetcher:        notion.NewClient(cfg.NotionToken),
+			Cache:          pc,
+			ID:             id,
+			Format:         format,
+			WithProperties: withProperties,
+			MaxDepth:       maxDepth,
+		})
+	}
+
+	if noCache || cfg.CacheDir == "" {
+		return run(ctx, nil)
+	}
+	return withCache(ctx, cfg, true, run)
+}
+
+// validateFormat rejects unknown --format values before any request is made.
+func validateFormat(format string) error {
+	switch format {
+	case formatMarkdown, formatJSON, formatText, formatHTML:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (want markdown, json, text or html)", format)
+	}
+}
+
+// getPageOutput is the JSON output of `get page`.
+type getPageOutput struct {
+	ID             string             `json:"id"`
+	Title          string             `json:"title"`
+	URL            string             `json:"url"`
+	CreatedTime    time.Time          `json:"created_time"`
+	LastEditedTime time.Time          `json:"last_edited_time"`
+	Properties     map[string]string  `json:"properties,omitempty"`
+	Markdown       string             `json:"markdown"`
+	Blocks         []notion.BlockNode `json:"blocks"`
+}
+
+// getPage implements `get page`.
+func getPage(ctx context.Context, w io.Writer, input getPageInput) error {
+	if err := validateFormat(input.Format); err != nil {
+		return err
+	}
+
+	page, err := loadPageMeta(ctx, input)
+	if err != nil {
+		return err
+	}
+	blocks, err := loadPageBlocks(ctx, input)
+	if err != nil {
+		return err
+	}
+	nodes, err := notion.ExpandBlockTree(ctx, input.Fetcher, blocks, input.MaxDepth)
+	if err != nil {
+		return fmt.Errorf("fetch blocks: %w", err)
+	}
+
+	markdown, err := notion.ConvertBlockTreeToMarkdown(nodes)
+	if err != nil {
+		return fmt.Errorf("render page: %w", err)
+	}
+
+	var properties map[string]string
+	if input.WithProperties {
+		properties = export.PageFrontMatter(page).Properties
+	}
+
+	switch input.Format {
+	case formatJSON:
+		if nodes == nil {
+			nodes = []notion.BlockNode{}
+		}
+		return writeJSON(w, getPageOutput{
+			ID:             string(page.ID),
+			Title:          notion.PageTitle(page),
+			URL:            page.URL,
+			CreatedTime:    page.CreatedTime,
+			LastEditedTime: page.LastEditedTime,
+			Properties:     properties,
+			Markdown:       markdown,
+			Blocks:         nodes,
+		})
+
+	case formatText:
+		if input.WithProperties {
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			for _, name := range notion.PropertyNames(page.Properties) {
+				fmt.Fprintf(tw, "%s:\t%s\n", name, properties[name])
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+		}
+		_, err := fmt.Fprintln(w, notion.ConvertBlockTreeToText(nodes))
+		return err
+
+	case formatHTML:
+		var buf bytes.Buffer
+		if input.WithProperties {
+			buf.WriteString("<dl>\n")
+			for _, name := range notion.PropertyNames(page.Properties) {
+				fmt.Fprintf(&buf, "<dt>%s</dt><dd>%s</dd>\n", html.EscapeString(name), html.EscapeString(properties[name]))
+			}
+			buf.WriteString("</dl>\n")
+		}
+		md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+		if err := md.Convert([]byte(markdown), &buf); err != nil {
+			return fmt.Errorf("render html: %w", err)
+		}
+		_, err := w.Write(buf.Bytes())
+		return err
+
+	default:
+		if input.WithProperties {
+			header, err := export.PageFrontMatter(page).Render()
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(header); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintln(w, markdown)
+		return err
+	}
+}
+
+// loadPageMeta returns the page metadata, from the cache when fresh.
+func loadPageMeta(ctx context.Context, input getPageInput) (*notionapi.Page, error) {
+	key := cache.MetaKey(notion.FormatID(input.ID))
+	if input.Cache != nil {
+		if data, ok := cachedJSON(ctx, input.Cache, key); ok {
+			if page, err := notion.DecodePage(data); err == nil {
+				return page, nil
+			}
+		}
+	}
+
+	page, err := input.Fetcher.GetPage(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Cache != nil {
+		// Errors are ignored: caching is an optimization only
+		_ = input.Cache.Set(ctx, cache.SetInput{PageID: key, Data: page, TTL: cache.DefaultPageTTL})
+	}
+	return page, nil
+}
+
+// loadPageBlocks returns the top-level blocks of the page, from the cache
+// when a fresh and complete copy is available.
+func loadPageBlocks(ctx context.Context, input getPageInput) ([]notionapi.Block, error) {
+	key := notion.FormatID(input.ID)
+	if input.Cache != nil {
+		if data, ok := cachedJSON(ctx, input.Cache, key); ok {
+			var response notionapi.GetChildrenResponse
+			if err := json.Unmarshal(data, &response); err == nil && !response.HasMore {
+				return response.Results, nil
+			}
+		}
+	}
+
+	blocks, err := notion.FetchBlocks(ctx, input.Fetcher, input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch blocks: %w", err)
+	}
+	if input.Cache != nil {
+		_ = input.Cache.Set(ctx, cache.SetInput{
+			PageID: key,
+			Data:   &notionapi.GetChildrenResponse{Object: notionapi.ObjectTypeList, Results: blocks},
+			TTL:    cache.DefaultPageTTL,
+		})
+	}
+	return blocks, nil
+}
+
+// cachedJSON returns a fresh cache entry re-encoded as JSON.
+func cachedJSON(ctx context.Context, pc *cache.PageCache, key string) ([]byte, bool) {
+	cached, err := pc.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// newGetPageClient returns a mock client serving a page whose bulleted list
+// item has a nested child.
+func newGetPageClient() *testhelpers.MockNotionClient {
+	parent := testhelpers.NewBulletedListItemBlock("Parent item")
+	parent.HasChildren = true
+
+	page := testhelpers.NewTestPage("page-9", "Launch plan")
+	page.Properties["Status"] = &notionapi.StatusProperty{
+		Type:   notionapi.PropertyTypeStatus,
+		Status: notionapi.Option{Name: "In progress"},
+	}
+
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.PageToReturn = page
+	mockClient.GetBlocksFunc = func(ctx context.Context, id string,
+		pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
+		if id == string(parent.ID) {
+			return testhelpers.NewGetChildrenResponse([]notionapi.Block{
+				testhelpers.NewBulletedListItemBlock("Nested item"),
+			}), nil
+		}
+		return testhelpers.NewGetChildrenResponse([]notionapi.Block{
+			testhelpers.NewHeading1Block("Goals"),
+			parent,
+		}), nil
+	}
+	return mockClient
+}
+
+func TestGetPageFormats(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		withProperties bool
+		want           []string
+	}{
+		{
+			name:   "markdown",
+			format: formatMarkdown,
+			want:   []string{"# Goals", "- Parent item\n  - Nested item"},
+		},
+		{
+			name:           "markdown with front matter",
+			format:         formatMarkdown,
+			withProperties: true,
+			want:           []string{"---\nid: page-9\ntitle: Launch plan\n", "Status: In progress", "---\n\n# Goals"},
+		},
+		{
+			name:   "text",
+			format: formatText,
+			want:   []string{"Goals\n\n- Parent item\n  - Nested item"},
+		},
+		{
+			name:           "text with properties",
+			format:         formatText,
+			withProperties: true,
+			want:           []string{"title:   Launch plan", "Status:  In progress"},
+		},
+		{
+			name:   "html",
+			format: formatHTML,
+			want:   []string{"<h1>Goals</h1>", "<li>Parent item\n<ul>\n<li>Nested item</li>"},
+		},
+		{
+			name:           "html with properties",
+			format:         formatHTML,
+			withProperties: true,
+			want:           []string{"<dt>Status</dt><dd>In progress</dd>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := getPage(context.Background(), &out, getPageInput{
+				Fetcher:        newGetPageClient(),
+				ID:             "page-9",
+				Format:         tt.format,
+				WithProperties: tt.withProperties,
+			})
+			if err != nil {
+				t.Fatalf("getPage() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("getPage() output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGetPageJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := getPage(context.Background(), &out, getPageInput{
+		Fetcher:        newGetPageClient(),
+		ID:             "page-9",
+		Format:         formatJSON,
+		WithProperties: true,
+	})
+	if err != nil {
+		t.Fatalf("getPage() error = %v", err)
+	}
+
+	var got struct {
+		ID         string            `json:"id"`
+		Title      string            `json:"title"`
+		Properties map[string]string `json:"properties"`
+		Markdown   string            `json:"markdown"`
+		Blocks     []struct {
+			Children []json.RawMessage `json:"children"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if got.ID != "page-9" || got.Title != "Launch plan" || got.Properties["Status"] != "In progress" {
+		t.Errorf("getPage() JSON = %+v", got)
+	}
+	if len(got.Blocks) != 2 || len(got.Blocks[1].Children) != 1 {
+		t.Errorf("getPage() JSON blocks = %+v, want the nested child", got.Blocks)
+	}
+	if !strings.Contains(got.Markdown, "Nested item") {
+		t.Errorf("getPage() JSON markdown = %q", got.Markdown)
+	}
+}
+
+func TestGetPageMaxDepth(t *testing.T) {
+	var out bytes.Buffer
+	err := getPage(context.Background(), &out, getPageInput{
+		Fetcher:  newGetPageClient(),
+		ID:       "page-9",
+		Format:   formatMarkdown,
+		MaxDepth: 1,
+	})
+	if err != nil {
+		t.Fatalf("getPage() error = %v", err)
+	}
+	if strings.Contains(out.String(), "Nested item") {
+		t.Errorf("getPage() with max depth 1 fetched nested blocks:\n%s", out.String())
+	}
+}
+
+func TestGetPageCache(t *testing.T) {
+	pc := newTestCache(t)
+	ctx := context.Background()
+
+	// A fully cached page is served without touching Notion
+	offline := testhelpers.NewMockNotionClient()
+	offline.ErrorToReturn = errors.New("offline")
+
+	var out bytes.Buffer
+	err := getPage(ctx, &out, getPageInput{Fetcher: offline, Cache: pc, ID: "page-1", Format: formatMarkdown})
+	if err != nil {
+		t.Fatalf("getPage() from cache error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Ship the cache CLI") {
+		t.Errorf("getPage() from cache output = %q", out.String())
+	}
+
+	// A fetched page is written back to the cache
+	err = getPage(ctx, &bytes.Buffer{}, getPageInput{Fetcher: newGetPageClient(), Cache: pc, ID: "page-9", Format: formatMarkdown})
+	if err != nil {
+		t.Fatalf("getPage() error = %v", err)
+	}
+	if !pc.Has(ctx, "page-9") || !pc.Has(ctx, cache.MetaKey("page-9")) {
+		t.Error("getPage() did not cache the fetched page")
+	}
+}
+
+func TestGetPageErrors(t *testing.T) {
+	err := getPage(context.Background(), &bytes.Buffer{}, getPageInput{
+		Fetcher: newGetPageClient(),
+		ID:      "page-9",
+		Format:  "pdf",
+	})
+	if err == nil {
+		t.Error("getPage() accepted an unknown format")
+	}
+
+	notFound := testhelpers.NewMockNotionClient()
+	notFound.ErrorToReturn = &notionapi.Error{Status: 404, Message: "Could not find page"}
+	err = getPage(context.Background(), &bytes.Buffer{}, getPageInput{Fetcher: notFound, ID: "page-9", Format: formatText})
+	if ExitCode(err) != ExitNotFound {
+		t.Errorf("ExitCode(%v) = %d, want %d", err, ExitCode(err), ExitNotFound)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: 0},
+		{name: "generic", err: errors.New("boom"), want: ExitError},
+		{name: "auth", err: &notionapi.Error{Status: 401}, want: ExitAuth},
+		{name: "not found", err: &notionapi.Error{Status: 404}, want: ExitNotFound},
+		{name: "rate limit", err: &notionapi.RateLimitedError{}, want: ExitRateLimit},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
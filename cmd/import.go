@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/importer"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <dir> --into <database-or-page>",
+	Short: "Import a directory of Markdown files into a database or page",
+	Long: `Create a Notion page for every Markdown file in a directory.
+
+Imported into a database, top-level files become rows and their YAML front
+matter is mapped to properties by name using the database schema; keys that
+match no property are reported and skipped. Imported into a page, files
+become its child pages. The title comes from a "title" key, a leading
+"# " heading or the file name.
+
+Sub-directories become child pages, so the folder hierarchy is kept: a
+directory next to a file of the same name, such as guide/ beside guide.md,
+holds that file's children. Images with absolute URLs are linked. Local
+images can only be shown when the directory is served somewhere; pass that
+URL as --asset-base-url.
+
+--dry-run prints the pages that would be created without creating any.
+A manifest in the directory records the pages created, so running the
+import again skips files that were already imported.`,
+	Example: `  notion-tui import ./wiki --into Wiki --dry-run
+  notion-tui import ./wiki --into https://www.notion.so/acme/Handbook-0123456789abcdef0123456789abcdef
+  notion-tui import ./wiki --into Wiki --asset-base-url https://example.com/wiki`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().String("into", "", "database name, ID or URL, or page ID or URL to import into (required)")
+	importCmd.Flags().Bool("dry-run", false, "print the planned pages without creating them")
+	importCmd.Flags().String("asset-base-url", "", "URL the directory is served at, used to link local images")
+	_ = importCmd.MarkFlagRequired("into")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+// importInput contains parameters for importDir.
+type importInput struct {
+	Client       importer.Client
+	Dir          string
+	TargetID     string
+	AssetBaseURL string
+	DryRun       bool
+	Now          time.Time
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	into, _ := cmd.Flags().GetString("into")
+	targetID, err := notion.ParseID(resolveDatabase(cfg, into))
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	assetBaseURL, _ := cmd.Flags().GetString("asset-base-url")
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	return importDir(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), importInput{
+		Client:       notion.NewClient(cfg.NotionToken),
+		Dir:          args[0],
+		TargetID:     targetID,
+		AssetBaseURL: assetBaseURL,
+		DryRun:       dryRun,
+		Now:          time.Now(),
+	})
+}
+
+// importDir implements `import`. The plan or summary goes to w; progress and
+// warnings go to errOut.
+func importDir(ctx context.Context, w, errOut io.Writer, input importInput) error {
+	imp, err := importer.NewImporter(importer.NewImporterInput{
+		Client:       input.Client,
+		Dir:          input.Dir,
+		AssetBaseURL: input.AssetBaseURL,
+		Now:          input.Now,
+		Progress: func(p importer.Progress) {
+			status := "created"
+			if p.Skipped {
+				status = "skipped (already imported)"
+			}
+			fmt.Fprintf(errOut, "[%d/%d] %s %s\n", p.Done, p.Total, status, p.Item.Path)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	plan, err := imp.Plan(ctx, input.TargetID)
+	if err != nil {
+		return err
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(errOut, "warning: %s\n", warning)
+	}
+
+	if input.DryRun {
+		return writeImportPlan(w, plan)
+	}
+
+	result, err := imp.Apply(ctx, plan)
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("import interrupted after %d pages; run it again to resume: %w", result.Created, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "Imported %d pages into %q (%d already imported)\n",
+		result.Created, plan.Target.Title, result.Skipped)
+	return err
+}
+
+// writeImportPlan prints the pages an import would create.
+func writeImportPlan(w io.Writer, plan *importer.Plan) error {
+	kind := "page"
+	if plan.Target.Database {
+		kind = "database"
+	}
+	pending := plan.Pending()
+	fmt.Fprintf(w, "Import into %s %q: %d pages to create, %d already imported\n\n",
+		kind, plan.Target.Title, pending, len(plan.Items)-pending)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, item := range plan.Items {
+		if item.Done {
+			fmt.Fprintf(tw, "skip\t%s\t%q\t%s\n", item.Path, item.Title, item.URL)
+			continue
+		}
+
+		detail := fmt.Sprintf("%d blocks", len(item.Blocks))
+		if len(item.Properties) > 0 {
+			detail = fmt.Sprintf("%d properties, %s", len(item.Properties), detail)
+		}
+		if item.Parent != nil {
+			detail = fmt.Sprintf("under %q, %s", item.Parent.Title, detail)
+		}
+		fmt.Fprintf(tw, "create\t%s\t%q\t%s\n", item.Path, item.Title, detail)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// writeImportDir writes a directory with a row that has a child page.
+func writeImportDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"renew-passport.md":       "---\nStatus: Todo\nOwner: sam\n---\n\nBring the old one.\n",
+		"renew-passport/forms.md": "# Forms\n\n- Photo\n- Application\n",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestImportDirDryRun(t *testing.T) {
+	mockClient := newCaptureClient()
+
+	var out, errOut bytes.Buffer
+	err := importDir(context.Background(), &out, &errOut, importInput{
+		Client:   mockClient,
+		Dir:      writeImportDir(t),
+		TargetID: "db-inbox",
+		DryRun:   true,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("importDir() error = %v", err)
+	}
+
+	want := `Import into database "Test Database": 2 pages to create, 0 already imported
+
+create  renew-passport.md        "renew-passport"  1 properties, 1 blocks
+create  renew-passport/forms.md  "Forms"           under "renew-passport", 2 blocks
+`
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+	if !strings.Contains(errOut.String(), `warning: front matter key "Owner" does not match a property`) {
+		t.Errorf("warnings = %q", errOut.String())
+	}
+	if mockClient.CreatePageCallCount() != 0 {
+		t.Error("a dry run should not create pages")
+	}
+}
+
+func TestImportDir(t *testing.T) {
+	mockClient := newCaptureClient()
+	dir := writeImportDir(t)
+
+	var out, errOut bytes.Buffer
+	input := importInput{Client: mockClient, Dir: dir, TargetID: "db-inbox", Now: time.Now()}
+	if err := importDir(context.Background(), &out, &errOut, input); err != nil {
+		t.Fatalf("importDir() error = %v", err)
+	}
+
+	if got, want := out.String(), "Imported 2 pages into \"Test Database\" (0 already imported)\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if !strings.Contains(errOut.String(), "[2/2] created renew-passport/forms.md\n") {
+		t.Errorf("progress = %q", errOut.String())
+	}
+	calls := mockClient.CreatePageCalls
+	if len(calls) != 2 || calls[1].Request.Parent.Type != notionapi.ParentTypePageID {
+		t.Fatalf("CreatePage calls = %+v, want a row and a child page", calls)
+	}
+
+	// Running again creates nothing
+	out.Reset()
+	if err := importDir(context.Background(), &out, &bytes.Buffer{}, input); err != nil {
+		t.Fatalf("second importDir() error = %v", err)
+	}
+	if got, want := out.String(), "Imported 0 pages into \"Test Database\" (2 already imported)\n"; got != want {
+		t.Errorf("second output = %q, want %q", got, want)
+	}
+	if mockClient.CreatePageCallCount() != 2 {
+		t.Errorf("CreatePage called %d times, want 2", mockClient.CreatePageCallCount())
+	}
+}
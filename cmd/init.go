@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/setup"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file with an interactive setup",
+	Long: `Walk through creating a config file:
+
+  1. paste an integration token, which is checked with Notion
+  2. pick the databases shared with the integration, renaming them if you like
+  3. the config is written to ~/.config/notion-tui/config.yaml (or --config)
+
+The file is created readable only by you, since it holds the token. An
+existing file is left alone unless --force is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		return runInit(cmd.OutOrStdout(), configFilePath(), force, runSetup)
+	},
+}
+
+func init() {
+	initCmd.Flags().Bool("force", false, "replace an existing config file")
+	rootCmd.AddCommand(initCmd)
+}
+
+// setupFunc runs the setup wizard, writing path, and reports whether the
+// config was written.
+type setupFunc func(path string, overwrite bool) (bool, error)
+
+// runInit runs the setup wizard unless path already exists.
+func runInit(out io.Writer, path string, force bool, runSetup setupFunc) error {
+	expanded, err := config.ExpandHome(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(expanded); err == nil && !force {
+		return fmt.Errorf("config file %s already exists; run notion-tui init --force to replace it", expanded)
+	}
+
+	written, err := runSetup(expanded, force)
+	if err != nil {
+		return err
+	}
+	if !written {
+		fmt.Fprintln(out, "Setup cancelled; nothing was written.")
+		return nil
+	}
+	fmt.Fprintf(out, "Config written to %s\n", expanded)
+	return nil
+}
+
+// configFilePath is the config file read at startup: --config, the file
+// found in the search path, or the default location.
+func configFilePath() string {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+	if path := viper.GetString("config"); path != "" {
+		return path
+	}
+	return config.DefaultConfigFile
+}
+
+// runSetup runs the setup wizard in the terminal. The config is not loaded
+// yet, so it uses the default theme and key bindings.
+func runSetup(path string, overwrite bool) (bool, error) {
+	t, err := theme.Load(theme.LoadInput{NoColor: os.Getenv("NO_COLOR") != ""})
+	if err != nil {
+		return false, err
+	}
+
+	model := setup.NewModel(setup.NewModelInput{
+		ConfigFile: path,
+		Overwrite:  overwrite,
+		NewClient:  func(token string) setup.Client { return notion.NewClient(token) },
+		Theme:      &t,
+	})
+	final, err := tea.NewProgram(model).Run()
+	if err != nil {
+		return false, fmt.Errorf("run setup: %w", err)
+	}
+	m, ok := final.(setup.Model)
+	return ok && m.Completed(), nil
+}
+
+// firstRun offers the setup wizard when the TUI is started without a token
+// or a config file, and reports whether it wrote the config. Without a
+// terminal to ask in, or with a config file that lacks only the token, the
+// original error is returned with a hint.
+func firstRun(loadErr error) (bool, error) {
+	if !errors.Is(loadErr, config.ErrTokenRequired) {
+		return false, loadErr
+	}
+
+	path, err := config.ExpandHome(configFilePath())
+	if err != nil {
+		return false, loadErr
+	}
+	if _, err := os.Stat(path); err == nil {
+		return false, fmt.Errorf("%w; add one to %s or run notion-tui init --force", loadErr, path)
+	}
+	if !isTerminal(os.Stdin) {
+		return false, fmt.Errorf("%w; run notion-tui init to create a config file", loadErr)
+	}
+
+	written, err := runSetup(path, false)
+	if err != nil || !written {
+		return false, err
+	}
+
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return false, fmt.Errorf("read config file: %w", err)
+	}
+	return true, nil
+}
+
+// isTerminal reports whether f is an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
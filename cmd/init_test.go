@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Panandika/notion-tui/internal/config"
+)
+
+// TestRunInit verifies init refuses to replace a config file without
+// --force and reports the wizard's outcome.
+func TestRunInit(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.yaml")
+	if err := os.WriteFile(existing, []byte("notion_token: secret_x\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "new.yaml")
+
+	tests := []struct {
+		name          string
+		path          string
+		force         bool
+		written       bool
+		wantErr       string
+		wantOut       string
+		wantOverwrite bool
+		wantRun       bool
+	}{
+		{name: "new file", path: missing, written: true, wantOut: "Config written to " + missing, wantRun: true},
+		{name: "cancelled", path: missing, wantOut: "nothing was written", wantRun: true},
+		{name: "existing file", path: existing, wantErr: "already exists"},
+		{name: "existing file with force", path: existing, force: true, written: true, wantOut: "Config written", wantOverwrite: true, wantRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			overwrite := false
+			fake := func(path string, o bool) (bool, error) {
+				ran = true
+				overwrite = o
+				return tt.written, nil
+			}
+
+			var out bytes.Buffer
+			err := runInit(&out, tt.path, tt.force, fake)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("runInit() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("runInit() error = %v", err)
+			}
+			if ran != tt.wantRun {
+				t.Errorf("setup ran = %v, want %v", ran, tt.wantRun)
+			}
+			if overwrite != tt.wantOverwrite {
+				t.Errorf("overwrite = %v, want %v", overwrite, tt.wantOverwrite)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+// TestFirstRunPassesOtherErrors verifies only a missing token starts setup.
+func TestFirstRunPassesOtherErrors(t *testing.T) {
+	loadErr := errors.New("invalid theme")
+	written, err := firstRun(loadErr)
+	if written || !errors.Is(err, loadErr) {
+		t.Errorf("firstRun() = %v, %v; want false, %v", written, err, loadErr)
+	}
+}
+
+// TestFirstRunExistingConfig verifies a config file without a token is not
+// replaced by setup.
+func TestFirstRunExistingConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("databases: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NOTION_TUI_CONFIG", path)
+
+	written, err := firstRun(config.ErrTokenRequired)
+	if written {
+		t.Error("firstRun() wrote a config")
+	}
+	if !errors.Is(err, config.ErrTokenRequired) || !strings.Contains(err.Error(), "init --force") {
+		t.Errorf("firstRun() error = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/logging"
+	"github.com/Panandika/notion-tui/internal/version"
+)
+
+// logFile is the open debug log, closed by Execute; nil when nothing is logged.
+var logFile io.Closer
+
+// logSecrets are the values the debug log redacts. A token read from
+// notion_token_file or notion_token_command and the cache key are only known
+// once the command loads them, so they are added as they are resolved, as are
+// those of profiles switched to in the TUI.
+var logSecrets = logging.NewSecrets()
+
+// setupLogging makes the debug log the default slog logger, which the Notion
+// client, the cache and the pages write to. Without debug or log.level set,
+// records are dropped rather than written to the terminal. Config errors are
+// left for the command to report.
+func setupLogging() {
+	cfg, err := config.LoadLocal()
+	if err != nil {
+		slog.SetDefault(logging.Discard())
+		return
+	}
+
+	logger, closer, err := openLog(cfg, logSecrets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: debug log disabled: %v\n", err)
+		logger = logging.Discard()
+	}
+	slog.SetDefault(logger)
+	logFile = closer
+
+	logger.Info("notion-tui started", "version", version.Short(), "profile", cfg.Profile)
+}
+
+// openLog opens the debug log cfg asks for, redacting secrets and those set in
+// cfg. It returns a logger that drops every record and a nil Closer when
+// logging is off.
+func openLog(cfg *config.Config, secrets *logging.Secrets) (*slog.Logger, io.Closer, error) {
+	if !cfg.LoggingEnabled() {
+		return logging.Discard(), nil, nil
+	}
+	secrets.Add(cfg.NotionToken, cfg.CacheEncryption.Passphrase)
+
+	logger, closer, err := logging.Open(logging.OpenInput{
+		File:          cfg.Log.File,
+		Level:         cfg.LogLevel(),
+		MaxSize:       int64(cfg.Log.MaxSizeMB) << 20,
+		MaxFiles:      cfg.Log.MaxFiles,
+		Secrets:       secrets,
+		RedactContent: cfg.Log.RedactContent,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("open debug log: %w", err)
+	}
+	return logger, closer, nil
+}
+
+// loadConfig loads the config like config.Load and keeps the token it
+// resolved out of the debug log.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	redactConfig(cfg)
+	return cfg, nil
+}
+
+// redactConfig keeps the token and cache passphrase of cfg out of the debug log.
+func redactConfig(cfg *config.Config) {
+	logSecrets.Add(cfg.NotionToken, cfg.CacheEncryption.Passphrase)
+}
+
+// redactKey keeps the material of a cache key out of the debug log. A nil key
+// is ignored.
+func redactKey(key *cache.Key) {
+	if key != nil {
+		logSecrets.Add(key.Secret())
+	}
+}
+
+// closeLogging closes the debug log, if one is open.
+func closeLogging() {
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/logging"
+)
+
+// TestOpenLog verifies the debug log is only written when asked for, and
+// never contains the token.
+func TestOpenLog(t *testing.T) {
+	t.Run("off", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notion-tui.log")
+		logger, closer, err := openLog(&config.Config{Log: config.LogConfig{File: path}}, logging.NewSecrets())
+		if err != nil {
+			t.Fatalf("openLog() error = %v", err)
+		}
+		if closer != nil {
+			t.Error("openLog() returned a file while logging is off")
+		}
+		logger.Error("dropped")
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("log file was created while logging is off: %v", err)
+		}
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state", "notion-tui.log")
+		cfg := &config.Config{
+			NotionToken: "secret_abc",
+			Debug:       true,
+			Log:         config.LogConfig{File: path},
+		}
+		logger, closer, err := openLog(cfg, logging.NewSecrets())
+		if err != nil {
+			t.Fatalf("openLog() error = %v", err)
+		}
+		logger.Debug("request", "header", "Bearer secret_abc")
+		if err := closer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `header="Bearer ***"`) {
+			t.Errorf("log = %q, want the token redacted", data)
+		}
+		if strings.Contains(string(data), "secret_abc") {
+			t.Error("log contains the token")
+		}
+	})
+
+	t.Run("bad level", func(t *testing.T) {
+		cfg := &config.Config{Log: config.LogConfig{File: filepath.Join(t.TempDir(), "x.log"), Level: "loud"}}
+		if _, _, err := openLog(cfg, logging.NewSecrets()); err == nil {
+			t.Error("openLog() should reject an unknown level")
+		}
+	})
+}
+
+// TestLogRedactsResolvedSecrets verifies secrets only known once resolved,
+// such as the material of a cache key file, are kept out of the debug log.
+func TestLogRedactsResolvedSecrets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notion-tui.log")
+	logger, closer, err := openLog(&config.Config{Debug: true, Log: config.LogConfig{File: path}}, logSecrets)
+	if err != nil {
+		t.Fatalf("openLog() error = %v", err)
+	}
+
+	keyFile := filepath.Join(dir, "cache.key")
+	if err := os.WriteFile(keyFile, []byte("key-file-material\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cacheKey(t.Context(), config.CacheEncryptionConfig{KeyFile: keyFile}); err != nil {
+		t.Fatalf("cacheKey() error = %v", err)
+	}
+	redactConfig(&config.Config{NotionToken: "token-from-command"})
+
+	logger.Debug("request", "header", "Bearer token-from-command", "key", "key-file-material")
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"token-from-command", "key-file-material"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("log = %q, want %q redacted", data, secret)
+		}
+	}
+}
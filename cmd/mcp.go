@@ -0,0 +1,535 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/mcp"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/version"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the workspace to local assistants over MCP",
+	Long: `Run a Model Context Protocol server on stdin and stdout, so coding
+assistants and agents on this machine can use the configured integration.
+
+Assistants can search the workspace, read pages as Markdown and query
+databases. Tools that change the workspace (append_blocks,
+update_properties and create_page) are only offered when listed under
+mcp.allow_write in the config file.
+
+Requests share the client's rate limit and the page cache with the rest
+of notion-tui. Register the server with an assistant by giving it the
+command "notion-tui mcp".`,
+	Example: `  # config.yaml
+  mcp:
+    allow_write: [append_blocks, create_page]`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+// Names of the tools that change the workspace.
+const (
+	toolAppendBlocks     = "append_blocks"
+	toolUpdateProperties = "update_properties"
+	toolCreatePage       = "create_page"
+)
+
+// writeTools are the tools mcp.allow_write may name.
+var writeTools = []string{toolAppendBlocks, toolUpdateProperties, toolCreatePage}
+
+// mcpClient is the subset of the Notion client used by the MCP tools.
+type mcpClient interface {
+	pageFetcher
+	captureClient
+	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
+	UpdatePage(ctx context.Context, id string, req *notionapi.PageUpdateRequest) (*notionapi.Page, error)
+	Search(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error)
+}
+
+// mcpInput contains parameters for mcpTools.
+type mcpInput struct {
+	Client     mcpClient
+	Cache      *cache.PageCache // optional
+	Config     *config.Config   // resolves database names
+	AllowWrite []string
+	Now        func() time.Time
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	input := mcpInput{
+		Client:     notion.NewClient(cfg.NotionToken),
+		Config:     cfg,
+		AllowWrite: cfg.MCP.AllowWrite,
+		Now:        time.Now,
+	}
+
+	var ix *index.Index
+	pc, err := openCache(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if pc != nil {
+		defer pc.Close()
+		if ix, err = index.Open(ctx, index.OpenInput{Cache: pc}); err != nil {
+			return fmt.Errorf("open search index: %w", err)
+		}
+		input.Cache = pc
+	}
+
+	tools, err := mcpTools(input)
+	if err != nil {
+		return err
+	}
+	server, err := mcp.NewServer(mcp.NewServerInput{
+		Name:    "notion-tui",
+		Version: version.Short(),
+		Tools:   tools,
+	})
+	if err != nil {
+		return err
+	}
+
+	// stdout carries the protocol, so nothing else may be printed there
+	err = server.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+	if ix != nil {
+		if saveErr := ix.Save(); saveErr != nil && err == nil {
+			err = fmt.Errorf("save search index: %w", saveErr)
+		}
+	}
+	return err
+}
+
+// mcpTools returns the read tools and the write tools allowed by input.
+func mcpTools(input mcpInput) ([]mcp.Tool, error) {
+	for _, name := range input.AllowWrite {
+		if !slices.Contains(writeTools, name) {
+			return nil, fmt.Errorf("mcp.allow_write: unknown tool %q (want %s)", name, strings.Join(writeTools, ", "))
+		}
+	}
+
+	tools := []mcp.Tool{
+		{
+			Name:        "search",
+			Description: "Search the pages and databases shared with the integration by title. Returns JSON with the ID, type, title and URL of each match.",
+			InputSchema: objectSchema(nil, map[string]any{
+				"query": stringSchema("Text to look for in titles; empty lists recently edited items"),
+				"type":  map[string]any{"type": "string", "enum": []string{"page", "database"}, "description": "Only return pages or only databases"},
+				"limit": intSchema("Maximum number of results (default 20, at most 100)"),
+			}),
+			ReadOnly: true,
+			Handler:  input.search,
+		},
+		{
+			Name:        "get_page",
+			Description: "Get a page with all nested blocks as Markdown.",
+			InputSchema: objectSchema([]string{"page"}, map[string]any{
+				"page":            stringSchema("Page ID or Notion URL"),
+				"with_properties": map[string]any{"type": "boolean", "description": "Start with YAML front matter holding the page's properties"},
+			}),
+			ReadOnly: true,
+			Handler:  input.getPage,
+		},
+		{
+			Name: "query_database",
+			Description: `Query the rows of a database. Returns JSON with the ID, URL and properties of each row. ` +
+				`Filters are expressions such as: Status = "Done" and Due < today; Tags contains urgent or Priority >= 2; Assignee is empty.`,
+			InputSchema: objectSchema([]string{"database"}, map[string]any{
+				"database": stringSchema("Database name from the config file, ID or Notion URL"),
+				"where":    stringSchema("Filter expression"),
+				"sort":     stringListSchema(`Properties to sort by, with a "-" prefix for descending`),
+				"columns":  stringListSchema("Properties to return (default all)"),
+				"limit":    intSchema("Maximum number of rows (default 50)"),
+			}),
+			ReadOnly: true,
+			Handler:  input.queryDatabase,
+		},
+	}
+
+	if slices.Contains(input.AllowWrite, toolAppendBlocks) {
+		tools = append(tools, mcp.Tool{
+			Name:        toolAppendBlocks,
+			Description: "Add Markdown to the end of a page. Returns JSON with the ID and URL of the first new block.",
+			InputSchema: objectSchema([]string{"page", "markdown"}, map[string]any{
+				"page":     stringSchema("Page ID or Notion URL"),
+				"markdown": stringSchema("Content to add"),
+			}),
+			Handler: input.appendBlocks,
+		})
+	}
+	if slices.Contains(input.AllowWrite, toolUpdateProperties) {
+		tools = append(tools, mcp.Tool{
+			Name: toolUpdateProperties,
+			Description: "Set properties of a page. Values are text as shown by query_database: " +
+				"dates such as 2024-05-01 or tomorrow, comma-separated multi-select options, true or false for checkboxes.",
+			InputSchema: objectSchema([]string{"page", "properties"}, map[string]any{
+				"page":       stringSchema("Page ID or Notion URL"),
+				"properties": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}, "description": "Property names and new values"},
+			}),
+			Handler: input.updateProperties,
+		})
+	}
+	if slices.Contains(input.AllowWrite, toolCreatePage) {
+		tools = append(tools, mcp.Tool{
+			Name:        toolCreatePage,
+			Description: "Create a page as a row of a database or below another page. Returns JSON with the ID and URL of the page.",
+			InputSchema: objectSchema([]string{"title"}, map[string]any{
+				"database":   stringSchema("Database to add a row to: name from the config file, ID or Notion URL"),
+				"parent":     stringSchema("Page to create the page below, when not adding to a database: ID or Notion URL"),
+				"title":      stringSchema("Title of the page"),
+				"markdown":   stringSchema("Content of the page"),
+				"properties": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}, "description": "Property values of the row"},
+			}),
+			Handler: input.createPage,
+		})
+	}
+	return tools, nil
+}
+
+// searchOutput is a result of the search tool.
+type searchOutput struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	URL            string    `json:"url"`
+	LastEditedTime time.Time `json:"last_edited_time"`
+}
+
+func (input mcpInput) search(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Query string `json:"query"`
+		Type  string `json:"type"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	if args.Type != "" && args.Type != "page" && args.Type != "database" {
+		return "", fmt.Errorf("type must be page or database, not %q", args.Type)
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	limit = min(limit, 100)
+
+	resp, err := input.Client.Search(ctx, notion.SearchInput{Query: args.Query, Filter: args.Type, PageSize: limit})
+	if err != nil {
+		return "", err
+	}
+	results := make([]searchOutput, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, searchOutput{
+			ID:             r.ID,
+			Type:           r.ObjectType,
+			Title:          r.Title,
+			URL:            "https://www.notion.so/" + notion.NormalizeID(r.ID),
+			LastEditedTime: r.LastEdited,
+		})
+	}
+	var buf bytes.Buffer
+	err = writeJSON(&buf, results)
+	return buf.String(), err
+}
+
+func (input mcpInput) getPage(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Page           string `json:"page"`
+		WithProperties bool   `json:"with_properties"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	id, err := requireID("page", args.Page)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = getPage(ctx, &buf, getPageInput{
+		Fetcher:        input.Client,
+		Cache:          input.Cache,
+		ID:             id,
+		Format:         formatMarkdown,
+		WithProperties: args.WithProperties,
+	})
+	return buf.String(), err
+}
+
+func (input mcpInput) queryDatabase(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Database string   `json:"database"`
+		Where    string   `json:"where"`
+		Sort     []string `json:"sort"`
+		Columns  []string `json:"columns"`
+		Limit    int      `json:"limit"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	id, err := requireID("database", input.resolveDatabase(args.Database))
+	if err != nil {
+		return "", err
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var buf bytes.Buffer
+	err = query(ctx, &buf, queryInput{
+		Querier:    input.Client,
+		DatabaseID: id,
+		Where:      args.Where,
+		Sorts:      args.Sort,
+		Output:     outputJSON,
+		Columns:    args.Columns,
+		Limit:      limit,
+		Now:        input.Now(),
+	})
+	return buf.String(), err
+}
+
+func (input mcpInput) appendBlocks(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Page     string `json:"page"`
+		Markdown string `json:"markdown"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	id, err := requireID("page", args.Page)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.Markdown) == "" {
+		return "", errors.New("markdown is required")
+	}
+
+	var buf bytes.Buffer
+	err = appendMarkdown(ctx, &buf, appendInput{
+		Appender: input.Client,
+		Cache:    input.Cache,
+		PageID:   id,
+		Markdown: args.Markdown,
+	}, true)
+	return buf.String(), err
+}
+
+func (input mcpInput) updateProperties(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Page       string            `json:"page"`
+		Properties map[string]string `json:"properties"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	id, err := requireID("page", args.Page)
+	if err != nil {
+		return "", err
+	}
+	if len(args.Properties) == 0 {
+		return "", errors.New("properties is required")
+	}
+
+	page, err := input.Client.GetPage(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	configs := notionapi.PropertyConfigs{}
+	if page.Parent.Type == notionapi.ParentTypeDatabaseID {
+		db, err := input.Client.GetDatabase(ctx, string(page.Parent.DatabaseID))
+		if err != nil {
+			return "", err
+		}
+		configs = db.Properties
+	} else {
+		// A page outside a database only has its title
+		for name, prop := range page.Properties {
+			if prop.GetType() == notionapi.PropertyTypeTitle {
+				configs[name] = &notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle}
+			}
+		}
+	}
+
+	props := notionapi.Properties{}
+	for _, key := range sortedKeys(args.Properties) {
+		name, ok := findProperty(configs, key)
+		if !ok {
+			return "", fmt.Errorf("unknown property %q", key)
+		}
+		prop, err := notion.ParsePropertyValue(configs[name].GetType(), args.Properties[key], input.Now())
+		if err != nil {
+			return "", fmt.Errorf("property %q: %w", key, err)
+		}
+		props[name] = prop
+	}
+
+	updated, err := input.Client.UpdatePage(ctx, id, &notionapi.PageUpdateRequest{Properties: props})
+	if input.Cache != nil {
+		// The cached metadata no longer matches the page
+		_ = input.Cache.Delete(cache.MetaKey(notion.FormatID(id)))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = printCreated(&buf, createdOutput{ID: string(updated.ID), URL: updated.URL}, true)
+	return buf.String(), err
+}
+
+func (input mcpInput) createPage(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Database   string            `json:"database"`
+		Parent     string            `json:"parent"`
+		Title      string            `json:"title"`
+		Markdown   string            `json:"markdown"`
+		Properties map[string]string `json:"properties"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	if (args.Database == "") == (args.Parent == "") {
+		return "", errors.New("give either database or parent")
+	}
+
+	var buf bytes.Buffer
+	if args.Database != "" {
+		id, err := requireID("database", input.resolveDatabase(args.Database))
+		if err != nil {
+			return "", err
+		}
+		set := make([]string, 0, len(args.Properties))
+		for _, key := range sortedKeys(args.Properties) {
+			set = append(set, key+"="+args.Properties[key])
+		}
+		err = capture(ctx, &buf, captureInput{
+			Client:     input.Client,
+			DatabaseID: id,
+			Title:      args.Title,
+			Body:       args.Markdown,
+			Set:        set,
+			Now:        input.Now(),
+		}, true)
+		return buf.String(), err
+	}
+
+	parentID, err := requireID("parent", args.Parent)
+	if err != nil {
+		return "", err
+	}
+	if len(args.Properties) > 0 {
+		return "", errors.New("properties can only be set on database rows")
+	}
+	if strings.TrimSpace(args.Title) == "" {
+		return "", errors.New("title is required")
+	}
+	title, err := notion.ParsePropertyValue(notionapi.PropertyConfigTypeTitle, args.Title, input.Now())
+	if err != nil {
+		return "", err
+	}
+	page, err := input.Client.CreatePage(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(parentID)},
+		Properties: notionapi.Properties{"title": title},
+	})
+	if err != nil {
+		return "", err
+	}
+	if nodes := notion.ParseMarkdown(args.Markdown); len(nodes) > 0 {
+		if _, err := notion.AppendBlockTree(ctx, input.Client, string(page.ID), nodes); err != nil {
+			return "", fmt.Errorf("add content to %s: %w", page.ID, err)
+		}
+	}
+	err = printCreated(&buf, createdOutput{ID: string(page.ID), URL: page.URL}, true)
+	return buf.String(), err
+}
+
+// resolveDatabase maps a configured database name to its ID.
+func (input mcpInput) resolveDatabase(nameOrID string) string {
+	if input.Config == nil {
+		return nameOrID
+	}
+	return resolveDatabase(input.Config, nameOrID)
+}
+
+// decodeArgs decodes tool arguments, rejecting unknown ones so that a
+// misspelled argument is not silently ignored.
+func decodeArgs(raw json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// requireID parses a required ID or URL argument.
+func requireID(name, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("%s is required", name)
+	}
+	return notion.ParseID(value)
+}
+
+// sortedKeys returns the keys of m in order, so requests are built the same
+// way every time.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// objectSchema returns the JSON Schema of an object with the given
+// properties.
+func objectSchema(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringSchema(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func intSchema(description string) map[string]any {
+	return map[string]any{"type": "integer", "description": description}
+}
+
+func stringListSchema(description string) map[string]any {
+	return map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": description}
+}
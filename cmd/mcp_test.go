@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/mcp"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// mcpResponse is a JSON-RPC response read back from the server.
+type mcpResponse struct {
+	ID     int `json:"id"`
+	Result struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// runMCPScript sends the initialize handshake and then one request per call,
+// as an assistant would on stdin, and returns the responses to the calls.
+func runMCPScript(t *testing.T, input mcpInput, calls ...string) []mcpResponse {
+	t.Helper()
+
+	tools, err := mcpTools(input)
+	if err != nil {
+		t.Fatalf("mcpTools() error = %v", err)
+	}
+	server, err := mcp.NewServer(mcp.NewServerInput{Name: "notion-tui", Tools: tools})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := []string{
+		`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"script","version":"0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	}
+	for i, call := range calls {
+		script = append(script, `{"jsonrpc":"2.0","id":`+strconv.Itoa(i+1)+`,`+call+`}`)
+	}
+
+	var out bytes.Buffer
+	if err := server.Serve(context.Background(), strings.NewReader(strings.Join(script, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var responses []mcpResponse
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var resp mcpResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("response %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+	if len(responses) != len(calls)+1 {
+		t.Fatalf("got %d responses, want %d:\n%s", len(responses), len(calls)+1, out.String())
+	}
+	return responses[1:]
+}
+
+func newMCPInput(allowWrite ...string) (mcpInput, *testhelpers.MockNotionClient) {
+	mockClient := newCaptureClient()
+	return mcpInput{
+		Client:     mockClient,
+		AllowWrite: allowWrite,
+		Now:        func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) },
+	}, mockClient
+}
+
+func TestMCPToolsAllowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowWrite []string
+		want       []string
+	}{
+		{name: "read only by default", want: []string{"search", "get_page", "query_database"}},
+		{
+			name:       "allowed write tools",
+			allowWrite: []string{toolCreatePage},
+			want:       []string{"search", "get_page", "query_database", "create_page"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, _ := newMCPInput(tt.allowWrite...)
+			resp := runMCPScript(t, input, `"method":"tools/list"`)
+
+			var got []string
+			for _, tool := range resp[0].Result.Tools {
+				got = append(got, tool.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("tools = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	input, _ := newMCPInput("delete_page")
+	if _, err := mcpTools(input); err == nil || !strings.Contains(err.Error(), `unknown tool "delete_page"`) {
+		t.Errorf("mcpTools() error = %v", err)
+	}
+}
+
+func TestMCPReadTools(t *testing.T) {
+	input, mockClient := newMCPInput()
+	mockClient.BlocksToReturn = testhelpers.NewGetChildrenResponse([]notionapi.Block{
+		testhelpers.NewHeading1Block("Plan"),
+		testhelpers.NewParagraphBlock("Ship it."),
+	})
+	mockClient.PageToReturn = testhelpers.NewTestPage("page-1", "Roadmap")
+
+	resp := runMCPScript(t, input,
+		`"method":"tools/call","params":{"name":"get_page","arguments":{"page":"page-1"}}`,
+		`"method":"tools/call","params":{"name":"get_page","arguments":{"id":"page-1"}}`,
+		`"method":"tools/call","params":{"name":"create_page","arguments":{"title":"x"}}`,
+	)
+
+	if resp[0].Result.IsError || !strings.Contains(resp[0].Result.Content[0].Text, "# Plan\n\nShip it.") {
+		t.Errorf("get_page = %+v", resp[0].Result)
+	}
+	if !resp[1].Result.IsError || !strings.Contains(resp[1].Result.Content[0].Text, `unknown field "id"`) {
+		t.Errorf("get_page with a wrong argument = %+v", resp[1].Result)
+	}
+	if resp[2].Error == nil || !strings.Contains(resp[2].Error.Message, "unknown tool") {
+		t.Errorf("create_page without the allowlist = %+v", resp[2])
+	}
+	if got := mockClient.CreatePageCallCount(); got != 0 {
+		t.Errorf("CreatePage calls = %d, want 0", got)
+	}
+}
+
+func TestMCPWriteTools(t *testing.T) {
+	input, mockClient := newMCPInput(toolCreatePage, toolUpdateProperties)
+	row := testhelpers.NewTestPage("page-1", "Renew passport")
+	row.Parent = notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: "db-inbox"}
+	mockClient.PageToReturn = row
+
+	resp := runMCPScript(t, input,
+		`"method":"tools/call","params":{"name":"create_page","arguments":{"database":"db-inbox","title":"Renew passport","markdown":"Bring the old one.","properties":{"Status":"Todo"}}}`,
+		`"method":"tools/call","params":{"name":"update_properties","arguments":{"page":"page-1","properties":{"due":"tomorrow"}}}`,
+		`"method":"tools/call","params":{"name":"update_properties","arguments":{"page":"page-1","properties":{"Owner":"sam"}}}`,
+	)
+
+	for i, r := range resp[:2] {
+		if r.Result.IsError {
+			t.Fatalf("call %d failed: %s", i, r.Result.Content[0].Text)
+		}
+	}
+	if got := mockClient.CreatePageCallCount(); got != 1 {
+		t.Fatalf("CreatePage calls = %d, want 1", got)
+	}
+	created := mockClient.CreatePageCalls[0].Request
+	if status, ok := created.Properties["Status"].(*notionapi.StatusProperty); !ok || status.Status.Name != "Todo" {
+		t.Errorf("Status = %#v", created.Properties["Status"])
+	}
+
+	if got := mockClient.UpdatePageCallCount(); got != 1 {
+		t.Fatalf("UpdatePage calls = %d, want 1", got)
+	}
+	due, ok := mockClient.UpdatePageCalls[0].Request.Properties["Due"].(*notionapi.DateProperty)
+	if !ok || time.Time(*due.Date.Start).Format("2006-01-02") != "2024-03-16" {
+		t.Errorf("Due = %#v", mockClient.UpdatePageCalls[0].Request.Properties["Due"])
+	}
+
+	if !resp[2].Result.IsError || !strings.Contains(resp[2].Result.Content[0].Text, `unknown property "Owner"`) {
+		t.Errorf("update_properties with an unknown property = %+v", resp[2].Result)
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <url-or-id>",
+	Short: "Start the TUI on a page, database or block",
+	Long: `Start the TUI on the page or database a Notion link points at, instead of
+the dashboard. The same works with the link as the only argument of
+notion-tui itself.
+
+Any link copied from Notion works: with or without the workspace and page
+title, notion:// links from the desktop app, database views (?v=), rows
+opened as a peek (?p=) and links to a block (#...), which open its page
+scrolled to the block. A page, database or block ID, or the name of a
+configured database, works too.
+
+With --desktop-file, a desktop entry is printed instead that registers
+notion-tui as the handler of notion:// links.`,
+	Example: `  notion-tui open https://www.notion.so/acme/Roadmap-0123456789abcdef0123456789abcdef
+  notion-tui https://www.notion.so/acme/0123456789abcdef0123456789abcdef?v=fedcba9876543210fedcba9876543210
+
+  notion-tui open --desktop-file > ~/.local/share/applications/notion-tui.desktop
+  xdg-mime default notion-tui.desktop x-scheme-handler/notion`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOpen,
+}
+
+func init() {
+	openCmd.Flags().Bool("desktop-file", false, "print a desktop entry that opens notion:// links in notion-tui")
+
+	rootCmd.AddCommand(openCmd)
+}
+
+// openClient is the subset of the Notion client used to resolve a link.
+type openClient interface {
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
+	GetPage(ctx context.Context, id string) (*notionapi.Page, error)
+	GetBlock(ctx context.Context, id string) (notionapi.Block, error)
+}
+
+// maxBlockDepth bounds the walk from a nested block up to its page.
+const maxBlockDepth = 32
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	if desktop, _ := cmd.Flags().GetBool("desktop-file"); desktop {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("find executable: %w", err)
+		}
+		return writeDesktopFile(cmd.OutOrStdout(), exe)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("requires a Notion URL or ID")
+	}
+	return runTUI(cmd, args)
+}
+
+// resolveStart works out what a link given on the command line opens.
+func resolveStart(ctx context.Context, client openClient, link notion.Link) (ui.StartTarget, error) {
+	if link.BlockID != "" {
+		// The page is still worth opening when the block is gone
+		block, err := client.GetBlock(ctx, link.BlockID)
+		if err != nil {
+			return ui.StartTarget{PageID: link.ID}, nil
+		}
+		if _, blockID, err := blockPage(ctx, client, block); err == nil {
+			return ui.StartTarget{PageID: link.ID, BlockID: blockID}, nil
+		}
+		return ui.StartTarget{PageID: link.ID}, nil
+	}
+	if link.ViewID != "" {
+		return ui.StartTarget{DatabaseID: link.ID}, nil
+	}
+
+	// An ID may name a database, a page or a block
+	if _, err := client.GetDatabase(ctx, link.ID); err == nil {
+		return ui.StartTarget{DatabaseID: link.ID}, nil
+	}
+	_, err := client.GetPage(ctx, link.ID)
+	if err == nil {
+		return ui.StartTarget{PageID: link.ID}, nil
+	}
+	if notion.ClassifyError(err) != notion.ErrorClassNotFound {
+		return ui.StartTarget{}, fmt.Errorf("open %s: %w", link.ID, err)
+	}
+	block, blockErr := client.GetBlock(ctx, link.ID)
+	if blockErr != nil {
+		return ui.StartTarget{}, fmt.Errorf("open %s: %w", link.ID, err)
+	}
+	pageID, blockID, err := blockPage(ctx, client, block)
+	if err != nil {
+		return ui.StartTarget{}, fmt.Errorf("open %s: %w", link.ID, err)
+	}
+	return ui.StartTarget{PageID: pageID, BlockID: blockID}, nil
+}
+
+// blockPage walks up from a block to the page it is on. It returns the page
+// and the top-level block of the page that holds the block.
+func blockPage(ctx context.Context, client openClient, block notionapi.Block) (string, string, error) {
+	for depth := 0; depth < maxBlockDepth; depth++ {
+		parent := block.GetParent()
+		if parent == nil {
+			return "", "", fmt.Errorf("block %s has no parent", block.GetID())
+		}
+
+		switch parent.Type {
+		case notionapi.ParentTypePageID:
+			return notion.NormalizeID(string(parent.PageID)), notion.NormalizeID(string(block.GetID())), nil
+		case notionapi.ParentTypeBlockID:
+			next, err := client.GetBlock(ctx, string(parent.BlockID))
+			if err != nil {
+				return "", "", fmt.Errorf("fetch block: %w", err)
+			}
+			block = next
+		default:
+			return "", "", fmt.Errorf("block %s is not on a page", block.GetID())
+		}
+	}
+	return "", "", fmt.Errorf("block %s is nested too deeply", block.GetID())
+}
+
+// startTarget resolves the link given to the root command or `open`, or
+// returns the zero target when there is none.
+func startTarget(ctx context.Context, cfg *config.Config, args []string) (ui.StartTarget, error) {
+	if len(args) == 0 {
+		return ui.StartTarget{}, nil
+	}
+	link, err := notion.ParseLink(resolveDatabase(cfg, args[0]))
+	if err != nil {
+		return ui.StartTarget{}, err
+	}
+	return resolveStart(ctx, notion.NewClient(cfg.NotionToken), link)
+}
+
+// writeDesktopFile writes a desktop entry that runs `open` in a terminal for
+// notion:// links.
+func writeDesktopFile(w io.Writer, exe string) error {
+	if strings.ContainsAny(exe, " \t\"'\\") {
+		exe = `"` + strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`).Replace(exe) + `"`
+	}
+	_, err := fmt.Fprintf(w, `[Desktop Entry]
+Type=Application
+Name=notion-tui
+Comment=Open Notion links in the terminal
+Exec=%s open %%u
+Terminal=true
+NoDisplay=true
+MimeType=x-scheme-handler/notion;
+Categories=Office;
+`, exe)
+	return err
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+	"github.com/Panandika/notion-tui/internal/ui"
+)
+
+const (
+	openPageID  = "11111111111111111111111111111111"
+	openBlockID = "22222222222222222222222222222222"
+	openToggle  = "33333333333333333333333333333333"
+)
+
+// newOpenClient returns a mock where openPageID is a page holding a toggle
+// with openBlockID inside it, and every other ID is not found.
+func newOpenClient() *testhelpers.MockNotionClient {
+	notFound := &notionapi.Error{Status: 404, Message: "Could not find object"}
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.GetDatabaseFunc = func(ctx context.Context, id string) (*notionapi.Database, error) {
+		return nil, &notionapi.Error{Status: 400, Message: "is a page, not a database"}
+	}
+	mockClient.GetPageFunc = func(ctx context.Context, id string) (*notionapi.Page, error) {
+		if id == openPageID {
+			return testhelpers.NewTestPage(id, "Roadmap"), nil
+		}
+		return nil, notFound
+	}
+	mockClient.GetBlockFunc = func(ctx context.Context, id string) (notionapi.Block, error) {
+		block := testhelpers.NewParagraphBlock("text")
+		block.ID = notionapi.BlockID(id)
+		switch id {
+		case openBlockID:
+			block.Parent = &notionapi.Parent{Type: notionapi.ParentTypeBlockID, BlockID: notionapi.BlockID(notion.FormatID(openToggle))}
+		case notion.FormatID(openToggle):
+			block.Parent = &notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(notion.FormatID(openPageID))}
+		default:
+			return nil, notFound
+		}
+		return block, nil
+	}
+	return mockClient
+}
+
+func TestResolveStart(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want ui.StartTarget
+	}{
+		{
+			name: "page",
+			link: "https://www.notion.so/acme/Roadmap-" + openPageID,
+			want: ui.StartTarget{PageID: openPageID},
+		},
+		{
+			name: "database view",
+			link: "https://www.notion.so/acme/" + openToggle + "?v=" + openBlockID,
+			want: ui.StartTarget{DatabaseID: openToggle},
+		},
+		{
+			name: "nested block anchor",
+			link: "https://www.notion.so/Roadmap-" + openPageID + "#" + openBlockID,
+			want: ui.StartTarget{PageID: openPageID, BlockID: openToggle},
+		},
+		{
+			name: "missing block anchor",
+			link: "https://www.notion.so/Roadmap-" + openPageID + "#" + strings.Repeat("4", 32),
+			want: ui.StartTarget{PageID: openPageID},
+		},
+		{
+			name: "block id",
+			link: openBlockID,
+			want: ui.StartTarget{PageID: openPageID, BlockID: openToggle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := notion.ParseLink(tt.link)
+			if err != nil {
+				t.Fatalf("ParseLink() error = %v", err)
+			}
+			got, err := resolveStart(context.Background(), newOpenClient(), link)
+			if err != nil {
+				t.Fatalf("resolveStart() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveStart() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveStartDatabase(t *testing.T) {
+	mockClient := newOpenClient()
+	mockClient.GetDatabaseFunc = nil
+
+	got, err := resolveStart(context.Background(), mockClient, notion.Link{ID: openToggle})
+	if err != nil {
+		t.Fatalf("resolveStart() error = %v", err)
+	}
+	if want := (ui.StartTarget{DatabaseID: openToggle}); got != want {
+		t.Errorf("resolveStart() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveStartNotFound(t *testing.T) {
+	_, err := resolveStart(context.Background(), newOpenClient(), notion.Link{ID: strings.Repeat("4", 32)})
+	if err == nil {
+		t.Fatal("resolveStart() error = nil")
+	}
+	if got := ExitCode(err); got != ExitNotFound {
+		t.Errorf("ExitCode() = %d, want %d", got, ExitNotFound)
+	}
+}
+
+func TestWriteDesktopFile(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeDesktopFile(&out, "/opt/notion tui/notion-tui"); err != nil {
+		t.Fatalf("writeDesktopFile() error = %v", err)
+	}
+
+	for _, line := range []string{
+		`Exec="/opt/notion tui/notion-tui" open %u`,
+		"Terminal=true",
+		"MimeType=x-scheme-handler/notion;",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("desktop file is missing %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/filter"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// Output formats supported by `query`.
+const (
+	outputTable = "table"
+	outputCSV   = "csv"
+	outputJSON  = "json"
+)
+
+// queryPageSize is the largest page size the Notion API accepts.
+const queryPageSize = 100
+
+// maxCellWidth is the widest a table cell is printed before truncation.
+const maxCellWidth = 40
+
+var queryCmd = &cobra.Command{
+	Use:   "query <database>",
+	Short: "Print the rows of a database as a table, CSV or JSON",
+	Long: `Query a database and print every matching row.
+
+The database is given by its name from the config file, by ID or by URL.
+Property values are flattened to text, one column per property.
+
+--where takes a filter expression:
+
+  Status = "In progress" and (Due < today or Priority >= 2)
+
+Comparisons are =, !=, <, <=, >, >=, contains, not contains, starts_with,
+ends_with, is empty and is not empty; which apply depends on the property
+type. Dates accept YYYY-MM-DD, RFC 3339, today, yesterday, tomorrow and now,
+and "= past_week" style ranges. created_time and last_edited_time filter on
+the page timestamps.
+
+--sort takes property names separated by commas; prefix a name with "-" to
+sort descending.`,
+	Example: `  notion-tui query tasks --where 'Status = "In progress" and Due < today' --sort -Due --output csv
+  notion-tui query tasks --where 'Tags contains urgent' --columns Name,Due --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().String("where", "", "filter expression")
+	queryCmd.Flags().StringArray("sort", nil, "sort by properties, \"-\" prefix for descending (repeatable)")
+	queryCmd.Flags().StringP("output", "o", outputTable, "output format: table, csv or json")
+	queryCmd.Flags().StringSlice("columns", nil, "properties to print (default all, title first)")
+	queryCmd.Flags().Int("limit", 0, "maximum number of rows (0 = all)")
+
+	rootCmd.AddCommand(queryCmd)
+}
+
+// databaseQuerier is the subset of the Notion client used to query a database.
+type databaseQuerier interface {
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
+	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
+}
+
+// queryInput contains parameters for query.
+type queryInput struct {
+	Querier    databaseQuerier
+	DatabaseID string
+	Where      string
+	Sorts      []string
+	Output     string
+	Columns    []string
+	Limit      int
+	Now        time.Time
+}
+
+// queryRow is a row of the JSON output of `query`.
+type queryRow struct {
+	ID         string            `json:"id"`
+	URL        string            `json:"url"`
+	Properties map[string]string `json:"properties"`
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	if err := validateOutput(output); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	id, err := notion.ParseID(resolveDatabase(cfg, args[0]))
+	if err != nil {
+		return err
+	}
+
+	where, _ := cmd.Flags().GetString("where")
+	sorts, _ := cmd.Flags().GetStringArray("sort")
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	return query(ctx, cmd.OutOrStdout(), queryInput{
+		Querier:    notion.NewClient(cfg.NotionToken),
+		DatabaseID: id,
+		Where:      where,
+		Sorts:      sorts,
+		Output:     output,
+		Columns:    columns,
+		Limit:      limit,
+		Now:        time.Now(),
+	})
+}
+
+// validateOutput rejects unknown --output values before any request is made.
+func validateOutput(output string) error {
+	switch output {
+	case outputTable, outputCSV, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (want table, csv or json)", output)
+	}
+}
+
+// query implements `query`.
+func query(ctx context.Context, w io.Writer, input queryInput) error {
+	if err := validateOutput(input.Output); err != nil {
+		return err
+	}
+
+	db, err := input.Querier.GetDatabase(ctx, input.DatabaseID)
+	if err != nil {
+		return err
+	}
+	schema := filter.SchemaOf(db)
+
+	req, err := buildQueryRequest(input, schema)
+	if err != nil {
+		return err
+	}
+	columns, err := queryColumns(db, input.Columns)
+	if err != nil {
+		return err
+	}
+	pages, err := queryAll(ctx, input.Querier, input.DatabaseID, req, input.Limit)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]queryRow, len(pages))
+	for i, page := range pages {
+		props := make(map[string]string, len(columns))
+		for _, name := range columns {
+			if prop, ok := page.Properties[name]; ok {
+				props[name] = notion.PropertyString(prop)
+			} else {
+				props[name] = ""
+			}
+		}
+		rows[i] = queryRow{ID: string(page.ID), URL: page.URL, Properties: props}
+	}
+
+	switch input.Output {
+	case outputJSON:
+		return writeJSON(w, rows)
+	case outputCSV:
+		return writeQueryCSV(w, columns, rows)
+	default:
+		return writeQueryTable(w, columns, rows)
+	}
+}
+
+// buildQueryRequest compiles the filter and sort flags against the schema.
+func buildQueryRequest(input queryInput, schema filter.Schema) (*notionapi.DatabaseQueryRequest, error) {
+	req := &notionapi.DatabaseQueryRequest{}
+	if strings.TrimSpace(input.Where) != "" {
+		expr, err := filter.Parse(input.Where)
+		if err != nil {
+			return nil, err
+		}
+		f, err := filter.Compile(expr, schema, input.Now)
+		if err != nil {
+			return nil, fmt.Errorf("compile filter: %w", err)
+		}
+		req.Filter = f
+	}
+
+	sorts, err := filter.ParseSorts(input.Sorts, schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse sort: %w", err)
+	}
+	req.Sorts = sorts
+	return req, nil
+}
+
+// queryColumns returns the requested columns with their schema names, or all
+// properties with the title first when none were requested.
+func queryColumns(db *notionapi.Database, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		columns := make([]string, 0, len(db.Properties))
+		for name := range db.Properties {
+			columns = append(columns, name)
+		}
+		sort.Slice(columns, func(i, j int) bool {
+			ti := db.Properties[columns[i]].GetType() == notionapi.PropertyConfigTypeTitle
+			tj := db.Properties[columns[j]].GetType() == notionapi.PropertyConfigTypeTitle
+			if ti != tj {
+				return ti
+			}
+			return columns[i] < columns[j]
+		})
+		return columns, nil
+	}
+
+	columns := make([]string, 0, len(requested))
+	for _, want := range requested {
+		name, ok := findProperty(db.Properties, strings.TrimSpace(want))
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", want)
+		}
+		columns = append(columns, name)
+	}
+	return columns, nil
+}
+
+// findProperty resolves a property name, ignoring case when there is no exact
+// match.
+func findProperty(props notionapi.PropertyConfigs, name string) (string, bool) {
+	if _, ok := props[name]; ok {
+		return name, true
+	}
+	for candidate := range props {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// queryAll follows the pagination cursor until every matching page, or limit
+// pages when limit is positive, has been read.
+func queryAll(ctx context.Context, q databaseQuerier, id string, req *notionapi.DatabaseQueryRequest, limit int) ([]notionapi.Page, error) {
+	var pages []notionapi.Page
+	for {
+		req.PageSize = queryPageSize
+		if limit > 0 && limit-len(pages) < queryPageSize {
+			req.PageSize = limit - len(pages)
+		}
+
+		resp, err := q.QueryDatabase(ctx, id, req)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, resp.Results...)
+
+		if limit > 0 && len(pages) >= limit {
+			return pages[:limit], nil
+		}
+		if !resp.HasMore || resp.NextCursor == "" {
+			return pages, nil
+		}
+		req.StartCursor = resp.NextCursor
+	}
+}
+
+// writeQueryCSV writes the rows as CSV with a header line.
+func writeQueryCSV(w io.Writer, columns []string, rows []queryRow) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"id"}, columns...)
+	if err := cw.Write(append(header, "url")); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	for _, row := range rows {
+		record := make([]string, 0, len(columns)+2)
+		record = append(record, row.ID)
+		for _, name := range columns {
+			record = append(record, row.Properties[name])
+		}
+		if err := cw.Write(append(record, row.URL)); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
+
+// writeQueryTable writes the rows as an aligned table for reading in a
+// terminal. Long values are truncated and line breaks flattened.
+func writeQueryTable(w io.Writer, columns []string, rows []queryRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, name := range columns {
+			cells[i] = tableCell(row.Properties[name])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tableCell prepares a value for a single table cell.
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxCellWidth {
+		return string(r[:maxCellWidth-1]) + "…"
+	}
+	return orDash(s)
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// newQueryClient returns a mock client for a task database whose rows are
+// served in two pages.
+func newQueryClient() *testhelpers.MockNotionClient {
+	schema := testhelpers.NewTestDatabaseSchema("db-tasks")
+	schema.Properties["Status"] = &notionapi.StatusPropertyConfig{Type: notionapi.PropertyConfigStatus}
+	schema.Properties["Due"] = &notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate}
+
+	row := func(id, title, status string) notionapi.Page {
+		page := testhelpers.NewTestPage(id, title)
+		page.Properties["Status"] = &notionapi.StatusProperty{
+			Type:   notionapi.PropertyTypeStatus,
+			Status: notionapi.Option{Name: status},
+		}
+		return *page
+	}
+
+	mockClient := testhelpers.NewMockNotionClient().WithSchema(schema)
+	mockClient.QueryDatabaseFunc = func(ctx context.Context, id string,
+		req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		if req.StartCursor == "" {
+			return &notionapi.DatabaseQueryResponse{
+				Results:    []notionapi.Page{row("task-1", "Write docs", "In progress")},
+				HasMore:    true,
+				NextCursor: "cursor-2",
+			}, nil
+		}
+		return &notionapi.DatabaseQueryResponse{
+			Results: []notionapi.Page{row("task-2", "Ship, then rest", "Done")},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestQueryOutputs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "csv",
+			output: outputCSV,
+			want: "id,title,Due,Status,url\n" +
+				"task-1,Write docs,,In progress,https://www.notion.so/task-1\n" +
+				"task-2,\"Ship, then rest\",,Done,https://www.notion.so/task-2\n",
+		},
+		{
+			name:   "table",
+			output: outputTable,
+			want: "title            Due  Status\n" +
+				"Write docs       -    In progress\n" +
+				"Ship, then rest  -    Done\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := query(context.Background(), &out, queryInput{
+				Querier:    newQueryClient(),
+				DatabaseID: "db-tasks",
+				Output:     tt.output,
+			})
+			if err != nil {
+				t.Fatalf("query() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("query() output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryJSONColumns(t *testing.T) {
+	var out bytes.Buffer
+	err := query(context.Background(), &out, queryInput{
+		Querier:    newQueryClient(),
+		DatabaseID: "db-tasks",
+		Output:     outputJSON,
+		Columns:    []string{"status"},
+	})
+	if err != nil {
+		t.Fatalf("query() error = %v", err)
+	}
+
+	var rows []queryRow
+	if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	want := map[string]string{"Status": "In progress"}
+	if len(rows[0].Properties) != 1 || rows[0].Properties["Status"] != want["Status"] {
+		t.Errorf("rows[0].Properties = %v, want %v", rows[0].Properties, want)
+	}
+	if rows[1].ID != "task-2" || rows[1].URL != "https://www.notion.so/task-2" {
+		t.Errorf("rows[1] = %+v", rows[1])
+	}
+}
+
+func TestQueryRequest(t *testing.T) {
+	mockClient := newQueryClient()
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	err := query(context.Background(), &out, queryInput{
+		Querier:    mockClient,
+		DatabaseID: "db-tasks",
+		Where:      `Status = "In progress" and Due < today`,
+		Sorts:      []string{"-Due"},
+		Output:     outputCSV,
+		Now:        now,
+	})
+	if err != nil {
+		t.Fatalf("query() error = %v", err)
+	}
+
+	if got := mockClient.QueryDatabaseCallCount(); got != 2 {
+		t.Fatalf("QueryDatabase called %d times, want 2", got)
+	}
+	req := mockClient.LastQueryDatabaseCall().Request
+	if req.StartCursor != "cursor-2" || req.PageSize != queryPageSize {
+		t.Errorf("last request cursor = %q, page size = %d", req.StartCursor, req.PageSize)
+	}
+
+	and, ok := req.Filter.(notionapi.AndCompoundFilter)
+	if !ok || len(and) != 2 {
+		t.Fatalf("Filter = %#v, want an and of two filters", req.Filter)
+	}
+	due, ok := and[1].(notionapi.PropertyFilter)
+	if !ok || due.Date == nil || due.Date.Before == nil {
+		t.Fatalf("second filter = %#v, want Due before", and[1])
+	}
+	if got := time.Time(*due.Date.Before); !got.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Due before = %v, want start of today", got)
+	}
+
+	wantSorts := []notionapi.SortObject{{Property: "Due", Direction: notionapi.SortOrderDESC}}
+	if len(req.Sorts) != 1 || req.Sorts[0] != wantSorts[0] {
+		t.Errorf("Sorts = %v, want %v", req.Sorts, wantSorts)
+	}
+}
+
+func TestQueryLimit(t *testing.T) {
+	mockClient := newQueryClient()
+
+	var out bytes.Buffer
+	err := query(context.Background(), &out, queryInput{
+		Querier:    mockClient,
+		DatabaseID: "db-tasks",
+		Output:     outputCSV,
+		Limit:      1,
+	})
+	if err != nil {
+		t.Fatalf("query() error = %v", err)
+	}
+	if got := mockClient.QueryDatabaseCallCount(); got != 1 {
+		t.Errorf("QueryDatabase called %d times, want 1", got)
+	}
+	if got := mockClient.LastQueryDatabaseCall().Request.PageSize; got != 1 {
+		t.Errorf("PageSize = %d, want 1", got)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 {
+		t.Errorf("got %d lines, want header and one row:\n%s", lines, out.String())
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   queryInput
+		wantErr string
+	}{
+		{
+			name:    "unknown output",
+			input:   queryInput{Output: "xml"},
+			wantErr: `unknown output format "xml"`,
+		},
+		{
+			name:    "syntax error",
+			input:   queryInput{Output: outputCSV, Where: "Status ="},
+			wantErr: "filter syntax error at position 9",
+		},
+		{
+			name:    "unknown property",
+			input:   queryInput{Output: outputCSV, Where: "Owner = me"},
+			wantErr: `compile filter: unknown property "Owner"`,
+		},
+		{
+			name:    "unknown sort",
+			input:   queryInput{Output: outputCSV, Sorts: []string{"Size"}},
+			wantErr: `parse sort: unknown sort property "Size"`,
+		},
+		{
+			name:    "unknown column",
+			input:   queryInput{Output: outputCSV, Columns: []string{"Size"}},
+			wantErr: `unknown column "Size"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newQueryClient()
+			tt.input.Querier = mockClient
+			tt.input.DatabaseID = "db-tasks"
+
+			err := query(context.Background(), &bytes.Buffer{}, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("query() error = %v, want %q", err, tt.wantErr)
+			}
+			if got := mockClient.QueryDatabaseCallCount(); got != 0 {
+				t.Errorf("QueryDatabase called %d times, want 0", got)
+			}
+		})
+	}
+}
@@ -1,20 +1,33 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/Panandika/notion-tui/internal/cache"
 	"github.com/Panandika/notion-tui/internal/config"
 	"github.com/Panandika/notion-tui/internal/ui"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 	"github.com/Panandika/notion-tui/internal/version"
 )
 
+// viperMu serializes access to viper while the TUI runs: a profile picked in
+// the TUI is loaded on a command goroutine and config edits are read on the
+// watcher's, and viper is not safe for concurrent use.
+var viperMu sync.Mutex
+
 var rootCmd = &cobra.Command{
-	Use:     "notion-tui",
+	Use:     "notion-tui [url-or-id]",
 	Short:   "A terminal UI for Notion",
 	Version: version.Short(),
 	Long: `notion-tui is a keyboard-driven terminal interface for browsing,
@@ -26,15 +39,20 @@ Configuration can be provided via:
 - Configuration file (~/.config/notion-tui/config.yaml)
 - Default values (lowest priority)
 
+Given a Notion URL or ID, the TUI starts on that page or database, as
+with ` + "`notion-tui open`" + `.
+
 Example:
   export NOTION_TOKEN="secret_xxx"
   export NOTION_TUI_DATABASE_ID="db_id"
   notion-tui`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runTUI,
 }
 
 // Execute runs the root command.
 func Execute() error {
+	defer closeLogging()
 	return rootCmd.Execute()
 }
 
@@ -54,6 +72,10 @@ func init() {
 		"database-id", "",
 		"Notion database ID to open (env: NOTION_TUI_DATABASE_ID)",
 	)
+	rootCmd.PersistentFlags().String(
+		"profile", "",
+		"config profile to use (env: NOTION_TUI_PROFILE)",
+	)
 	rootCmd.PersistentFlags().Bool(
 		"debug", false,
 		"enable debug logging (env: NOTION_TUI_DEBUG)",
@@ -63,8 +85,16 @@ func init() {
 	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 	viper.BindPFlag("notion_token", rootCmd.PersistentFlags().Lookup("token"))
 	viper.BindPFlag("database_id", rootCmd.PersistentFlags().Lookup("database-id"))
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 
+	viper.SetDefault("cache_dir", config.DefaultCacheDir)
+	viper.SetDefault("export_dir", config.DefaultExportDir)
+	viper.SetDefault("log.file", config.DefaultLogFile)
+
+	// Keep the cache passphrase out of config files
+	viper.BindEnv("cache_encryption.passphrase", "NOTION_TUI_CACHE_PASSPHRASE")
+
 	// Automatically bind environment variables with NOTION_TUI_ prefix
 	viper.SetEnvPrefix("NOTION_TUI")
 	viper.AutomaticEnv()
@@ -93,22 +123,186 @@ func initConfig() {
 	// Try to read the config file, but don't fail if it doesn't exist
 	// (config can come from env vars or flags instead)
 	_ = viper.ReadInConfig()
+
+	setupLogging()
 }
 
-// runTUI is the main entry point for the TUI application.
+// runTUI is the main entry point for the TUI application. A link in args
+// picks the page or database it starts on.
 func runTUI(cmd *cobra.Command, args []string) error {
-	// Load configuration (validation happens in config.Load)
-	cfg, err := config.Load()
+	// Bindings and the theme are checked before anything is drawn
+	cfg, keys, t, err := loadSettings()
+	if err != nil {
+		written, setupErr := firstRun(err)
+		if !written {
+			return setupErr
+		}
+		if cfg, keys, t, err = loadSettings(); err != nil {
+			return err
+		}
+	}
+
+	start, err := startTarget(commandContext(cmd), cfg, args)
+	if err != nil {
+		return err
+	}
+
+	pageCache, err := openTUICache(cfg)
 	if err != nil {
 		return err
 	}
 
 	// Create and run the TUI
 	model := ui.NewModel(ui.NewModelInput{
-		Config: cfg,
-		Cache:  nil, // Will use default cache
+		Config:      cfg,
+		Cache:       pageCache,
+		Start:       start,
+		Keys:        keys,
+		Theme:       &t,
+		LoadProfile: loadProfile,
 	})
 	p := tea.NewProgram(model)
-	_, err = p.Run()
+	defer watchConfig(p)()
+	final, err := p.Run()
+	// A profile switched in the TUI replaced the services opened here; the
+	// final model closes them along with its own
+	if m, ok := final.(ui.AppModel); ok {
+		model = m
+	}
+	if closeErr := model.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
 	return err
 }
+
+// loadSettings loads the config with the key bindings and theme built from
+// it. A broken theme is reported rather than drawn in the wrong colors.
+func loadSettings() (*config.Config, *keymap.KeyMap, theme.Theme, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, theme.Theme{}, err
+	}
+
+	keys, err := keymap.New(keymap.NewInput{Preset: cfg.Keys.Preset, Overrides: cfg.Keys.Overrides()})
+	if err != nil {
+		return nil, nil, theme.Theme{}, err
+	}
+
+	t, err := loadTheme(cfg, os.Getenv("NO_COLOR") != "")
+	if err != nil {
+		return nil, nil, theme.Theme{}, err
+	}
+	return cfg, keys, t, nil
+}
+
+// openTUICache opens the cache for the TUI. An encrypted cache must not
+// silently fall back to running uncached, so key problems are reported;
+// other problems leave the TUI to open the cache itself or run without one.
+func openTUICache(cfg *config.Config) (*cache.PageCache, error) {
+	pageCache, err := openCache(context.Background(), cfg)
+	if err != nil {
+		if cfg.CacheEncryption.Enabled() || errors.Is(err, cache.ErrKeyRequired) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return pageCache, nil
+}
+
+// loadProfile opens a profile picked in the TUI. The profile stays active
+// when the config file is reloaded; one that cannot be opened leaves the
+// active profile as it was.
+func loadProfile(name string) tea.Msg {
+	viperMu.Lock()
+	defer viperMu.Unlock()
+
+	previous := viper.GetString("profile")
+	viper.Set("profile", name)
+
+	cfg, keys, t, err := loadSettings()
+	if err != nil {
+		viper.Set("profile", previous)
+		return ui.ProfileSwitchFailedMsg{Profile: name, Err: err}
+	}
+
+	pageCache, err := openTUICache(cfg)
+	if err != nil {
+		viper.Set("profile", previous)
+		return ui.ProfileSwitchFailedMsg{Profile: name, Err: err}
+	}
+
+	return ui.ProfileSwitchedMsg{Config: cfg, Cache: pageCache, Keys: keys, Theme: t}
+}
+
+// watchConfig applies edits to the config file while the TUI runs. A config
+// that does not load is reported and the one in use is kept. The returned
+// function stops watching.
+//
+// viper.WatchConfig is not used as it reads the file on its own goroutine,
+// outside viperMu.
+func watchConfig(p *tea.Program) (stop func()) {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return func() {}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return func() {}
+	}
+	// Editors often replace the file rather than write it, so its directory
+	// is watched
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return func() {}
+	}
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == filepath.Clean(file) && event.Has(fsnotify.Write|fsnotify.Create) {
+					p.Send(reloadConfig())
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return func() { watcher.Close() }
+}
+
+// reloadConfig reads the config file again and builds the key bindings and
+// theme from it, returning the message that applies them to the TUI.
+func reloadConfig() tea.Msg {
+	viperMu.Lock()
+	defer viperMu.Unlock()
+
+	// viper keeps the old values when the file does not parse
+	if err := viper.ReadInConfig(); err != nil {
+		return ui.ConfigReloadFailedMsg{Err: fmt.Errorf("read config: %w", err)}
+	}
+
+	cfg, keys, t, err := loadSettings()
+	if err != nil {
+		return ui.ConfigReloadFailedMsg{Err: err}
+	}
+
+	return ui.ConfigReloadedMsg{Config: cfg, Keys: keys, Theme: t}
+}
+
+// loadTheme builds the theme from the config. noColor forces the no-color
+// theme, as when NO_COLOR is set.
+func loadTheme(cfg *config.Config, noColor bool) (theme.Theme, error) {
+	return theme.Load(theme.LoadInput{
+		Name:    cfg.Theme.Name,
+		File:    cfg.Theme.File,
+		Colors:  cfg.Theme.Colors,
+		NoColor: noColor,
+	})
+}
@@ -20,6 +20,9 @@ func TestRootCommandInitialization(t *testing.T) {
 	if rootCmd.PersistentFlags().Lookup("config") == nil {
 		t.Error("--config flag not registered")
 	}
+	if rootCmd.PersistentFlags().Lookup("profile") == nil {
+		t.Error("--profile flag not registered")
+	}
 }
 
 // TestRootCommandExecution verifies the command runs without errors.
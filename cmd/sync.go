@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/syncer"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <database> <dir>",
+	Short: "Keep a database and a directory of Markdown files in step",
+	Long: `Sync the rows of a database with a directory of Markdown files, one file
+per row, in both directions.
+
+The database is given by a name from the config file, by ID or by URL.
+The first sync writes every row as a file in the format of ` + "`export`" + `.
+After that, edits to a file are pushed to its page, edits made in Notion
+are pulled into the file, new files become new rows and files of deleted
+rows are removed. The directory keeps a .notion-sync.json state file
+recording what was synced last.
+
+When a page changed on both sides since the last sync, the file is left
+as it is and the Notion version is written beside it as <name>.remote.md.
+Merge it into the file and delete it; the next sync pushes the result.
+
+With --watch the sync runs again every --interval until interrupted.`,
+	Example: `  notion-tui sync tasks ~/notes/tasks
+  notion-tui sync tasks ~/notes/tasks --watch --interval 1m`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().Bool("watch", false, "keep syncing until interrupted")
+	syncCmd.Flags().Duration("interval", 30*time.Second, "time between syncs with --watch")
+
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncInput contains parameters for syncDir.
+type syncInput struct {
+	Client     syncer.Client
+	Cache      syncer.Cache // optional
+	DatabaseID string
+	Dir        string
+	Watch      bool
+	Interval   time.Duration
+	// AfterSync runs after every successful sync, such as to save the
+	// search index.
+	AfterSync func() error
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	id, err := notion.ParseID(resolveDatabase(cfg, args[0]))
+	if err != nil {
+		return err
+	}
+	dir, err := config.ExpandHome(args[1])
+	if err != nil {
+		return err
+	}
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	input := syncInput{
+		Client:     notion.NewClient(cfg.NotionToken),
+		DatabaseID: id,
+		Dir:        dir,
+		Watch:      watch,
+		Interval:   interval,
+	}
+
+	// Pulled pages go into the cache and the search index, as if opened
+	pc, err := openCache(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if pc != nil {
+		defer pc.Close()
+		ix, err := index.Open(ctx, index.OpenInput{Cache: pc})
+		if err != nil {
+			return fmt.Errorf("open search index: %w", err)
+		}
+		input.Cache = pc
+		input.AfterSync = func() error {
+			if err := ix.Save(); err != nil {
+				return fmt.Errorf("save search index: %w", err)
+			}
+			return nil
+		}
+	}
+
+	return syncDir(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), input)
+}
+
+// syncDir implements `sync`. Changes and the summary go to w, warnings to
+// errOut. In watch mode a failed sync is reported and retried at the next
+// interval, and cancelling ctx ends the command without an error.
+func syncDir(ctx context.Context, w, errOut io.Writer, input syncInput) error {
+	s, err := syncer.NewSyncer(syncer.NewSyncerInput{
+		Client:     input.Client,
+		Cache:      input.Cache,
+		Dir:        input.Dir,
+		DatabaseID: input.DatabaseID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := syncOnce(ctx, s, w, errOut, input)
+		if input.Watch && ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("sync interrupted; run it again to finish: %w", err)
+		}
+		if !input.Watch {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(errOut, "%s sync failed: %v\n", time.Now().Format(time.TimeOnly), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(input.Interval):
+		}
+	}
+}
+
+// syncOnce runs one sync and reports it. In watch mode a sync that changed
+// nothing prints nothing.
+func syncOnce(ctx context.Context, s *syncer.Syncer, w, errOut io.Writer, input syncInput) error {
+	result, err := s.Sync(ctx)
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(errOut, "warning: %s\n", warning)
+	}
+	for _, change := range result.Changes {
+		fmt.Fprintf(w, "%-8s  %s\n", change.Action, change.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if input.AfterSync != nil {
+		if err := input.AfterSync(); err != nil {
+			return err
+		}
+	}
+
+	conflicts := result.Count(syncer.ActionConflict)
+	if conflicts > 0 {
+		fmt.Fprintf(errOut, "%d pages changed on both sides: merge each <name>.remote.md into its file and delete it\n", conflicts)
+	}
+	if input.Watch && len(result.Changes) == 0 {
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "Synced %s: %d pulled, %d pushed, %d created, %d removed, %d conflicts\n",
+		input.Dir,
+		result.Count(syncer.ActionPulled),
+		result.Count(syncer.ActionPushed),
+		result.Count(syncer.ActionCreated),
+		result.Count(syncer.ActionRemoved),
+		conflicts)
+	return err
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// newSyncClient returns a mock database with one row, "Renew passport".
+func newSyncClient() *testhelpers.MockNotionClient {
+	mockClient := testhelpers.NewMockNotionClient().
+		WithSchema(testhelpers.NewTestDatabaseSchema("db-inbox"))
+	mockClient.DatabaseToReturn = &notionapi.DatabaseQueryResponse{
+		Results: []notionapi.Page{*testhelpers.NewTestPage("aaaaaaaa-0000-0000-0000-000000000001", "Renew passport")},
+	}
+	mockClient.BlocksToReturn = testhelpers.NewGetChildrenResponse(
+		[]notionapi.Block{testhelpers.NewParagraphBlock("Bring the old one.")})
+	return mockClient
+}
+
+func TestSyncDir(t *testing.T) {
+	dir := t.TempDir()
+	input := syncInput{Client: newSyncClient(), DatabaseID: "db-inbox", Dir: dir}
+
+	var out, errOut bytes.Buffer
+	if err := syncDir(context.Background(), &out, &errOut, input); err != nil {
+		t.Fatalf("syncDir() error = %v", err)
+	}
+
+	want := "pulled    renew-passport-aaaaaaaa.md\n" +
+		"Synced " + dir + ": 1 pulled, 0 pushed, 0 created, 0 removed, 0 conflicts\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "renew-passport-aaaaaaaa.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Bring the old one.") {
+		t.Errorf("file = %q", data)
+	}
+
+	// Nothing changed on either side
+	out.Reset()
+	if err := syncDir(context.Background(), &out, &errOut, input); err != nil {
+		t.Fatalf("syncDir() error = %v", err)
+	}
+	if want := "Synced " + dir + ": 0 pulled, 0 pushed, 0 created, 0 removed, 0 conflicts\n"; out.String() != want {
+		t.Errorf("second output = %q, want %q", out.String(), want)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("errOut = %q", errOut.String())
+	}
+}
+
+func TestSyncDirWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	syncs := 0
+	input := syncInput{
+		Client:     newSyncClient(),
+		DatabaseID: "db-inbox",
+		Dir:        t.TempDir(),
+		Watch:      true,
+		Interval:   time.Millisecond,
+		AfterSync: func() error {
+			syncs++
+			if syncs == 3 {
+				cancel()
+			}
+			return nil
+		},
+	}
+
+	var out, errOut bytes.Buffer
+	if err := syncDir(ctx, &out, &errOut, input); err != nil {
+		t.Fatalf("syncDir() error = %v, want nil when interrupted", err)
+	}
+	if syncs != 3 {
+		t.Errorf("syncs = %d, want 3", syncs)
+	}
+	// Only the first sync changed anything
+	if got := strings.Count(out.String(), "Synced"); got != 1 {
+		t.Errorf("output =\n%s\nwant one summary", out.String())
+	}
+}
+
+func TestSyncDirWatchKeepsGoing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mockClient := newSyncClient()
+	mockClient.GetDatabaseFunc = func(ctx context.Context, id string) (*notionapi.Database, error) {
+		if mockClient.GetDatabaseCallCount() == 2 {
+			cancel()
+		}
+		return nil, testhelpers.ErrNotFound
+	}
+
+	var out, errOut bytes.Buffer
+	err := syncDir(ctx, &out, &errOut, syncInput{
+		Client:     mockClient,
+		DatabaseID: "db-inbox",
+		Dir:        t.TempDir(),
+		Watch:      true,
+		Interval:   time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("syncDir() error = %v", err)
+	}
+	if !strings.Contains(errOut.String(), "sync failed") {
+		t.Errorf("errOut = %q", errOut.String())
+	}
+}
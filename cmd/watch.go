@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/watch"
+)
+
+// hookTimeout bounds how long a watch hook may run.
+const hookTimeout = time.Minute
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [database...]",
+	Short: "Print a feed of page changes as JSON lines",
+	Long: `Poll databases for changes and print each one as a line of JSON.
+
+Databases are given by name from the config file, by ID or by URL; with none
+given every configured database is watched. With --workspace, or
+watch.workspace in the config file, pages edited anywhere in the workspace
+are reported too.
+
+Events have a type of page_created, page_updated or property_changed.
+property_changed events carry the before and after value of each changed
+property:
+
+  {"type":"property_changed","page_id":"...","title":"Plan launch",
+   "database_id":"...","last_edited_time":"...",
+   "changes":{"Status":{"before":"Todo","after":"Done"}}}
+
+The first run records what is there without reporting it. A state file,
+by default in the cache directory, keeps the position between runs, so
+changes made while the command was not running are reported on the next.
+
+Hooks under watch.hooks in the config file run a shell command for each
+matching event, with the event as JSON on stdin and NOTION_EVENT,
+NOTION_PAGE_ID, NOTION_PAGE_TITLE, NOTION_PAGE_URL and NOTION_DATABASE_ID
+set in its environment. Hook output goes to stderr.`,
+	Example: `  notion-tui watch
+  notion-tui watch tasks --interval 5m
+  notion-tui watch --workspace --once | jq -r .title`,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", time.Minute, "time between polls")
+	watchCmd.Flags().Bool("once", false, "poll once and exit")
+	watchCmd.Flags().Bool("workspace", false, "also report pages edited anywhere in the workspace")
+	watchCmd.Flags().String("state", "", "state file, stored unencrypted (default: watch/state.json in the cache directory, encrypted with the cache)")
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchHook is a configured hook with its database resolved to an ID.
+type watchHook struct {
+	Event      watch.EventType // empty matches every event
+	DatabaseID string          // normalized; empty matches every database
+	Command    string
+}
+
+// matches reports whether the hook runs for an event.
+func (h watchHook) matches(event watch.Event) bool {
+	if h.Event != "" && h.Event != event.Type {
+		return false
+	}
+	return h.DatabaseID == "" || h.DatabaseID == notion.NormalizeID(event.DatabaseID)
+}
+
+// watchInput contains parameters for watchFeed.
+type watchInput struct {
+	Client      watch.Client
+	StatePath   string
+	StateCodec  watch.Codec // optional; the state file is written as is when nil
+	DatabaseIDs []string
+	Workspace   bool
+	Hooks       []watchHook
+	Once        bool
+	Interval    time.Duration
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	once, _ := cmd.Flags().GetBool("once")
+	workspace, _ := cmd.Flags().GetBool("workspace")
+
+	// The default state file lives in the cache directory and is sealed like
+	// the search index, so it is encrypted and rekeyed along with the cache.
+	var stateCodec watch.Codec
+	statePath, _ := cmd.Flags().GetString("state")
+	if statePath == "" {
+		pc, err := openCache(commandContext(cmd), cfg)
+		if err != nil {
+			return err
+		}
+		if pc == nil {
+			return fmt.Errorf("caching is disabled: give a state file with --state")
+		}
+		defer pc.Close()
+		statePath = filepath.Join(cfg.CacheDir, "watch", "state.json")
+		stateCodec = pc
+	} else if statePath, err = config.ExpandHome(statePath); err != nil {
+		return err
+	}
+
+	var databaseIDs []string
+	if len(args) == 0 {
+		for _, db := range cfg.Databases {
+			databaseIDs = append(databaseIDs, db.ID)
+		}
+	}
+	for _, arg := range args {
+		id, err := notion.ParseID(resolveDatabase(cfg, arg))
+		if err != nil {
+			return err
+		}
+		databaseIDs = append(databaseIDs, id)
+	}
+
+	hooks, err := watchHooks(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt)
+	defer stop()
+
+	return watchFeed(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), watchInput{
+		Client:      notion.NewClient(cfg.NotionToken),
+		StatePath:   statePath,
+		StateCodec:  stateCodec,
+		DatabaseIDs: databaseIDs,
+		Workspace:   workspace || cfg.Watch.Workspace,
+		Hooks:       hooks,
+		Once:        once,
+		Interval:    interval,
+	})
+}
+
+// watchHooks checks the configured hooks and resolves their databases.
+func watchHooks(cfg *config.Config) ([]watchHook, error) {
+	hooks := make([]watchHook, 0, len(cfg.Watch.Hooks))
+	for i, hook := range cfg.Watch.Hooks {
+		h := watchHook{Event: watch.EventType(hook.Event), Command: hook.Command}
+		if h.Event != "" && !knownEvent(h.Event) {
+			return nil, fmt.Errorf("watch.hooks[%d]: unknown event %q (want one of %v)", i, hook.Event, watch.EventTypes)
+		}
+		if hook.Database != "" {
+			id, err := notion.ParseID(resolveDatabase(cfg, hook.Database))
+			if err != nil {
+				return nil, fmt.Errorf("watch.hooks[%d]: %w", i, err)
+			}
+			h.DatabaseID = notion.NormalizeID(id)
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, nil
+}
+
+// knownEvent reports whether t is an event type the watcher emits.
+func knownEvent(t watch.EventType) bool {
+	for _, known := range watch.EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// watchFeed implements `watch`. Events go to w as JSON lines; failed polls
+// and hooks are reported on errOut and retried or skipped, so a long-running
+// watch survives network trouble. With Once a failed poll is the command's
+// error. Cancelling ctx ends the command without an error.
+func watchFeed(ctx context.Context, w, errOut io.Writer, input watchInput) error {
+	watcher, err := watch.NewWatcher(watch.NewWatcherInput{
+		Client:      input.Client,
+		StatePath:   input.StatePath,
+		Codec:       input.StateCodec,
+		DatabaseIDs: input.DatabaseIDs,
+		Workspace:   input.Workspace,
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	for {
+		events, err := watcher.Poll(ctx)
+		for _, event := range events {
+			if err := enc.Encode(event); err != nil {
+				return fmt.Errorf("write event: %w", err)
+			}
+			runHooks(ctx, errOut, input.Hooks, event)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if input.Once {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(errOut, "%s watch failed: %v\n", time.Now().Format(time.TimeOnly), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(input.Interval):
+		}
+	}
+}
+
+// runHooks runs the hooks matching an event, one after another. A failing
+// hook is reported and does not stop the others.
+func runHooks(ctx context.Context, errOut io.Writer, hooks []watchHook, event watch.Event) {
+	for _, hook := range hooks {
+		if !hook.matches(event) {
+			continue
+		}
+		if err := runHook(ctx, errOut, hook.Command, event); err != nil {
+			fmt.Fprintf(errOut, "hook %q failed for %s: %v\n", hook.Command, event.PageID, err)
+		}
+	}
+}
+
+// runHook runs a hook command with the event on stdin and in its environment.
+func runHook(ctx context.Context, errOut io.Writer, command string, event watch.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	cmd.Stdout = errOut
+	cmd.Stderr = errOut
+	cmd.Env = append(os.Environ(),
+		"NOTION_EVENT="+string(event.Type),
+		"NOTION_PAGE_ID="+event.PageID,
+		"NOTION_PAGE_TITLE="+event.Title,
+		"NOTION_PAGE_URL="+event.URL,
+		"NOTION_DATABASE_ID="+event.DatabaseID,
+	)
+	return cmd.Run()
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+	"github.com/Panandika/notion-tui/internal/watch"
+)
+
+// newWatchClient returns a client for a database holding one row whose
+// status the test sets.
+func newWatchClient(status *string, edited *time.Time) *testhelpers.MockNotionClient {
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		page := testhelpers.NewTestPage("page-1", "Plan launch")
+		page.CreatedTime = time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+		page.LastEditedTime = *edited
+		page.Properties["Status"] = &notionapi.StatusProperty{Status: notionapi.Status{Name: *status}}
+		return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{*page}}, nil
+	}
+	return mockClient
+}
+
+func TestWatchFeed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run with sh")
+	}
+
+	dir := t.TempDir()
+	hookLog := filepath.Join(dir, "hook.log")
+	status := "Todo"
+	edited := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	input := watchInput{
+		Client:      newWatchClient(&status, &edited),
+		StatePath:   filepath.Join(dir, "state.json"),
+		DatabaseIDs: []string{"db-1"},
+		Hooks: []watchHook{
+			{Event: watch.EventPropertyChanged, Command: `echo "$NOTION_EVENT $NOTION_PAGE_ID $NOTION_DATABASE_ID" >> ` + hookLog + `; cat >> ` + hookLog},
+			{Event: watch.EventPageCreated, Command: `echo created >> ` + hookLog},
+			{Command: `exit 3`},
+		},
+		Once: true,
+	}
+
+	var out, errOut bytes.Buffer
+	if err := watchFeed(context.Background(), &out, &errOut, input); err != nil {
+		t.Fatalf("first watchFeed() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("the first run printed %q, want nothing", out.String())
+	}
+
+	status = "Done"
+	edited = edited.Add(2 * time.Minute)
+	if err := watchFeed(context.Background(), &out, &errOut, input); err != nil {
+		t.Fatalf("second watchFeed() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d events, want 1:\n%s", len(lines), out.String())
+	}
+	var event watch.Event
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("event %q: %v", lines[0], err)
+	}
+	if event.Type != watch.EventPropertyChanged || event.Changes["Status"] != (watch.Change{Before: "Todo", After: "Done"}) {
+		t.Errorf("event = %+v", event)
+	}
+
+	log, err := os.ReadFile(hookLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(log), "property_changed page-1 db-1\n{") || strings.Contains(string(log), "created") {
+		t.Errorf("hook log = %q", log)
+	}
+	if !strings.Contains(errOut.String(), `hook "exit 3" failed for page-1: exit status 3`) {
+		t.Errorf("errOut = %q, want the failing hook reported", errOut.String())
+	}
+}
+
+func TestWatchHooks(t *testing.T) {
+	cfg := &config.Config{
+		Databases: []config.DatabaseConfig{{ID: "0123456789abcdef0123456789abcdef", Name: "Tasks"}},
+		Watch: config.WatchConfig{Hooks: []config.WatchHook{
+			{Event: "property_changed", Database: "tasks", Command: "true"},
+		}},
+	}
+	hooks, err := watchHooks(cfg)
+	if err != nil {
+		t.Fatalf("watchHooks() error = %v", err)
+	}
+	if hooks[0].DatabaseID != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("DatabaseID = %q", hooks[0].DatabaseID)
+	}
+	if hooks[0].matches(watch.Event{Type: watch.EventPropertyChanged, DatabaseID: "other"}) {
+		t.Error("hook matched an event of another database")
+	}
+
+	cfg.Watch.Hooks[0].Event = "page_deleted"
+	if _, err := watchHooks(cfg); err == nil || !strings.Contains(err.Error(), `unknown event "page_deleted"`) {
+		t.Errorf("watchHooks() error = %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+// removeTempFiles deletes writes left behind by a crashed process.
+// Callers must hold the exclusive file lock.
+func removeTempFiles(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read cache directory %s: %w", dir, err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), fsutil.TempSuffix) {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove stale temp file %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -1,27 +1,80 @@
 package cache
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+	"github.com/Panandika/notion-tui/internal/logging"
 )
 
+// DefaultPageTTL is how long fetched page content stays fresh in the cache.
+const DefaultPageTTL = time.Hour
+
+// quarantineDirName is the subdirectory corrupt entries are moved to.
+const quarantineDirName = "quarantine"
+
+// ErrCorrupt is returned for cache entries that fail integrity checks.
+// Such entries are moved to the quarantine directory and treated as misses.
+var ErrCorrupt = errors.New("cache entry is corrupt")
+
+// metaKeyPrefix distinguishes page metadata entries from block list entries.
+const metaKeyPrefix = "meta:"
+
+// MetaKey returns the cache key under which a page's metadata is stored.
+// The page's block list is stored under the page ID itself.
+func MetaKey(pageID string) string {
+	return metaKeyPrefix + pageID
+}
+
+// IsMetaKey reports whether a cache key refers to page metadata.
+func IsMetaKey(key string) bool {
+	return strings.HasPrefix(key, metaKeyPrefix)
+}
+
 // PageCache provides file-based caching for Notion pages with TTL support.
+// It is safe for concurrent use by multiple goroutines and, through an advisory
+// file lock, by multiple processes sharing the cache directory.
 type PageCache struct {
-	cacheDir string
-	mu       sync.Mutex
-	stats    CacheStats
+	cacheDir  string
+	mu        sync.Mutex
+	flock     *fileLock
+	stats     CacheStats
+	observers []Observer
+	sealer    *sealer // nil when entries are stored in plaintext
+	logger    *slog.Logger
+}
+
+// Observer is notified after cache entries are written or removed.
+// It lets other subsystems, such as the search index, follow the cache
+// without the cache depending on them. Observers must not call back into
+// the cache from these methods.
+type Observer interface {
+	EntryWritten(pageID string, data json.RawMessage)
+	EntryDeleted(pageID string)
 }
 
 // NewPageCacheInput contains the parameters for creating a new PageCache.
 type NewPageCacheInput struct {
 	Dir string
+	// Key enables encryption at rest. Existing plaintext entries are encrypted
+	// when a key is first given. An encrypted cache cannot be opened without
+	// its key: ErrKeyRequired or ErrWrongKey is returned instead.
+	Key *Key
+	// Logger receives hits, misses and evictions at debug level; optional,
+	// slog.Default() when nil.
+	Logger *slog.Logger
 }
 
 // NewPageCache creates a new PageCache instance and ensures the cache directory exists.
@@ -34,10 +87,65 @@ func NewPageCache(input NewPageCacheInput) (*PageCache, error) {
 		return nil, fmt.Errorf("create cache directory %s: %w", input.Dir, err)
 	}
 
-	return &PageCache{
+	flock, err := openFileLock(filepath.Join(input.Dir, lockFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &PageCache{
 		cacheDir: input.Dir,
+		flock:    flock,
 		stats:    CacheStats{},
-	}, nil
+		logger:   input.Logger,
+	}
+	if c.logger == nil {
+		c.logger = slog.Default()
+	}
+
+	if err := c.init(input.Key); err != nil {
+		flock.close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// init cleans up after crashed writers and sets up encryption while holding
+// the exclusive file lock, so concurrent starts agree on the key.
+func (c *PageCache) init(key *Key) error {
+	if err := c.flock.lock(); err != nil {
+		return err
+	}
+	defer c.flock.unlock()
+
+	if err := removeTempFiles(c.cacheDir); err != nil {
+		return err
+	}
+
+	s, migrate, err := loadSealer(c.cacheDir, key)
+	if err != nil {
+		return err
+	}
+
+	if !migrate {
+		c.sealer = s
+		return nil
+	}
+	if err := c.rekeyLocked(context.Background(), s); err != nil {
+		return fmt.Errorf("encrypt existing cache entries: %w", err)
+	}
+	return nil
+}
+
+// Close releases the cache's lock file. The cache must not be used afterwards.
+func (c *PageCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flock.close(); err != nil {
+		return fmt.Errorf("close cache lock: %w", err)
+	}
+	return nil
 }
 
 // Get retrieves cached data for a given page ID.
@@ -50,25 +158,26 @@ func (c *PageCache) Get(ctx context.Context, pageID string) (interface{}, error)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	cachePath := makeCachePath(c.cacheDir, pageID)
-
-	data, err := os.ReadFile(cachePath)
+	data, err := c.readFileLocked(pageID)
 	if err != nil {
 		if os.IsNotExist(err) {
 			c.stats.MissCount++
+			c.logger.DebugContext(ctx, "cache miss", logging.KeyPageID, pageID, "reason", "absent")
 			return nil, fmt.Errorf("cache miss for page %s: %w", pageID, err)
 		}
-		return nil, fmt.Errorf("read cache file %s: %w", cachePath, err)
+		return nil, err
 	}
 
-	var entry CacheEntry
-	if err := entry.Unmarshal(data); err != nil {
+	entry, err := c.checkEntryLocked(pageID, data)
+	if err != nil {
 		c.stats.MissCount++
-		return nil, fmt.Errorf("unmarshal cache entry for page %s: %w", pageID, err)
+		c.logger.DebugContext(ctx, "cache miss", logging.KeyPageID, pageID, "reason", "unreadable", "error", err)
+		return nil, fmt.Errorf("read cache entry for page %s: %w", pageID, err)
 	}
 
 	if c.IsExpired(&entry) {
 		c.stats.MissCount++
+		c.logger.DebugContext(ctx, "cache miss", logging.KeyPageID, pageID, "reason", "expired")
 		return nil, fmt.Errorf("cache entry expired for page %s", pageID)
 	}
 
@@ -78,9 +187,32 @@ func (c *PageCache) Get(ctx context.Context, pageID string) (interface{}, error)
 	}
 
 	c.stats.HitCount++
+	c.logger.DebugContext(ctx, "cache hit", logging.KeyPageID, pageID)
 	return result, nil
 }
 
+// Has reports whether an unexpired entry exists for the given key.
+// Unlike Get it does not decode the data or affect hit/miss statistics.
+func (c *PageCache) Has(ctx context.Context, pageID string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := c.readFileLocked(pageID)
+	if err != nil {
+		return false
+	}
+
+	entry, err := c.checkEntryLocked(pageID, data)
+	if err != nil {
+		return false
+	}
+	return !c.IsExpired(&entry)
+}
+
 // SetInput contains the parameters for caching data.
 type SetInput struct {
 	PageID string
@@ -118,14 +250,29 @@ func (c *PageCache) Set(ctx context.Context, input SetInput) error {
 		return fmt.Errorf("marshal cache entry for page %s: %w", input.PageID, err)
 	}
 
+	if c.sealer != nil {
+		if entryBytes, err = c.sealer.seal(input.PageID, entryBytes); err != nil {
+			return fmt.Errorf("encrypt cache entry for page %s: %w", input.PageID, err)
+		}
+	}
+
 	cachePath := makeCachePath(c.cacheDir, input.PageID)
 
-	if err := os.WriteFile(cachePath, entryBytes, 0600); err != nil {
+	if err := c.flock.lock(); err != nil {
+		return err
+	}
+	err = fsutil.WriteFile(cachePath, entryBytes, 0600)
+	c.flock.unlock()
+	if err != nil {
 		return fmt.Errorf("write cache file %s: %w", cachePath, err)
 	}
 
 	c.stats.Size += int64(len(entryBytes))
 
+	for _, o := range c.observers {
+		o.EntryWritten(input.PageID, entry.Data)
+	}
+
 	return nil
 }
 
@@ -136,6 +283,11 @@ func (c *PageCache) Delete(pageID string) error {
 
 	cachePath := makeCachePath(c.cacheDir, pageID)
 
+	if err := c.flock.lock(); err != nil {
+		return err
+	}
+	defer c.flock.unlock()
+
 	info, err := os.Stat(cachePath)
 	if err == nil {
 		c.stats.Size -= info.Size()
@@ -147,6 +299,11 @@ func (c *PageCache) Delete(pageID string) error {
 		}
 		return fmt.Errorf("delete cache file %s: %w", cachePath, err)
 	}
+	c.logger.Debug("cache evict", logging.KeyPageID, pageID, "reason", "deleted")
+
+	for _, o := range c.observers {
+		o.EntryDeleted(pageID)
+	}
 
 	return nil
 }
@@ -156,6 +313,11 @@ func (c *PageCache) Clear() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if err := c.flock.lock(); err != nil {
+		return err
+	}
+	defer c.flock.unlock()
+
 	entries, err := os.ReadDir(c.cacheDir)
 	if err != nil {
 		return fmt.Errorf("read cache directory %s: %w", c.cacheDir, err)
@@ -174,6 +336,12 @@ func (c *PageCache) Clear() error {
 		if err := os.Remove(path); err != nil {
 			return fmt.Errorf("remove cache file %s: %w", path, err)
 		}
+
+		if pageID, ok := pageIDFromFileName(entry.Name()); ok {
+			for _, o := range c.observers {
+				o.EntryDeleted(pageID)
+			}
+		}
 	}
 
 	c.stats.Size = 0
@@ -181,6 +349,367 @@ func (c *PageCache) Clear() error {
 	return nil
 }
 
+// AddObserver registers an observer for cache writes and deletions.
+func (c *PageCache) AddObserver(o Observer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observers = append(c.observers, o)
+}
+
+// Dir returns the directory the cache stores its entries in.
+func (c *PageCache) Dir() string {
+	return c.cacheDir
+}
+
+// Entries returns every entry stored in the cache directory, including expired ones.
+// Unreadable or malformed files are skipped. It does not affect hit/miss statistics.
+func (c *PageCache) Entries(ctx context.Context) ([]CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context error: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, _, err := c.scanLocked(ctx)
+	return entries, err
+}
+
+// Verify checks the integrity of every cache entry, moves corrupt entries to
+// the quarantine directory and returns their page IDs.
+func (c *PageCache) Verify(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context error: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, quarantined, err := c.scanLocked(ctx)
+	return quarantined, err
+}
+
+// scanLocked reads every entry under a shared file lock, then quarantines the
+// corrupt ones. Unreadable entries are skipped. Callers must hold c.mu.
+func (c *PageCache) scanLocked(ctx context.Context) ([]CacheEntry, []string, error) {
+	type corruptFile struct {
+		pageID string
+		data   []byte
+	}
+
+	if err := c.flock.rlock(); err != nil {
+		return nil, nil, err
+	}
+
+	files, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		c.flock.unlock()
+		return nil, nil, fmt.Errorf("read cache directory %s: %w", c.cacheDir, err)
+	}
+
+	entries := make([]CacheEntry, 0, len(files))
+	var corrupt []corruptFile
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			c.flock.unlock()
+			return nil, nil, fmt.Errorf("context error: %w", err)
+		}
+
+		if file.IsDir() {
+			continue
+		}
+		pageID, ok := pageIDFromFileName(file.Name())
+		if !ok {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(c.cacheDir, file.Name()))
+		if err != nil {
+			continue
+		}
+
+		entry, err := c.decodeEntryLocked(pageID, data)
+		if errors.Is(err, ErrCorrupt) {
+			corrupt = append(corrupt, corruptFile{pageID: pageID, data: data})
+		}
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	c.flock.unlock()
+
+	var quarantined []string
+	for _, f := range corrupt {
+		if c.quarantineLocked(f.pageID, f.data) {
+			quarantined = append(quarantined, f.pageID)
+		}
+	}
+
+	return entries, quarantined, nil
+}
+
+// Entry returns the stored entry for a key, even if it has expired.
+// It does not affect hit/miss statistics. Corrupt entries are quarantined.
+func (c *PageCache) Entry(ctx context.Context, pageID string) (CacheEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return CacheEntry{}, fmt.Errorf("context error: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := c.readFileLocked(pageID)
+	if err != nil {
+		return CacheEntry{}, fmt.Errorf("read cache entry %s: %w", pageID, err)
+	}
+
+	entry, err := c.checkEntryLocked(pageID, data)
+	if err != nil {
+		return CacheEntry{}, fmt.Errorf("read cache entry %s: %w", pageID, err)
+	}
+	return entry, nil
+}
+
+// Prune removes expired entries and returns their keys.
+// Undecodable entries are left for Verify to quarantine.
+func (c *PageCache) Prune(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context error: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flock.lock(); err != nil {
+		return nil, err
+	}
+	defer c.flock.unlock()
+
+	files, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("read cache directory %s: %w", c.cacheDir, err)
+	}
+
+	var pruned []string
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return pruned, fmt.Errorf("context error: %w", err)
+		}
+
+		if file.IsDir() {
+			continue
+		}
+		pageID, ok := pageIDFromFileName(file.Name())
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(c.cacheDir, file.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		entry, err := c.decodeEntryLocked(pageID, data)
+		if err != nil || !c.IsExpired(&entry) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return pruned, fmt.Errorf("remove cache file %s: %w", path, err)
+		}
+		c.stats.Size -= int64(len(data))
+		pruned = append(pruned, pageID)
+		c.logger.DebugContext(ctx, "cache evict", logging.KeyPageID, pageID, "reason", "expired")
+
+		for _, o := range c.observers {
+			o.EntryDeleted(pageID)
+		}
+	}
+
+	return pruned, nil
+}
+
+// PurgeQuarantine deletes all quarantined files and returns how many were removed.
+func (c *PageCache) PurgeQuarantine() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flock.lock(); err != nil {
+		return 0, err
+	}
+	defer c.flock.unlock()
+
+	dir := filepath.Join(c.cacheDir, quarantineDirName)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read quarantine directory %s: %w", dir, err)
+	}
+
+	removed := 0
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name())
+		if err := os.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("remove quarantined file %s: %w", path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// DiskUsage reports how many entries the cache directory holds and their size.
+func (c *PageCache) DiskUsage(ctx context.Context) (DiskUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return DiskUsage{}, fmt.Errorf("context error: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flock.rlock(); err != nil {
+		return DiskUsage{}, err
+	}
+	defer c.flock.unlock()
+
+	files, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		return DiskUsage{}, fmt.Errorf("read cache directory %s: %w", c.cacheDir, err)
+	}
+
+	var usage DiskUsage
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		pageID, ok := pageIDFromFileName(file.Name())
+		if !ok {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(c.cacheDir, file.Name()))
+		if err != nil {
+			continue
+		}
+		usage.Bytes += int64(len(data))
+
+		entry, err := c.decodeEntryLocked(pageID, data)
+		if err != nil {
+			usage.Unreadable++
+			continue
+		}
+		usage.Entries++
+		if c.IsExpired(&entry) {
+			usage.Expired++
+		}
+	}
+
+	if quarantined, err := os.ReadDir(filepath.Join(c.cacheDir, quarantineDirName)); err == nil {
+		usage.Quarantined = len(quarantined)
+	}
+
+	return usage, nil
+}
+
+// Encrypted reports whether cache entries are encrypted at rest.
+func (c *PageCache) Encrypted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sealer != nil
+}
+
+// Rotate re-encrypts every cache entry, and the companion files sealed with
+// Seal, under a new key derived from key. A nil key decrypts the cache and
+// turns encryption off.
+//
+// The rotation is recorded before any entry is rewritten. When it is
+// interrupted, both keys open the cache, and calling Rotate again with the
+// same new key on a cache opened with the old key finishes it. Corrupt
+// entries are quarantined; an entry sealed with neither key stops the
+// rotation rather than being lost.
+func (c *PageCache) Rotate(ctx context.Context, key *Key) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flock.lock(); err != nil {
+		return err
+	}
+	defer c.flock.unlock()
+
+	next, err := c.rotationTargetLocked(key)
+	if err != nil {
+		return err
+	}
+	return c.rekeyLocked(ctx, next)
+}
+
+// rotationTargetLocked returns the sealer a rotation to key writes with:
+// that of an interrupted rotation when one is pending, or a new one.
+// Callers must hold c.mu and the exclusive file lock.
+func (c *PageCache) rotationTargetLocked(key *Key) (*sealer, error) {
+	pending, err := readPendingRotation(c.cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case pending == nil:
+		if key == nil {
+			return nil, nil
+		}
+		return newSealer(key)
+	case pending.Next == nil:
+		if key != nil {
+			return nil, errors.New("an interrupted rotation is decrypting the cache; finish it by decrypting again")
+		}
+		return nil, nil
+	case key == nil:
+		return nil, errors.New("an interrupted rotation is re-encrypting the cache; finish it with the same new key")
+	default:
+		next, err := openSealer(key, *pending.Next)
+		if err != nil {
+			return nil, fmt.Errorf("an interrupted rotation is re-encrypting the cache; finish it with the same new key: %w", err)
+		}
+		return next, nil
+	}
+}
+
+// Seal prepares a companion file, such as the search index, for storage in the
+// cache directory. It encrypts data when the cache is encrypted and returns it
+// unchanged otherwise. The name must be passed to Unseal unchanged.
+func (c *PageCache) Seal(name string, data []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sealer == nil {
+		return data, nil
+	}
+	return c.sealer.seal(name, data)
+}
+
+// Unseal reverses Seal. It refuses plaintext data while the cache is encrypted
+// so stale unencrypted companion files are replaced rather than trusted.
+func (c *PageCache) Unseal(name string, data []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, encrypted := parseEnvelope(data)
+	switch {
+	case encrypted && c.sealer == nil:
+		return nil, ErrKeyRequired
+	case encrypted:
+		return c.sealer.open(name, data)
+	case c.sealer != nil:
+		return nil, fmt.Errorf("%s is not encrypted", name)
+	default:
+		return data, nil
+	}
+}
+
 // Stats returns the current cache statistics.
 func (c *PageCache) Stats() CacheStats {
 	c.mu.Lock()
@@ -197,8 +726,274 @@ func (c *PageCache) IsExpired(entry *CacheEntry) bool {
 	return time.Since(entry.Timestamp) > entry.TTL
 }
 
+// decodeEntryLocked decrypts, if needed, and parses a cache file.
+// Callers must hold the lock.
+func (c *PageCache) decodeEntryLocked(pageID string, data []byte) (CacheEntry, error) {
+	if _, ok := parseEnvelope(data); ok {
+		if c.sealer == nil {
+			return CacheEntry{}, ErrKeyRequired
+		}
+
+		plaintext, err := c.sealer.open(pageID, data)
+		if err != nil {
+			return CacheEntry{}, fmt.Errorf("decrypt: %w", err)
+		}
+		data = plaintext
+	}
+
+	var entry CacheEntry
+	if err := entry.Unmarshal(data); err != nil {
+		return CacheEntry{}, fmt.Errorf("%w: unmarshal: %w", ErrCorrupt, err)
+	}
+	if entry.PageID != pageID {
+		return CacheEntry{}, fmt.Errorf("%w: stored under %q", ErrCorrupt, entry.PageID)
+	}
+	if entry.Hash != "" {
+		sum := sha256.Sum256(entry.Data)
+		if hex.EncodeToString(sum[:]) != entry.Hash {
+			return CacheEntry{}, fmt.Errorf("%w: hash mismatch", ErrCorrupt)
+		}
+	}
+	return entry, nil
+}
+
+// readFileLocked reads the raw cache file for pageID under a shared file lock.
+// Callers must hold c.mu.
+func (c *PageCache) readFileLocked(pageID string) ([]byte, error) {
+	if err := c.flock.rlock(); err != nil {
+		return nil, err
+	}
+	defer c.flock.unlock()
+
+	return os.ReadFile(makeCachePath(c.cacheDir, pageID))
+}
+
+// checkEntryLocked decodes a cache file, quarantining it when corrupt.
+// Callers must hold c.mu.
+func (c *PageCache) checkEntryLocked(pageID string, data []byte) (CacheEntry, error) {
+	entry, err := c.decodeEntryLocked(pageID, data)
+	if errors.Is(err, ErrCorrupt) {
+		c.quarantineLocked(pageID, data)
+	}
+	return entry, err
+}
+
+// quarantineLocked moves a corrupt cache file out of the way, unless another
+// process replaced it after data was read. It reports whether the file was moved.
+// Callers must hold c.mu but not the file lock.
+func (c *PageCache) quarantineLocked(pageID string, data []byte) bool {
+	if err := c.flock.lock(); err != nil {
+		return false
+	}
+	defer c.flock.unlock()
+
+	return c.quarantineFileLocked(pageID, data)
+}
+
+// quarantineFileLocked is quarantineLocked for callers that hold c.mu and the
+// exclusive file lock.
+func (c *PageCache) quarantineFileLocked(pageID string, data []byte) bool {
+	path := makeCachePath(c.cacheDir, pageID)
+	current, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(current, data) {
+		return false
+	}
+
+	dir := filepath.Join(c.cacheDir, quarantineDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return false
+	}
+	dest := filepath.Join(dir, fmt.Sprintf("%s.%d", filepath.Base(path), time.Now().UnixNano()))
+	if err := os.Rename(path, dest); err != nil {
+		return false
+	}
+
+	c.stats.Quarantined++
+	c.logger.Warn("cache evict", logging.KeyPageID, pageID, "reason", "corrupt", "moved_to", dest)
+	for _, o := range c.observers {
+		o.EntryDeleted(pageID)
+	}
+	return true
+}
+
+// rekeyLocked rewrites every entry and companion file with next, which may be
+// nil for plaintext, and then records next as the cache's key. The rotation is
+// recorded first, so an interruption leaves a cache that both keys open and
+// that another call with the same next finishes: entries already sealed with
+// next are skipped. Callers must hold c.mu and the exclusive file lock.
+func (c *PageCache) rekeyLocked(ctx context.Context, next *sealer) error {
+	if err := writePendingRotation(c.cacheDir, next); err != nil {
+		return err
+	}
+
+	files, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		return fmt.Errorf("read cache directory %s: %w", c.cacheDir, err)
+	}
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("context error: %w", err)
+		}
+
+		if file.IsDir() {
+			continue
+		}
+		pageID, ok := pageIDFromFileName(file.Name())
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(c.cacheDir, file.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read cache file %s: %w", path, err)
+		}
+
+		if sealedWith(data, next) {
+			continue
+		}
+
+		entry, err := c.decodeEntryLocked(pageID, data)
+		switch {
+		case errors.Is(err, ErrCorrupt):
+			c.quarantineFileLocked(pageID, data)
+			continue
+		case err != nil:
+			return fmt.Errorf("read cache entry for page %s: %w", pageID, err)
+		}
+
+		out, err := entry.Marshal()
+		if err != nil {
+			return fmt.Errorf("marshal cache entry for page %s: %w", pageID, err)
+		}
+		if next != nil {
+			if out, err = next.seal(pageID, out); err != nil {
+				return fmt.Errorf("encrypt cache entry for page %s: %w", pageID, err)
+			}
+		}
+
+		if err := fsutil.WriteFile(path, out, 0600); err != nil {
+			return fmt.Errorf("write cache file %s: %w", path, err)
+		}
+	}
+
+	if err := c.resealCompanionsLocked(next); err != nil {
+		return err
+	}
+
+	if err := writeEncryptionInfo(c.cacheDir, next); err != nil {
+		return err
+	}
+	c.sealer = next
+	return removePendingRotation(c.cacheDir)
+}
+
+// plaintextCompanions are the companion files, relative to the cache
+// directory, that hold no page content and are read without Unseal. They are
+// never sealed.
+var plaintextCompanions = map[string]bool{
+	filepath.Join("prefetch", "history.json"): true,
+}
+
+// resealCompanionsLocked rewrites the companion files kept in subdirectories
+// of the cache directory with next, sealing them under their name as Seal
+// does. Files the current key cannot open are left alone: their owners
+// rebuild them. Plaintext files are sealed too when next is set, except for
+// plaintextCompanions, and the plaintext copies of corrupt entries in the
+// quarantine directory are removed, so turning encryption on leaves no page
+// content in plaintext. Callers must hold c.mu and the exclusive file lock.
+func (c *PageCache) resealCompanionsLocked(next *sealer) error {
+	dirs, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		return fmt.Errorf("read cache directory %s: %w", c.cacheDir, err)
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		sub := filepath.Join(c.cacheDir, dir.Name())
+		files, err := os.ReadDir(sub)
+		if err != nil {
+			return fmt.Errorf("read cache directory %s: %w", sub, err)
+		}
+
+		for _, file := range files {
+			rel := filepath.Join(dir.Name(), file.Name())
+			if !file.Type().IsRegular() || strings.HasSuffix(file.Name(), fsutil.TempSuffix) || plaintextCompanions[rel] {
+				continue
+			}
+			path := filepath.Join(sub, file.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read companion file %s: %w", path, err)
+			}
+			_, sealed := parseEnvelope(data)
+
+			if dir.Name() == quarantineDirName {
+				// Corrupt entries cannot be carried over to the new key
+				if !sealed && next != nil {
+					if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+						return fmt.Errorf("remove quarantined file %s: %w", path, err)
+					}
+				}
+				continue
+			}
+
+			out := data
+			switch {
+			case sealedWith(data, next):
+				continue
+			case sealed:
+				if c.sealer == nil {
+					continue
+				}
+				if out, err = c.sealer.open(file.Name(), data); err != nil {
+					continue
+				}
+			case next == nil:
+				continue
+			}
+
+			if next != nil {
+				if out, err = next.seal(file.Name(), out); err != nil {
+					return fmt.Errorf("encrypt companion file %s: %w", path, err)
+				}
+			}
+			if err := fsutil.WriteFile(path, out, 0600); err != nil {
+				return fmt.Errorf("write companion file %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sealedWith reports whether data is an envelope sealed with s. It is false
+// for a nil s.
+func sealedWith(data []byte, s *sealer) bool {
+	if s == nil {
+		return false
+	}
+	env, ok := parseEnvelope(data)
+	return ok && env.KeyID == s.keyID
+}
+
 // makeCachePath generates the file path for a cached page.
 func makeCachePath(dir, pageID string) string {
 	safeID := hex.EncodeToString([]byte(pageID))
 	return filepath.Join(dir, safeID+".json")
 }
+
+// pageIDFromFileName reverses makeCachePath for a bare file name.
+func pageIDFromFileName(name string) (string, bool) {
+	if filepath.Ext(name) != ".json" {
+		return "", false
+	}
+
+	raw, err := hex.DecodeString(name[:len(name)-len(".json")])
+	if err != nil {
+		return "", false
+	}
+	return string(raw), true
+}
@@ -1,8 +1,10 @@
 package cache
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
@@ -229,6 +231,30 @@ func TestDelete(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestLogsHitsMissesAndEvictions(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	cache, err := NewPageCache(NewPageCacheInput{
+		Dir:    t.TempDir(),
+		Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = cache.Get(ctx, "page-1")
+	require.Error(t, err)
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "page-1", Data: "a", TTL: time.Hour}))
+	_, err = cache.Get(ctx, "page-1")
+	require.NoError(t, err)
+	require.NoError(t, cache.Delete("page-1"))
+
+	logged := buf.String()
+	assert.Contains(t, logged, `msg="cache miss" page_id=page-1 reason=absent`)
+	assert.Contains(t, logged, `msg="cache hit" page_id=page-1`)
+	assert.Contains(t, logged, `msg="cache evict" page_id=page-1 reason=deleted`)
+}
+
 func TestClear(t *testing.T) {
 	t.Parallel()
 
@@ -457,3 +483,207 @@ func TestIsExpired(t *testing.T) {
 		})
 	}
 }
+
+// recordingObserver records observer notifications for tests.
+type recordingObserver struct {
+	mu      sync.Mutex
+	written map[string]string
+	deleted []string
+}
+
+func (r *recordingObserver) EntryWritten(pageID string, data json.RawMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.written == nil {
+		r.written = make(map[string]string)
+	}
+	r.written[pageID] = string(data)
+}
+
+func (r *recordingObserver) EntryDeleted(pageID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, pageID)
+}
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewPageCache(NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	obs := &recordingObserver{}
+	cache.AddObserver(obs)
+
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "page-1", Data: map[string]string{"k": "v"}}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "page-2", Data: "two"}))
+	assert.Equal(t, map[string]string{"page-1": `{"k":"v"}`, "page-2": `"two"`}, obs.written)
+
+	require.NoError(t, cache.Delete("page-1"))
+	assert.Equal(t, []string{"page-1"}, obs.deleted)
+
+	// Deleting a missing entry does not notify.
+	require.NoError(t, cache.Delete("page-1"))
+	assert.Equal(t, []string{"page-1"}, obs.deleted)
+
+	require.NoError(t, cache.Clear())
+	assert.Equal(t, []string{"page-1", "page-2"}, obs.deleted)
+}
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, dir, cache.Dir())
+
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "fresh", Data: "a", TTL: time.Hour}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "stale", Data: "b", TTL: time.Nanosecond}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "index"), 0700))
+
+	time.Sleep(time.Millisecond)
+
+	entries, err := cache.Entries(ctx)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.PageID)
+	}
+	assert.ElementsMatch(t, []string{"fresh", "stale"}, ids)
+
+	// Listing entries does not count as hits or misses.
+	stats := cache.Stats()
+	assert.Zero(t, stats.HitCount)
+	assert.Zero(t, stats.MissCount)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = cache.Entries(cancelled)
+	assert.Error(t, err)
+}
+
+func TestHas(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewPageCache(NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "fresh", Data: "a", TTL: time.Hour}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "stale", Data: "b", TTL: time.Nanosecond}))
+	time.Sleep(time.Millisecond)
+
+	assert.True(t, cache.Has(ctx, "fresh"))
+	assert.False(t, cache.Has(ctx, "stale"))
+	assert.False(t, cache.Has(ctx, "missing"))
+
+	stats := cache.Stats()
+	assert.Zero(t, stats.HitCount)
+	assert.Zero(t, stats.MissCount)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, cache.Has(cancelled, "fresh"))
+}
+
+func TestMetaKey(t *testing.T) {
+	t.Parallel()
+
+	key := MetaKey("page-1")
+	assert.NotEqual(t, "page-1", key)
+	assert.True(t, IsMetaKey(key))
+	assert.False(t, IsMetaKey("page-1"))
+}
+
+func TestEntry(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewPageCache(NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "stale", Data: "old", TTL: time.Nanosecond}))
+	time.Sleep(time.Millisecond)
+
+	// Expired entries are still returned
+	entry, err := cache.Entry(ctx, "stale")
+	require.NoError(t, err)
+	assert.Equal(t, "stale", entry.PageID)
+	assert.JSONEq(t, `"old"`, string(entry.Data))
+	assert.True(t, cache.IsExpired(&entry))
+
+	_, err = cache.Entry(ctx, "missing")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.Zero(t, cache.Stats().HitCount)
+	assert.Zero(t, cache.Stats().MissCount)
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+
+	observer := &recordingObserver{}
+	cache.AddObserver(observer)
+
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "fresh", Data: "a", TTL: time.Hour}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "forever", Data: "b"}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "stale", Data: "c", TTL: time.Nanosecond}))
+	time.Sleep(time.Millisecond)
+
+	pruned, err := cache.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stale"}, pruned)
+	assert.Contains(t, observer.deleted, "stale")
+
+	entries, err := cache.Entries(ctx)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestDiskUsageAndPurgeQuarantine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "fresh", Data: "a", TTL: time.Hour}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "stale", Data: "b", TTL: time.Nanosecond}))
+	require.NoError(t, cache.Set(ctx, SetInput{PageID: "bad", Data: "c"}))
+	require.NoError(t, os.WriteFile(makeCachePath(dir, "bad"), []byte("{"), 0600))
+	time.Sleep(time.Millisecond)
+
+	usage, err := cache.DiskUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Entries)
+	assert.Equal(t, 1, usage.Expired)
+	assert.Equal(t, 1, usage.Unreadable)
+	assert.Positive(t, usage.Bytes)
+	assert.Zero(t, usage.Quarantined)
+
+	_, err = cache.Verify(ctx)
+	require.NoError(t, err)
+	usage, err = cache.DiskUsage(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Unreadable)
+	assert.Equal(t, 1, usage.Quarantined)
+
+	removed, err := cache.PurgeQuarantine()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	usage, err = cache.DiskUsage(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Quarantined)
+}
@@ -0,0 +1,423 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+const (
+	// encryptionInfoFile stores the key derivation parameters of an encrypted cache.
+	// It has no .json extension so it is never mistaken for a cache entry.
+	encryptionInfoFile = "encryption.info"
+	// rotationFile records the target of a key rotation until every entry is
+	// rewritten, so an interrupted rotation can be resumed.
+	rotationFile = "encryption.info.next"
+	// encryptionVersion is the format version of encrypted files.
+	encryptionVersion = 1
+	// encryptionKDF names the key derivation function recorded in encryption.info.
+	encryptionKDF = "pbkdf2-sha256"
+	// defaultKDFIterations is the PBKDF2 work factor for newly encrypted caches.
+	defaultKDFIterations = 600_000
+	// keyCheckPlaintext is sealed into encryption.info to detect a wrong key up front.
+	keyCheckPlaintext = "notion-tui cache key check"
+	// keyCheckName is the associated data used for the key check.
+	keyCheckName = "encryption.info"
+)
+
+// kdfIterations is the work factor used when encrypting a cache. Tests lower it.
+var kdfIterations = defaultKDFIterations
+
+var (
+	// ErrWrongKey is returned when encrypted cache data cannot be decrypted with
+	// the configured key.
+	ErrWrongKey = errors.New("cache encryption key is wrong")
+	// ErrKeyRequired is returned when an encrypted cache is opened without a key.
+	ErrKeyRequired = errors.New("cache is encrypted but no encryption key is configured")
+)
+
+// Key is the secret from which the cache encryption key is derived.
+// Create one with KeyFromPassphrase, KeyFromFile or KeyFromCommand.
+type Key struct {
+	secret []byte
+}
+
+// KeyFromPassphrase returns a key derived from a passphrase.
+func KeyFromPassphrase(passphrase string) (*Key, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("cache passphrase cannot be empty")
+	}
+	return &Key{secret: []byte(passphrase)}, nil
+}
+
+// KeyFromFile returns a key read from a file. Trailing whitespace is ignored.
+// On Unix the file must not be readable by group or others.
+func KeyFromFile(path string) (*Key, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat key file %s: %w", path, err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("key file %s is accessible by other users (chmod 600 it)", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file %s: %w", path, err)
+	}
+
+	secret := bytes.TrimRight(data, " \t\r\n")
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("key file %s is empty", path)
+	}
+	return &Key{secret: secret}, nil
+}
+
+// KeyFromCommand returns a key printed to stdout by a shell command, such as
+// a password manager lookup. Trailing whitespace is ignored.
+func KeyFromCommand(ctx context.Context, command string) (*Key, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("key command cannot be empty")
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("run key command: %w", err)
+	}
+
+	secret := bytes.TrimRight(out, " \t\r\n")
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("key command printed nothing")
+	}
+	return &Key{secret: secret}, nil
+}
+
+// Secret returns the key material, so that it can be kept out of logs.
+func (k *Key) Secret() string {
+	return string(k.secret)
+}
+
+// encryptionInfo is the content of encryption.info.
+type encryptionInfo struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	KeyID      string `json:"key_id"`
+	Check      []byte `json:"check"`
+}
+
+// pendingRotation is the content of encryption.info.next.
+type pendingRotation struct {
+	Next *encryptionInfo `json:"next"` // nil when the cache is being decrypted
+}
+
+// targets reports whether the rotation leads to the key described by info,
+// or to plaintext when info is nil.
+func (p pendingRotation) targets(info *encryptionInfo) bool {
+	if p.Next == nil || info == nil {
+		return p.Next == nil && info == nil
+	}
+	return p.Next.KeyID == info.KeyID
+}
+
+// envelope is the on-disk form of an encrypted file.
+type envelope struct {
+	Encrypted  int    `json:"encrypted"` // format version; zero means plaintext
+	KeyID      string `json:"key_id"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// sealer encrypts and decrypts files with AES-256-GCM.
+type sealer struct {
+	aead  cipher.AEAD
+	keyID string
+	info  encryptionInfo
+}
+
+// newSealer derives a fresh encryption key from key with a new random salt.
+func newSealer(key *Key) (*sealer, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("generate key id: %w", err)
+	}
+
+	info := encryptionInfo{
+		Version:    encryptionVersion,
+		KDF:        encryptionKDF,
+		Iterations: kdfIterations,
+		Salt:       salt,
+		KeyID:      hex.EncodeToString(id),
+	}
+	s, err := deriveSealer(key, info)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := s.seal(keyCheckName, []byte(keyCheckPlaintext))
+	if err != nil {
+		return nil, err
+	}
+	s.info.Check = check
+	return s, nil
+}
+
+// openSealer derives the encryption key described by info and verifies it
+// against the stored key check.
+func openSealer(key *Key, info encryptionInfo) (*sealer, error) {
+	if info.Version != encryptionVersion || info.KDF != encryptionKDF {
+		return nil, fmt.Errorf("unsupported cache encryption format %d/%s", info.Version, info.KDF)
+	}
+
+	s, err := deriveSealer(key, info)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := s.open(keyCheckName, info.Check)
+	if err != nil || string(check) != keyCheckPlaintext {
+		return nil, ErrWrongKey
+	}
+	return s, nil
+}
+
+// deriveSealer runs the key derivation function and sets up the cipher.
+func deriveSealer(key *Key, info encryptionInfo) (*sealer, error) {
+	derived, err := pbkdf2.Key(sha256.New, string(key.secret), info.Salt, info.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive cache key: %w", err)
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+
+	return &sealer{aead: aead, keyID: info.KeyID, info: info}, nil
+}
+
+// seal encrypts plaintext into an envelope. The name is authenticated so an
+// encrypted file cannot be swapped for another one.
+func (s *sealer) seal(name string, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	env := envelope{
+		Encrypted:  encryptionVersion,
+		KeyID:      s.keyID,
+		Nonce:      nonce,
+		Ciphertext: s.aead.Seal(nil, nonce, plaintext, []byte(name)),
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("marshal envelope: %w", err)
+	}
+	return data, nil
+}
+
+// open decrypts an envelope produced by seal.
+func (s *sealer) open(name string, data []byte) ([]byte, error) {
+	env, ok := parseEnvelope(data)
+	if !ok {
+		return nil, fmt.Errorf("data is not encrypted")
+	}
+	if env.KeyID != s.keyID {
+		return nil, ErrWrongKey
+	}
+
+	plaintext, err := s.aead.Open(nil, env.Nonce, env.Ciphertext, []byte(name))
+	if err != nil {
+		// The key ID matched, so the data itself was damaged or swapped
+		return nil, ErrCorrupt
+	}
+	return plaintext, nil
+}
+
+// parseEnvelope reports whether data is an encrypted envelope.
+func parseEnvelope(data []byte) (envelope, bool) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Encrypted == 0 {
+		return envelope{}, false
+	}
+	return env, true
+}
+
+// loadSealer opens the encryption state of a cache directory. It returns a nil
+// sealer for an unencrypted cache opened without a key. A key given for an
+// unencrypted cache enables encryption; migrate is set when the caller must
+// then rewrite the existing entries with the sealer.
+//
+// While a rotation is unfinished both its old and its new key open the cache.
+// Entries sealed with the other key read as misses until the rotation is
+// resumed with Rotate.
+func loadSealer(dir string, key *Key) (s *sealer, migrate bool, err error) {
+	info, err := readEncryptionInfo(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	pending, err := readPendingRotation(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	if pending != nil && pending.targets(info) {
+		// The rotation finished but its record was not removed
+		if err := removePendingRotation(dir); err != nil {
+			return nil, false, err
+		}
+		pending = nil
+	}
+
+	if info == nil {
+		switch {
+		case key == nil:
+			return nil, false, nil
+		case pending != nil && pending.Next != nil:
+			// Encrypting the plaintext cache was interrupted; finish it
+			s, err := openSealer(key, *pending.Next)
+			if err != nil {
+				return nil, false, fmt.Errorf("open encrypted cache %s: %w", dir, err)
+			}
+			return s, true, nil
+		}
+		s, err := newSealer(key)
+		if err != nil {
+			return nil, false, err
+		}
+		return s, true, nil
+	}
+
+	if key == nil {
+		return nil, false, ErrKeyRequired
+	}
+
+	s, err = openSealer(key, *info)
+	if errors.Is(err, ErrWrongKey) && pending != nil && pending.Next != nil {
+		s, err = openSealer(key, *pending.Next)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("open encrypted cache %s: %w", dir, err)
+	}
+	return s, false, nil
+}
+
+// readEncryptionInfo returns the recorded encryption parameters of a cache
+// directory, or nil when the cache is not encrypted.
+func readEncryptionInfo(dir string) (*encryptionInfo, error) {
+	path := filepath.Join(dir, encryptionInfoFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var info encryptionInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+	return &info, nil
+}
+
+// readPendingRotation returns the unfinished rotation of a cache directory,
+// or nil when there is none.
+func readPendingRotation(dir string) (*pendingRotation, error) {
+	path := filepath.Join(dir, rotationFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var pending pendingRotation
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+	return &pending, nil
+}
+
+// writePendingRotation records that the cache is being rewritten for next,
+// which is nil when it is being decrypted.
+func writePendingRotation(dir string, next *sealer) error {
+	var pending pendingRotation
+	if next != nil {
+		info := next.info
+		pending.Next = &info
+	}
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return fmt.Errorf("marshal pending rotation: %w", err)
+	}
+	path := filepath.Join(dir, rotationFile)
+	if err := fsutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// removePendingRotation marks the rotation as finished.
+func removePendingRotation(dir string) error {
+	path := filepath.Join(dir, rotationFile)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeEncryptionInfo records the sealer's parameters, or removes them when
+// the cache is no longer encrypted.
+func writeEncryptionInfo(dir string, s *sealer) error {
+	path := filepath.Join(dir, encryptionInfoFile)
+	if s == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", path, err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(s.info)
+	if err != nil {
+		return fmt.Errorf("marshal encryption info: %w", err)
+	}
+	if err := fsutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
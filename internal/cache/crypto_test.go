@@ -0,0 +1,469 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustKey(t *testing.T, passphrase string) *Key {
+	t.Helper()
+	key, err := KeyFromPassphrase(passphrase)
+	require.NoError(t, err)
+	return key
+}
+
+func TestEncryptedCacheRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+	assert.True(t, pc.Encrypted())
+
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-1", Data: "top secret roadmap", TTL: time.Hour}))
+
+	raw, err := os.ReadFile(makeCachePath(dir, "page-1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "top secret")
+
+	got, err := pc.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "top secret roadmap", got)
+	assert.True(t, pc.Has(ctx, "page-1"))
+
+	entries, err := pc.Entries(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "page-1", entries[0].PageID)
+
+	// Reopening with the same key reads the entry back
+	reopened, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+	got, err = reopened.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "top secret roadmap", got)
+}
+
+func TestEncryptedCacheOpenErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "right")})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     *Key
+		wantErr error
+	}{
+		{name: "wrong key", key: mustKey(t, "wrong"), wantErr: ErrWrongKey},
+		{name: "missing key", key: nil, wantErr: ErrKeyRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: tt.key})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEncryptedCacheMigratesPlaintext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	plain, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, plain.Set(ctx, SetInput{PageID: "page-1", Data: "legacy content"}))
+
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(makeCachePath(dir, "page-1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "legacy content")
+
+	got, err := pc.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy content", got)
+}
+
+func TestEncryptedCacheMigratesPlaintextCompanions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	plain, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, plain.Set(ctx, SetInput{PageID: "page-1", Data: "secret plan"}))
+	require.NoError(t, plain.Close())
+
+	index := []byte(`{"documents":[{"body":"secret plan"}]}`)
+	history := []byte(`["page-1"]`)
+	for path, data := range map[string][]byte{
+		filepath.Join(dir, "index", "index.json"):            index,
+		filepath.Join(dir, "prefetch", "history.json"):       history,
+		filepath.Join(dir, quarantineDirName, "page-2.json"): []byte(`{"data":"secret pla`),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, data, 0600))
+	}
+
+	encrypted, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+
+	// No page content is left in plaintext
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret pla", path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(filepath.Join(dir, "index", "index.json"))
+	require.NoError(t, err)
+	opened, err := encrypted.Unseal("index.json", raw)
+	require.NoError(t, err)
+	assert.Equal(t, index, opened)
+
+	// The prefetch history holds no content and stays readable as is
+	raw, err = os.ReadFile(filepath.Join(dir, "prefetch", "history.json"))
+	require.NoError(t, err)
+	assert.Equal(t, history, raw)
+}
+
+func TestEncryptedCacheRejectsSwappedEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-a", Data: "a"}))
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-b", Data: "b"}))
+
+	raw, err := os.ReadFile(makeCachePath(dir, "page-a"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(makeCachePath(dir, "page-b"), raw, 0600))
+
+	_, err = pc.Get(ctx, "page-b")
+	assert.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "old")})
+	require.NoError(t, err)
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-1", Data: "content"}))
+
+	require.NoError(t, pc.Rotate(ctx, mustKey(t, "new")))
+
+	// The running cache keeps working under the new key
+	got, err := pc.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "content", got)
+
+	_, err = NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "old")})
+	assert.ErrorIs(t, err, ErrWrongKey)
+
+	rotated, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "new")})
+	require.NoError(t, err)
+	got, err = rotated.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "content", got)
+
+	// Rotating to no key decrypts the cache
+	require.NoError(t, rotated.Rotate(ctx, nil))
+	assert.False(t, rotated.Encrypted())
+
+	raw, err := os.ReadFile(makeCachePath(dir, "page-1"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "content")
+
+	plain, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	got, err = plain.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "content", got)
+}
+
+func TestRotateKeepsEntriesSealedWithAnotherKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-1", Data: "content"}))
+
+	// An entry sealed under some other key stops the rotation
+	other, err := NewPageCache(NewPageCacheInput{Dir: t.TempDir(), Key: mustKey(t, "other")})
+	require.NoError(t, err)
+	foreign, err := other.Seal("page-2", []byte(`{}`))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(makeCachePath(dir, "page-2"), foreign, 0600))
+
+	err = pc.Rotate(ctx, mustKey(t, "new"))
+	assert.ErrorIs(t, err, ErrWrongKey)
+
+	raw, err := os.ReadFile(makeCachePath(dir, "page-2"))
+	require.NoError(t, err)
+	assert.Equal(t, foreign, raw, "the entry is kept")
+	_, err = os.Stat(filepath.Join(dir, rotationFile))
+	assert.NoError(t, err, "the rotation stays pending")
+
+	// Once the entry is gone, the rotation finishes
+	require.NoError(t, os.Remove(makeCachePath(dir, "page-2")))
+	require.NoError(t, pc.Rotate(ctx, mustKey(t, "new")))
+	_, err = os.Stat(filepath.Join(dir, rotationFile))
+	assert.True(t, os.IsNotExist(err))
+
+	rotated, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "new")})
+	require.NoError(t, err)
+	got, err := rotated.Get(ctx, "page-1")
+	require.NoError(t, err)
+	assert.Equal(t, "content", got)
+}
+
+func TestRotateResumesAfterInterruption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "old")})
+	require.NoError(t, err)
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-1", Data: "first"}))
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-2", Data: "second"}))
+
+	// A rotation that stopped after rewriting page-1
+	next, err := newSealer(mustKey(t, "new"))
+	require.NoError(t, err)
+	require.NoError(t, writePendingRotation(dir, next))
+	raw, err := os.ReadFile(makeCachePath(dir, "page-1"))
+	require.NoError(t, err)
+	plain, err := pc.sealer.open("page-1", raw)
+	require.NoError(t, err)
+	resealed, err := next.seal("page-1", plain)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(makeCachePath(dir, "page-1"), resealed, 0600))
+
+	// Both keys open the cache meanwhile
+	_, err = NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "new")})
+	require.NoError(t, err)
+	_, err = NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "unrelated")})
+	assert.ErrorIs(t, err, ErrWrongKey)
+
+	old, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "old")})
+	require.NoError(t, err)
+
+	// Finishing it needs the same new key
+	assert.ErrorIs(t, old.Rotate(ctx, mustKey(t, "unrelated")), ErrWrongKey)
+	assert.Error(t, old.Rotate(ctx, nil))
+	require.NoError(t, old.Rotate(ctx, mustKey(t, "new")))
+
+	rotated, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "new")})
+	require.NoError(t, err)
+	for id, want := range map[string]string{"page-1": "first", "page-2": "second"} {
+		got, err := rotated.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err = NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "old")})
+	assert.ErrorIs(t, err, ErrWrongKey)
+}
+
+func TestRotateReseals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "old")})
+	require.NoError(t, err)
+
+	data := []byte(`{"documents":[]}`)
+	sealed, err := pc.Seal("index.json", data)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "index"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index", "index.json"), sealed, 0600))
+	history := []byte(`{"pages":{}}`)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "prefetch"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prefetch", "history.json"), history, 0600))
+
+	require.NoError(t, pc.Rotate(ctx, mustKey(t, "new")))
+
+	rotated, err := NewPageCache(NewPageCacheInput{Dir: dir, Key: mustKey(t, "new")})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(dir, "index", "index.json"))
+	require.NoError(t, err)
+	opened, err := rotated.Unseal("index.json", raw)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	// Plaintext companions are left alone
+	raw, err = os.ReadFile(filepath.Join(dir, "prefetch", "history.json"))
+	require.NoError(t, err)
+	assert.Equal(t, history, raw)
+
+	// Decrypting the cache leaves the index in plaintext
+	require.NoError(t, rotated.Rotate(ctx, nil))
+	raw, err = os.ReadFile(filepath.Join(dir, "index", "index.json"))
+	require.NoError(t, err)
+	assert.Equal(t, data, raw)
+}
+
+func TestSealUnseal(t *testing.T) {
+	t.Parallel()
+
+	plain, err := NewPageCache(NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+	encrypted, err := NewPageCache(NewPageCacheInput{Dir: t.TempDir(), Key: mustKey(t, "passphrase")})
+	require.NoError(t, err)
+
+	data := []byte(`{"documents":[]}`)
+
+	t.Run("plaintext cache passes data through", func(t *testing.T) {
+		t.Parallel()
+
+		sealed, err := plain.Seal("index.json", data)
+		require.NoError(t, err)
+		assert.Equal(t, data, sealed)
+
+		opened, err := plain.Unseal("index.json", sealed)
+		require.NoError(t, err)
+		assert.Equal(t, data, opened)
+	})
+
+	t.Run("encrypted cache round trips", func(t *testing.T) {
+		t.Parallel()
+
+		sealed, err := encrypted.Seal("index.json", data)
+		require.NoError(t, err)
+		assert.NotEqual(t, data, sealed)
+
+		opened, err := encrypted.Unseal("index.json", sealed)
+		require.NoError(t, err)
+		assert.Equal(t, data, opened)
+
+		_, err = encrypted.Unseal("other.json", sealed)
+		assert.ErrorIs(t, err, ErrCorrupt)
+
+		_, err = plain.Unseal("index.json", sealed)
+		assert.ErrorIs(t, err, ErrKeyRequired)
+	})
+
+	t.Run("encrypted cache refuses plaintext", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := encrypted.Unseal("index.json", data)
+		assert.Error(t, err)
+	})
+}
+
+func TestKeySources(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), perm))
+		require.NoError(t, os.Chmod(path, perm))
+		return path
+	}
+
+	privateKey := writeFile("private.key", "s3cret\n", 0600)
+	sharedKey := writeFile("shared.key", "s3cret\n", 0644)
+	emptyKey := writeFile("empty.key", "\n", 0600)
+
+	tests := []struct {
+		name       string
+		load       func() (*Key, error)
+		wantSecret string
+		wantErr    bool
+		unixOnly   bool
+	}{
+		{
+			name:       "passphrase",
+			load:       func() (*Key, error) { return KeyFromPassphrase("s3cret") },
+			wantSecret: "s3cret",
+		},
+		{
+			name:    "empty passphrase",
+			load:    func() (*Key, error) { return KeyFromPassphrase("") },
+			wantErr: true,
+		},
+		{
+			name:       "key file",
+			load:       func() (*Key, error) { return KeyFromFile(privateKey) },
+			wantSecret: "s3cret",
+		},
+		{
+			name:     "key file readable by others",
+			load:     func() (*Key, error) { return KeyFromFile(sharedKey) },
+			wantErr:  true,
+			unixOnly: true,
+		},
+		{
+			name:    "empty key file",
+			load:    func() (*Key, error) { return KeyFromFile(emptyKey) },
+			wantErr: true,
+		},
+		{
+			name:    "missing key file",
+			load:    func() (*Key, error) { return KeyFromFile(filepath.Join(dir, "missing.key")) },
+			wantErr: true,
+		},
+		{
+			name:       "key command",
+			load:       func() (*Key, error) { return KeyFromCommand(context.Background(), "echo s3cret") },
+			wantSecret: "s3cret",
+			unixOnly:   true,
+		},
+		{
+			name:     "failing key command",
+			load:     func() (*Key, error) { return KeyFromCommand(context.Background(), "exit 1") },
+			wantErr:  true,
+			unixOnly: true,
+		},
+		{
+			name:    "empty key command",
+			load:    func() (*Key, error) { return KeyFromCommand(context.Background(), " ") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.unixOnly && runtime.GOOS == "windows" {
+				t.Skip("unix only")
+			}
+
+			key, err := tt.load()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSecret, string(key.secret))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+)
+
+// lockFileName is the advisory lock shared by every process using a cache directory.
+const lockFileName = ".lock"
+
+// fileLock is an advisory lock that coordinates cache access between processes.
+// It does not coordinate goroutines; PageCache.mu does that.
+type fileLock struct {
+	f *os.File
+}
+
+// openFileLock opens, creating if needed, the lock file at path.
+func openFileLock(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file %s: %w", path, err)
+	}
+	return &fileLock{f: f}, nil
+}
+
+// lock blocks until the lock is held exclusively.
+func (l *fileLock) lock() error {
+	if err := lockFile(l.f, true); err != nil {
+		return fmt.Errorf("lock %s: %w", l.f.Name(), err)
+	}
+	return nil
+}
+
+// rlock blocks until the lock is held shared with other readers.
+func (l *fileLock) rlock() error {
+	if err := lockFile(l.f, false); err != nil {
+		return fmt.Errorf("lock %s: %w", l.f.Name(), err)
+	}
+	return nil
+}
+
+// unlock releases the lock. Errors are ignored: closing the file or exiting
+// the process releases it as well.
+func (l *fileLock) unlock() {
+	_ = unlockFile(l.f)
+}
+
+// close releases the lock and closes the lock file.
+func (l *fileLock) close() error {
+	return l.f.Close()
+}
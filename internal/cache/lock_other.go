@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package cache
+
+import "os"
+
+// lockFile is a no-op on platforms without file locking; only in-process
+// access is coordinated there.
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without file locking.
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+const (
+	stressDirEnv     = "NOTION_TUI_CACHE_STRESS_DIR"
+	stressWorkerEnv  = "NOTION_TUI_CACHE_STRESS_WORKER"
+	stressWorkers    = 4
+	stressIterations = 150
+	stressPages      = 8
+)
+
+func TestSetWritesAtomically(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, pc.Set(context.Background(), SetInput{PageID: "page-1", Data: i}))
+	}
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		assert.False(t, strings.HasSuffix(f.Name(), fsutil.TempSuffix), "left temp file %s", f.Name())
+	}
+}
+
+func TestOpenRemovesStaleTempFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "abc.json.123"+fsutil.TempSuffix)
+	require.NoError(t, os.WriteFile(stale, []byte("{"), 0600))
+
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	_, err = os.Stat(stale)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCorruptEntriesAreQuarantined(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, path string)
+	}{
+		{
+			name: "truncated file",
+			corrupt: func(t *testing.T, path string) {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, data[:len(data)/2], 0600))
+			},
+		},
+		{
+			name: "data does not match hash",
+			corrupt: func(t *testing.T, path string) {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				data = bytes.Replace(data, []byte("original"), []byte("tampered"), 1)
+				require.NoError(t, os.WriteFile(path, data, 0600))
+			},
+		},
+		{
+			name: "entry stored under another page",
+			corrupt: func(t *testing.T, path string) {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				data = bytes.Replace(data, []byte(`"page-1"`), []byte(`"page-2"`), 1)
+				require.NoError(t, os.WriteFile(path, data, 0600))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+			pc, err := NewPageCache(NewPageCacheInput{Dir: dir})
+			require.NoError(t, err)
+			defer pc.Close()
+
+			require.NoError(t, pc.Set(ctx, SetInput{PageID: "page-1", Data: "original"}))
+			tt.corrupt(t, makeCachePath(dir, "page-1"))
+
+			_, err = pc.Get(ctx, "page-1")
+			assert.ErrorIs(t, err, ErrCorrupt)
+			assert.Equal(t, int64(1), pc.Stats().Quarantined)
+
+			quarantined, err := os.ReadDir(filepath.Join(dir, quarantineDirName))
+			require.NoError(t, err)
+			assert.Len(t, quarantined, 1)
+
+			// The entry is now a plain miss
+			_, err = pc.Get(ctx, "page-1")
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "good", Data: "fine"}))
+	require.NoError(t, pc.Set(ctx, SetInput{PageID: "bad", Data: "fine"}))
+	require.NoError(t, os.WriteFile(makeCachePath(dir, "bad"), []byte("{not json"), 0600))
+
+	quarantined, err := pc.Verify(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bad"}, quarantined)
+	assert.True(t, pc.Has(ctx, "good"))
+
+	quarantined, err = pc.Verify(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, quarantined)
+}
+
+// TestCrossProcessStress runs several processes against one cache directory
+// and checks that no reader ever sees a torn or corrupt entry.
+func TestCrossProcessStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping multi-process stress test in short mode")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	cmds := make([]*exec.Cmd, stressWorkers)
+	outputs := make([]*bytes.Buffer, stressWorkers)
+	for i := range cmds {
+		outputs[i] = &bytes.Buffer{}
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^$")
+		cmds[i].Env = append(os.Environ(),
+			stressDirEnv+"="+dir,
+			fmt.Sprintf("%s=%d", stressWorkerEnv, i),
+		)
+		cmds[i].Stdout = outputs[i]
+		cmds[i].Stderr = outputs[i]
+		require.NoError(t, cmds[i].Start())
+	}
+
+	for i, cmd := range cmds {
+		assert.NoError(t, cmd.Wait(), "worker %d output:\n%s", i, outputs[i])
+	}
+
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	quarantined, err := pc.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, quarantined)
+
+	_, err = os.Stat(filepath.Join(dir, quarantineDirName))
+	assert.True(t, os.IsNotExist(err), "workers quarantined entries")
+}
+
+// stressPayload is the value stress workers write. Check detects torn content.
+type stressPayload struct {
+	Worker string `json:"worker"`
+	Iter   int    `json:"iter"`
+	Body   string `json:"body"`
+}
+
+// runStressWorker hammers the cache at dir and returns a process exit code.
+func runStressWorker(dir, worker string) int {
+	ctx := context.Background()
+	pc, err := NewPageCache(NewPageCacheInput{Dir: dir})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open cache: %v\n", err)
+		return 1
+	}
+	defer pc.Close()
+
+	for i := 0; i < stressIterations; i++ {
+		pageID := fmt.Sprintf("page-%d", i%stressPages)
+		body := strings.Repeat(fmt.Sprintf("%s:%d;", worker, i), 200)
+		if err := pc.Set(ctx, SetInput{
+			PageID: pageID,
+			Data:   stressPayload{Worker: worker, Iter: i, Body: body},
+			TTL:    time.Hour,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "set %s: %v\n", pageID, err)
+			return 1
+		}
+
+		readID := fmt.Sprintf("page-%d", (i*7)%stressPages)
+		got, err := pc.Get(ctx, readID)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "get %s: %v\n", readID, err)
+			return 1
+		}
+
+		m, ok := got.(map[string]interface{})
+		if !ok {
+			fmt.Fprintf(os.Stderr, "get %s: unexpected value %T\n", readID, got)
+			return 1
+		}
+		want := strings.Repeat(fmt.Sprintf("%s:%v;", m["worker"], m["iter"]), 200)
+		if m["body"] != want {
+			fmt.Fprintf(os.Stderr, "get %s: torn entry\n", readID)
+			return 1
+		}
+
+		if i%25 == 0 {
+			if err := pc.Delete(readID); err != nil {
+				fmt.Fprintf(os.Stderr, "delete %s: %v\n", readID, err)
+				return 1
+			}
+		}
+	}
+	return 0
+}
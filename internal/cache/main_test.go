@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Re-executed test binaries act as stress test workers
+	if dir := os.Getenv(stressDirEnv); dir != "" {
+		os.Exit(runStressWorker(dir, os.Getenv(stressWorkerEnv)))
+	}
+
+	// Keep key derivation cheap in tests
+	kdfIterations = 1000
+	os.Exit(m.Run())
+}
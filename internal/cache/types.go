@@ -19,6 +19,17 @@ type CacheStats struct {
 	HitCount  int64 `json:"hit_count"`
 	MissCount int64 `json:"miss_count"`
 	Size      int64 `json:"size"`
+	// Quarantined counts corrupt entries moved aside by this process.
+	Quarantined int64 `json:"quarantined"`
+}
+
+// DiskUsage summarizes what a cache directory holds on disk.
+type DiskUsage struct {
+	Entries     int   `json:"entries"`     // Readable entries, including expired ones
+	Expired     int   `json:"expired"`     // Entries past their TTL
+	Unreadable  int   `json:"unreadable"`  // Entry files that failed to decode
+	Bytes       int64 `json:"bytes"`       // Size of all entry files
+	Quarantined int   `json:"quarantined"` // Files in the quarantine directory
 }
 
 // Marshal serializes a CacheEntry to JSON bytes.
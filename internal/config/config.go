@@ -3,9 +3,16 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/viper"
+
+	"github.com/Panandika/notion-tui/internal/filter"
+	"github.com/Panandika/notion-tui/internal/logging"
 )
 
 // isValidNotionID checks if the string is a valid Notion ID format.
@@ -29,25 +36,230 @@ func isValidNotionID(id string) bool {
 
 // DatabaseConfig represents a single database configuration.
 type DatabaseConfig struct {
-	ID   string `mapstructure:"id"`
-	Name string `mapstructure:"name"`
-	Icon string `mapstructure:"icon"` // Optional emoji/icon
+	ID             string   `mapstructure:"id"`
+	Name           string   `mapstructure:"name"`
+	Icon           string   `mapstructure:"icon"`            // Optional emoji/icon
+	Description    string   `mapstructure:"description"`     // Optional; shown in the database list
+	Prefetch       *bool    `mapstructure:"prefetch"`        // Optional; overrides prefetch.enabled
+	PrefetchRecent int      `mapstructure:"prefetch_recent"` // Optional; overrides prefetch.recent_pages
+	TitleProperty  string   `mapstructure:"title_property"`  // Optional; the title property when empty
+	StatusProperty string   `mapstructure:"status_property"` // Optional; "Status" when empty
+	DateProperty   string   `mapstructure:"date_property"`   // Optional; a date shown with each page
+	Sort           []string `mapstructure:"sort"`            // Optional; as `query --sort`, e.g. "-Due"
+	Filter         string   `mapstructure:"filter"`          // Optional; as `query --where`
+	View           string   `mapstructure:"view"`            // Optional; one of Views, "list" when empty
+	Properties     []string `mapstructure:"properties"`      // Optional; shown with each page
+}
+
+// Database views accepted by DatabaseConfig.View.
+const (
+	ViewList     = "list"
+	ViewTable    = "table"
+	ViewBoard    = "board"
+	ViewCalendar = "calendar"
+)
+
+// Views lists the accepted database views in the order the TUI cycles
+// through them.
+var Views = []string{ViewList, ViewTable, ViewBoard, ViewCalendar}
+
+// DefaultStatusProperty is the status property used when status_property is
+// not set. Its lowercase spelling is accepted too.
+const DefaultStatusProperty = "Status"
+
+// DefaultView returns the view the database opens in.
+func (d DatabaseConfig) DefaultView() string {
+	if d.View == "" {
+		return ViewList
+	}
+	return strings.ToLower(d.View)
+}
+
+// Default prefetch settings used when the config leaves them unset.
+const (
+	DefaultPrefetchRecentPages = 10
+	DefaultPrefetchHistorySize = 20
+)
+
+// PrefetchConfig controls background cache warming.
+type PrefetchConfig struct {
+	Enabled     *bool `mapstructure:"enabled"`      // Default: true
+	RecentPages int   `mapstructure:"recent_pages"` // Recently edited pages warmed per database
+	HistorySize int   `mapstructure:"history_size"` // Recently opened pages remembered and warmed
+}
+
+// CacheEncryptionConfig selects the secret used to encrypt the cache at rest.
+// At most one source may be set. With none set the cache is stored in plaintext.
+type CacheEncryptionConfig struct {
+	Passphrase string `mapstructure:"passphrase"`  // Prefer NOTION_TUI_CACHE_PASSPHRASE over the config file
+	KeyFile    string `mapstructure:"key_file"`    // File holding the secret; must be mode 0600
+	KeyCommand string `mapstructure:"key_command"` // Shell command printing the secret
+}
+
+// Enabled reports whether a key source is configured.
+func (e CacheEncryptionConfig) Enabled() bool {
+	return e.Passphrase != "" || e.KeyFile != "" || e.KeyCommand != ""
+}
+
+// MCPConfig controls the tools `mcp` offers to assistants.
+type MCPConfig struct {
+	AllowWrite []string `mapstructure:"allow_write"` // Tools that change the workspace, such as create_page; none by default
+}
+
+// WatchHook is a shell command `watch` runs for each matching event, with
+// the event as JSON on stdin.
+type WatchHook struct {
+	Event    string `mapstructure:"event"`    // page_created, page_updated or property_changed; every event when empty
+	Database string `mapstructure:"database"` // Optional database name or ID the hook is limited to
+	Command  string `mapstructure:"command"`  // Run with sh -c
+}
+
+// WatchConfig controls `watch`.
+type WatchConfig struct {
+	Workspace bool        `mapstructure:"workspace"` // Also report pages edited anywhere in the workspace
+	Hooks     []WatchHook `mapstructure:"hooks"`
+}
+
+// KeysConfig overrides key bindings. Each context maps action names to the
+// keys that replace the preset's, such as list: {refresh: [r, ctrl+r]}.
+type KeysConfig struct {
+	Preset  string              `mapstructure:"preset"` // default, vim or emacs
+	Global  map[string][]string `mapstructure:"global"`
+	List    map[string][]string `mapstructure:"list"`
+	Detail  map[string][]string `mapstructure:"detail"`
+	Edit    map[string][]string `mapstructure:"edit"`
+	Search  map[string][]string `mapstructure:"search"`
+	Palette map[string][]string `mapstructure:"palette"`
+}
+
+// Overrides returns the overridden actions of every context that has any,
+// keyed by context name.
+func (k KeysConfig) Overrides() map[string]map[string][]string {
+	overrides := make(map[string]map[string][]string)
+	for context, actions := range map[string]map[string][]string{
+		"global":  k.Global,
+		"list":    k.List,
+		"detail":  k.Detail,
+		"edit":    k.Edit,
+		"search":  k.Search,
+		"palette": k.Palette,
+	} {
+		if len(actions) > 0 {
+			overrides[context] = actions
+		}
+	}
+	return overrides
+}
+
+// ThemeConfig picks the colors of the TUI. Colors override the theme's by
+// role, such as primary: "#FF5F87".
+type ThemeConfig struct {
+	Name   string            `mapstructure:"name"`   // dark, light, high-contrast or no-color
+	File   string            `mapstructure:"file"`   // YAML theme file read after Name
+	Colors map[string]string `mapstructure:"colors"` // Applied after the file
+}
+
+// LogConfig controls the debug log. Nothing is logged unless debug is set
+// or a level is given.
+type LogConfig struct {
+	File          string `mapstructure:"file"`           // Default: DefaultLogFile
+	Level         string `mapstructure:"level"`          // debug, info, warn or error; debug when only debug is set
+	MaxSizeMB     int    `mapstructure:"max_size_mb"`    // Size at which the file is rotated; 10 when zero
+	MaxFiles      int    `mapstructure:"max_files"`      // Rotated files kept; 3 when zero
+	RedactContent bool   `mapstructure:"redact_content"` // Hide titles, queries and page text as well as tokens
+}
+
+// ProfileConfig is a named workspace selected with --profile or
+// NOTION_TUI_PROFILE. Its settings replace the top-level ones; those it
+// leaves unset keep their top-level values.
+type ProfileConfig struct {
+	NotionToken        string           `mapstructure:"notion_token"`
+	NotionTokenFile    string           `mapstructure:"notion_token_file"`
+	NotionTokenCommand string           `mapstructure:"notion_token_command"`
+	Databases          []DatabaseConfig `mapstructure:"databases"`        // Replace the top-level databases when set
+	DefaultDatabase    string           `mapstructure:"default_database"` // Used with the profile's databases
+	InboxDatabase      string           `mapstructure:"inbox_database"`   // Used with the profile's databases
+	CacheDir           string           `mapstructure:"cache_dir"`        // Default: profiles/<name> below the top-level cache_dir
+	Theme              ThemeConfig      `mapstructure:"theme"`
 }
 
 // Config holds the application configuration.
-// It is immutable after initialization (per CLAUDE.md CFG-2).
+// It is immutable after initialization (per CLAUDE.md CFG-2); a reload
+// while the TUI runs replaces it with a new Config.
 type Config struct {
-	NotionToken     string           `mapstructure:"notion_token"`
-	DatabaseID      string           `mapstructure:"database_id"`      // Deprecated: use Databases
-	Databases       []DatabaseConfig `mapstructure:"databases"`        // Multiple database support
-	DefaultDatabase string           `mapstructure:"default_database"` // Default database ID
-	Debug           bool             `mapstructure:"debug"`
-	CacheDir        string           `mapstructure:"cache_dir"`
+	NotionToken        string                   `mapstructure:"notion_token"`
+	NotionTokenFile    string                   `mapstructure:"notion_token_file"`    // Read when notion_token is not set; must be mode 0600
+	NotionTokenCommand string                   `mapstructure:"notion_token_command"` // Shell command printing the token, run when neither is set
+	DatabaseID         string                   `mapstructure:"database_id"`          // Deprecated: use Databases
+	Databases          []DatabaseConfig         `mapstructure:"databases"`            // Multiple database support
+	DefaultDatabase    string                   `mapstructure:"default_database"`     // Default database ID
+	InboxDatabase      string                   `mapstructure:"inbox_database"`       // Database `capture` adds rows to (ID or name)
+	Debug              bool                     `mapstructure:"debug"`                // Write the debug log at debug level
+	Log                LogConfig                `mapstructure:"log"`
+	CacheDir           string                   `mapstructure:"cache_dir"`
+	ExportDir          string                   `mapstructure:"export_dir"` // Where `export` and the palette write Markdown
+	Prefetch           PrefetchConfig           `mapstructure:"prefetch"`
+	StatsFile          string                   `mapstructure:"stats_file"` // Optional; the TUI appends its API and cache stats here as JSON lines
+	CacheEncryption    CacheEncryptionConfig    `mapstructure:"cache_encryption"`
+	MCP                MCPConfig                `mapstructure:"mcp"`
+	Watch              WatchConfig              `mapstructure:"watch"`
+	Keys               KeysConfig               `mapstructure:"keys"`
+	Theme              ThemeConfig              `mapstructure:"theme"`
+	Profile            string                   `mapstructure:"profile"`  // Active profile; none when empty
+	Profiles           map[string]ProfileConfig `mapstructure:"profiles"` // Keyed by lower-case name
+
+	tokenSource string // where ResolveToken read the token from, if it did
 }
 
+// DefaultConfigFile is the config file read when --config is not given.
+const DefaultConfigFile = "~/.config/notion-tui/config.yaml"
+
+// DefaultCacheDir is the cache directory used when cache_dir is not set.
+const DefaultCacheDir = "~/.cache/notion-tui"
+
+// DefaultExportDir is the export directory used when export_dir is not set.
+const DefaultExportDir = "~/notion-export"
+
+// DefaultLogFile is the debug log used when log.file is not set.
+const DefaultLogFile = "~/.local/state/notion-tui/notion-tui.log"
+
 // Load reads configuration from viper and validates it.
 // Per BP-2 and CFG-1, configuration is validated on startup.
 func Load() (*Config, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.ResolveToken(); err != nil {
+		return nil, err
+	}
+
+	// Validate required fields
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadLocal reads configuration like Load but does not require a Notion token.
+// It is meant for commands that only work with local state such as the cache.
+func LoadLocal() (*Config, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateSettings(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// load unmarshals and normalizes the configuration without validating it.
+func load() (*Config, error) {
 	var cfg Config
 
 	// Unmarshal viper config into struct
@@ -55,19 +267,124 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("unmarshal config: %w", err)
 	}
 
+	if err := cfg.applyProfile(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.expandEnv(); err != nil {
+		return nil, err
+	}
+
 	// Migrate legacy single database config to new format
 	if err := cfg.migrateLegacyConfig(); err != nil {
 		return nil, fmt.Errorf("migrate config: %w", err)
 	}
 
-	// Validate required fields
-	if err := cfg.Validate(); err != nil {
-		return nil, err
+	cacheDir, err := ExpandHome(cfg.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("expand cache_dir: %w", err)
 	}
+	cfg.CacheDir = cacheDir
+
+	exportDir, err := ExpandHome(cfg.ExportDir)
+	if err != nil {
+		return nil, fmt.Errorf("expand export_dir: %w", err)
+	}
+	cfg.ExportDir = exportDir
+
+	tokenFile, err := ExpandHome(cfg.NotionTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("expand notion_token_file: %w", err)
+	}
+	cfg.NotionTokenFile = tokenFile
+
+	themeFile, err := ExpandHome(cfg.Theme.File)
+	if err != nil {
+		return nil, fmt.Errorf("expand theme.file: %w", err)
+	}
+	cfg.Theme.File = themeFile
+
+	logFile, err := ExpandHome(cfg.Log.File)
+	if err != nil {
+		return nil, fmt.Errorf("expand log.file: %w", err)
+	}
+	cfg.Log.File = logFile
+
+	statsFile, err := ExpandHome(cfg.StatsFile)
+	if err != nil {
+		return nil, fmt.Errorf("expand stats_file: %w", err)
+	}
+	cfg.StatsFile = statsFile
 
 	return &cfg, nil
 }
 
+// ExpandHome replaces a leading "~" in path with the user's home directory.
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get home directory: %w", err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// applyProfile replaces the top-level settings with those of the active
+// profile. A profile without its own cache directory gets one below the
+// top-level cache directory, so workspaces never share cached pages.
+func (c *Config) applyProfile() error {
+	if c.Profile == "" {
+		return nil
+	}
+
+	// Viper lower-cases keys, so profile names match in any case
+	name := strings.ToLower(c.Profile)
+	p, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("profile %q not found: no profiles are configured", c.Profile)
+		}
+		return fmt.Errorf("profile %q not found (available: %s)", c.Profile, strings.Join(c.ProfileNames(), ", "))
+	}
+	c.Profile = name
+
+	// A profile's token source replaces every top-level one
+	if p.NotionToken != "" || p.NotionTokenFile != "" || p.NotionTokenCommand != "" {
+		c.NotionToken = p.NotionToken
+		c.NotionTokenFile = p.NotionTokenFile
+		c.NotionTokenCommand = p.NotionTokenCommand
+	}
+	if len(p.Databases) > 0 {
+		c.Databases = p.Databases
+		c.DefaultDatabase = p.DefaultDatabase
+		c.InboxDatabase = p.InboxDatabase
+		c.DatabaseID = ""
+	}
+	switch {
+	case p.CacheDir != "":
+		c.CacheDir = p.CacheDir
+	case c.CacheDir != "":
+		c.CacheDir = filepath.Join(c.CacheDir, "profiles", name)
+	}
+	if p.Theme.Name != "" || p.Theme.File != "" || len(p.Theme.Colors) > 0 {
+		c.Theme = p.Theme
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the configured profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // migrateLegacyConfig converts old single database config to new multi-database format.
 func (c *Config) migrateLegacyConfig() error {
 	// If new format is already set, skip migration
@@ -96,13 +413,21 @@ func (c *Config) migrateLegacyConfig() error {
 	return nil
 }
 
+// ErrTokenRequired is returned by Validate when no token is configured.
+var ErrTokenRequired = errors.New("notion_token is required (set via --token flag, NOTION_TUI_NOTION_TOKEN env var, or notion_token, notion_token_file or notion_token_command in the config file)")
+
 // Validate checks that required configuration values are set.
 // Implements CFG-1: fail fast on invalid config.
 func (c *Config) Validate() error {
 	if c.NotionToken == "" {
-		return errors.New("notion_token is required (set via --token flag, NOTION_TUI_NOTION_TOKEN env var, or config file)")
+		return ErrTokenRequired
 	}
 
+	return c.validateSettings()
+}
+
+// validateSettings checks everything Validate does except credentials.
+func (c *Config) validateSettings() error {
 	// Database is now optional - users can use workspace search to discover content
 	// If legacy DatabaseID is set but Databases is empty, migrate it (only if valid UUID)
 	if len(c.Databases) == 0 && c.DatabaseID != "" && isValidNotionID(c.DatabaseID) {
@@ -116,6 +441,17 @@ func (c *Config) Validate() error {
 		c.DefaultDatabase = c.DatabaseID
 	}
 
+	// Only one cache key source may be used
+	sources := 0
+	for _, v := range []string{c.CacheEncryption.Passphrase, c.CacheEncryption.KeyFile, c.CacheEncryption.KeyCommand} {
+		if v != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return errors.New("cache_encryption: set only one of passphrase, key_file or key_command")
+	}
+
 	// Validate each database config (if any)
 	for i, db := range c.Databases {
 		if db.ID == "" {
@@ -124,6 +460,29 @@ func (c *Config) Validate() error {
 		if db.Name == "" {
 			return fmt.Errorf("database[%d] is missing required field 'name'", i)
 		}
+		if !slices.Contains(Views, db.DefaultView()) {
+			return fmt.Errorf("database[%d] view %q is not one of %s", i, db.View, strings.Join(Views, ", "))
+		}
+		// The filter is compiled against the database schema when it opens;
+		// a syntax error is reported now rather than then
+		if strings.TrimSpace(db.Filter) != "" {
+			if _, err := filter.Parse(db.Filter); err != nil {
+				return fmt.Errorf("database[%d] filter: %w", i, err)
+			}
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+	if c.Log.MaxSizeMB < 0 || c.Log.MaxFiles < 0 {
+		return errors.New("log: max_size_mb and max_files must not be negative")
+	}
+
+	for i, hook := range c.Watch.Hooks {
+		if strings.TrimSpace(hook.Command) == "" {
+			return fmt.Errorf("watch.hooks[%d] is missing required field 'command'", i)
+		}
 	}
 
 	// Set default database if databases exist
@@ -152,11 +511,14 @@ func (c *Config) Validate() error {
 // Implements SEC-2: never log secrets.
 func (c *Config) String() string {
 	return fmt.Sprintf(
-		"Config{Token: ***, Databases: %d, DefaultDB: %s, Debug: %v, CacheDir: %s}",
+		"Config{Token: ***, TokenSource: %s, Profile: %s, Databases: %d, DefaultDB: %s, Debug: %v, CacheDir: %s, CacheEncrypted: %v}",
+		c.TokenSource(),
+		c.Profile,
 		len(c.Databases),
 		c.DefaultDatabase,
 		c.Debug,
 		c.CacheDir,
+		c.CacheEncryption.Enabled(),
 	)
 }
 
@@ -186,7 +548,70 @@ func (c *Config) GetDatabaseID() string {
 	return c.DatabaseID
 }
 
+// GetInboxDatabase returns the ID or name of the database `capture` adds rows
+// to: inbox_database when set, otherwise the default database.
+func (c *Config) GetInboxDatabase() string {
+	if c.InboxDatabase != "" {
+		return c.InboxDatabase
+	}
+	return c.GetDatabaseID()
+}
+
 // HasDatabases returns true if any databases are configured.
 func (c *Config) HasDatabases() bool {
 	return len(c.Databases) > 0
 }
+
+// PrefetchEnabled reports whether background cache warming is enabled globally.
+func (c *Config) PrefetchEnabled() bool {
+	return c.Prefetch.Enabled == nil || *c.Prefetch.Enabled
+}
+
+// PrefetchHistorySize returns the number of recently opened pages to keep warm.
+func (c *Config) PrefetchHistorySize() int {
+	if c.Prefetch.HistorySize > 0 {
+		return c.Prefetch.HistorySize
+	}
+	return DefaultPrefetchHistorySize
+}
+
+// PrefetchEnabledFor reports whether cache warming is enabled for a database.
+func (c *Config) PrefetchEnabledFor(databaseID string) bool {
+	if !c.PrefetchEnabled() {
+		return false
+	}
+	db := c.GetDatabase(databaseID)
+	if db == nil || db.Prefetch == nil {
+		return true
+	}
+	return *db.Prefetch
+}
+
+// PrefetchRecentPages returns how many recently edited pages of a database to warm.
+// It returns 0 when prefetching is disabled for the database.
+func (c *Config) PrefetchRecentPages(databaseID string) int {
+	if !c.PrefetchEnabledFor(databaseID) {
+		return 0
+	}
+	if db := c.GetDatabase(databaseID); db != nil && db.PrefetchRecent > 0 {
+		return db.PrefetchRecent
+	}
+	if c.Prefetch.RecentPages > 0 {
+		return c.Prefetch.RecentPages
+	}
+	return DefaultPrefetchRecentPages
+}
+
+// LoggingEnabled reports whether the debug log is written.
+func (c *Config) LoggingEnabled() bool {
+	return c.Debug || c.Log.Level != ""
+}
+
+// LogLevel returns the level of the debug log: log.level when set, debug
+// when only debug is set.
+func (c *Config) LogLevel() string {
+	if c.Log.Level != "" {
+		return strings.ToLower(c.Log.Level)
+	}
+	return "debug"
+}
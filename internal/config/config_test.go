@@ -2,7 +2,12 @@ package config
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 // TestValidate runs validation tests (T-1: table-driven).
@@ -96,6 +101,91 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "missing required field 'name'",
 		},
+		{
+			name: "cache encryption with one key source",
+			cfg: &Config{
+				NotionToken:     "secret_xxx",
+				CacheEncryption: CacheEncryptionConfig{KeyCommand: "pass show notion-tui"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cache encryption with several key sources",
+			cfg: &Config{
+				NotionToken:     "secret_xxx",
+				CacheEncryption: CacheEncryptionConfig{Passphrase: "hunter2", KeyFile: "/tmp/key"},
+			},
+			wantErr: true,
+			errMsg:  "set only one of passphrase, key_file or key_command",
+		},
+		{
+			name: "watch hook without a command",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Watch:       WatchConfig{Hooks: []WatchHook{{Event: "page_created"}}},
+			},
+			wantErr: true,
+			errMsg:  "watch.hooks[0] is missing required field 'command'",
+		},
+		{
+			name: "database view settings",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Databases: []DatabaseConfig{{
+					ID:             "db_1",
+					Name:           "Tasks",
+					StatusProperty: "Stage",
+					Sort:           []string{"-Due"},
+					Filter:         `Stage != "Done"`,
+					View:           "Board",
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown database view",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Databases:   []DatabaseConfig{{ID: "db_1", Name: "Tasks", View: "gallery"}},
+			},
+			wantErr: true,
+			errMsg:  `database[0] view "gallery" is not one of list, table, board, calendar`,
+		},
+		{
+			name: "database filter syntax error",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Databases:   []DatabaseConfig{{ID: "db_1", Name: "Tasks", Filter: "Status ="}},
+			},
+			wantErr: true,
+			errMsg:  "database[0] filter",
+		},
+		{
+			name: "log settings",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Log:         LogConfig{Level: "WARN", MaxSizeMB: 5, MaxFiles: 2, RedactContent: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown log level",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Log:         LogConfig{Level: "verbose"},
+			},
+			wantErr: true,
+			errMsg:  `log: log level "verbose" is not one of debug, info, warn or error`,
+		},
+		{
+			name: "negative log size",
+			cfg: &Config{
+				NotionToken: "secret_xxx",
+				Log:         LogConfig{MaxSizeMB: -1},
+			},
+			wantErr: true,
+			errMsg:  "log: max_size_mb and max_files must not be negative",
+		},
 	}
 
 	for _, tt := range tests {
@@ -153,6 +243,22 @@ func TestString(t *testing.T) {
 	}
 }
 
+// TestStringRedactsCachePassphrase verifies the cache passphrase is never printed (SEC-2).
+func TestStringRedactsCachePassphrase(t *testing.T) {
+	cfg := &Config{
+		NotionToken:     "secret_xxx",
+		CacheEncryption: CacheEncryptionConfig{Passphrase: "correct horse battery staple"},
+	}
+
+	str := cfg.String()
+	if contains(str, "correct horse") {
+		t.Error("String() exposed cache passphrase in output")
+	}
+	if !contains(str, "CacheEncrypted: true") {
+		t.Errorf("String() = %q, want cache encryption state", str)
+	}
+}
+
 // TestConfigImmutable verifies that Config is used as immutable after creation.
 // This is more of a documentation test—Go doesn't enforce immutability,
 // but this test documents the intended usage pattern (CFG-2).
@@ -362,3 +468,377 @@ func TestHasDatabasesWithInvalidLegacyID(t *testing.T) {
 		t.Error("HasDatabases() should return false for invalid legacy database_id")
 	}
 }
+
+// TestPrefetchSettings tests prefetch defaults and per-database overrides (T-1: table-driven).
+func TestPrefetchSettings(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	tests := []struct {
+		name        string
+		cfg         *Config
+		databaseID  string
+		wantEnabled bool
+		wantRecent  int
+		wantHistory int
+	}{
+		{
+			name: "defaults",
+			cfg: &Config{
+				Databases: []DatabaseConfig{{ID: "db_1", Name: "One"}},
+			},
+			databaseID:  "db_1",
+			wantEnabled: true,
+			wantRecent:  DefaultPrefetchRecentPages,
+			wantHistory: DefaultPrefetchHistorySize,
+		},
+		{
+			name: "global settings",
+			cfg: &Config{
+				Databases: []DatabaseConfig{{ID: "db_1", Name: "One"}},
+				Prefetch:  PrefetchConfig{RecentPages: 5, HistorySize: 3},
+			},
+			databaseID:  "db_1",
+			wantEnabled: true,
+			wantRecent:  5,
+			wantHistory: 3,
+		},
+		{
+			name: "globally disabled",
+			cfg: &Config{
+				Databases: []DatabaseConfig{{ID: "db_1", Name: "One", Prefetch: &enabled}},
+				Prefetch:  PrefetchConfig{Enabled: &disabled},
+			},
+			databaseID:  "db_1",
+			wantEnabled: false,
+			wantRecent:  0,
+			wantHistory: DefaultPrefetchHistorySize,
+		},
+		{
+			name: "disabled for database",
+			cfg: &Config{
+				Databases: []DatabaseConfig{{ID: "db_1", Name: "One", Prefetch: &disabled}},
+			},
+			databaseID:  "db_1",
+			wantEnabled: false,
+			wantRecent:  0,
+			wantHistory: DefaultPrefetchHistorySize,
+		},
+		{
+			name: "database override",
+			cfg: &Config{
+				Databases: []DatabaseConfig{{ID: "db_1", Name: "One", PrefetchRecent: 25}},
+				Prefetch:  PrefetchConfig{RecentPages: 5},
+			},
+			databaseID:  "db_1",
+			wantEnabled: true,
+			wantRecent:  25,
+			wantHistory: DefaultPrefetchHistorySize,
+		},
+		{
+			name:        "unknown database uses global settings",
+			cfg:         &Config{},
+			databaseID:  "db_x",
+			wantEnabled: true,
+			wantRecent:  DefaultPrefetchRecentPages,
+			wantHistory: DefaultPrefetchHistorySize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.PrefetchEnabledFor(tt.databaseID); got != tt.wantEnabled {
+				t.Errorf("PrefetchEnabledFor() = %v, want %v", got, tt.wantEnabled)
+			}
+			if got := tt.cfg.PrefetchRecentPages(tt.databaseID); got != tt.wantRecent {
+				t.Errorf("PrefetchRecentPages() = %v, want %v", got, tt.wantRecent)
+			}
+			if got := tt.cfg.PrefetchHistorySize(); got != tt.wantHistory {
+				t.Errorf("PrefetchHistorySize() = %v, want %v", got, tt.wantHistory)
+			}
+		})
+	}
+}
+
+// TestExpandHome verifies that a leading ~ is resolved to the home directory.
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "empty", path: "", want: ""},
+		{name: "absolute", path: "/var/cache/notion-tui", want: "/var/cache/notion-tui"},
+		{name: "relative", path: "./local-cache", want: "./local-cache"},
+		{name: "home only", path: "~", want: home},
+		{name: "under home", path: "~/.cache/notion-tui", want: filepath.Join(home, ".cache", "notion-tui")},
+		{name: "other user not expanded", path: "~bob/cache", want: "~bob/cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandHome(tt.path)
+			if err != nil {
+				t.Fatalf("ExpandHome() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandHome(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateSettingsWithoutToken verifies local commands can run without credentials.
+func TestValidateSettingsWithoutToken(t *testing.T) {
+	cfg := &Config{
+		Databases: []DatabaseConfig{{ID: "db_1", Name: "One"}},
+	}
+	if err := cfg.validateSettings(); err != nil {
+		t.Errorf("validateSettings() error = %v, want nil", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should still require a token")
+	}
+
+	invalid := &Config{Databases: []DatabaseConfig{{ID: "db_1"}}}
+	if err := invalid.validateSettings(); err == nil {
+		t.Error("validateSettings() should reject a database without a name")
+	}
+}
+
+// TestGetInboxDatabase verifies that capture falls back to the default database.
+func TestGetInboxDatabase(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{
+			name: "inbox set",
+			cfg:  &Config{InboxDatabase: "Inbox", DefaultDatabase: "db_1"},
+			want: "Inbox",
+		},
+		{
+			name: "default database",
+			cfg:  &Config{DefaultDatabase: "db_1"},
+			want: "db_1",
+		},
+		{
+			name: "nothing configured",
+			cfg:  &Config{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.GetInboxDatabase(); got != tt.want {
+				t.Errorf("GetInboxDatabase() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysOverrides(t *testing.T) {
+	keys := KeysConfig{
+		Preset: "vim",
+		List:   map[string][]string{"refresh": {"R"}},
+		Edit:   map[string][]string{},
+	}
+
+	got := keys.Overrides()
+	if len(got) != 1 {
+		t.Fatalf("Overrides() = %v, want only the list context", got)
+	}
+	if refresh := got["list"]["refresh"]; len(refresh) != 1 || refresh[0] != "R" {
+		t.Errorf("Overrides()[list][refresh] = %v, want [R]", refresh)
+	}
+}
+
+// TestApplyProfile verifies a profile's settings replace the top-level ones.
+func TestApplyProfile(t *testing.T) {
+	base := func() *Config {
+		return &Config{
+			NotionToken:     "secret_top",
+			Databases:       []DatabaseConfig{{ID: "db_top", Name: "Top"}},
+			DefaultDatabase: "db_top",
+			CacheDir:        "/cache",
+			Theme:           ThemeConfig{Name: "dark"},
+			Profiles: map[string]ProfileConfig{
+				"work": {
+					NotionToken: "secret_work",
+					Databases:   []DatabaseConfig{{ID: "db_work", Name: "Work"}},
+					Theme:       ThemeConfig{Name: "light"},
+				},
+				"personal": {CacheDir: "/personal-cache"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		profile       string
+		wantToken     string
+		wantDefaultDB string
+		wantCacheDir  string
+		wantTheme     string
+		wantErr       bool
+	}{
+		{name: "no profile", wantToken: "secret_top", wantDefaultDB: "db_top", wantCacheDir: "/cache", wantTheme: "dark"},
+		{name: "full profile", profile: "Work", wantToken: "secret_work", wantDefaultDB: "db_work", wantCacheDir: filepath.Join("/cache", "profiles", "work"), wantTheme: "light"},
+		{name: "partial profile", profile: "personal", wantToken: "secret_top", wantDefaultDB: "db_top", wantCacheDir: "/personal-cache", wantTheme: "dark"},
+		{name: "unknown profile", profile: "school", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			cfg.Profile = tt.profile
+
+			err := cfg.applyProfile()
+			if tt.wantErr {
+				if err == nil || !contains(err.Error(), "personal, work") {
+					t.Errorf("applyProfile() error = %v, want one listing the profiles", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyProfile() error = %v", err)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			if cfg.NotionToken != tt.wantToken {
+				t.Errorf("NotionToken = %q, want %q", cfg.NotionToken, tt.wantToken)
+			}
+			if cfg.GetDatabaseID() != tt.wantDefaultDB {
+				t.Errorf("GetDatabaseID() = %q, want %q", cfg.GetDatabaseID(), tt.wantDefaultDB)
+			}
+			if cfg.CacheDir != tt.wantCacheDir {
+				t.Errorf("CacheDir = %q, want %q", cfg.CacheDir, tt.wantCacheDir)
+			}
+			if cfg.Theme.Name != tt.wantTheme {
+				t.Errorf("Theme.Name = %q, want %q", cfg.Theme.Name, tt.wantTheme)
+			}
+		})
+	}
+}
+
+// TestWriteFile verifies a written config file loads back and is private.
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notion-tui", "config.yaml")
+	input := WriteFileInput{
+		Token: "secret_written",
+		Databases: []DatabaseConfig{
+			{ID: "db_1", Name: "Tasks", Icon: "✅"},
+			{ID: "db_2", Name: "Notes"},
+		},
+	}
+
+	if err := WriteFile(path, input); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %o, want 600", info.Mode().Perm())
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig() error = %v", err)
+	}
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if cfg.NotionToken != "secret_written" || len(cfg.Databases) != 2 || cfg.DefaultDatabase != "db_1" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if cfg.Databases[0].Icon != "✅" {
+		t.Errorf("Databases[0].Icon = %q", cfg.Databases[0].Icon)
+	}
+
+	if err := WriteFile(path, input); err == nil || !contains(err.Error(), "already exists") {
+		t.Errorf("WriteFile() over an existing file error = %v", err)
+	}
+	input.Overwrite = true
+	input.Token = "secret_replaced"
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, input); err != nil {
+		t.Errorf("WriteFile() with Overwrite error = %v", err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("mode after Overwrite = %o, want 600", info.Mode().Perm())
+	}
+	if data, err := os.ReadFile(path); err != nil || !contains(string(data), "secret_replaced") {
+		t.Errorf("config after Overwrite = %q, %v", data, err)
+	}
+	// The file is replaced through a temporary file that is renamed over it
+	if entries, err := os.ReadDir(filepath.Dir(path)); err != nil || len(entries) != 1 {
+		t.Errorf("config directory after Overwrite = %v, %v", entries, err)
+	}
+
+	if err := WriteFile(path, WriteFileInput{Overwrite: true}); !errors.Is(err, ErrTokenRequired) {
+		t.Errorf("WriteFile() without a token error = %v", err)
+	}
+}
+
+// TestDatabaseDefaultView verifies databases open as a list unless a view is set.
+func TestDatabaseDefaultView(t *testing.T) {
+	tests := []struct {
+		view string
+		want string
+	}{
+		{view: "", want: ViewList},
+		{view: "table", want: ViewTable},
+		{view: "Calendar", want: ViewCalendar},
+	}
+	for _, tt := range tests {
+		if got := (DatabaseConfig{View: tt.view}).DefaultView(); got != tt.want {
+			t.Errorf("DefaultView() for %q = %q, want %q", tt.view, got, tt.want)
+		}
+	}
+}
+
+// TestLogLevel verifies the debug log is off unless debug or a level is set.
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		wantEnabled bool
+		wantLevel   string
+	}{
+		{name: "off", cfg: Config{}, wantEnabled: false, wantLevel: "debug"},
+		{name: "debug", cfg: Config{Debug: true}, wantEnabled: true, wantLevel: "debug"},
+		{name: "level", cfg: Config{Log: LogConfig{Level: "Warn"}}, wantEnabled: true, wantLevel: "warn"},
+		{name: "level wins over debug", cfg: Config{Debug: true, Log: LogConfig{Level: "info"}}, wantEnabled: true, wantLevel: "info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.LoggingEnabled(); got != tt.wantEnabled {
+				t.Errorf("LoggingEnabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if got := tt.cfg.LogLevel(); got != tt.wantLevel {
+				t.Errorf("LogLevel() = %q, want %q", got, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Token sources reported by TokenSource.
+const (
+	TokenSourceNone    = "none"
+	TokenSourceValue   = "value" // notion_token, --token or NOTION_TUI_NOTION_TOKEN
+	TokenSourceFile    = "file"
+	TokenSourceCommand = "command"
+)
+
+// envRef matches a ${VAR} reference in a config value.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// tokenCommandCache holds the output of each token command run in this
+// process, so reloading the config does not prompt a password manager again.
+var tokenCommandCache = struct {
+	sync.Mutex
+	tokens map[string]string
+}{tokens: make(map[string]string)}
+
+// ResolveToken reads the token from notion_token_file or runs
+// notion_token_command when notion_token is not set. Load calls it; commands
+// that use LoadLocal call it when they need the token after all.
+func (c *Config) ResolveToken() error {
+	if c.NotionToken != "" {
+		return nil
+	}
+	if c.NotionTokenFile != "" && c.NotionTokenCommand != "" {
+		return errors.New("set only one of notion_token_file or notion_token_command")
+	}
+
+	switch {
+	case c.NotionTokenFile != "":
+		token, err := tokenFromFile(c.NotionTokenFile)
+		if err != nil {
+			return err
+		}
+		c.NotionToken = token
+		c.tokenSource = TokenSourceFile
+	case c.NotionTokenCommand != "":
+		token, err := tokenFromCommand(c.NotionTokenCommand)
+		if err != nil {
+			return err
+		}
+		c.NotionToken = token
+		c.tokenSource = TokenSourceCommand
+	}
+	return nil
+}
+
+// TokenSource names where the token comes from, without revealing it.
+func (c *Config) TokenSource() string {
+	switch {
+	case c.tokenSource != "":
+		return c.tokenSource
+	case c.NotionToken != "":
+		return TokenSourceValue
+	case c.NotionTokenFile != "":
+		return TokenSourceFile
+	case c.NotionTokenCommand != "":
+		return TokenSourceCommand
+	default:
+		return TokenSourceNone
+	}
+}
+
+// Redact replaces every occurrence of the token in s, so text headed for a
+// log or an error message never carries it (SEC-2).
+func (c *Config) Redact(s string) string {
+	if c.NotionToken == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.NotionToken, "***")
+}
+
+// tokenFromFile reads a token file. Like a cache key file, it must not be
+// readable by other users.
+func tokenFromFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("stat notion_token_file %s: %w", path, err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("notion_token_file %s is accessible by other users (chmod 600 it)", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read notion_token_file %s: %w", path, err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("notion_token_file %s is empty", path)
+	}
+	return token, nil
+}
+
+// tokenFromCommand runs a shell command printing the token, such as a
+// password manager lookup. Its output is kept for the rest of the session.
+func tokenFromCommand(command string) (string, error) {
+	tokenCommandCache.Lock()
+	defer tokenCommandCache.Unlock()
+
+	if token, ok := tokenCommandCache.tokens[command]; ok {
+		return token, nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin // password managers may ask to unlock
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("run notion_token_command: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("run notion_token_command: %w", err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("notion_token_command printed nothing")
+	}
+	tokenCommandCache.tokens[command] = token
+	return token, nil
+}
+
+// expandEnv replaces ${VAR} references in every string value of the config
+// with the environment variable's value. Values run by the shell, the
+// *_command settings, are left for the shell to expand. Profiles are skipped:
+// the active one is already applied, and the others may reference variables
+// only set when they are used.
+func (c *Config) expandEnv() error {
+	return expandValue(reflect.ValueOf(c).Elem(), "")
+}
+
+// expandValue expands the strings in v, a field at path in the config.
+func expandValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		expanded, err := expandString(v.String())
+		if err != nil {
+			return fmt.Errorf("expand %s: %w", path, err)
+		}
+		v.SetString(expanded)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name := v.Type().Field(i).Tag.Get("mapstructure")
+			if name == "" || name == "profiles" || strings.HasSuffix(name, "command") {
+				continue
+			}
+			if err := expandValue(v.Field(i), joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := expandValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		// Map values cannot be set in place, so each is copied and put back
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := expandValue(elem, joinPath(path, fmt.Sprint(key.Interface()))); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return expandValue(v.Elem(), path)
+		}
+	}
+	return nil
+}
+
+// expandString replaces the ${VAR} references in s. A variable that is not
+// set is an error, so a missing secret is not read as an empty one.
+func expandString(s string) (string, error) {
+	var missing []string
+	expanded := envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envRef.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// joinPath appends a key to a dotted config path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
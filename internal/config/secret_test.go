@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestResolveToken verifies the token sources and their precedence.
+func TestResolveToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token commands and file permissions are tested with sh")
+	}
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("secret_from_file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	openFile := filepath.Join(dir, "open-token")
+	if err := os.WriteFile(openFile, []byte("secret_open\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		cfg        *Config
+		wantToken  string
+		wantSource string
+		wantErr    string
+	}{
+		{
+			name:       "value wins",
+			cfg:        &Config{NotionToken: "secret_value", NotionTokenFile: tokenFile},
+			wantToken:  "secret_value",
+			wantSource: TokenSourceValue,
+		},
+		{
+			name:       "file",
+			cfg:        &Config{NotionTokenFile: tokenFile},
+			wantToken:  "secret_from_file",
+			wantSource: TokenSourceFile,
+		},
+		{
+			name:    "file readable by others",
+			cfg:     &Config{NotionTokenFile: openFile},
+			wantErr: "chmod 600",
+		},
+		{
+			name:       "command",
+			cfg:        &Config{NotionTokenCommand: "echo secret_from_command"},
+			wantToken:  "secret_from_command",
+			wantSource: TokenSourceCommand,
+		},
+		{
+			name:    "command fails",
+			cfg:     &Config{NotionTokenCommand: "echo 'item not found' >&2; exit 1"},
+			wantErr: "run notion_token_command: exit status 1: item not found",
+		},
+		{
+			name:    "command prints nothing",
+			cfg:     &Config{NotionTokenCommand: "true"},
+			wantErr: "printed nothing",
+		},
+		{
+			name:    "file and command",
+			cfg:     &Config{NotionTokenFile: tokenFile, NotionTokenCommand: "echo x"},
+			wantErr: "set only one",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.ResolveToken()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveToken() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveToken() error = %v", err)
+			}
+			if tt.cfg.NotionToken != tt.wantToken {
+				t.Errorf("NotionToken = %q, want %q", tt.cfg.NotionToken, tt.wantToken)
+			}
+			if got := tt.cfg.TokenSource(); got != tt.wantSource {
+				t.Errorf("TokenSource() = %q, want %q", got, tt.wantSource)
+			}
+			if str := tt.cfg.String(); strings.Contains(str, tt.wantToken) {
+				t.Errorf("String() exposed the token: %s", str)
+			}
+		})
+	}
+}
+
+// TestTokenCommandCached verifies a token command runs once per session.
+func TestTokenCommandCached(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token commands are tested with sh")
+	}
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	command := "echo run >> " + counter + "; echo secret_cached"
+
+	for i := 0; i < 2; i++ {
+		cfg := &Config{NotionTokenCommand: command}
+		if err := cfg.ResolveToken(); err != nil {
+			t.Fatalf("ResolveToken() error = %v", err)
+		}
+	}
+
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(runs), "run"); n != 1 {
+		t.Errorf("command ran %d times, want 1", n)
+	}
+}
+
+// TestExpandEnv verifies ${VAR} references are replaced in config values.
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("NOTION_TUI_TEST_TOKEN", "secret_env")
+	t.Setenv("NOTION_TUI_TEST_DB", "db_env")
+
+	cfg := &Config{
+		NotionToken:        "${NOTION_TUI_TEST_TOKEN}",
+		NotionTokenCommand: "pass show ${ITEM}",
+		Databases:          []DatabaseConfig{{ID: "${NOTION_TUI_TEST_DB}", Name: "Tasks"}},
+		Theme:              ThemeConfig{Colors: map[string]string{"primary": "${NOTION_TUI_TEST_DB}"}},
+		Watch:              WatchConfig{Hooks: []WatchHook{{Command: "echo ${NOTION_PAGE_TITLE}"}}},
+		Profiles:           map[string]ProfileConfig{"work": {NotionToken: "${UNSET_WORK_TOKEN}"}},
+	}
+	if err := cfg.expandEnv(); err != nil {
+		t.Fatalf("expandEnv() error = %v", err)
+	}
+
+	if cfg.NotionToken != "secret_env" {
+		t.Errorf("NotionToken = %q", cfg.NotionToken)
+	}
+	if cfg.Databases[0].ID != "db_env" {
+		t.Errorf("Databases[0].ID = %q", cfg.Databases[0].ID)
+	}
+	if cfg.Theme.Colors["primary"] != "db_env" {
+		t.Errorf("Theme.Colors[primary] = %q", cfg.Theme.Colors["primary"])
+	}
+	// Commands are left for the shell
+	if cfg.NotionTokenCommand != "pass show ${ITEM}" {
+		t.Errorf("NotionTokenCommand = %q", cfg.NotionTokenCommand)
+	}
+	if cfg.Watch.Hooks[0].Command != "echo ${NOTION_PAGE_TITLE}" {
+		t.Errorf("Watch.Hooks[0].Command = %q", cfg.Watch.Hooks[0].Command)
+	}
+
+	missing := &Config{CacheDir: "${NOTION_TUI_TEST_UNSET}/cache"}
+	err := missing.expandEnv()
+	if err == nil || !strings.Contains(err.Error(), "expand cache_dir: environment variable NOTION_TUI_TEST_UNSET is not set") {
+		t.Errorf("expandEnv() error = %v", err)
+	}
+}
+
+// TestRedact verifies the token is removed from text.
+func TestRedact(t *testing.T) {
+	cfg := &Config{NotionToken: "secret_xyz"}
+	if got := cfg.Redact("Authorization: Bearer secret_xyz"); got != "Authorization: Bearer ***" {
+		t.Errorf("Redact() = %q", got)
+	}
+	if got := (&Config{}).Redact("nothing to hide"); got != "nothing to hide" {
+		t.Errorf("Redact() = %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+// configFileHeader starts a config file written by WriteFile.
+const configFileHeader = "# Written by `notion-tui init`. config.example.yaml documents every option.\n"
+
+// WriteFileInput is the content of a new config file.
+type WriteFileInput struct {
+	Token           string
+	Databases       []DatabaseConfig
+	DefaultDatabase string // optional; the first database when empty
+	Overwrite       bool   // replace an existing file
+}
+
+// fileDatabase is a database entry as written to a config file.
+type fileDatabase struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	Icon string `yaml:"icon,omitempty"`
+}
+
+// fileConfig is the part of the config WriteFile writes.
+type fileConfig struct {
+	NotionToken     string         `yaml:"notion_token"`
+	Databases       []fileDatabase `yaml:"databases,omitempty"`
+	DefaultDatabase string         `yaml:"default_database,omitempty"`
+}
+
+// WriteFile writes a new config file that only the user can read, since it
+// holds the token. The settings are validated first. An existing file is
+// replaced atomically, so a crash never leaves a truncated config behind.
+func WriteFile(path string, input WriteFileInput) error {
+	cfg := Config{
+		NotionToken:     input.Token,
+		Databases:       input.Databases,
+		DefaultDatabase: input.DefaultDatabase,
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	content := fileConfig{
+		NotionToken:     cfg.NotionToken,
+		DefaultDatabase: cfg.DefaultDatabase,
+	}
+	for _, db := range cfg.Databases {
+		content.Databases = append(content.Databases, fileDatabase{ID: db.ID, Name: db.Name, Icon: db.Icon})
+	}
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	path, err = ExpandHome(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	if !input.Overwrite {
+		if _, err := os.Lstat(path); err == nil {
+			return fmt.Errorf("config file %s already exists", path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("create config file: %w", err)
+		}
+	}
+
+	// A replaced file keeps its old mode, which may let others read it, so it
+	// is restricted before the new token goes in
+	if err := os.Chmod(path, 0600); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("restrict config file: %w", err)
+	}
+	if err := fsutil.WriteFile(path, append([]byte(configFileHeader), data...), 0600); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+// Package export writes Notion pages to a directory of Markdown files. Each
+// page becomes a file with YAML front matter for its properties, its images
+// and files are downloaded beside it, and links between exported pages are
+// rewritten to relative paths. A manifest in the output directory records
+// what was written, so an interrupted export can be resumed and a repeated
+// export only fetches pages that changed.
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+const (
+	// queryPageSize is the largest page size accepted by database queries.
+	queryPageSize = 100
+	// downloadTimeout bounds the download of a single image or file.
+	downloadTimeout = 2 * time.Minute
+)
+
+// Client is the subset of the Notion client used to read pages.
+type Client interface {
+	notion.BlockFetcher
+	GetPage(ctx context.Context, id string) (*notionapi.Page, error)
+	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
+}
+
+// Progress reports a page that has been exported or skipped.
+type Progress struct {
+	Done    int    // pages handled so far
+	Total   int    // pages found so far; grows as child pages are discovered
+	Title   string // title of the page just handled
+	Path    string // file of the page, relative to the output directory
+	Skipped bool   // the page was unchanged since a previous run
+}
+
+// Result summarizes an export.
+type Result struct {
+	Written  int      // pages written
+	Skipped  int      // pages unchanged since a previous run
+	Assets   int      // images and files downloaded
+	Warnings []string // images and files that could not be downloaded
+}
+
+// Exporter writes pages and databases to an output directory.
+type Exporter struct {
+	client   Client
+	dir      string
+	http     *http.Client
+	progress func(Progress)
+}
+
+// NewExporterInput contains the parameters for creating an Exporter.
+type NewExporterInput struct {
+	Client     Client
+	Dir        string         // output directory, created when missing
+	HTTPClient *http.Client   // used for downloads; default has a two-minute timeout
+	Progress   func(Progress) // optional; called after every page
+}
+
+// NewExporter creates an Exporter.
+func NewExporter(input NewExporterInput) (*Exporter, error) {
+	if input.Client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	if input.Dir == "" {
+		return nil, fmt.Errorf("output directory cannot be empty")
+	}
+
+	httpClient := input.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: downloadTimeout}
+	}
+	progress := input.Progress
+	if progress == nil {
+		progress = func(Progress) {}
+	}
+
+	return &Exporter{
+		client:   input.Client,
+		dir:      input.Dir,
+		http:     httpClient,
+		progress: progress,
+	}, nil
+}
+
+// job is a page or database waiting to be exported into dir, a path relative
+// to the output directory.
+type job struct {
+	id         string
+	page       *notionapi.Page // already fetched, such as a database row
+	databaseID string
+	dir        string
+}
+
+// ExportPage exports a page and every page and database below it.
+func (e *Exporter) ExportPage(ctx context.Context, id string) (Result, error) {
+	return e.run(ctx, job{id: id})
+}
+
+// ExportDatabase exports every row of a database and the pages below them.
+func (e *Exporter) ExportDatabase(ctx context.Context, id string) (Result, error) {
+	return e.run(ctx, job{databaseID: id})
+}
+
+// run exports pages breadth first, starting with root. Links are rewritten
+// once every page is on disk, since a page may link to one found later.
+func (e *Exporter) run(ctx context.Context, root job) (Result, error) {
+	var result Result
+
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return result, fmt.Errorf("create output directory: %w", err)
+	}
+	m, err := loadManifest(e.dir)
+	if err != nil {
+		return result, err
+	}
+
+	queue := []job{root}
+	pending := 0
+	if root.databaseID == "" {
+		pending = 1
+	}
+	seen := make(map[string]bool)
+	var exported []string
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		j := queue[0]
+		queue = queue[1:]
+
+		if j.databaseID != "" {
+			rows, err := e.queryRows(ctx, j.databaseID)
+			if err != nil {
+				return result, fmt.Errorf("query database %s: %w", j.databaseID, err)
+			}
+			for i := range rows {
+				queue = append(queue, job{id: string(rows[i].ID), page: &rows[i], dir: j.dir})
+			}
+			pending += len(rows)
+			continue
+		}
+
+		pending--
+		key := notion.NormalizeID(j.id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		entry, skipped, err := e.exportPage(ctx, m, j, &result)
+		if err != nil {
+			return result, err
+		}
+		exported = append(exported, entry.Path)
+		for _, c := range entry.Children {
+			queue = append(queue, c.job())
+			if !c.Database {
+				pending++
+			}
+		}
+
+		done := result.Written + result.Skipped
+		e.progress(Progress{
+			Done:    done,
+			Total:   done + pending,
+			Title:   entry.Title,
+			Path:    entry.Path,
+			Skipped: skipped,
+		})
+	}
+
+	targets := make(map[string]string, len(m.Pages)+len(m.Databases))
+	for id, dir := range m.Databases {
+		targets[id] = dir
+	}
+	for id, entry := range m.Pages {
+		targets[id] = entry.Path
+	}
+	for _, from := range exported {
+		if err := e.rewriteFileLinks(from, targets); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// queryRows returns every row of a database.
+func (e *Exporter) queryRows(ctx context.Context, id string) ([]notionapi.Page, error) {
+	var rows []notionapi.Page
+	req := &notionapi.DatabaseQueryRequest{PageSize: queryPageSize}
+	for {
+		resp, err := e.client.QueryDatabase(ctx, id, req)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == "" {
+			return rows, nil
+		}
+		req.StartCursor = resp.NextCursor
+	}
+}
+
+// exportPage writes a single page unless the manifest shows it unchanged
+// since a previous run, and returns its manifest entry.
+func (e *Exporter) exportPage(ctx context.Context, m *manifest, j job, result *Result) (*manifestEntry, bool, error) {
+	page := j.page
+	if page == nil {
+		var err error
+		page, err = e.client.GetPage(ctx, j.id)
+		if err != nil {
+			return nil, false, fmt.Errorf("get page %s: %w", j.id, err)
+		}
+	}
+
+	key := notion.NormalizeID(string(page.ID))
+	title := notion.PageTitle(page)
+	file := path.Join(j.dir, BaseName(title, string(page.ID))+".md")
+
+	previous := m.Pages[key]
+	if previous != nil && previous.Path == file && previous.LastEditedTime.Equal(page.LastEditedTime) {
+		if _, err := os.Stat(filepath.Join(e.dir, filepath.FromSlash(file))); err == nil {
+			result.Skipped++
+			return previous, true, nil
+		}
+	}
+
+	nodes, err := notion.FetchBlockTree(ctx, e.client, string(page.ID), 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetch blocks of %s: %w", title, err)
+	}
+
+	assetDir := file[:len(file)-len(".md")]
+	if err := e.downloadAssets(ctx, nodes, assetDir, result); err != nil {
+		return nil, false, err
+	}
+
+	content, err := RenderPage(page, nodes)
+	if err != nil {
+		return nil, false, fmt.Errorf("render %s: %w", title, err)
+	}
+	if err := writeFile(filepath.Join(e.dir, filepath.FromSlash(file)), bytes.NewReader(content)); err != nil {
+		return nil, false, fmt.Errorf("write %s: %w", file, err)
+	}
+	if previous != nil && previous.Path != file {
+		// The page was renamed or moved; the old copy would be a stale duplicate
+		_ = os.Remove(filepath.Join(e.dir, filepath.FromSlash(previous.Path)))
+	}
+
+	entry := &manifestEntry{
+		Path:           file,
+		Title:          title,
+		LastEditedTime: page.LastEditedTime,
+		Children:       children(nodes, assetDir),
+	}
+	for _, c := range entry.Children {
+		if c.Database {
+			m.Databases[notion.NormalizeID(c.ID)] = c.Dir
+		}
+	}
+	m.Pages[key] = entry
+	if err := m.save(); err != nil {
+		return nil, false, err
+	}
+
+	result.Written++
+	return entry, false, nil
+}
+
+// children returns the child pages and databases found anywhere in a page.
+// Child pages are exported into dir and the rows of a child database into a
+// directory of its own below dir.
+func children(nodes []notion.BlockNode, dir string) []child {
+	var found []child
+	for _, node := range nodes {
+		switch b := node.Block.(type) {
+		case *notionapi.ChildPageBlock:
+			found = append(found, child{ID: string(b.ID), Dir: dir})
+		case *notionapi.ChildDatabaseBlock:
+			found = append(found, child{
+				ID:       string(b.ID),
+				Dir:      path.Join(dir, BaseName(b.ChildDatabase.Title, string(b.ID))),
+				Database: true,
+			})
+		}
+		found = append(found, children(node.Children, dir)...)
+	}
+	return found
+}
+
+// RenderPage returns the Markdown file of a page: front matter followed by
+// the page content.
+func RenderPage(page *notionapi.Page, nodes []notion.BlockNode) ([]byte, error) {
+	header, err := PageFrontMatter(page).Render()
+	if err != nil {
+		return nil, err
+	}
+
+	markdown, err := notion.ConvertBlockTreeToMarkdown(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(header)
+	if markdown != "" {
+		buf.WriteString(markdown)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,238 @@
+package export
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+const (
+	handbookID   = "aaaaaaaa-0000-0000-0000-000000000001"
+	onboardingID = "cccccccc-0000-0000-0000-000000000002"
+	tasksID      = "dddddddd-0000-0000-0000-000000000003"
+	writeDocsID  = "eeeeeeee-0000-0000-0000-000000000004"
+	imageID      = "ffffffff-0000-0000-0000-000000000005"
+)
+
+// workspace is a fake Notion workspace: a handbook page with an image, a
+// child page and a child database with one row.
+type workspace struct {
+	pages  map[string]*notionapi.Page
+	blocks map[string][]notionapi.Block
+	rows   map[string][]notionapi.Page
+}
+
+// newWorkspace returns the workspace, with images served by server.
+func newWorkspace(server *httptest.Server) *workspace {
+	link := func(text, id string) notionapi.RichText {
+		return notionapi.RichText{
+			Type:      notionapi.ObjectTypeText,
+			Text:      &notionapi.Text{Content: text},
+			PlainText: text,
+			Href:      "https://www.notion.so/" + notion.NormalizeID(id),
+		}
+	}
+
+	intro := testhelpers.NewParagraphBlock("")
+	intro.Paragraph.RichText = []notionapi.RichText{link("Start here", onboardingID)}
+
+	image := testhelpers.NewImageBlock(server.URL+"/img/Diagram%20v2.PNG", "Diagram")
+	image.ID = imageID
+	image.Image.Type = notionapi.FileTypeFile
+	image.Image.File, image.Image.External = image.Image.External, nil
+
+	childPage := &notionapi.ChildPageBlock{
+		BasicBlock: notionapi.BasicBlock{ID: onboardingID, Type: notionapi.BlockTypeChildPage, HasChildren: true},
+	}
+	childPage.ChildPage.Title = "Onboarding"
+	childDatabase := &notionapi.ChildDatabaseBlock{
+		BasicBlock: notionapi.BasicBlock{ID: tasksID, Type: notionapi.BlockTypeChildDatabase, HasChildren: true},
+	}
+	childDatabase.ChildDatabase.Title = "Tasks"
+
+	back := testhelpers.NewParagraphBlock("")
+	back.Paragraph.RichText = []notionapi.RichText{link("Back", handbookID), link("Elsewhere", "12345678-0000-0000-0000-000000000009")}
+	missing := testhelpers.NewImageBlock(server.URL+"/missing.png", "")
+
+	return &workspace{
+		pages: map[string]*notionapi.Page{
+			notion.NormalizeID(handbookID):   testhelpers.NewTestPage(handbookID, "Handbook"),
+			notion.NormalizeID(onboardingID): testhelpers.NewTestPage(onboardingID, "Onboarding"),
+		},
+		blocks: map[string][]notionapi.Block{
+			notion.NormalizeID(handbookID):   {intro, image, childPage, childDatabase},
+			notion.NormalizeID(onboardingID): {back, missing},
+		},
+		rows: map[string][]notionapi.Page{
+			notion.NormalizeID(tasksID): {*testhelpers.NewTestPage(writeDocsID, "Write docs")},
+		},
+	}
+}
+
+// client returns a mock client serving the workspace.
+func (w *workspace) client() *testhelpers.MockNotionClient {
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.GetPageFunc = func(ctx context.Context, id string) (*notionapi.Page, error) {
+		if page, ok := w.pages[notion.NormalizeID(id)]; ok {
+			return page, nil
+		}
+		return nil, testhelpers.ErrNotFound
+	}
+	mockClient.GetBlocksFunc = func(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
+		return testhelpers.NewGetChildrenResponse(w.blocks[notion.NormalizeID(id)]), nil
+	}
+	mockClient.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		return &notionapi.DatabaseQueryResponse{Results: w.rows[notion.NormalizeID(id)]}, nil
+	}
+	return mockClient
+}
+
+// newImageServer serves a PNG at /img/ and 404 for everything else.
+func newImageServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/img/Diagram v2.PNG" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("png"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExportPage(t *testing.T) {
+	t.Parallel()
+
+	server := newImageServer(t)
+	ws := newWorkspace(server)
+	dir := t.TempDir()
+
+	var progress []Progress
+	exporter, err := NewExporter(NewExporterInput{
+		Client:   ws.client(),
+		Dir:      dir,
+		Progress: func(p Progress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+
+	result, err := exporter.ExportPage(context.Background(), handbookID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Written)
+	assert.Equal(t, 1, result.Assets)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "404")
+
+	require.Len(t, progress, 3)
+	assert.Equal(t, Progress{Done: 1, Total: 2, Title: "Handbook", Path: "handbook-aaaaaaaa.md"}, progress[0])
+	assert.Equal(t, 3, progress[2].Done)
+	assert.Equal(t, 3, progress[2].Total)
+
+	handbook := readFile(t, dir, "handbook-aaaaaaaa.md")
+	assert.Contains(t, handbook, "---\nid: "+handbookID+"\ntitle: Handbook\n")
+	assert.Contains(t, handbook, "[Start here](handbook-aaaaaaaa/onboarding-cccccccc.md)")
+	assert.Contains(t, handbook, "![Diagram](handbook-aaaaaaaa/files/ffffffff-diagram-v2.png)")
+	assert.Contains(t, handbook, "[Tasks](handbook-aaaaaaaa/tasks-dddddddd)")
+	assert.Equal(t, "png", readFile(t, dir, "handbook-aaaaaaaa/files/ffffffff-diagram-v2.png"))
+
+	onboarding := readFile(t, dir, "handbook-aaaaaaaa/onboarding-cccccccc.md")
+	assert.Contains(t, onboarding, "[Back](../handbook-aaaaaaaa.md)")
+	assert.Contains(t, onboarding, "[Elsewhere](https://www.notion.so/12345678000000000000000000000009)",
+		"links to pages outside the export are kept")
+	assert.Contains(t, onboarding, server.URL+"/missing.png", "failed downloads keep their URL")
+
+	assert.Contains(t, readFile(t, dir, "handbook-aaaaaaaa/tasks-dddddddd/write-docs-eeeeeeee.md"), "title: Write docs")
+}
+
+func TestExportPageResume(t *testing.T) {
+	t.Parallel()
+
+	ws := newWorkspace(newImageServer(t))
+	dir := t.TempDir()
+
+	first, err := NewExporter(NewExporterInput{Client: ws.client(), Dir: dir})
+	require.NoError(t, err)
+	_, err = first.ExportPage(context.Background(), handbookID)
+	require.NoError(t, err)
+
+	// An edit to one page and a deleted file are picked up; the rest is skipped
+	ws.pages[notion.NormalizeID(onboardingID)].LastEditedTime = time.Now().Add(time.Minute)
+	require.NoError(t, os.Remove(filepath.Join(dir, "handbook-aaaaaaaa", "tasks-dddddddd", "write-docs-eeeeeeee.md")))
+
+	mockClient := ws.client()
+	second, err := NewExporter(NewExporterInput{Client: mockClient, Dir: dir})
+	require.NoError(t, err)
+	result, err := second.ExportPage(context.Background(), handbookID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Written)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 2, mockClient.GetBlocksCallCount(), "only changed pages are fetched")
+	assert.Contains(t, readFile(t, dir, "handbook-aaaaaaaa.md"), "[Start here](handbook-aaaaaaaa/onboarding-cccccccc.md)")
+	assert.FileExists(t, filepath.Join(dir, "handbook-aaaaaaaa", "tasks-dddddddd", "write-docs-eeeeeeee.md"))
+}
+
+func TestExportPageCancelled(t *testing.T) {
+	t.Parallel()
+
+	ws := newWorkspace(newImageServer(t))
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exporter, err := NewExporter(NewExporterInput{
+		Client:   ws.client(),
+		Dir:      dir,
+		Progress: func(Progress) { cancel() },
+	})
+	require.NoError(t, err)
+
+	result, err := exporter.ExportPage(ctx, handbookID)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, result.Written)
+
+	m, err := loadManifest(dir)
+	require.NoError(t, err)
+	assert.Contains(t, m.Pages, notion.NormalizeID(handbookID), "finished pages are recorded for resuming")
+}
+
+func TestExportDatabase(t *testing.T) {
+	t.Parallel()
+
+	ws := newWorkspace(newImageServer(t))
+	dir := t.TempDir()
+
+	exporter, err := NewExporter(NewExporterInput{Client: ws.client(), Dir: dir})
+	require.NoError(t, err)
+	result, err := exporter.ExportDatabase(context.Background(), tasksID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Written)
+	assert.FileExists(t, filepath.Join(dir, "write-docs-eeeeeeee.md"))
+}
+
+func TestNewExporterErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewExporter(NewExporterInput{Dir: t.TempDir()})
+	assert.Error(t, err)
+
+	_, err = NewExporter(NewExporterInput{Client: testhelpers.NewMockNotionClient()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// maxSlugLength limits the title part of generated file names, in runes.
+const maxSlugLength = 60
+
+// slug turns a title into a file name part of lowercase letters, digits and
+// dashes.
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	n := 0
+	for _, r := range strings.ToLower(title) {
+		if n >= maxSlugLength {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			n++
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+			n++
+		}
+	}
+
+	s := strings.Trim(b.String(), "-")
+	if s == "" {
+		return "untitled"
+	}
+	return s
+}
+
+// shortID returns the first characters of an ID, enough to keep file names
+// of pages with the same title apart.
+func shortID(id string) string {
+	compact := strings.ReplaceAll(notion.NormalizeID(id), "-", "")
+	if len(compact) > 8 {
+		return compact[:8]
+	}
+	return compact
+}
+
+// BaseName returns the file name, without extension, used for a page or
+// database such as "launch-plan-1a2b3c4d".
+func BaseName(title, id string) string {
+	return slug(title) + "-" + shortID(id)
+}
+
+// extPattern matches file extensions that are kept on downloaded assets.
+var extPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// assetName returns the file name for an image or file downloaded from rawURL.
+// It is prefixed with the block ID so that files with the same name on one
+// page do not collide.
+func assetName(blockID, rawURL string) string {
+	name := "file"
+	ext := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		base := path.Base(u.Path)
+		if unescaped, err := url.PathUnescape(base); err == nil {
+			base = unescaped
+		}
+		ext = strings.ToLower(path.Ext(base))
+		if !extPattern.MatchString(ext) {
+			ext = ""
+		}
+		if stem := slug(strings.TrimSuffix(base, path.Ext(base))); stem != "untitled" {
+			name = stem
+		}
+	}
+	return shortID(blockID) + "-" + name + ext
+}
+
+// fileObject returns the file referenced by an image, file or PDF block, or
+// nil for other blocks.
+func fileObject(block notionapi.Block) *notionapi.FileObject {
+	var fileType notionapi.FileType
+	var file, external *notionapi.FileObject
+	switch b := block.(type) {
+	case *notionapi.ImageBlock:
+		fileType, file, external = b.Image.Type, b.Image.File, b.Image.External
+	case *notionapi.FileBlock:
+		fileType, file, external = b.File.Type, b.File.File, b.File.External
+	case *notionapi.PdfBlock:
+		fileType, file, external = b.Pdf.Type, b.Pdf.File, b.Pdf.External
+	default:
+		return nil
+	}
+
+	if fileType == notionapi.FileTypeExternal {
+		return external
+	}
+	return file
+}
+
+// downloadAssets downloads the images and files of a page into assetDir and
+// points their blocks at the local copies, relative to the page file. Files
+// that cannot be downloaded keep their URL and are reported as warnings.
+func (e *Exporter) downloadAssets(ctx context.Context, nodes []notion.BlockNode, assetDir string, result *Result) error {
+	for _, node := range nodes {
+		if obj := fileObject(node.Block); obj != nil && isHTTP(obj.URL) {
+			name := assetName(string(node.Block.GetID()), obj.URL)
+			dest := path.Join(assetDir, "files", name)
+			if err := e.download(ctx, obj.URL, filepath.Join(e.dir, filepath.FromSlash(dest))); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", dest, err))
+			} else {
+				obj.URL = path.Join(path.Base(assetDir), "files", name)
+				result.Assets++
+			}
+		}
+
+		if err := e.downloadAssets(ctx, node.Children, assetDir, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isHTTP reports whether rawURL can be downloaded.
+func isHTTP(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "https://") || strings.HasPrefix(rawURL, "http://")
+}
+
+// download saves the content of rawURL to dest.
+func (e *Exporter) download(ctx context.Context, rawURL, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download: %s", resp.Status)
+	}
+	return writeFile(dest, resp.Body)
+}
+
+// notionLink matches the target of a Markdown link to a Notion page.
+var notionLink = regexp.MustCompile(`\]\((https?://(?:www\.)?notion\.so/[^)\s]*)\)`)
+
+// rewriteLinks replaces links to exported pages and databases in the
+// Markdown of the page at from with paths relative to it. Links to anything
+// that was not exported are left pointing at Notion.
+func rewriteLinks(markdown, from string, targets map[string]string) string {
+	return notionLink.ReplaceAllStringFunc(markdown, func(match string) string {
+		link := notionLink.FindStringSubmatch(match)[1]
+		id, err := notion.ParseID(link)
+		if err != nil {
+			return match
+		}
+		target, ok := targets[id]
+		if !ok {
+			return match
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(target))
+		if err != nil {
+			return match
+		}
+		return "](" + filepath.ToSlash(rel) + ")"
+	})
+}
+
+// rewriteFileLinks applies rewriteLinks to an exported file.
+func (e *Exporter) rewriteFileLinks(from string, targets map[string]string) error {
+	file := filepath.Join(e.dir, filepath.FromSlash(from))
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", from, err)
+	}
+
+	rewritten := rewriteLinks(string(data), from, targets)
+	if rewritten == string(data) {
+		return nil
+	}
+	if err := writeFile(file, strings.NewReader(rewritten)); err != nil {
+		return fmt.Errorf("rewrite links in %s: %w", from, err)
+	}
+	return nil
+}
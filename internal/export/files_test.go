@@ -0,0 +1,74 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlug(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Launch Plan", want: "launch-plan"},
+		{title: "  Q3 / Q4: goals!  ", want: "q3-q4-goals"},
+		{title: "Café notes", want: "café-notes"},
+		{title: "???", want: "untitled"},
+		{title: "", want: "untitled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, slug(tt.title))
+		})
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "signed notion file",
+			url:  "https://prod-files-secure.s3.amazonaws.com/x/y/Screen%20Shot.PNG?X-Amz-Signature=abc",
+			want: "ffffffff-screen-shot.png",
+		},
+		{name: "no extension", url: "https://example.com/download", want: "ffffffff-download"},
+		{name: "no name", url: "https://example.com/", want: "ffffffff-file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, assetName(imageID, tt.url))
+		})
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	t.Parallel()
+
+	targets := map[string]string{
+		"aaaaaaaa000000000000000000000001": "handbook-aaaaaaaa.md",
+		"cccccccc000000000000000000000002": "handbook-aaaaaaaa/onboarding-cccccccc.md",
+	}
+
+	markdown := "See [intro](https://www.notion.so/acme/Onboarding-cccccccc000000000000000000000002) " +
+		"and [home](https://notion.so/aaaaaaaa000000000000000000000001#section), " +
+		"not [other](https://www.notion.so/bbbbbbbb000000000000000000000003)."
+
+	assert.Equal(t,
+		"See [intro](onboarding-cccccccc.md) and [home](../handbook-aaaaaaaa.md), "+
+			"not [other](https://www.notion.so/bbbbbbbb000000000000000000000003).",
+		rewriteLinks(markdown, "handbook-aaaaaaaa/other-11111111.md", targets))
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// FrontMatter is the YAML front matter of a page file, as written by export,
+// `get page --with-properties` and sync.
+type FrontMatter struct {
+	ID             string            `yaml:"id"`
+	Title          string            `yaml:"title"`
+	URL            string            `yaml:"url"`
+	CreatedTime    time.Time         `yaml:"created_time"`
+	LastEditedTime time.Time         `yaml:"last_edited_time"`
+	Properties     map[string]string `yaml:"properties,omitempty"`
+}
+
+// PageFrontMatter returns the front matter of a page, with its properties
+// rendered as text.
+func PageFrontMatter(page *notionapi.Page) FrontMatter {
+	properties := make(map[string]string, len(page.Properties))
+	for name, prop := range page.Properties {
+		properties[name] = notion.PropertyString(prop)
+	}
+
+	return FrontMatter{
+		ID:             string(page.ID),
+		Title:          notion.PageTitle(page),
+		URL:            page.URL,
+		CreatedTime:    page.CreatedTime,
+		LastEditedTime: page.LastEditedTime,
+		Properties:     properties,
+	}
+}
+
+// Render returns the front matter between its delimiters, followed by the
+// blank line that separates it from the page content.
+func (f FrontMatter) Render() ([]byte, error) {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("encode front matter: %w", err)
+	}
+	return fmt.Appendf(nil, "---\n%s---\n\n", data), nil
+}
+
+// ParseFrontMatter splits a page file into its front matter and its Markdown
+// body. A file without front matter is all body.
+func ParseFrontMatter(data []byte) (FrontMatter, string, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if !strings.HasPrefix(text, "---\n") {
+		return FrontMatter{}, text, nil
+	}
+	header, body, found := strings.Cut(text[len("---\n"):], "\n---\n")
+	if !found {
+		return FrontMatter{}, text, nil
+	}
+
+	var f FrontMatter
+	if err := yaml.Unmarshal([]byte(header), &f); err != nil {
+		return FrontMatter{}, "", fmt.Errorf("parse front matter: %w", err)
+	}
+	return f, body, nil
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontMatterRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	front := FrontMatter{
+		ID:             "page-1",
+		Title:          "Launch Plan",
+		URL:            "https://www.notion.so/page-1",
+		CreatedTime:    time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		LastEditedTime: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+		Properties:     map[string]string{"Status": "Doing"},
+	}
+
+	header, err := front.Render()
+	require.NoError(t, err)
+	assert.Contains(t, string(header), "---\nid: page-1\n")
+	assert.True(t, strings.HasSuffix(string(header), "\n---\n\n"))
+
+	parsed, body, err := ParseFrontMatter(append(header, "# Goals\n"...))
+	require.NoError(t, err)
+	assert.Equal(t, front, parsed)
+	assert.Equal(t, "\n# Goals\n", body)
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     string
+		wantID   string
+		wantBody string
+		wantErr  bool
+	}{
+		{name: "no front matter", data: "Just text\n", wantBody: "Just text\n"},
+		{name: "unterminated", data: "---\nid: abc\n", wantBody: "---\nid: abc\n"},
+		{name: "crlf", data: "---\r\nid: abc\r\n---\r\nBody\r\n", wantID: "abc", wantBody: "Body\n"},
+		{name: "malformed", data: "---\nproperties:\n  Tags: [a, b]\n---\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			front, body, err := ParseFrontMatter([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, front.ID)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+// manifestName is the file in the output directory that records what an
+// export has written.
+const manifestName = ".notion-export.json"
+
+// manifest records the pages written to an output directory so that a later
+// run can skip pages that have not changed and resume an interrupted export.
+type manifest struct {
+	Pages     map[string]*manifestEntry `json:"pages"`               // keyed by normalized page ID
+	Databases map[string]string         `json:"databases,omitempty"` // database ID to directory
+
+	path string
+}
+
+// manifestEntry describes one exported page. Paths use forward slashes and
+// are relative to the output directory.
+type manifestEntry struct {
+	Path           string    `json:"path"`
+	Title          string    `json:"title"`
+	LastEditedTime time.Time `json:"last_edited_time"`
+	Children       []child   `json:"children,omitempty"`
+}
+
+// child is a page or database found inside an exported page.
+type child struct {
+	ID       string `json:"id"`
+	Dir      string `json:"dir"`
+	Database bool   `json:"database,omitempty"`
+}
+
+// job returns the export work for the child.
+func (c child) job() job {
+	if c.Database {
+		return job{databaseID: c.ID, dir: c.Dir}
+	}
+	return job{id: c.ID, dir: c.Dir}
+}
+
+// loadManifest reads the manifest of an output directory. A missing or
+// corrupt manifest starts a fresh export.
+func loadManifest(dir string) (*manifest, error) {
+	m := &manifest{
+		Pages:     make(map[string]*manifestEntry),
+		Databases: make(map[string]string),
+		path:      filepath.Join(dir, manifestName),
+	}
+
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var stored manifest
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return m, nil
+	}
+	for id, entry := range stored.Pages {
+		if entry != nil {
+			m.Pages[id] = entry
+		}
+	}
+	for id, dir := range stored.Databases {
+		m.Databases[id] = dir
+	}
+	return m, nil
+}
+
+// save writes the manifest. It is called after every page so that an
+// interrupted run loses at most the page in progress.
+func (m *manifest) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := writeFile(m.path, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// writeFile copies r to path atomically, creating its directory, so an
+// interrupted write never leaves a truncated file behind.
+func writeFile(path string, r io.Reader) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory %s: %w", dir, err)
+	}
+
+	return fsutil.WriteReader(path, r, 0644)
+}
@@ -0,0 +1,404 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// Schema maps the property names of a database to their types.
+type Schema map[string]notionapi.PropertyConfigType
+
+// SchemaOf returns the schema of a database.
+func SchemaOf(db *notionapi.Database) Schema {
+	schema := make(Schema, len(db.Properties))
+	for name, prop := range db.Properties {
+		schema[name] = prop.GetType()
+	}
+	return schema
+}
+
+// lookup resolves a property name, ignoring case when there is no exact match.
+func (s Schema) lookup(name string) (string, notionapi.PropertyConfigType, bool) {
+	if t, ok := s[name]; ok {
+		return name, t, true
+	}
+	for candidate, t := range s {
+		if strings.EqualFold(candidate, name) {
+			return candidate, t, true
+		}
+	}
+	return "", "", false
+}
+
+// names returns the property names in alphabetical order.
+func (s Schema) names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compile converts a parsed expression into a Notion query filter. Property
+// types come from the schema. Relative dates such as "today" are resolved
+// against now.
+func Compile(e Expr, schema Schema, now time.Time) (notionapi.Filter, error) {
+	switch e := e.(type) {
+	case And:
+		filters, err := compileAll(e.Exprs, schema, now)
+		if err != nil {
+			return nil, err
+		}
+		return notionapi.AndCompoundFilter(filters), nil
+	case Or:
+		filters, err := compileAll(e.Exprs, schema, now)
+		if err != nil {
+			return nil, err
+		}
+		return notionapi.OrCompoundFilter(filters), nil
+	case Comparison:
+		return compileComparison(e, schema, now)
+	default:
+		return nil, fmt.Errorf("unsupported expression %T", e)
+	}
+}
+
+func compileAll(exprs []Expr, schema Schema, now time.Time) ([]notionapi.Filter, error) {
+	filters := make([]notionapi.Filter, 0, len(exprs))
+	for _, e := range exprs {
+		f, err := Compile(e, schema, now)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// compileComparison builds the filter for a single comparison.
+func compileComparison(c Comparison, schema Schema, now time.Time) (notionapi.Filter, error) {
+	name, propType, ok := schema.lookup(c.Property)
+	if !ok {
+		// The page timestamps can be filtered without a matching property
+		switch strings.ToLower(c.Property) {
+		case "created_time":
+			propType = notionapi.PropertyConfigCreatedTime
+		case "last_edited_time":
+			propType = notionapi.PropertyConfigLastEditedTime
+		default:
+			return nil, fmt.Errorf("unknown property %q (have: %s)", c.Property, strings.Join(schema.names(), ", "))
+		}
+	}
+
+	f := notionapi.PropertyFilter{Property: name}
+	var err error
+	switch propType {
+	case notionapi.PropertyConfigTypeTitle, notionapi.PropertyConfigTypeRichText,
+		notionapi.PropertyConfigTypeURL, notionapi.PropertyConfigTypeEmail,
+		notionapi.PropertyConfigTypePhoneNumber:
+		f.RichText, err = textCondition(c)
+
+	case notionapi.PropertyConfigTypeNumber:
+		f.Number, err = numberCondition(c)
+
+	case notionapi.PropertyConfigTypeCheckbox:
+		f.Checkbox, err = checkboxCondition(c)
+
+	case notionapi.PropertyConfigTypeSelect:
+		var cond *notionapi.StatusFilterCondition
+		if cond, err = optionCondition(c); cond != nil {
+			f.Select = (*notionapi.SelectFilterCondition)(cond)
+		}
+
+	case notionapi.PropertyConfigStatus:
+		f.Status, err = optionCondition(c)
+
+	case notionapi.PropertyConfigTypeMultiSelect:
+		var cond *notionapi.RelationFilterCondition
+		if cond, err = listCondition(c); cond != nil {
+			f.MultiSelect = (*notionapi.MultiSelectFilterCondition)(cond)
+		}
+
+	case notionapi.PropertyConfigTypePeople:
+		var cond *notionapi.RelationFilterCondition
+		if cond, err = listCondition(c); cond != nil {
+			f.People = (*notionapi.PeopleFilterCondition)(cond)
+		}
+
+	case notionapi.PropertyConfigTypeRelation:
+		f.Relation, err = listCondition(c)
+
+	case notionapi.PropertyConfigTypeFiles:
+		f.Files, err = filesCondition(c)
+
+	case notionapi.PropertyConfigTypeDate:
+		f.Date, err = dateCondition(c, now)
+
+	case notionapi.PropertyConfigCreatedTime:
+		cond, err := dateCondition(c, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Property, err)
+		}
+		return notionapi.TimestampFilter{Timestamp: notionapi.TimestampCreated, CreatedTime: cond}, nil
+
+	case notionapi.PropertyConfigLastEditedTime:
+		cond, err := dateCondition(c, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Property, err)
+		}
+		return notionapi.TimestampFilter{Timestamp: notionapi.TimestampLastEdited, LastEditedTime: cond}, nil
+
+	case notionapi.PropertyConfigUniqueID:
+		f.UniqueId, err = uniqueIDCondition(c)
+
+	default:
+		return nil, fmt.Errorf("filtering on %s properties is not supported (%s)", propType, c.Property)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Property, err)
+	}
+	return f, nil
+}
+
+// unsupported reports an operator that does not apply to a property type.
+func unsupported(c Comparison, kind string) error {
+	return fmt.Errorf("operator %q is not supported for %s properties", c.Op, kind)
+}
+
+func textCondition(c Comparison) (*notionapi.TextFilterCondition, error) {
+	cond := &notionapi.TextFilterCondition{}
+	// Empty strings are dropped from the request, so compare with emptiness instead
+	if c.Value == "" {
+		switch c.Op {
+		case OpEq:
+			c.Op = OpEmpty
+		case OpNe:
+			c.Op = OpNotEmpty
+		}
+	}
+
+	switch c.Op {
+	case OpEq:
+		cond.Equals = c.Value
+	case OpNe:
+		cond.DoesNotEqual = c.Value
+	case OpContains:
+		cond.Contains = c.Value
+	case OpNotContains:
+		cond.DoesNotContain = c.Value
+	case OpStartsWith:
+		cond.StartsWith = c.Value
+	case OpEndsWith:
+		cond.EndsWith = c.Value
+	case OpEmpty:
+		cond.IsEmpty = true
+	case OpNotEmpty:
+		cond.IsNotEmpty = true
+	default:
+		return nil, unsupported(c, "text")
+	}
+	return cond, nil
+}
+
+func numberCondition(c Comparison) (*notionapi.NumberFilterCondition, error) {
+	cond := &notionapi.NumberFilterCondition{}
+	switch c.Op {
+	case OpEmpty:
+		cond.IsEmpty = true
+		return cond, nil
+	case OpNotEmpty:
+		cond.IsNotEmpty = true
+		return cond, nil
+	}
+
+	n, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", c.Value)
+	}
+	switch c.Op {
+	case OpEq:
+		cond.Equals = &n
+	case OpNe:
+		cond.DoesNotEqual = &n
+	case OpLt:
+		cond.LessThan = &n
+	case OpLe:
+		cond.LessThanOrEqualTo = &n
+	case OpGt:
+		cond.GreaterThan = &n
+	case OpGe:
+		cond.GreaterThanOrEqualTo = &n
+	default:
+		return nil, unsupported(c, "number")
+	}
+	return cond, nil
+}
+
+func checkboxCondition(c Comparison) (*notionapi.CheckboxFilterCondition, error) {
+	var want bool
+	switch strings.ToLower(c.Value) {
+	case "true", "yes", "checked":
+		want = true
+	case "false", "no", "unchecked":
+		want = false
+	default:
+		return nil, fmt.Errorf("%q is not a checkbox value (want true or false)", c.Value)
+	}
+
+	switch c.Op {
+	case OpEq:
+	case OpNe:
+		want = !want
+	default:
+		return nil, unsupported(c, "checkbox")
+	}
+
+	// false values are dropped from the request, so express them as negations
+	if want {
+		return &notionapi.CheckboxFilterCondition{Equals: true}, nil
+	}
+	return &notionapi.CheckboxFilterCondition{DoesNotEqual: true}, nil
+}
+
+// optionCondition builds a condition for select and status properties, which
+// share the same shape.
+func optionCondition(c Comparison) (*notionapi.StatusFilterCondition, error) {
+	cond := &notionapi.StatusFilterCondition{}
+	switch {
+	case c.Op == OpEmpty || (c.Op == OpEq && c.Value == ""):
+		cond.IsEmpty = true
+	case c.Op == OpNotEmpty || (c.Op == OpNe && c.Value == ""):
+		cond.IsNotEmpty = true
+	case c.Op == OpEq:
+		cond.Equals = c.Value
+	case c.Op == OpNe:
+		cond.DoesNotEqual = c.Value
+	default:
+		return nil, unsupported(c, "select and status")
+	}
+	return cond, nil
+}
+
+// listCondition builds a condition for multi-select, people and relation
+// properties, which share the same shape. = and != test membership.
+func listCondition(c Comparison) (*notionapi.RelationFilterCondition, error) {
+	cond := &notionapi.RelationFilterCondition{}
+	switch c.Op {
+	case OpEq, OpContains:
+		cond.Contains = c.Value
+	case OpNe, OpNotContains:
+		cond.DoesNotContain = c.Value
+	case OpEmpty:
+		cond.IsEmpty = true
+	case OpNotEmpty:
+		cond.IsNotEmpty = true
+	default:
+		return nil, unsupported(c, "multi-select, people and relation")
+	}
+	if (cond.Contains == "" && cond.DoesNotContain == "") && !cond.IsEmpty && !cond.IsNotEmpty {
+		return nil, fmt.Errorf("operator %q needs a value", c.Op)
+	}
+	return cond, nil
+}
+
+func filesCondition(c Comparison) (*notionapi.FilesFilterCondition, error) {
+	switch c.Op {
+	case OpEmpty:
+		return &notionapi.FilesFilterCondition{IsEmpty: true}, nil
+	case OpNotEmpty:
+		return &notionapi.FilesFilterCondition{IsNotEmpty: true}, nil
+	default:
+		return nil, unsupported(c, "files")
+	}
+}
+
+// relativeRanges are the date values that select a range relative to today.
+var relativeRanges = map[string]func(*notionapi.DateFilterCondition){
+	"past_week":  func(d *notionapi.DateFilterCondition) { d.PastWeek = &struct{}{} },
+	"past_month": func(d *notionapi.DateFilterCondition) { d.PastMonth = &struct{}{} },
+	"past_year":  func(d *notionapi.DateFilterCondition) { d.PastYear = &struct{}{} },
+	"next_week":  func(d *notionapi.DateFilterCondition) { d.NextWeek = &struct{}{} },
+	"next_month": func(d *notionapi.DateFilterCondition) { d.NextMonth = &struct{}{} },
+	"next_year":  func(d *notionapi.DateFilterCondition) { d.NextYear = &struct{}{} },
+}
+
+func dateCondition(c Comparison, now time.Time) (*notionapi.DateFilterCondition, error) {
+	cond := &notionapi.DateFilterCondition{}
+	switch c.Op {
+	case OpEmpty:
+		cond.IsEmpty = true
+		return cond, nil
+	case OpNotEmpty:
+		cond.IsNotEmpty = true
+		return cond, nil
+	}
+
+	if set, ok := relativeRanges[strings.ToLower(c.Value)]; ok {
+		if c.Op != OpEq {
+			return nil, fmt.Errorf("%s can only be compared with =", c.Value)
+		}
+		set(cond)
+		return cond, nil
+	}
+
+	t, err := notion.ParseDate(c.Value, now)
+	if err != nil {
+		return nil, err
+	}
+	d := notionapi.Date(t)
+	switch c.Op {
+	case OpEq:
+		cond.Equals = &d
+	case OpLt:
+		cond.Before = &d
+	case OpLe:
+		cond.OnOrBefore = &d
+	case OpGt:
+		cond.After = &d
+	case OpGe:
+		cond.OnOrAfter = &d
+	default:
+		return nil, unsupported(c, "date")
+	}
+	return cond, nil
+}
+
+func uniqueIDCondition(c Comparison) (*notionapi.UniqueIdFilterCondition, error) {
+	// IDs are shown with their prefix, such as TASK-42
+	value := c.Value
+	if i := strings.LastIndexByte(value, '-'); i >= 0 {
+		value = value[i+1:]
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a unique ID", c.Value)
+	}
+
+	cond := &notionapi.UniqueIdFilterCondition{}
+	switch c.Op {
+	case OpEq:
+		cond.Equals = &n
+	case OpNe:
+		cond.DoesNotEqual = &n
+	case OpLt:
+		cond.LessThan = &n
+	case OpLe:
+		cond.LessThanOrEqualTo = &n
+	case OpGt:
+		cond.GreaterThan = &n
+	case OpGe:
+		cond.GreaterThanOrEqualTo = &n
+	default:
+		return nil, unsupported(c, "unique ID")
+	}
+	return cond, nil
+}
@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchema covers every property type the compiler handles.
+var testSchema = Schema{
+	"Name":     notionapi.PropertyConfigTypeTitle,
+	"Notes":    notionapi.PropertyConfigTypeRichText,
+	"Priority": notionapi.PropertyConfigTypeNumber,
+	"Done":     notionapi.PropertyConfigTypeCheckbox,
+	"Stage":    notionapi.PropertyConfigTypeSelect,
+	"Status":   notionapi.PropertyConfigStatus,
+	"Tags":     notionapi.PropertyConfigTypeMultiSelect,
+	"Owner":    notionapi.PropertyConfigTypePeople,
+	"Files":    notionapi.PropertyConfigTypeFiles,
+	"Due":      notionapi.PropertyConfigTypeDate,
+	"ID":       notionapi.PropertyConfigUniqueID,
+	"Score":    notionapi.PropertyConfigTypeFormula,
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func date(t time.Time) *notionapi.Date {
+	d := notionapi.Date(t)
+	return &d
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input string
+		want  notionapi.Filter
+	}{
+		{
+			name:  "title contains",
+			input: `name contains launch`,
+			want: notionapi.PropertyFilter{Property: "Name",
+				RichText: &notionapi.TextFilterCondition{Contains: "launch"}},
+		},
+		{
+			name:  "empty text equality",
+			input: `Notes = ""`,
+			want: notionapi.PropertyFilter{Property: "Notes",
+				RichText: &notionapi.TextFilterCondition{IsEmpty: true}},
+		},
+		{
+			name:  "number",
+			input: `Priority >= 2.5`,
+			want: notionapi.PropertyFilter{Property: "Priority",
+				Number: &notionapi.NumberFilterCondition{GreaterThanOrEqualTo: ptr(2.5)}},
+		},
+		{
+			name:  "checkbox true",
+			input: `Done = yes`,
+			want: notionapi.PropertyFilter{Property: "Done",
+				Checkbox: &notionapi.CheckboxFilterCondition{Equals: true}},
+		},
+		{
+			name:  "checkbox false",
+			input: `Done = false`,
+			want: notionapi.PropertyFilter{Property: "Done",
+				Checkbox: &notionapi.CheckboxFilterCondition{DoesNotEqual: true}},
+		},
+		{
+			name:  "select",
+			input: `Stage != Backlog`,
+			want: notionapi.PropertyFilter{Property: "Stage",
+				Select: &notionapi.SelectFilterCondition{DoesNotEqual: "Backlog"}},
+		},
+		{
+			name:  "status",
+			input: `Status = "In progress"`,
+			want: notionapi.PropertyFilter{Property: "Status",
+				Status: &notionapi.StatusFilterCondition{Equals: "In progress"}},
+		},
+		{
+			name:  "multi-select membership",
+			input: `Tags = urgent`,
+			want: notionapi.PropertyFilter{Property: "Tags",
+				MultiSelect: &notionapi.MultiSelectFilterCondition{Contains: "urgent"}},
+		},
+		{
+			name:  "people empty",
+			input: `Owner is empty`,
+			want: notionapi.PropertyFilter{Property: "Owner",
+				People: &notionapi.PeopleFilterCondition{IsEmpty: true}},
+		},
+		{
+			name:  "files not empty",
+			input: `Files is not empty`,
+			want: notionapi.PropertyFilter{Property: "Files",
+				Files: &notionapi.FilesFilterCondition{IsNotEmpty: true}},
+		},
+		{
+			name:  "date before today",
+			input: `Due < today`,
+			want: notionapi.PropertyFilter{Property: "Due",
+				Date: &notionapi.DateFilterCondition{Before: date(today)}},
+		},
+		{
+			name:  "date on or after a day",
+			input: `Due >= 2024-04-01`,
+			want: notionapi.PropertyFilter{Property: "Due",
+				Date: &notionapi.DateFilterCondition{OnOrAfter: date(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))}},
+		},
+		{
+			name:  "date relative range",
+			input: `Due = next_week`,
+			want: notionapi.PropertyFilter{Property: "Due",
+				Date: &notionapi.DateFilterCondition{NextWeek: &struct{}{}}},
+		},
+		{
+			name:  "unique ID with prefix",
+			input: `ID = TASK-42`,
+			want: notionapi.PropertyFilter{Property: "ID",
+				UniqueId: &notionapi.UniqueIdFilterCondition{Equals: ptr(42)}},
+		},
+		{
+			name:  "created time pseudo-property",
+			input: `created_time > yesterday`,
+			want: notionapi.TimestampFilter{Timestamp: notionapi.TimestampCreated,
+				CreatedTime: &notionapi.DateFilterCondition{After: date(today.AddDate(0, 0, -1))}},
+		},
+		{
+			name:  "compound",
+			input: `Done = false and (Priority > 1 or Tags = urgent)`,
+			want: notionapi.AndCompoundFilter{
+				notionapi.PropertyFilter{Property: "Done",
+					Checkbox: &notionapi.CheckboxFilterCondition{DoesNotEqual: true}},
+				notionapi.OrCompoundFilter{
+					notionapi.PropertyFilter{Property: "Priority",
+						Number: &notionapi.NumberFilterCondition{GreaterThan: ptr(1.0)}},
+					notionapi.PropertyFilter{Property: "Tags",
+						MultiSelect: &notionapi.MultiSelectFilterCondition{Contains: "urgent"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse(tt.input)
+			require.NoError(t, err)
+			got, err := Compile(expr, testSchema, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "unknown property", input: `Owner2 = me`, wantErr: `unknown property "Owner2"`},
+		{name: "operator not valid for type", input: `Stage > 3`, wantErr: `operator ">" is not supported for select and status properties`},
+		{name: "not a number", input: `Priority = high`, wantErr: `"high" is not a number`},
+		{name: "not a checkbox value", input: `Done = maybe`, wantErr: `"maybe" is not a checkbox value`},
+		{name: "not a date", input: `Due < soon`, wantErr: `"soon" is not a date`},
+		{name: "relative range with order", input: `Due < past_week`, wantErr: "can only be compared with ="},
+		{name: "unsupported type", input: `Score = 1`, wantErr: "filtering on formula properties is not supported"},
+		{name: "error inside compound", input: `Done = true and Priority = x`, wantErr: "Priority: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse(tt.input)
+			require.NoError(t, err)
+			_, err = Compile(expr, testSchema, time.Now())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Package filter compiles a small expression language into Notion database
+// query filters and sorts, for example:
+//
+//	Status = "In progress" and (Due < today or Priority >= 2)
+//
+// Grammar:
+//
+//	expr       = and { "or" and }
+//	and        = term { "and" term }
+//	term       = "(" expr ")" | comparison
+//	comparison = property op value | property "is" [ "not" ] "empty"
+//	op         = "=" | "!=" | "<" | "<=" | ">" | ">=" | "contains" |
+//	             "not" "contains" | "starts_with" | "ends_with"
+//
+// Properties and values are bare words or quoted strings. Keywords are
+// case-insensitive.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Op is a comparison operator.
+type Op string
+
+// Comparison operators. Which of them apply depends on the property type.
+const (
+	OpEq          Op = "="
+	OpNe          Op = "!="
+	OpLt          Op = "<"
+	OpLe          Op = "<="
+	OpGt          Op = ">"
+	OpGe          Op = ">="
+	OpContains    Op = "contains"
+	OpNotContains Op = "not contains"
+	OpStartsWith  Op = "starts_with"
+	OpEndsWith    Op = "ends_with"
+	OpEmpty       Op = "is empty"
+	OpNotEmpty    Op = "is not empty"
+)
+
+// Expr is a parsed filter expression: an And, an Or or a Comparison.
+type Expr interface {
+	expr()
+}
+
+// And matches when every expression matches.
+type And struct {
+	Exprs []Expr
+}
+
+// Or matches when any expression matches.
+type Or struct {
+	Exprs []Expr
+}
+
+// Comparison compares a property with a value. Value is empty for OpEmpty
+// and OpNotEmpty.
+type Comparison struct {
+	Property string
+	Op       Op
+	Value    string
+}
+
+func (And) expr()        {}
+func (Or) expr()         {}
+func (Comparison) expr() {}
+
+// SyntaxError reports an invalid filter expression.
+type SyntaxError struct {
+	Pos int // byte offset in the expression
+	Msg string
+}
+
+// Error implements error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("filter syntax error at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// tokenKind identifies the kind of a lexical token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexical token of a filter expression.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is reports whether t is the given keyword, ignoring case.
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// lex splits an expression into tokens.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case c == '"' || c == '\'':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, &SyntaxError{Pos: i, Msg: err.Error()}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i += n
+
+		case strings.ContainsRune("=!<>", rune(c)):
+			start := i
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			i += len(op)
+			switch op {
+			case "!":
+				return nil, &SyntaxError{Pos: start, Msg: `unexpected "!"`}
+			case "==":
+				op = "="
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start})
+
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r()\"'=!<>", rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[start:i], pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(s)}), nil
+}
+
+// lexString reads a quoted string at the start of s and returns its unquoted
+// text and length. A backslash escapes the next character.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// parser is a recursive descent parser over tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a filter expression.
+func Parse(s string) (Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty expression"}
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", describe(t))}
+	}
+	return e, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{first}
+	for p.peek().is("or") {
+		p.next()
+		e, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return Or{Exprs: exprs}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	first, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{first}
+	for p.peek().is("and") {
+		p.next()
+		e, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return And{Exprs: exprs}, nil
+}
+
+func (p *parser) parseTerm() (Expr, error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(`expected ")", found %s`, describe(t))}
+		}
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	prop := p.next()
+	if prop.kind != tokenWord && prop.kind != tokenString {
+		return nil, &SyntaxError{Pos: prop.pos, Msg: fmt.Sprintf("expected a property name, found %s", describe(prop))}
+	}
+
+	opToken := p.next()
+	var op Op
+	switch {
+	case opToken.kind == tokenOp:
+		op = Op(opToken.text)
+	case opToken.is("contains"):
+		op = OpContains
+	case opToken.is("starts_with"):
+		op = OpStartsWith
+	case opToken.is("ends_with"):
+		op = OpEndsWith
+	case opToken.is("not"):
+		if t := p.next(); !t.is("contains") {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(`expected "contains" after "not", found %s`, describe(t))}
+		}
+		op = OpNotContains
+	case opToken.is("is"):
+		op = OpEmpty
+		if p.peek().is("not") {
+			p.next()
+			op = OpNotEmpty
+		}
+		if t := p.next(); !t.is("empty") {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(`expected "empty", found %s`, describe(t))}
+		}
+		return Comparison{Property: prop.text, Op: op}, nil
+	default:
+		return nil, &SyntaxError{Pos: opToken.pos, Msg: fmt.Sprintf("expected an operator after %q, found %s", prop.text, describe(opToken))}
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, &SyntaxError{Pos: value.pos, Msg: fmt.Sprintf("expected a value, found %s", describe(value))}
+	}
+	return Comparison{Property: prop.text, Op: op, Value: value.text}, nil
+}
+
+// describe names a token for error messages.
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  Expr
+	}{
+		{
+			name:  "single comparison",
+			input: `Priority >= 2`,
+			want:  Comparison{Property: "Priority", Op: OpGe, Value: "2"},
+		},
+		{
+			name:  "quoted property and value",
+			input: `"Due date" = 'it\'s'`,
+			want:  Comparison{Property: "Due date", Op: OpEq, Value: "it's"},
+		},
+		{
+			name:  "operators without spaces",
+			input: `Priority!=3`,
+			want:  Comparison{Property: "Priority", Op: OpNe, Value: "3"},
+		},
+		{
+			name:  "double equals",
+			input: `Done == true`,
+			want:  Comparison{Property: "Done", Op: OpEq, Value: "true"},
+		},
+		{
+			name:  "word operators ignore case",
+			input: `Name NOT CONTAINS draft`,
+			want:  Comparison{Property: "Name", Op: OpNotContains, Value: "draft"},
+		},
+		{
+			name:  "is not empty",
+			input: `Assignee is not empty`,
+			want:  Comparison{Property: "Assignee", Op: OpNotEmpty},
+		},
+		{
+			name:  "and binds tighter than or",
+			input: `A = 1 or B = 2 and C = 3`,
+			want: Or{Exprs: []Expr{
+				Comparison{Property: "A", Op: OpEq, Value: "1"},
+				And{Exprs: []Expr{
+					Comparison{Property: "B", Op: OpEq, Value: "2"},
+					Comparison{Property: "C", Op: OpEq, Value: "3"},
+				}},
+			}},
+		},
+		{
+			name:  "parentheses",
+			input: `Status = "In progress" and (Due < today or Priority >= 2)`,
+			want: And{Exprs: []Expr{
+				Comparison{Property: "Status", Op: OpEq, Value: "In progress"},
+				Or{Exprs: []Expr{
+					Comparison{Property: "Due", Op: OpLt, Value: "today"},
+					Comparison{Property: "Priority", Op: OpGe, Value: "2"},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantPos int
+		wantMsg string
+	}{
+		{name: "empty", input: "  ", wantPos: 0, wantMsg: "empty expression"},
+		{name: "missing operator", input: "Status", wantPos: 6, wantMsg: `expected an operator after "Status"`},
+		{name: "missing value", input: "Status =", wantPos: 8, wantMsg: "expected a value"},
+		{name: "unterminated string", input: `Name = "draft`, wantPos: 7, wantMsg: "unterminated string"},
+		{name: "lone bang", input: "Done ! true", wantPos: 5, wantMsg: `unexpected "!"`},
+		{name: "unclosed paren", input: "(A = 1", wantPos: 6, wantMsg: `expected ")"`},
+		{name: "trailing token", input: "A = 1 B", wantPos: 6, wantMsg: `unexpected "B"`},
+		{name: "is without empty", input: "A is set", wantPos: 5, wantMsg: `expected "empty"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.input)
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.wantPos, syntaxErr.Pos)
+			assert.Contains(t, syntaxErr.Msg, tt.wantMsg)
+		})
+	}
+}
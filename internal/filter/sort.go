@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jomei/notionapi"
+)
+
+// ParseSorts parses sort keys such as "-Due" or "Priority,-created_time".
+// Keys are comma-separated; a leading "-" sorts descending and an optional
+// leading "+" ascending. created_time and last_edited_time sort by the page
+// timestamps unless the database has a property of that name.
+func ParseSorts(specs []string, schema Schema) ([]notionapi.SortObject, error) {
+	var sorts []notionapi.SortObject
+	for _, spec := range specs {
+		for _, key := range strings.Split(spec, ",") {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+
+			direction := notionapi.SortOrderASC
+			switch key[0] {
+			case '-':
+				direction = notionapi.SortOrderDESC
+				key = key[1:]
+			case '+':
+				key = key[1:]
+			}
+
+			if name, _, ok := schema.lookup(key); ok {
+				sorts = append(sorts, notionapi.SortObject{Property: name, Direction: direction})
+				continue
+			}
+
+			switch strings.ToLower(key) {
+			case "created_time":
+				sorts = append(sorts, notionapi.SortObject{Timestamp: notionapi.TimestampCreated, Direction: direction})
+			case "last_edited_time":
+				sorts = append(sorts, notionapi.SortObject{Timestamp: notionapi.TimestampLastEdited, Direction: direction})
+			default:
+				return nil, fmt.Errorf("unknown sort property %q (have: %s)", key, strings.Join(schema.names(), ", "))
+			}
+		}
+	}
+	return sorts, nil
+}
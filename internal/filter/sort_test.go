@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSorts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []notionapi.SortObject
+		wantErr string
+	}{
+		{
+			name:  "none",
+			specs: nil,
+			want:  nil,
+		},
+		{
+			name:  "descending property",
+			specs: []string{"-Due"},
+			want:  []notionapi.SortObject{{Property: "Due", Direction: notionapi.SortOrderDESC}},
+		},
+		{
+			name:  "comma separated and repeated",
+			specs: []string{"+priority, Name", "-last_edited_time"},
+			want: []notionapi.SortObject{
+				{Property: "Priority", Direction: notionapi.SortOrderASC},
+				{Property: "Name", Direction: notionapi.SortOrderASC},
+				{Timestamp: notionapi.TimestampLastEdited, Direction: notionapi.SortOrderDESC},
+			},
+		},
+		{
+			name:    "unknown property",
+			specs:   []string{"Size"},
+			wantErr: `unknown sort property "Size"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSorts(tt.specs, testSchema)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package fsutil replaces files atomically: a reader, or the file after a
+// crash, holds either the old content or the new, never a partial write.
+package fsutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// TempSuffix ends the name of the temporary file a write goes to before it is
+// renamed into place. Such files are left behind only by a crashed process.
+const TempSuffix = ".tmp"
+
+// WriteFile writes data to path atomically. A new file is created with perm;
+// a file being replaced keeps its mode.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return WriteReader(path, bytes.NewReader(data), perm)
+}
+
+// WriteReader copies r to a temporary file beside path, flushes it to disk and
+// renames it over path. A new file is created with perm; a file being
+// replaced keeps its mode. The directory of path must exist.
+func WriteReader(path string, r io.Reader, perm os.FileMode) error {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		perm = info.Mode().Perm()
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*"+TempSuffix)
+	if err != nil {
+		return fmt.Errorf("create temp file in %s: %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file %s: %w", tmp.Name(), err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("chmod temp file %s: %w", tmp.Name(), err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename %s to %s: %w", tmp.Name(), path, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	require.NoError(t, WriteFile(path, []byte("first"), 0600))
+	require.NoError(t, WriteFile(path, []byte("second"), 0600))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "no temp files are left")
+	assert.False(t, strings.HasSuffix(files[0].Name(), TempSuffix))
+}
+
+func TestWriteFileMode(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not kept on Windows")
+	}
+
+	dir := t.TempDir()
+
+	// A new file gets perm
+	created := filepath.Join(dir, "new.md")
+	require.NoError(t, WriteFile(created, []byte("x"), 0644))
+	info, err := os.Stat(created)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// A replaced file keeps the mode its owner gave it
+	existing := filepath.Join(dir, "script.md")
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0600))
+	require.NoError(t, os.Chmod(existing, 0750))
+	require.NoError(t, WriteFile(existing, []byte("new"), 0644))
+	info, err = os.Stat(existing)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+}
+
+func TestWriteReaderMissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	err := WriteReader(filepath.Join(t.TempDir(), "missing", "file"), strings.NewReader("x"), 0600)
+	assert.Error(t, err)
+}
@@ -0,0 +1,433 @@
+// Package importer creates Notion pages from a directory of Markdown files.
+// Files become rows of a database, with front matter mapped to properties
+// through the database schema, or pages below a page. Sub-directories become
+// child pages, so the folder hierarchy is kept. Pages are created one at a time
+// through the client, which applies its rate limit to every request. A
+// manifest in the directory records the pages created, so a re-run skips
+// files that were already imported.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// metadataKeys are front matter keys written by `export` that describe the
+// exported page rather than its properties. They are skipped silently unless
+// the database has a property of the same name.
+var metadataKeys = map[string]bool{
+	"id":               true,
+	"url":              true,
+	"created_time":     true,
+	"last_edited_time": true,
+}
+
+// Client is the subset of the Notion client used to import pages.
+type Client interface {
+	notion.BlockAppender
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
+	GetPage(ctx context.Context, id string) (*notionapi.Page, error)
+	CreatePage(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error)
+	DeleteBlock(ctx context.Context, id string) (notionapi.Block, error)
+}
+
+// Target is the database or page that files are imported into.
+type Target struct {
+	ID       string
+	Title    string
+	Database bool
+
+	titleProperty string                    // name of the database's title property
+	schema        notionapi.PropertyConfigs // database properties
+}
+
+// Item is a page the import creates from a Markdown file or a directory.
+type Item struct {
+	Path       string               // relative to the import directory; directories end in "/"
+	Title      string               // page title
+	Parent     *Item                // nil for pages created directly in the target
+	Properties notionapi.Properties // database properties other than the title
+	Blocks     []notion.BlockNode   // page content
+	Done       bool                 // created by a previous run
+	ID         string               // page ID, once created
+	URL        string               // page URL, once created
+}
+
+// Plan lists the pages an import creates, each parent before its children.
+type Plan struct {
+	Target   Target
+	Items    []*Item
+	Warnings []string // problems that do not stop the import, such as unknown properties
+
+	manifest *manifest
+	warned   map[string]bool
+}
+
+// warn records a warning once, however many files it applies to.
+func (p *Plan) warn(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if p.warned[msg] {
+		return
+	}
+	p.warned[msg] = true
+	p.Warnings = append(p.Warnings, msg)
+}
+
+// Pending returns the number of pages the plan still has to create.
+func (p *Plan) Pending() int {
+	n := 0
+	for _, item := range p.Items {
+		if !item.Done {
+			n++
+		}
+	}
+	return n
+}
+
+// Progress reports a page that has been created or skipped.
+type Progress struct {
+	Done    int   // pages handled so far
+	Total   int   // pages in the plan
+	Item    *Item // the page just handled
+	Skipped bool  // the page was created by a previous run
+}
+
+// Result summarizes an import.
+type Result struct {
+	Created int // pages created
+	Skipped int // pages created by a previous run
+}
+
+// Importer plans and runs imports from a directory.
+type Importer struct {
+	client       Client
+	dir          string
+	assetBaseURL string
+	now          time.Time
+	progress     func(Progress)
+}
+
+// NewImporterInput contains the parameters for creating an Importer.
+type NewImporterInput struct {
+	Client       Client
+	Dir          string         // directory of Markdown files
+	AssetBaseURL string         // optional; URL the directory is served at, used to link local images
+	Now          time.Time      // resolves dates such as "today"; default time.Now()
+	Progress     func(Progress) // optional; called after every page
+}
+
+// NewImporter creates an Importer.
+func NewImporter(input NewImporterInput) (*Importer, error) {
+	if input.Client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	info, err := os.Stat(input.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("import directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("import directory: %s is not a directory", input.Dir)
+	}
+
+	now := input.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	progress := input.Progress
+	if progress == nil {
+		progress = func(Progress) {}
+	}
+
+	return &Importer{
+		client:       input.Client,
+		dir:          input.Dir,
+		assetBaseURL: input.AssetBaseURL,
+		now:          now,
+		progress:     progress,
+	}, nil
+}
+
+// Plan reads the directory and returns the pages an import into the target
+// database or page creates. Nothing is created; files with front matter that
+// cannot be converted are reported as errors.
+func (im *Importer) Plan(ctx context.Context, targetID string) (*Plan, error) {
+	target, err := im.target(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := loadManifest(im.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Target: target, manifest: m, warned: make(map[string]bool)}
+	if err := im.collect(plan, "", nil); err != nil {
+		return nil, err
+	}
+
+	entries := m.Targets[notion.NormalizeID(target.ID)]
+	for _, item := range plan.Items {
+		if entry := entries[item.Path]; entry != nil && entry.Complete {
+			item.Done = true
+			item.ID, item.URL = entry.ID, entry.URL
+		}
+	}
+	return plan, nil
+}
+
+// target looks up the database or page to import into.
+func (im *Importer) target(ctx context.Context, id string) (Target, error) {
+	if db, err := im.client.GetDatabase(ctx, id); err == nil {
+		target := Target{
+			ID:       id,
+			Title:    notion.GetRichTextString(db.Title),
+			Database: true,
+			schema:   db.Properties,
+		}
+		for name, config := range db.Properties {
+			if config.GetType() == notionapi.PropertyConfigTypeTitle {
+				target.titleProperty = name
+			}
+		}
+		return target, nil
+	}
+
+	page, err := im.client.GetPage(ctx, id)
+	if err != nil {
+		return Target{}, fmt.Errorf("get import target %s: %w", id, err)
+	}
+	return Target{ID: id, Title: notion.PageTitle(page)}, nil
+}
+
+// collect adds the pages for the files and sub-directories of dir, a path
+// relative to the import directory. A directory next to a file of the same
+// name, such as "guide/" beside "guide.md", holds that file's child pages;
+// other directories become pages of their own.
+func (im *Importer) collect(plan *Plan, dir string, parent *Item) error {
+	abs := filepath.Join(im.dir, filepath.FromSlash(dir))
+	entries, err := os.ReadDir(abs)
+	if err != nil {
+		return fmt.Errorf("read directory: %w", err)
+	}
+
+	stems := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() && isMarkdown(entry.Name()) {
+			stems[stem(entry.Name())] = true
+		}
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		rel := path.Join(dir, name)
+
+		switch {
+		case entry.IsDir():
+			if stems[name] || !hasMarkdown(filepath.Join(abs, name)) {
+				continue
+			}
+			item := &Item{Path: rel + "/", Title: name, Parent: parent}
+			plan.Items = append(plan.Items, item)
+			if err := im.collect(plan, rel, item); err != nil {
+				return err
+			}
+
+		case isMarkdown(name):
+			item, err := im.fileItem(plan, rel, parent)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			plan.Items = append(plan.Items, item)
+
+			sub := path.Join(dir, stem(name))
+			if info, err := os.Stat(filepath.Join(im.dir, filepath.FromSlash(sub))); err == nil && info.IsDir() {
+				if err := im.collect(plan, sub, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fileItem converts a Markdown file into a page. The title comes from the
+// front matter, a leading "# " heading or the file name, in that order.
+func (im *Importer) fileItem(plan *Plan, rel string, parent *Item) (*Item, error) {
+	src, err := readSource(filepath.Join(im.dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+
+	item := &Item{Path: rel, Parent: parent}
+	if parent == nil && plan.Target.Database {
+		if item.Title, item.Properties, err = im.properties(plan, src.fields); err != nil {
+			return nil, err
+		}
+	} else {
+		item.Title, _ = fieldString(src.fields["title"])
+		for key := range src.fields {
+			if key != "title" && !metadataKeys[key] {
+				plan.warn("%s: front matter is only imported as properties of database rows", rel)
+				break
+			}
+		}
+	}
+
+	body := src.body
+	if item.Title == "" {
+		item.Title, body = takeHeading(body)
+	}
+	if item.Title == "" {
+		item.Title = stem(path.Base(rel))
+	}
+
+	body, warnings := linkImages(body, rel, im.assetBaseURL)
+	for _, warning := range warnings {
+		plan.warn("%s: %s", rel, warning)
+	}
+	item.Blocks = notion.ParseMarkdown(body)
+	return item, nil
+}
+
+// properties maps front matter to the properties of the target database and
+// returns the title separately. Keys are matched to property names exactly,
+// then ignoring case.
+func (im *Importer) properties(plan *Plan, fields map[string]any) (string, notionapi.Properties, error) {
+	var title string
+	properties := notionapi.Properties{}
+
+	for _, key := range sortedKeys(fields) {
+		name, config := lookupProperty(plan.Target.schema, key)
+		if config == nil {
+			switch {
+			case strings.EqualFold(key, "title"):
+				title, _ = fieldString(fields[key])
+			case !metadataKeys[key]:
+				plan.warn("front matter key %q does not match a property of %s and is ignored", key, plan.Target.Title)
+			}
+			continue
+		}
+
+		value, ok := fieldString(fields[key])
+		if !ok {
+			if fields[key] != nil {
+				plan.warn("front matter key %q has a value that cannot be imported", key)
+			}
+			continue
+		}
+		if config.GetType() == notionapi.PropertyConfigTypeTitle {
+			title = value
+			continue
+		}
+
+		prop, err := notion.ParsePropertyValue(config.GetType(), value, im.now)
+		if err != nil {
+			if errors.Is(err, notion.ErrUnsupportedProperty) {
+				plan.warn("property %q is ignored: %v", name, err)
+				continue
+			}
+			return "", nil, fmt.Errorf("property %s: %w", name, err)
+		}
+		properties[name] = prop
+	}
+	return title, properties, nil
+}
+
+// lookupProperty finds a property by name, exactly or ignoring case.
+func lookupProperty(schema notionapi.PropertyConfigs, key string) (string, notionapi.PropertyConfig) {
+	if config, ok := schema[key]; ok {
+		return key, config
+	}
+	for name, config := range schema {
+		if strings.EqualFold(name, key) {
+			return name, config
+		}
+	}
+	return "", nil
+}
+
+// Apply creates the pages of a plan that were not created by a previous run,
+// recording each in the manifest as it goes.
+func (im *Importer) Apply(ctx context.Context, plan *Plan) (Result, error) {
+	var result Result
+	entries := plan.manifest.entries(notion.NormalizeID(plan.Target.ID))
+
+	for i, item := range plan.Items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		skipped := item.Done
+		if skipped {
+			result.Skipped++
+		} else {
+			if err := im.create(ctx, plan, entries, item); err != nil {
+				return result, fmt.Errorf("import %s: %w", item.Path, err)
+			}
+			result.Created++
+		}
+		im.progress(Progress{Done: i + 1, Total: len(plan.Items), Item: item, Skipped: skipped})
+	}
+	return result, nil
+}
+
+// create creates the page for an item and adds its blocks.
+func (im *Importer) create(ctx context.Context, plan *Plan, entries map[string]*manifestEntry, item *Item) error {
+	if stale := entries[item.Path]; stale != nil {
+		// A previous run stopped while adding blocks; start the page over
+		if _, err := im.client.DeleteBlock(ctx, stale.ID); err != nil && notion.ClassifyError(err) != notion.ErrorClassNotFound {
+			return fmt.Errorf("remove incomplete page: %w", err)
+		}
+	}
+
+	title, _ := notion.ParsePropertyValue(notionapi.PropertyConfigTypeTitle, item.Title, im.now)
+	req := &notionapi.PageCreateRequest{Properties: notionapi.Properties{}}
+	switch {
+	case item.Parent != nil:
+		req.Parent = notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(item.Parent.ID)}
+		req.Properties["title"] = title
+	case plan.Target.Database:
+		req.Parent = notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: notionapi.DatabaseID(plan.Target.ID)}
+		for name, prop := range item.Properties {
+			req.Properties[name] = prop
+		}
+		req.Properties[plan.Target.titleProperty] = title
+	default:
+		req.Parent = notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(plan.Target.ID)}
+		req.Properties["title"] = title
+	}
+
+	page, err := im.client.CreatePage(ctx, req)
+	if err != nil {
+		return err
+	}
+	entry := &manifestEntry{ID: string(page.ID), URL: page.URL}
+	entries[item.Path] = entry
+	if err := plan.manifest.save(); err != nil {
+		return err
+	}
+
+	if _, err := notion.AppendBlockTree(ctx, im.client, entry.ID, item.Blocks); err != nil {
+		return fmt.Errorf("add blocks: %w", err)
+	}
+	entry.Complete = true
+	if err := plan.manifest.save(); err != nil {
+		return err
+	}
+
+	item.ID, item.URL = entry.ID, entry.URL
+	return nil
+}
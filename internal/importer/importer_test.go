@@ -0,0 +1,260 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// writeTree creates files below dir from a map of slash-separated paths.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	}
+}
+
+// newWiki writes a small wiki: a guide with a child page and an image, a
+// folder of notes, and files that are not imported.
+func newWiki(t *testing.T) string {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"guide.md":             "---\ntitle: Guide\nstatus: Draft\nTags: [a, b]\nOwner: sam\nScore: 3\nid: old-id\n---\n\nWelcome.\n",
+		"guide/setup.md":       "# Setup\n\n![Screenshot](img/shot%201.png)\n",
+		"guide/img/shot 1.png": "png",
+		"notes/idea.md":        "Just text\n",
+		"assets/logo.png":      "png",
+		".hidden/secret.md":    "hidden\n",
+		"readme.txt":           "not markdown\n",
+	})
+	return dir
+}
+
+// newWikiClient returns a mock client for a database with a title, select,
+// multi-select and formula property. Created pages get IDs "created-1", ...
+func newWikiClient() *testhelpers.MockNotionClient {
+	schema := testhelpers.NewTestDatabaseSchema("db-wiki")
+	schema.Title = testhelpers.NewTestRichText("Wiki")
+	schema.Properties = notionapi.PropertyConfigs{
+		"Name":   &notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+		"Status": &notionapi.SelectPropertyConfig{Type: notionapi.PropertyConfigTypeSelect},
+		"Tags":   &notionapi.MultiSelectPropertyConfig{Type: notionapi.PropertyConfigTypeMultiSelect},
+		"Score":  &notionapi.FormulaPropertyConfig{Type: notionapi.PropertyConfigTypeFormula},
+	}
+
+	mockClient := testhelpers.NewMockNotionClient().WithSchema(schema)
+	mockClient.CreatePageFunc = func(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+		id := fmt.Sprintf("created-%d", mockClient.CreatePageCallCount())
+		return testhelpers.NewTestPage(id, ""), nil
+	}
+	mockClient.AppendBlocksFunc = func(ctx context.Context, id string, req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+		resp := &notionapi.AppendBlockChildrenResponse{}
+		for i := range req.Children {
+			block := testhelpers.NewParagraphBlock("")
+			block.ID = notionapi.BlockID(fmt.Sprintf("%s-%d", id, i))
+			resp.Results = append(resp.Results, block)
+		}
+		return resp, nil
+	}
+	return mockClient
+}
+
+func paths(items []*Item) []string {
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Path)
+	}
+	return got
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	importer, err := NewImporter(NewImporterInput{
+		Client:       newWikiClient(),
+		Dir:          newWiki(t),
+		AssetBaseURL: "https://cdn.example.com/wiki/",
+	})
+	require.NoError(t, err)
+
+	plan, err := importer.Plan(context.Background(), "db-wiki")
+	require.NoError(t, err)
+
+	assert.True(t, plan.Target.Database)
+	assert.Equal(t, "Wiki", plan.Target.Title)
+	assert.Equal(t, []string{"guide.md", "guide/setup.md", "notes/", "notes/idea.md"}, paths(plan.Items))
+	assert.Equal(t, 4, plan.Pending())
+
+	guide := plan.Items[0]
+	assert.Equal(t, "Guide", guide.Title)
+	assert.Nil(t, guide.Parent)
+	assert.Len(t, guide.Properties, 2)
+	assert.Equal(t, "Draft", notion.PropertyString(guide.Properties["Status"]))
+	assert.Equal(t, "a, b", notion.PropertyString(guide.Properties["Tags"]))
+
+	setup := plan.Items[1]
+	assert.Equal(t, "Setup", setup.Title, "title from the leading heading")
+	assert.Same(t, guide, setup.Parent)
+	require.Len(t, setup.Blocks, 1, "the heading is not repeated in the body")
+	image, ok := setup.Blocks[0].Block.(*notionapi.ImageBlock)
+	require.True(t, ok)
+	assert.Equal(t, "https://cdn.example.com/wiki/guide/img/shot%201.png", image.Image.External.URL)
+
+	assert.Equal(t, "notes", plan.Items[2].Title)
+	assert.Same(t, plan.Items[2], plan.Items[3].Parent)
+	assert.Equal(t, "idea", plan.Items[3].Title, "title from the file name")
+
+	assert.ElementsMatch(t, []string{
+		`front matter key "Owner" does not match a property of Wiki and is ignored`,
+		`property "Score" is ignored: setting formula properties is not supported`,
+	}, plan.Warnings)
+}
+
+func TestPlanInvalidProperty(t *testing.T) {
+	t.Parallel()
+
+	// Values without a text form are skipped; values that do not parse stop the import
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a.md": "---\nTags: {nested: map}\n---\n",
+		"b.md": "---\nDue: someday\n---\n",
+	})
+	mockClient := newWikiClient()
+	mockClient.SchemaToReturn.Properties["Due"] = &notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate}
+
+	importer, err := NewImporter(NewImporterInput{Client: mockClient, Dir: dir})
+	require.NoError(t, err)
+
+	_, err = importer.Plan(context.Background(), "db-wiki")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `b.md: property Due: "someday" is not a date`)
+}
+
+func TestPlanIntoPage(t *testing.T) {
+	t.Parallel()
+
+	mockClient := newWikiClient()
+	mockClient.GetDatabaseFunc = func(ctx context.Context, id string) (*notionapi.Database, error) {
+		return nil, testhelpers.ErrNotFound
+	}
+	mockClient.PageToReturn = testhelpers.NewTestPage("page-home", "Home")
+
+	importer, err := NewImporter(NewImporterInput{Client: mockClient, Dir: newWiki(t)})
+	require.NoError(t, err)
+	plan, err := importer.Plan(context.Background(), "page-home")
+	require.NoError(t, err)
+
+	assert.False(t, plan.Target.Database)
+	assert.Equal(t, "Home", plan.Target.Title)
+	assert.Equal(t, "Guide", plan.Items[0].Title)
+	assert.Empty(t, plan.Items[0].Properties)
+	assert.Contains(t, plan.Warnings, "guide.md: front matter is only imported as properties of database rows")
+	assert.Contains(t, plan.Warnings, "guide/setup.md: image guide/img/shot 1.png is a local file; pass --asset-base-url to link it")
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	mockClient := newWikiClient()
+	var progress []Progress
+	importer, err := NewImporter(NewImporterInput{
+		Client:   mockClient,
+		Dir:      newWiki(t),
+		Now:      time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		Progress: func(p Progress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	plan, err := importer.Plan(context.Background(), "db-wiki")
+	require.NoError(t, err)
+
+	result, err := importer.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: 4}, result)
+	require.Len(t, progress, 4)
+	assert.Equal(t, 4, progress[3].Done)
+
+	calls := mockClient.CreatePageCalls
+	require.Len(t, calls, 4)
+
+	row := calls[0].Request
+	assert.Equal(t, notionapi.ParentTypeDatabaseID, row.Parent.Type)
+	assert.Equal(t, notionapi.DatabaseID("db-wiki"), row.Parent.DatabaseID)
+	assert.Equal(t, "Guide", notion.PropertyString(row.Properties["Name"]))
+	assert.Equal(t, "Draft", notion.PropertyString(row.Properties["Status"]))
+
+	child := calls[1].Request
+	assert.Equal(t, notionapi.ParentTypePageID, child.Parent.Type)
+	assert.Equal(t, notionapi.PageID("created-1"), child.Parent.PageID)
+	assert.Equal(t, "Setup", notion.PropertyString(child.Properties["title"]))
+
+	folder := calls[2].Request
+	assert.Equal(t, notionapi.ParentTypeDatabaseID, folder.Parent.Type, "top-level folders become rows")
+	assert.Equal(t, notionapi.PageID("created-3"), calls[3].Request.Parent.PageID)
+}
+
+func TestApplyResume(t *testing.T) {
+	t.Parallel()
+
+	dir := newWiki(t)
+
+	// The first run fails while adding the blocks of the second page
+	failing := newWikiClient()
+	appendBlocks := failing.AppendBlocksFunc
+	failing.AppendBlocksFunc = func(ctx context.Context, id string, req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+		if id == "created-2" {
+			return nil, errors.New("connection reset")
+		}
+		return appendBlocks(ctx, id, req)
+	}
+	first, err := NewImporter(NewImporterInput{Client: failing, Dir: dir})
+	require.NoError(t, err)
+	plan, err := first.Plan(context.Background(), "db-wiki")
+	require.NoError(t, err)
+	result, err := first.Apply(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "import guide/setup.md: add blocks: connection reset")
+	assert.Equal(t, 1, result.Created)
+
+	mockClient := newWikiClient()
+	second, err := NewImporter(NewImporterInput{Client: mockClient, Dir: dir})
+	require.NoError(t, err)
+	plan, err = second.Plan(context.Background(), "db-wiki")
+	require.NoError(t, err)
+	assert.Equal(t, 3, plan.Pending())
+
+	result, err = second.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: 3, Skipped: 1}, result)
+	require.Equal(t, 1, mockClient.DeleteBlockCallCount(), "the incomplete page is removed")
+	assert.Equal(t, "created-2", mockClient.DeleteBlockCalls[0].ID)
+	assert.Equal(t, notionapi.PageID("created-1"), mockClient.CreatePageCalls[0].Request.Parent.PageID,
+		"children of imported pages go under the recorded page")
+
+	// A third run has nothing left to do
+	plan, err = second.Plan(context.Background(), "db-wiki")
+	require.NoError(t, err)
+	assert.Zero(t, plan.Pending())
+}
+
+func TestNewImporterErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewImporter(NewImporterInput{Dir: t.TempDir()})
+	assert.Error(t, err)
+
+	_, err = NewImporter(NewImporterInput{Client: testhelpers.NewMockNotionClient(), Dir: filepath.Join(t.TempDir(), "missing")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+// manifestName is the file in the import directory that records the pages an
+// import has created.
+const manifestName = ".notion-import.json"
+
+// manifest records the pages created from an import directory so that a
+// re-run skips files that were already imported. Entries are kept per target,
+// since the same directory may be imported into several places.
+type manifest struct {
+	Targets map[string]map[string]*manifestEntry `json:"targets"` // target ID to file path to entry
+
+	path string
+}
+
+// manifestEntry is a page created from a file or directory.
+type manifestEntry struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Complete is set once all blocks have been added. An incomplete page was
+	// interrupted part way and is replaced on the next run.
+	Complete bool `json:"complete"`
+}
+
+// loadManifest reads the manifest of an import directory. A missing manifest
+// starts an empty one; a corrupt one is an error, since ignoring it would
+// import every file a second time.
+func loadManifest(dir string) (*manifest, error) {
+	m := &manifest{path: filepath.Join(dir, manifestName)}
+
+	data, err := os.ReadFile(m.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("parse manifest %s: %w", m.path, err)
+		}
+	}
+	if m.Targets == nil {
+		m.Targets = make(map[string]map[string]*manifestEntry)
+	}
+	return m, nil
+}
+
+// entries returns the entries of a target, creating the map when needed.
+func (m *manifest) entries(target string) map[string]*manifestEntry {
+	entries := m.Targets[target]
+	if entries == nil {
+		entries = make(map[string]*manifestEntry)
+		m.Targets[target] = entries
+	}
+	return entries
+}
+
+// save writes the manifest beside the imported files.
+func (m *manifest) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	if err := fsutil.WriteFile(m.path, data, 0600); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
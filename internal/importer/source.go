@@ -0,0 +1,197 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// isMarkdown reports whether a file name is a Markdown file.
+func isMarkdown(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	default:
+		return false
+	}
+}
+
+// stem returns a file name without its extension.
+func stem(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// hasMarkdown reports whether a directory contains a Markdown file at any
+// depth. Directories without one, such as image folders, are not imported.
+func hasMarkdown(dir string) bool {
+	found := false
+	_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return filepath.SkipDir
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && isMarkdown(d.Name()) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// source is a Markdown file split into its front matter and body.
+type source struct {
+	fields map[string]any
+	body   string
+}
+
+// readSource reads a Markdown file and parses its YAML front matter, if any.
+// Keys nested under "properties", as written by `export`, are merged into the
+// top level.
+func readSource(file string) (source, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return source{}, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	src := source{fields: map[string]any{}, body: text}
+	if !strings.HasPrefix(text, "---\n") {
+		return src, nil
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return src, nil
+	}
+	header := text[4 : 4+end]
+	rest := text[4+end+len("\n---"):]
+	if nl := strings.IndexByte(rest, '\n'); nl >= 0 {
+		rest = rest[nl+1:]
+	} else {
+		rest = ""
+	}
+
+	var fields map[string]any
+	if err := yaml.Unmarshal([]byte(header), &fields); err != nil {
+		return source{}, fmt.Errorf("parse front matter: %w", err)
+	}
+	if nested, ok := fields["properties"].(map[string]any); ok {
+		delete(fields, "properties")
+		for key, value := range nested {
+			fields[key] = value
+		}
+	}
+	if fields != nil {
+		src.fields = fields
+	}
+	src.body = rest
+	return src, nil
+}
+
+// takeHeading removes a leading "# Title" line from body and returns the
+// title, or "" when the body does not start with one.
+func takeHeading(body string) (string, string) {
+	trimmed := strings.TrimLeft(body, "\n")
+	line, rest, _ := strings.Cut(trimmed, "\n")
+	if !strings.HasPrefix(line, "# ") {
+		return "", body
+	}
+	return strings.TrimSpace(line[2:]), rest
+}
+
+// fieldString converts a front matter value to the text form accepted by
+// notion.ParsePropertyValue. ok is false for values that have no such form.
+func fieldString(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format("2006-01-02"), true
+		}
+		return v.Format(time.RFC3339), true
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := fieldString(item)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ", "), true
+	default:
+		return "", false
+	}
+}
+
+// sortedKeys returns the keys of fields in order, so that warnings and
+// errors are reported deterministically.
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// imagePattern matches Markdown images.
+var imagePattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// linkImages points local images at their copy below assetBaseURL, so Notion
+// can show them. file is the Markdown file, relative to the import directory.
+// Without a base URL local images cannot be shown and are reported.
+func linkImages(body, file, assetBaseURL string) (string, []string) {
+	var warnings []string
+	body = imagePattern.ReplaceAllStringFunc(body, func(match string) string {
+		parts := imagePattern.FindStringSubmatch(match)
+		alt, target := parts[1], parts[2]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "data:") {
+			return match
+		}
+
+		local, err := url.PathUnescape(target)
+		if err != nil {
+			local = target
+		}
+		local = path.Join(path.Dir(file), local)
+		if strings.HasPrefix(local, "../") {
+			warnings = append(warnings, fmt.Sprintf("image %s is outside the import directory", target))
+			return match
+		}
+		if assetBaseURL == "" {
+			warnings = append(warnings, fmt.Sprintf("image %s is a local file; pass --asset-base-url to link it", local))
+			return match
+		}
+
+		segments := strings.Split(local, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return fmt.Sprintf("![%s](%s/%s)", alt, strings.TrimRight(assetBaseURL, "/"), strings.Join(segments, "/"))
+	})
+	return body, warnings
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSource(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"exported.md": "---\r\nid: 123\r\ntitle: Plan\r\nproperties:\r\n  Status: Done\r\n---\r\n\r\nBody\r\n",
+		"plain.md":    "# Heading\n\nBody\n",
+		"open.md":     "---\ntitle: never closed\n",
+	})
+
+	src, err := readSource(filepath.Join(dir, "exported.md"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": 123, "title": "Plan", "Status": "Done"}, src.fields)
+	assert.Equal(t, "\nBody\n", src.body)
+
+	src, err = readSource(filepath.Join(dir, "plain.md"))
+	require.NoError(t, err)
+	assert.Empty(t, src.fields)
+	assert.Equal(t, "# Heading\n\nBody\n", src.body)
+
+	src, err = readSource(filepath.Join(dir, "open.md"))
+	require.NoError(t, err)
+	assert.Empty(t, src.fields, "an unterminated block is body text")
+}
+
+func TestTakeHeading(t *testing.T) {
+	t.Parallel()
+
+	title, body := takeHeading("\n# Launch plan\n\nSteps")
+	assert.Equal(t, "Launch plan", title)
+	assert.Equal(t, "\nSteps", body)
+
+	title, body = takeHeading("## Not a title\n")
+	assert.Empty(t, title)
+	assert.Equal(t, "## Not a title\n", body)
+}
+
+func TestFieldString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		value  any
+		want   string
+		wantOK bool
+	}{
+		{name: "string", value: "High", want: "High", wantOK: true},
+		{name: "bool", value: true, want: "true", wantOK: true},
+		{name: "int", value: 3, want: "3", wantOK: true},
+		{name: "float", value: 2.5, want: "2.5", wantOK: true},
+		{name: "date", value: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), want: "2024-04-01", wantOK: true},
+		{name: "timestamp", value: time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC), want: "2024-04-01T09:30:00Z", wantOK: true},
+		{name: "list", value: []any{"a", 2}, want: "a, 2", wantOK: true},
+		{name: "map", value: map[string]any{"a": 1}},
+		{name: "null", value: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := fieldString(tt.value)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,756 @@
+// Package index provides a local full-text search index over cached Notion pages.
+// Documents are built from converted markdown and kept in an inverted index that
+// supports ranked term, phrase and prefix queries without network access.
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/fsutil"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+const (
+	// indexFileName is the file the index is persisted to inside its directory.
+	indexFileName = "index.json"
+	// indexVersion is bumped whenever the persisted format changes.
+	indexVersion = 1
+	// defaultLimit is the number of hits returned when SearchInput.Limit is zero.
+	defaultLimit = 20
+
+	// BM25 tuning parameters.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// field identifies the part of a document a term occurred in.
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldBody
+	fieldCount
+)
+
+// fieldWeights boosts title matches over body matches.
+var fieldWeights = [fieldCount]float64{3.0, 1.0}
+
+// Document is a single indexed page.
+type Document struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Content   string    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Hit is a single ranked search result.
+type Hit struct {
+	ID        string
+	Title     string
+	ParentID  string
+	Score     float64
+	Snippet   string
+	MatchType string // "title" or "content"
+}
+
+// posting records the token positions of a term within one document.
+type posting struct {
+	positions [fieldCount][]int
+}
+
+// Index is a concurrency-safe inverted index over page titles and content.
+type Index struct {
+	// saveMu serializes saves so an older snapshot never overwrites a newer one.
+	// It is taken before mu, and mu is released before the codec runs because
+	// the codec may lock the cache, which locks mu when notifying the index.
+	saveMu   sync.Mutex
+	mu       sync.RWMutex
+	dir      string
+	docs     map[string]*Document
+	postings map[string]map[string]*posting
+	docTerms map[string][]string
+	lengths  map[string][fieldCount]int
+	totalLen [fieldCount]int
+	dirty    bool
+	codec    Codec
+}
+
+// Codec transforms the index file on its way to and from disk, for example to
+// encrypt it alongside an encrypted cache. *cache.PageCache implements it.
+type Codec interface {
+	Seal(name string, data []byte) ([]byte, error)
+	Unseal(name string, data []byte) ([]byte, error)
+}
+
+// NewIndexInput contains the parameters for creating a new Index.
+type NewIndexInput struct {
+	// Dir is where the index is persisted. An empty Dir keeps the index in memory only.
+	Dir string
+	// Codec optionally transforms the persisted file.
+	Codec Codec
+}
+
+// NewIndex creates an empty Index.
+func NewIndex(input NewIndexInput) *Index {
+	return &Index{
+		dir:      input.Dir,
+		codec:    input.Codec,
+		docs:     make(map[string]*Document),
+		postings: make(map[string]map[string]*posting),
+		docTerms: make(map[string][]string),
+		lengths:  make(map[string][fieldCount]int),
+	}
+}
+
+// OpenInput contains the parameters for opening the index that belongs to a cache.
+type OpenInput struct {
+	Cache *cache.PageCache
+}
+
+// Open loads the index stored beside the cache, rebuilding it from cache entries
+// when the index file is missing or unreadable. The returned index is registered
+// as a cache observer so later cache writes keep it up to date.
+func Open(ctx context.Context, input OpenInput) (*Index, error) {
+	if input.Cache == nil {
+		return nil, fmt.Errorf("cache cannot be nil")
+	}
+
+	ix := NewIndex(NewIndexInput{
+		Dir:   filepath.Join(input.Cache.Dir(), "index"),
+		Codec: input.Cache,
+	})
+
+	if err := ix.Load(); err != nil {
+		entries, err := input.Cache.Entries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("read cache entries: %w", err)
+		}
+		if err := ix.Rebuild(ctx, entries); err != nil {
+			return nil, fmt.Errorf("rebuild index: %w", err)
+		}
+	}
+
+	input.Cache.AddObserver(ix)
+	return ix, nil
+}
+
+// Put adds or replaces a document.
+func (ix *Index) Put(doc Document) {
+	if doc.ID == "" {
+		return
+	}
+	if doc.UpdatedAt.IsZero() {
+		doc.UpdatedAt = time.Now()
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.putLocked(&doc)
+}
+
+// SetTitleInput contains the parameters for updating a document's metadata.
+type SetTitleInput struct {
+	ID       string
+	Title    string
+	ParentID string // optional; left unchanged when empty
+}
+
+// SetTitle updates the title and parent of a document, creating it without content
+// if it is not yet indexed. Existing content is preserved.
+func (ix *Index) SetTitle(input SetTitleInput) {
+	if input.ID == "" {
+		return
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	doc := Document{ID: input.ID, UpdatedAt: time.Now()}
+	if existing, ok := ix.docs[notion.NormalizeID(input.ID)]; ok {
+		doc = *existing
+		if doc.Title == input.Title && (input.ParentID == "" || doc.ParentID == input.ParentID) {
+			return
+		}
+	}
+
+	doc.Title = input.Title
+	if input.ParentID != "" {
+		doc.ParentID = input.ParentID
+	}
+	ix.putLocked(&doc)
+}
+
+// SetContent updates the content of a document, creating it without a title if it
+// is not yet indexed. The existing title and parent are preserved.
+func (ix *Index) SetContent(id, content string) {
+	if id == "" {
+		return
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	doc := Document{ID: id}
+	if existing, ok := ix.docs[notion.NormalizeID(id)]; ok {
+		doc = *existing
+	}
+
+	doc.Content = content
+	doc.UpdatedAt = time.Now()
+	ix.putLocked(&doc)
+}
+
+// Remove deletes a document from the index.
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if ix.removeLocked(notion.NormalizeID(id)) {
+		ix.dirty = true
+	}
+}
+
+// Get returns the indexed document with the given ID.
+func (ix *Index) Get(id string) (Document, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	doc, ok := ix.docs[notion.NormalizeID(id)]
+	if !ok {
+		return Document{}, false
+	}
+	return *doc, true
+}
+
+// Len returns the number of indexed documents.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// EntryWritten implements cache.Observer by indexing the content of cached block
+// lists and the titles of cached page metadata.
+func (ix *Index) EntryWritten(pageID string, data json.RawMessage) {
+	if cache.IsMetaKey(pageID) {
+		page, err := notion.DecodePage(data)
+		if err != nil {
+			return
+		}
+		ix.SetTitle(SetTitleInput{
+			ID:       string(page.ID),
+			Title:    notion.PageTitle(page),
+			ParentID: notion.PageParentID(page),
+		})
+		return
+	}
+
+	content, ok := decodeContent(data)
+	if !ok {
+		return
+	}
+	ix.SetContent(pageID, content)
+}
+
+// EntryDeleted implements cache.Observer. The page's document is dropped, title
+// and content alike, so that removed or quarantined pages stop being searchable
+// and are left out of the next save.
+func (ix *Index) EntryDeleted(pageID string) {
+	ix.Remove(strings.TrimPrefix(pageID, cache.MetaKey("")))
+}
+
+// SearchInput contains the parameters for a search.
+type SearchInput struct {
+	Query    string
+	ParentID string // optional; restricts results to children of this page or database
+	Limit    int    // default 20
+}
+
+// Search returns documents matching every clause of the query, best first.
+// Queries support plain terms, "quoted phrases" and prefix* terms.
+func (ix *Index) Search(input SearchInput) []Hit {
+	clauses := parseQuery(input.Query)
+	if len(clauses) == 0 {
+		return nil
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	parentID := notion.NormalizeID(input.ParentID)
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	type candidate struct {
+		score      float64
+		titleMatch bool
+	}
+
+	var candidates map[string]*candidate
+	for _, c := range clauses {
+		matches := ix.matchClause(c)
+
+		next := make(map[string]*candidate, len(matches))
+		for key, m := range matches {
+			if candidates != nil {
+				prev, ok := candidates[key]
+				if !ok {
+					continue
+				}
+				m.score += prev.score
+				m.titleOnly = m.titleOnly && prev.titleMatch
+			}
+			next[key] = &candidate{score: m.score, titleMatch: m.titleOnly}
+		}
+		candidates = next
+
+		if len(candidates) == 0 {
+			return nil
+		}
+	}
+
+	terms := highlightTerms(clauses)
+	hits := make([]Hit, 0, len(candidates))
+	for key, cand := range candidates {
+		doc := ix.docs[key]
+		if parentID != "" && notion.NormalizeID(doc.ParentID) != parentID {
+			continue
+		}
+
+		hit := Hit{
+			ID:        doc.ID,
+			Title:     doc.Title,
+			ParentID:  doc.ParentID,
+			Score:     cand.score,
+			MatchType: "content",
+		}
+		if cand.titleMatch {
+			hit.MatchType = "title"
+			hit.Snippet = Snippet(doc.Title, terms)
+		} else {
+			hit.Snippet = Snippet(strings.Join(strings.Fields(doc.Content), " "), terms)
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		di, dj := ix.docs[notion.NormalizeID(hits[i].ID)], ix.docs[notion.NormalizeID(hits[j].ID)]
+		if !di.UpdatedAt.Equal(dj.UpdatedAt) {
+			return di.UpdatedAt.After(dj.UpdatedAt)
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// clauseMatch is the score of one clause for one document.
+type clauseMatch struct {
+	score     float64
+	titleOnly bool
+}
+
+// matchClause returns the documents matching a clause with their scores.
+// Callers must hold at least a read lock.
+func (ix *Index) matchClause(c clause) map[string]clauseMatch {
+	result := make(map[string]clauseMatch)
+
+	switch c.kind {
+	case clauseTerm:
+		for key, p := range ix.postings[c.terms[0]] {
+			result[key] = clauseMatch{
+				score:     ix.termScore(c.terms[0], key, p),
+				titleOnly: len(p.positions[fieldTitle]) > 0,
+			}
+		}
+
+	case clausePrefix:
+		for term, docs := range ix.postings {
+			if !strings.HasPrefix(term, c.terms[0]) {
+				continue
+			}
+			for key, p := range docs {
+				score := ix.termScore(term, key, p)
+				prev, ok := result[key]
+				if ok && prev.score >= score {
+					continue
+				}
+				result[key] = clauseMatch{
+					score:     score,
+					titleOnly: len(p.positions[fieldTitle]) > 0,
+				}
+			}
+		}
+
+	case clausePhrase:
+		for key, p := range ix.postings[c.terms[0]] {
+			inTitle := ix.phraseAt(c.terms, key, p, fieldTitle)
+			inBody := ix.phraseAt(c.terms, key, p, fieldBody)
+			if !inTitle && !inBody {
+				continue
+			}
+
+			var score float64
+			for _, term := range c.terms {
+				score += ix.termScore(term, key, ix.postings[term][key])
+			}
+			result[key] = clauseMatch{score: score, titleOnly: inTitle}
+		}
+	}
+
+	return result
+}
+
+// phraseAt reports whether terms occur consecutively in the given field of a document.
+func (ix *Index) phraseAt(terms []string, key string, first *posting, f field) bool {
+	rest := make([]map[int]bool, 0, len(terms)-1)
+	for _, term := range terms[1:] {
+		p, ok := ix.postings[term][key]
+		if !ok || len(p.positions[f]) == 0 {
+			return false
+		}
+		set := make(map[int]bool, len(p.positions[f]))
+		for _, pos := range p.positions[f] {
+			set[pos] = true
+		}
+		rest = append(rest, set)
+	}
+
+	for _, start := range first.positions[f] {
+		match := true
+		for i, set := range rest {
+			if !set[start+i+1] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// termScore computes the field-weighted BM25 score of a term in a document.
+func (ix *Index) termScore(term, key string, p *posting) float64 {
+	if p == nil {
+		return 0
+	}
+
+	n := float64(len(ix.docs))
+	df := float64(len(ix.postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	lengths := ix.lengths[key]
+	var score float64
+	for f := field(0); f < fieldCount; f++ {
+		tf := float64(len(p.positions[f]))
+		if tf == 0 {
+			continue
+		}
+
+		avg := float64(ix.totalLen[f]) / n
+		if avg == 0 {
+			avg = 1
+		}
+		norm := 1 - bm25B + bm25B*float64(lengths[f])/avg
+		score += fieldWeights[f] * idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+	return score
+}
+
+// putLocked indexes a document, replacing any previous version.
+// Callers must hold the write lock.
+func (ix *Index) putLocked(doc *Document) {
+	key := notion.NormalizeID(doc.ID)
+	ix.removeLocked(key)
+
+	var lengths [fieldCount]int
+	seen := make(map[string]bool)
+	for f, text := range [fieldCount]string{doc.Title, doc.Content} {
+		tokens := tokenize(text)
+		lengths[f] = len(tokens)
+		ix.totalLen[f] += len(tokens)
+
+		for pos, term := range tokens {
+			docs, ok := ix.postings[term]
+			if !ok {
+				docs = make(map[string]*posting)
+				ix.postings[term] = docs
+			}
+			p, ok := docs[key]
+			if !ok {
+				p = &posting{}
+				docs[key] = p
+			}
+			p.positions[f] = append(p.positions[f], pos)
+
+			if !seen[term] {
+				seen[term] = true
+				ix.docTerms[key] = append(ix.docTerms[key], term)
+			}
+		}
+	}
+
+	ix.docs[key] = doc
+	ix.lengths[key] = lengths
+	ix.dirty = true
+}
+
+// removeLocked drops a document and its postings. Callers must hold the write lock.
+func (ix *Index) removeLocked(key string) bool {
+	if _, ok := ix.docs[key]; !ok {
+		return false
+	}
+
+	for _, term := range ix.docTerms[key] {
+		delete(ix.postings[term], key)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+
+	lengths := ix.lengths[key]
+	for f := field(0); f < fieldCount; f++ {
+		ix.totalLen[f] -= lengths[f]
+	}
+
+	delete(ix.docs, key)
+	delete(ix.docTerms, key)
+	delete(ix.lengths, key)
+	return true
+}
+
+// Rebuild replaces the index content with documents decoded from cache entries:
+// content from block lists, titles and parents from page metadata. Titles and
+// parents already known to the index are kept for pages without metadata.
+func (ix *Index) Rebuild(ctx context.Context, entries []cache.CacheEntry) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	previous := ix.docs
+	ix.docs = make(map[string]*Document)
+	ix.postings = make(map[string]map[string]*posting)
+	ix.docTerms = make(map[string][]string)
+	ix.lengths = make(map[string][fieldCount]int)
+	ix.totalLen = [fieldCount]int{}
+
+	for _, old := range previous {
+		doc := *old
+		doc.Content = ""
+		ix.putLocked(&doc)
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("context error: %w", err)
+		}
+
+		// Entries come in any order; each fills in its half of the document
+		if cache.IsMetaKey(entry.PageID) {
+			page, err := notion.DecodePage(entry.Data)
+			if err != nil {
+				continue
+			}
+			doc := ix.rebuiltDocLocked(string(page.ID), entry.Timestamp)
+			doc.Title = notion.PageTitle(page)
+			if parentID := notion.PageParentID(page); parentID != "" {
+				doc.ParentID = parentID
+			}
+			ix.putLocked(&doc)
+			continue
+		}
+
+		content, ok := decodeContent(entry.Data)
+		if !ok {
+			continue
+		}
+
+		doc := ix.rebuiltDocLocked(entry.PageID, entry.Timestamp)
+		doc.Content = content
+		ix.putLocked(&doc)
+	}
+
+	ix.dirty = true
+	return nil
+}
+
+// rebuiltDocLocked returns the document being rebuilt for id, or a new one,
+// updated at the later of its time and updated. Callers must hold the lock.
+func (ix *Index) rebuiltDocLocked(id string, updated time.Time) Document {
+	doc := Document{ID: id, UpdatedAt: updated}
+	if existing, ok := ix.docs[notion.NormalizeID(id)]; ok {
+		doc = *existing
+		if updated.After(doc.UpdatedAt) {
+			doc.UpdatedAt = updated
+		}
+	}
+	return doc
+}
+
+// persistedIndex is the on-disk representation of the index.
+type persistedIndex struct {
+	Version   int        `json:"version"`
+	Documents []Document `json:"documents"`
+}
+
+// Save writes the index to disk if it changed since the last save or load.
+// The file is replaced atomically so a crash never leaves a truncated index.
+func (ix *Index) Save() error {
+	if ix.dir == "" {
+		return nil
+	}
+
+	ix.saveMu.Lock()
+	defer ix.saveMu.Unlock()
+
+	data, err := ix.snapshot()
+	if err != nil || data == nil {
+		return err
+	}
+
+	if err := ix.write(data); err != nil {
+		ix.mu.Lock()
+		ix.dirty = true
+		ix.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// snapshot marshals the index and marks it clean, or returns nil data if it has
+// not changed since the last save or load.
+func (ix *Index) snapshot() ([]byte, error) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if !ix.dirty {
+		return nil, nil
+	}
+
+	state := persistedIndex{
+		Version:   indexVersion,
+		Documents: make([]Document, 0, len(ix.docs)),
+	}
+	for _, doc := range ix.docs {
+		state.Documents = append(state.Documents, *doc)
+	}
+	sort.Slice(state.Documents, func(i, j int) bool {
+		return state.Documents[i].ID < state.Documents[j].ID
+	})
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshal index: %w", err)
+	}
+
+	ix.dirty = false
+	return data, nil
+}
+
+// write seals data with the codec, if any, and replaces the index file.
+func (ix *Index) write(data []byte) error {
+	var err error
+	if ix.codec != nil {
+		if data, err = ix.codec.Seal(indexFileName, data); err != nil {
+			return fmt.Errorf("seal index: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(ix.dir, 0700); err != nil {
+		return fmt.Errorf("create index directory %s: %w", ix.dir, err)
+	}
+
+	path := filepath.Join(ix.dir, indexFileName)
+	if err := fsutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write index file: %w", err)
+	}
+	return nil
+}
+
+// ErrIndexVersion is returned by Load when the stored index uses another format version.
+var ErrIndexVersion = errors.New("unsupported index version")
+
+// Load replaces the index content with the persisted index.
+func (ix *Index) Load() error {
+	if ix.dir == "" {
+		return fmt.Errorf("index has no directory")
+	}
+
+	path := filepath.Join(ix.dir, indexFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read index file %s: %w", path, err)
+	}
+	if ix.codec != nil {
+		if data, err = ix.codec.Unseal(indexFileName, data); err != nil {
+			return fmt.Errorf("unseal index file %s: %w", path, err)
+		}
+	}
+
+	var state persistedIndex
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("unmarshal index file %s: %w", path, err)
+	}
+	if state.Version != indexVersion {
+		return fmt.Errorf("load index file %s: %w", path, ErrIndexVersion)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.docs = make(map[string]*Document)
+	ix.postings = make(map[string]map[string]*posting)
+	ix.docTerms = make(map[string][]string)
+	ix.lengths = make(map[string][fieldCount]int)
+	ix.totalLen = [fieldCount]int{}
+
+	for i := range state.Documents {
+		doc := state.Documents[i]
+		ix.putLocked(&doc)
+	}
+
+	ix.dirty = false
+	return nil
+}
+
+// decodeContent converts a cached block list into indexable markdown.
+func decodeContent(data json.RawMessage) (string, bool) {
+	var response notionapi.GetChildrenResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", false
+	}
+	if response.Object != "list" && len(response.Results) == 0 {
+		return "", false
+	}
+
+	content, err := notion.ConvertBlocksToMarkdown(response.Results)
+	if err != nil {
+		return "", false
+	}
+	return content, true
+}
@@ -0,0 +1,486 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+func newTestIndex() *Index {
+	ix := NewIndex(NewIndexInput{})
+	ix.Put(Document{
+		ID:       "page-1",
+		Title:    "Quarterly Planning",
+		ParentID: "db-1",
+		Content:  "Roadmap review for the mobile team. Discuss hiring and budget.",
+	})
+	ix.Put(Document{
+		ID:       "page-2",
+		Title:    "Meeting Notes",
+		ParentID: "db-1",
+		Content:  "The roadmap was approved. Planning continues next week with the design team.",
+	})
+	ix.Put(Document{
+		ID:       "page-3",
+		Title:    "Recipes",
+		ParentID: "db-2",
+		Content:  "Pancakes need flour, milk and eggs.",
+	})
+	return ix
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input SearchInput
+		want  []string
+	}{
+		{
+			name:  "single term matches title and body",
+			input: SearchInput{Query: "planning"},
+			want:  []string{"page-1", "page-2"},
+		},
+		{
+			name:  "case insensitive",
+			input: SearchInput{Query: "PANCAKES"},
+			want:  []string{"page-3"},
+		},
+		{
+			name:  "all terms required",
+			input: SearchInput{Query: "roadmap hiring"},
+			want:  []string{"page-1"},
+		},
+		{
+			name:  "phrase",
+			input: SearchInput{Query: `"design team"`},
+			want:  []string{"page-2"},
+		},
+		{
+			name:  "phrase words out of order do not match",
+			input: SearchInput{Query: `"team design"`},
+			want:  []string{},
+		},
+		{
+			name:  "prefix",
+			input: SearchInput{Query: "pan*"},
+			want:  []string{"page-3"},
+		},
+		{
+			name:  "parent filter",
+			input: SearchInput{Query: "roadmap", ParentID: "db-2"},
+			want:  []string{},
+		},
+		{
+			name:  "limit",
+			input: SearchInput{Query: "roadmap", Limit: 1},
+			want:  []string{"page-1"},
+		},
+		{
+			name:  "empty query",
+			input: SearchInput{Query: "  "},
+			want:  []string{},
+		},
+	}
+
+	ix := newTestIndex()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.ElementsMatch(t, tt.want, hitIDs(ix.Search(tt.input)))
+		})
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	t.Parallel()
+
+	ix := newTestIndex()
+	hits := ix.Search(SearchInput{Query: "planning"})
+	require.Len(t, hits, 2)
+
+	// Title matches outrank body matches.
+	assert.Equal(t, "page-1", hits[0].ID)
+	assert.Equal(t, "title", hits[0].MatchType)
+	assert.Equal(t, "Quarterly Planning", hits[0].Snippet)
+	assert.Equal(t, "content", hits[1].MatchType)
+	assert.Contains(t, hits[1].Snippet, "Planning continues")
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+}
+
+func TestPutReplacesDocument(t *testing.T) {
+	t.Parallel()
+
+	ix := newTestIndex()
+	ix.Put(Document{ID: "page-3", Title: "Recipes", Content: "Waffles"})
+
+	assert.Empty(t, ix.Search(SearchInput{Query: "pancakes"}))
+	assert.Len(t, ix.Search(SearchInput{Query: "waffles"}), 1)
+	assert.Equal(t, 3, ix.Len())
+}
+
+func TestSetTitleAndContent(t *testing.T) {
+	t.Parallel()
+
+	ix := NewIndex(NewIndexInput{})
+	ix.SetContent("page-1", "body text about gardening")
+	ix.SetTitle(SetTitleInput{ID: "page-1", Title: "Garden", ParentID: "db-1"})
+
+	doc, ok := ix.Get("page-1")
+	require.True(t, ok)
+	assert.Equal(t, "Garden", doc.Title)
+	assert.Equal(t, "db-1", doc.ParentID)
+	assert.Equal(t, "body text about gardening", doc.Content)
+
+	// Updating the title keeps content and parent.
+	ix.SetTitle(SetTitleInput{ID: "page-1", Title: "Backyard"})
+	doc, _ = ix.Get("page-1")
+	assert.Equal(t, "Backyard", doc.Title)
+	assert.Equal(t, "db-1", doc.ParentID)
+	assert.Len(t, ix.Search(SearchInput{Query: "gardening"}), 1)
+}
+
+func TestIDsAreNormalized(t *testing.T) {
+	t.Parallel()
+
+	ix := NewIndex(NewIndexInput{})
+	ix.Put(Document{ID: "12345678-90ab-cdef-1234-567890abcdef", Title: "Dashed"})
+	ix.SetContent("1234567890abcdef1234567890abcdef", "compact content")
+
+	assert.Equal(t, 1, ix.Len())
+	hits := ix.Search(SearchInput{Query: "dashed compact"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "12345678-90ab-cdef-1234-567890abcdef", hits[0].ID)
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	ix := newTestIndex()
+	ix.Remove("page-1")
+
+	assert.Equal(t, 2, ix.Len())
+	assert.Equal(t, []string{"page-2"}, hitIDs(ix.Search(SearchInput{Query: "roadmap"})))
+	assert.Empty(t, ix.Search(SearchInput{Query: "hiring"}))
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ix := NewIndex(NewIndexInput{Dir: dir})
+	ix.Put(Document{ID: "page-1", Title: "Persisted", Content: "survives restarts"})
+	require.NoError(t, ix.Save())
+
+	info, err := os.Stat(filepath.Join(dir, indexFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded := NewIndex(NewIndexInput{Dir: dir})
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, []string{"page-1"}, hitIDs(loaded.Search(SearchInput{Query: "restarts"})))
+}
+
+// reverseCodec is a reversible Codec that makes sealed files unreadable as JSON.
+type reverseCodec struct{}
+
+func (reverseCodec) Seal(name string, data []byte) ([]byte, error) {
+	return reverseBytes(data), nil
+}
+
+func (reverseCodec) Unseal(name string, data []byte) ([]byte, error) {
+	return reverseBytes(data), nil
+}
+
+func reverseBytes(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out
+}
+
+func TestSaveAndLoadWithCodec(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ix := NewIndex(NewIndexInput{Dir: dir, Codec: reverseCodec{}})
+	ix.Put(Document{ID: "page-1", Title: "Sealed", Content: "kept private"})
+	require.NoError(t, ix.Save())
+
+	raw, err := os.ReadFile(filepath.Join(dir, indexFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "kept private")
+
+	// Without the codec the file cannot be read
+	assert.Error(t, NewIndex(NewIndexInput{Dir: dir}).Load())
+
+	loaded := NewIndex(NewIndexInput{Dir: dir, Codec: reverseCodec{}})
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, []string{"page-1"}, hitIDs(loaded.Search(SearchInput{Query: "private"})))
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+		ix := NewIndex(NewIndexInput{Dir: t.TempDir()})
+		assert.Error(t, ix.Load())
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, indexFileName), []byte("{"), 0600))
+		ix := NewIndex(NewIndexInput{Dir: dir})
+		assert.Error(t, ix.Load())
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, indexFileName), []byte(`{"version":99}`), 0600))
+		ix := NewIndex(NewIndexInput{Dir: dir})
+		assert.ErrorIs(t, ix.Load(), ErrIndexVersion)
+	})
+}
+
+func TestOpenRebuildsFromCacheAndFollowsWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, pc.Set(ctx, cache.SetInput{
+		PageID: "page-1",
+		Data: testhelpers.NewGetChildrenResponse([]notionapi.Block{
+			testhelpers.NewParagraphBlock("Offline content is searchable"),
+		}),
+		TTL: time.Hour,
+	}))
+
+	ix, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page-1"}, hitIDs(ix.Search(SearchInput{Query: "offline"})))
+
+	// Later cache writes update the index.
+	require.NoError(t, pc.Set(ctx, cache.SetInput{
+		PageID: "page-2",
+		Data: testhelpers.NewGetChildrenResponse([]notionapi.Block{
+			testhelpers.NewHeading1Block("Fresh heading"),
+		}),
+		TTL: time.Hour,
+	}))
+	assert.Equal(t, []string{"page-2"}, hitIDs(ix.Search(SearchInput{Query: "fresh"})))
+
+	// Non block-list data is ignored.
+	require.NoError(t, pc.Set(ctx, cache.SetInput{PageID: "page-3", Data: "plain string"}))
+	assert.Equal(t, 2, ix.Len())
+
+	// Saved index is reused on the next open.
+	require.NoError(t, ix.Save())
+	reopened, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+}
+
+func TestOpenIndexesPageMeta(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	ix, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+
+	require.NoError(t, pc.Set(ctx, cache.SetInput{
+		PageID: cache.MetaKey("page-1"),
+		Data:   testhelpers.NewTestPage("page-1", "Prefetched Title"),
+		TTL:    time.Hour,
+	}))
+
+	hits := ix.Search(SearchInput{Query: "prefetched"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "page-1", hits[0].ID)
+	assert.Equal(t, "title", hits[0].MatchType)
+}
+
+func TestOpenRebuildsTitlesFromPageMeta(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	// Written before the index exists, so only a rebuild can pick them up
+	require.NoError(t, pc.Set(ctx, cache.SetInput{
+		PageID: "page-1",
+		Data: testhelpers.NewGetChildrenResponse([]notionapi.Block{
+			testhelpers.NewParagraphBlock("Quarterly numbers"),
+		}),
+		TTL: time.Hour,
+	}))
+	require.NoError(t, pc.Set(ctx, cache.SetInput{
+		PageID: cache.MetaKey("page-1"),
+		Data:   testhelpers.NewTestPage("page-1", "Roadmap"),
+		TTL:    time.Hour,
+	}))
+	require.NoError(t, pc.Set(ctx, cache.SetInput{
+		PageID: cache.MetaKey("page-2"),
+		Data:   testhelpers.NewTestPage("page-2", "Meeting Notes"),
+		TTL:    time.Hour,
+	}))
+
+	ix, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+	require.Equal(t, 2, ix.Len())
+
+	doc, ok := ix.Get("page-1")
+	require.True(t, ok)
+	assert.Equal(t, "Roadmap", doc.Title)
+	assert.Equal(t, "Quarterly numbers", doc.Content)
+
+	hits := ix.Search(SearchInput{Query: "meeting"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "page-2", hits[0].ID)
+	assert.Equal(t, "title", hits[0].MatchType)
+}
+
+func TestDeletedCacheEntriesLeaveTheIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	ix, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+
+	for _, id := range []string{"page-1", "page-2"} {
+		require.NoError(t, pc.Set(ctx, cache.SetInput{
+			PageID: id,
+			Data: testhelpers.NewGetChildrenResponse([]notionapi.Block{
+				testhelpers.NewParagraphBlock("Confidential salaries " + id),
+			}),
+			TTL: time.Hour,
+		}))
+		require.NoError(t, pc.Set(ctx, cache.SetInput{
+			PageID: cache.MetaKey(id),
+			Data:   testhelpers.NewTestPage(id, "Payroll "+id),
+			TTL:    time.Hour,
+		}))
+	}
+	require.NoError(t, ix.Save())
+
+	require.NoError(t, pc.Delete("page-1"))
+	assert.Equal(t, []string{"page-2"}, hitIDs(ix.Search(SearchInput{Query: "salaries"})))
+
+	require.NoError(t, pc.Clear())
+	assert.Empty(t, ix.Search(SearchInput{Query: "salaries"}))
+	assert.Empty(t, ix.Search(SearchInput{Query: "payroll"}))
+	assert.Zero(t, ix.Len())
+
+	require.NoError(t, ix.Save())
+	raw, err := os.ReadFile(filepath.Join(pc.Dir(), "index", indexFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "Confidential")
+	assert.NotContains(t, string(raw), "Payroll")
+}
+
+func TestSaveDuringCacheWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	ix, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+
+	// Set notifies the index while holding the cache lock and Save seals through
+	// the cache, so the two must not hold their locks across each other.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_ = pc.Set(ctx, cache.SetInput{
+					PageID: "page-1",
+					Data: testhelpers.NewGetChildrenResponse([]notionapi.Block{
+						testhelpers.NewParagraphBlock("racing writes"),
+					}),
+					TTL: time.Hour,
+				})
+			}()
+			go func() {
+				defer wg.Done()
+				_ = ix.Save()
+			}()
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Save and cache writes deadlocked")
+	}
+
+	require.NoError(t, ix.Save())
+	reopened, err := Open(ctx, OpenInput{Cache: pc})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page-1"}, hitIDs(reopened.Search(SearchInput{Query: "racing"})))
+}
+
+func TestOpenNilCache(t *testing.T) {
+	t.Parallel()
+
+	_, err := Open(context.Background(), OpenInput{})
+	assert.Error(t, err)
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	ix := newTestIndex()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ix.SetContent("page-4", "concurrent writes")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = ix.Search(SearchInput{Query: "concurrent"})
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, ix.Search(SearchInput{Query: "concurrent"}), 1)
+}
@@ -0,0 +1,102 @@
+package index
+
+import (
+	"strings"
+	"unicode"
+)
+
+// clauseKind identifies how a query clause is matched against documents.
+type clauseKind int
+
+const (
+	// clauseTerm matches a single exact term.
+	clauseTerm clauseKind = iota
+	// clausePrefix matches any term starting with the given prefix.
+	clausePrefix
+	// clausePhrase matches a sequence of consecutive terms.
+	clausePhrase
+)
+
+// clause is a single parsed query component. Every clause must match for a
+// document to be returned.
+type clause struct {
+	kind  clauseKind
+	terms []string
+}
+
+// parseQuery splits a query into clauses.
+// Supported syntax: plain terms, "quoted phrases" and prefix* terms.
+func parseQuery(query string) []clause {
+	var clauses []clause
+
+	rest := query
+	for {
+		open := strings.IndexByte(rest, '"')
+		if open == -1 {
+			clauses = append(clauses, parseTerms(rest)...)
+			break
+		}
+
+		clauses = append(clauses, parseTerms(rest[:open])...)
+
+		closing := strings.IndexByte(rest[open+1:], '"')
+		if closing == -1 {
+			// Unterminated quote: treat the remainder as a phrase.
+			clauses = appendPhrase(clauses, rest[open+1:])
+			break
+		}
+
+		clauses = appendPhrase(clauses, rest[open+1:open+1+closing])
+		rest = rest[open+1+closing+1:]
+	}
+
+	return clauses
+}
+
+// appendPhrase adds a phrase clause, degrading to a term clause for single words.
+func appendPhrase(clauses []clause, text string) []clause {
+	terms := tokenize(text)
+	switch len(terms) {
+	case 0:
+		return clauses
+	case 1:
+		return append(clauses, clause{kind: clauseTerm, terms: terms})
+	default:
+		return append(clauses, clause{kind: clausePhrase, terms: terms})
+	}
+}
+
+// parseTerms converts unquoted text into term and prefix clauses.
+func parseTerms(text string) []clause {
+	var clauses []clause
+	for _, field := range strings.Fields(text) {
+		prefix := strings.HasSuffix(field, "*")
+		for _, term := range tokenize(field) {
+			clauses = append(clauses, clause{kind: clauseTerm, terms: []string{term}})
+		}
+		if prefix && len(clauses) > 0 {
+			clauses[len(clauses)-1].kind = clausePrefix
+		}
+	}
+	return clauses
+}
+
+// tokenize lowercases text and splits it into letter/digit runs.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlightTerms returns the terms used to locate snippets for a query.
+func highlightTerms(clauses []clause) []string {
+	terms := make([]string, 0, len(clauses))
+	for _, c := range clauses {
+		if c.kind == clausePhrase {
+			terms = append(terms, strings.Join(c.terms, " "))
+			continue
+		}
+		terms = append(terms, c.terms...)
+	}
+	return terms
+}
@@ -0,0 +1,61 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []clause
+	}{
+		{
+			name:  "terms",
+			query: "Hello, World",
+			want: []clause{
+				{kind: clauseTerm, terms: []string{"hello"}},
+				{kind: clauseTerm, terms: []string{"world"}},
+			},
+		},
+		{
+			name:  "phrase and prefix",
+			query: `"design review" plan*`,
+			want: []clause{
+				{kind: clausePhrase, terms: []string{"design", "review"}},
+				{kind: clausePrefix, terms: []string{"plan"}},
+			},
+		},
+		{
+			name:  "single word phrase becomes term",
+			query: `"solo"`,
+			want: []clause{
+				{kind: clauseTerm, terms: []string{"solo"}},
+			},
+		},
+		{
+			name:  "unterminated quote",
+			query: `notes "open ended`,
+			want: []clause{
+				{kind: clauseTerm, terms: []string{"notes"}},
+				{kind: clausePhrase, terms: []string{"open", "ended"}},
+			},
+		},
+		{
+			name:  "bare star ignored",
+			query: "*",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parseQuery(tt.query))
+		})
+	}
+}
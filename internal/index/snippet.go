@@ -0,0 +1,85 @@
+package index
+
+import (
+	"unicode"
+)
+
+const (
+	// snippetBefore is the number of runes of context shown before a match.
+	snippetBefore = 20
+	// snippetAfter is the number of runes of context shown after a match.
+	snippetAfter = 30
+	// snippetFallback is the number of runes shown when nothing matches.
+	snippetFallback = 50
+)
+
+// Snippet returns a short excerpt of text around the first occurrence of any of the
+// given terms, tried in order. Matching is case-insensitive and the original case is
+// preserved. Ellipses mark truncated ends. If no term occurs, the start of the text
+// is returned. Snippet operates on runes, so multi-byte text is never split.
+func Snippet(text string, terms []string) string {
+	runes := []rune(text)
+	lower := lowerRunes(runes)
+
+	for _, term := range terms {
+		needle := lowerRunes([]rune(term))
+		if len(needle) == 0 {
+			continue
+		}
+
+		pos := indexRunes(lower, needle)
+		if pos == -1 {
+			continue
+		}
+
+		start := pos - snippetBefore
+		if start < 0 {
+			start = 0
+		}
+
+		end := pos + len(needle) + snippetAfter
+		if end > len(runes) {
+			end = len(runes)
+		}
+
+		snippet := string(runes[start:end])
+		if start > 0 {
+			snippet = "..." + snippet
+		}
+		if end < len(runes) {
+			snippet = snippet + "..."
+		}
+		return snippet
+	}
+
+	if len(runes) > snippetFallback {
+		return string(runes[:snippetFallback]) + "..."
+	}
+	return text
+}
+
+// lowerRunes lowercases each rune individually so indexes stay aligned with the input.
+func lowerRunes(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// indexRunes returns the index of the first occurrence of needle in haystack, or -1.
+func indexRunes(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,65 @@
+package index
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippet(t *testing.T) {
+	t.Parallel()
+
+	long := "This is a very long text with the word important somewhere in the middle of it"
+
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{
+			name:  "short text keeps original case",
+			text:  "Hello World",
+			terms: []string{"world"},
+			want:  "Hello World",
+		},
+		{
+			name:  "match in middle adds ellipses",
+			text:  long,
+			terms: []string{"important"},
+			want:  "... text with the word important somewhere in the middle of it",
+		},
+		{
+			name:  "later terms are tried when earlier ones miss",
+			text:  "alpha beta gamma",
+			terms: []string{"delta", "gamma"},
+			want:  "alpha beta gamma",
+		},
+		{
+			name:  "no match falls back to prefix",
+			text:  long,
+			terms: []string{"missing"},
+			want:  long[:50] + "...",
+		},
+		{
+			name:  "no terms",
+			text:  "short",
+			terms: nil,
+			want:  "short",
+		},
+		{
+			name:  "multi-byte text is not split",
+			text:  strings.Repeat("日本語", 10) + "検索" + strings.Repeat("テキスト", 10),
+			terms: []string{"検索"},
+			want:  "..." + strings.Repeat("本語日", 6) + "本語" + "検索" + strings.Repeat("テキスト", 7) + "テキ" + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Snippet(tt.text, tt.terms))
+		})
+	}
+}
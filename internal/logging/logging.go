@@ -0,0 +1,155 @@
+// Package logging sets up the debug log: structured records written to a
+// rotating file, with tokens and, optionally, page content redacted.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Attribute keys shared by the packages that log, so the same value always
+// has the same name. Values under the content keys are redacted when
+// OpenInput.RedactContent is set.
+const (
+	KeyPageID   = "page_id"
+	KeyObjectID = "object_id"
+	KeyTitle    = "title"   // content
+	KeyQuery    = "query"   // content
+	KeyContent  = "content" // content
+)
+
+// redacted replaces a secret or a piece of content.
+const redacted = "***"
+
+// contentKeys are the attribute keys whose values are page content.
+var contentKeys = map[string]bool{KeyTitle: true, KeyQuery: true, KeyContent: true}
+
+// tokenPattern matches Notion integration tokens, so one is redacted even
+// when it is not among the known secrets.
+var tokenPattern = regexp.MustCompile(`\b(secret|ntn)_[A-Za-z0-9]{20,}`)
+
+// OpenInput contains the parameters for opening the debug log.
+type OpenInput struct {
+	File          string
+	Level         string   // debug, info, warn or error; info when empty
+	MaxSize       int64    // bytes before the file is rotated; DefaultMaxSize when zero
+	MaxFiles      int      // rotated files kept; DefaultMaxFiles when zero
+	Secrets       *Secrets // optional; values replaced wherever they appear, such as the token
+	RedactContent bool     // also hide titles, queries and page text
+}
+
+// Open opens the debug log. The returned Closer closes its file.
+func Open(input OpenInput) (*slog.Logger, io.Closer, error) {
+	level, err := ParseLevel(input.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := openRotatingFile(input.File, input.MaxSize, input.MaxFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handler := slog.NewTextHandler(file, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: newRedactor(input.Secrets, input.RedactContent).replaceAttr,
+	})
+	return slog.New(handler), file, nil
+}
+
+// Discard returns a logger that drops every record.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// ParseLevel parses a level name. An empty name is info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("log level %q is not one of debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// Secrets is the set of values the debug log redacts. Secrets resolved after
+// the log is opened, such as a token printed by a command or the token of a
+// profile switched to later, are added to it as they become known. It is safe
+// for concurrent use.
+type Secrets struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// NewSecrets returns a set holding values. Empty values are ignored.
+func NewSecrets(values ...string) *Secrets {
+	s := &Secrets{}
+	s.Add(values...)
+	return s
+}
+
+// Add adds values to the set. Empty and known values are ignored.
+func (s *Secrets) Add(values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range values {
+		if v != "" && !slices.Contains(s.values, v) {
+			s.values = append(s.values, v)
+		}
+	}
+}
+
+// redact replaces every value of the set in str.
+func (s *Secrets) redact(str string) string {
+	if s == nil {
+		return str
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range s.values {
+		str = strings.ReplaceAll(str, v, redacted)
+	}
+	return str
+}
+
+// redactor rewrites attributes before they are written.
+type redactor struct {
+	secrets *Secrets
+	content bool
+}
+
+func newRedactor(secrets *Secrets, content bool) *redactor {
+	return &redactor{secrets: secrets, content: content}
+}
+
+// replaceAttr implements slog.HandlerOptions.ReplaceAttr.
+func (r *redactor) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if r.content && contentKeys[a.Key] {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.redact(a.Value.String()))
+	case slog.KindAny:
+		// Errors often quote requests and responses
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, r.redact(err.Error()))
+		}
+	}
+	return a
+}
+
+// redact removes secrets and tokens from s.
+func (r *redactor) redact(s string) string {
+	return tokenPattern.ReplaceAllString(r.secrets.redact(s), redacted)
+}
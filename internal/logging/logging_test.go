@@ -0,0 +1,201 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLog closes the log and returns its contents.
+func readLog(t *testing.T, path string, closer io.Closer) string {
+	t.Helper()
+	require.NoError(t, closer.Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "notion-tui.log")
+	logger, closer, err := Open(OpenInput{File: path, Level: "info"})
+	require.NoError(t, err)
+
+	logger.Debug("hidden")
+	logger.Info("shown", KeyPageID, "page-1")
+	logged := readLog(t, path, closer)
+
+	assert.NotContains(t, logged, "hidden")
+	assert.Contains(t, logged, `msg=shown page_id=page-1`)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestOpenInvalidLevel(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Open(OpenInput{File: filepath.Join(t.TempDir(), "x.log"), Level: "verbose"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `log level "verbose"`)
+}
+
+func TestRedaction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		redactContent bool
+		log           func(l *slog.Logger)
+		want          []string
+		wantNot       []string
+	}{
+		{
+			name: "known secret",
+			log: func(l *slog.Logger) {
+				l.Info("request", "auth", "Bearer my-passphrase")
+			},
+			want:    []string{`auth="Bearer ***"`},
+			wantNot: []string{"my-passphrase"},
+		},
+		{
+			name: "token pattern",
+			log: func(l *slog.Logger) {
+				l.Info("request", "url", "token=ntn_abcdefghijklmnopqrstuvwxyz0123")
+			},
+			want:    []string{`url="token=***"`},
+			wantNot: []string{"ntn_abcdef"},
+		},
+		{
+			name: "error",
+			log: func(l *slog.Logger) {
+				l.Info("failed", "error", fmt.Errorf("wrap: %w", errors.New("bad token my-passphrase")))
+			},
+			want:    []string{`error="wrap: bad token ***"`},
+			wantNot: []string{"my-passphrase"},
+		},
+		{
+			name: "content kept",
+			log: func(l *slog.Logger) {
+				l.Info("search", KeyQuery, "quarterly plan", KeyTitle, "Roadmap")
+			},
+			want: []string{`query="quarterly plan"`, "title=Roadmap"},
+		},
+		{
+			name:          "content redacted",
+			redactContent: true,
+			log: func(l *slog.Logger) {
+				l.Info("search", KeyQuery, "quarterly plan", KeyTitle, "Roadmap", KeyPageID, "page-1")
+			},
+			want:    []string{`query=***`, `title=***`, "page_id=page-1"},
+			wantNot: []string{"quarterly", "Roadmap"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "notion-tui.log")
+			logger, closer, err := Open(OpenInput{
+				File:          path,
+				Secrets:       NewSecrets("", "my-passphrase"),
+				RedactContent: tt.redactContent,
+			})
+			require.NoError(t, err)
+
+			tt.log(logger)
+			logged := readLog(t, path, closer)
+
+			for _, s := range tt.want {
+				assert.Contains(t, logged, s)
+			}
+			for _, s := range tt.wantNot {
+				assert.NotContains(t, logged, s)
+			}
+		})
+	}
+}
+
+func TestSecretsAddedLater(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "notion-tui.log")
+	secrets := NewSecrets()
+	logger, closer, err := Open(OpenInput{File: path, Secrets: secrets})
+	require.NoError(t, err)
+
+	// A token printed by notion_token_command is only known once resolved
+	secrets.Add("resolved-token", "")
+	logger.Info("request", "auth", "Bearer resolved-token")
+
+	logged := readLog(t, path, closer)
+	assert.Contains(t, logged, `auth="Bearer ***"`)
+	assert.NotContains(t, logged, "resolved-token")
+}
+
+func TestRotation(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "notion-tui.log")
+	file, err := openRotatingFile(path, 100, 2)
+	require.NoError(t, err)
+
+	line := strings.Repeat("x", 59) + "\n"
+	for i := 0; i < 5; i++ {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	// Each write after the first in a file pushes it past 100 bytes
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, line, string(data), name)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "only max files are kept")
+}
+
+func TestRotationAppendsToExistingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "notion-tui.log")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 90)), 0600))
+
+	file, err := openRotatingFile(path, 100, 1)
+	require.NoError(t, err)
+	_, err = file.Write([]byte("0123456789012\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789012\n", string(data))
+
+	old, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Len(t, old, 90)
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "debug", "INFO", "warn", "error"} {
+		_, err := ParseLevel(s)
+		assert.NoError(t, err, s)
+	}
+	_, err := ParseLevel("trace")
+	assert.Error(t, err)
+}
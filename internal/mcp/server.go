@@ -0,0 +1,295 @@
+// Package mcp implements a Model Context Protocol server that exposes tools
+// to local assistants over stdio.
+//
+// Messages are JSON-RPC 2.0 objects, one per line. The server answers the
+// lifecycle requests (initialize, ping) and the tools requests (tools/list,
+// tools/call); requests are handled one at a time in the order received.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ProtocolVersion is the latest protocol revision the server implements.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the protocol revisions a client may ask for.
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Handler runs a tool with the arguments of a tools/call request. The text
+// it returns is the tool's result. An error is reported to the client as a
+// failed tool call, so the model can read it and try again.
+type Handler func(ctx context.Context, args json.RawMessage) (string, error)
+
+// Tool is a tool the server offers.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the arguments, an object schema.
+	InputSchema map[string]any
+	// ReadOnly tells clients the tool does not change the workspace.
+	ReadOnly bool
+	Handler  Handler
+}
+
+// Server is an MCP server.
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+
+	out io.Writer // set by Serve
+}
+
+// NewServerInput contains the parameters for creating a Server.
+type NewServerInput struct {
+	Name    string // reported to clients as serverInfo
+	Version string
+	Tools   []Tool
+}
+
+// NewServer creates a Server offering the given tools.
+func NewServer(input NewServerInput) (*Server, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+	seen := make(map[string]bool, len(input.Tools))
+	for _, tool := range input.Tools {
+		if tool.Name == "" || tool.Handler == nil {
+			return nil, fmt.Errorf("tool %q needs a name and a handler", tool.Name)
+		}
+		if seen[tool.Name] {
+			return nil, fmt.Errorf("duplicate tool %q", tool.Name)
+		}
+		seen[tool.Name] = true
+	}
+	return &Server{name: input.Name, version: input.Version, tools: input.Tools}, nil
+}
+
+// request is a JSON-RPC request or notification. Notifications have no ID.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error of a failed request.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r is closed or
+// ctx is cancelled. Reaching the end of r is not an error.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = w
+	reader := bufio.NewReader(r)
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					readErr <- fmt.Errorf("read request: %w", err)
+				}
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-readErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			if err := s.handleLine(ctx, line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleLine handles one message. Only a failure to write is returned.
+func (s *Server) handleLine(ctx context.Context, line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return s.write(response{ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "parse error: " + err.Error()}})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if len(req.ID) == 0 {
+			return nil // a response from the client; the server sends no requests
+		}
+		return s.write(response{ID: req.ID, Error: &rpcError{Code: codeInvalidRequest, Message: "invalid request"}})
+	}
+	if len(req.ID) == 0 {
+		return nil // notifications need no answer
+	}
+
+	result, rpcErr := s.dispatch(ctx, req)
+	return s.write(response{ID: req.ID, Result: result, Error: rpcErr})
+}
+
+// dispatch runs a request and returns its result or error.
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+// initialize answers the initialize request, agreeing on the protocol
+// version the client asked for when the server knows it.
+func (s *Server) initialize(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	version := ProtocolVersion
+	if slices.Contains(supportedVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools": map[string]any{"listChanged": false},
+		},
+		"serverInfo": map[string]any{"name": s.name, "version": s.version},
+	}, nil
+}
+
+// toolInfo is a tool as listed by tools/list.
+type toolInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	Annotations map[string]any `json:"annotations"`
+}
+
+// listTools answers tools/list.
+func (s *Server) listTools() any {
+	tools := make([]toolInfo, 0, len(s.tools))
+	for _, tool := range s.tools {
+		schema := tool.InputSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		tools = append(tools, toolInfo{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: schema,
+			Annotations: map[string]any{"readOnlyHint": tool.ReadOnly},
+		})
+	}
+	return map[string]any{"tools": tools}
+}
+
+// textContent is a text item of a tool result.
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// toolResult is the result of tools/call.
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+// callTool answers tools/call.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + p.Name}
+	}
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	text, err := s.tools[i].Handler(ctx, args)
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: text}}}, nil
+}
+
+// write sends a message as one line.
+func (s *Server) write(resp response) error {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("encode response: %w", err)
+	}
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write response: %w", err)
+	}
+	return nil
+}
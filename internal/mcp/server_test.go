@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// client drives a server over a pipe, as an assistant would over stdio.
+type client struct {
+	t    *testing.T
+	in   *io.PipeWriter
+	out  *bufio.Reader
+	done chan error
+	next int
+}
+
+func startServer(t *testing.T, tools ...Tool) *client {
+	t.Helper()
+
+	server, err := NewServer(NewServerInput{Name: "test", Version: "1.0", Tools: tools})
+	require.NoError(t, err)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &client{t: t, in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+	go func() {
+		c.done <- server.Serve(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return c
+}
+
+// send writes a raw line.
+func (c *client) send(line string) {
+	c.t.Helper()
+	_, err := io.WriteString(c.in, line+"\n")
+	require.NoError(c.t, err)
+}
+
+// call sends a request and returns its response.
+func (c *client) call(method string, params any) map[string]any {
+	c.t.Helper()
+	c.next++
+	req := map[string]any{"jsonrpc": "2.0", "id": c.next, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	data, err := json.Marshal(req)
+	require.NoError(c.t, err)
+	c.send(string(data))
+	return c.read()
+}
+
+// read reads one response.
+func (c *client) read() map[string]any {
+	c.t.Helper()
+	line, err := c.out.ReadBytes('\n')
+	require.NoError(c.t, err)
+	var resp map[string]any
+	require.NoError(c.t, json.Unmarshal(line, &resp))
+	assert.Equal(c.t, "2.0", resp["jsonrpc"])
+	return resp
+}
+
+func echoTool() Tool {
+	return Tool{
+		Name:        "echo",
+		Description: "Echo the text",
+		InputSchema: map[string]any{"type": "object", "properties": map[string]any{"text": map[string]any{"type": "string"}}},
+		ReadOnly:    true,
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			if a.Text == "" {
+				return "", errors.New("text is required")
+			}
+			return a.Text, nil
+		},
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	t.Parallel()
+
+	c := startServer(t, echoTool())
+
+	resp := c.call("initialize", map[string]any{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "script", "version": "0"},
+	})
+	result := resp["result"].(map[string]any)
+	assert.Equal(t, "2025-03-26", result["protocolVersion"], "a known version is agreed on")
+	assert.Equal(t, map[string]any{"name": "test", "version": "1.0"}, result["serverInfo"])
+	assert.Contains(t, result["capabilities"], "tools")
+
+	// Notifications get no answer, so the next line read is the ping's
+	c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp = c.call("ping", nil)
+	assert.Equal(t, map[string]any{}, resp["result"])
+	assert.EqualValues(t, 2, resp["id"])
+
+	resp = c.call("tools/list", nil)
+	tools := resp["result"].(map[string]any)["tools"].([]any)
+	require.Len(t, tools, 1)
+	tool := tools[0].(map[string]any)
+	assert.Equal(t, "echo", tool["name"])
+	assert.Equal(t, map[string]any{"readOnlyHint": true}, tool["annotations"])
+	assert.Equal(t, "object", tool["inputSchema"].(map[string]any)["type"])
+
+	resp = c.call("tools/call", map[string]any{"name": "echo", "arguments": map[string]any{"text": "hello"}})
+	assert.Equal(t, map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": "hello"}},
+		"isError": false,
+	}, resp["result"])
+
+	resp = c.call("tools/call", map[string]any{"name": "echo"})
+	assert.Equal(t, map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": "text is required"}},
+		"isError": true,
+	}, resp["result"], "tool failures are results the model can read")
+
+	c.in.Close()
+	assert.NoError(t, <-c.done, "the end of input ends the session")
+}
+
+func TestServerErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		line     string
+		wantCode float64
+	}{
+		{name: "parse error", line: `{"jsonrpc":`, wantCode: codeParseError},
+		{name: "unknown method", line: `{"jsonrpc":"2.0","id":"a","method":"resources/list"}`, wantCode: codeMethodNotFound},
+		{name: "unknown tool", line: `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"rm"}}`, wantCode: codeInvalidParams},
+		{name: "invalid request", line: `{"jsonrpc":"1.0","id":"a","method":"ping"}`, wantCode: codeInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := startServer(t, echoTool())
+			c.send(tt.line)
+			resp := c.read()
+			require.Contains(t, resp, "error")
+			assert.Equal(t, tt.wantCode, resp["error"].(map[string]any)["code"])
+			assert.NotContains(t, resp, "result")
+		})
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewServer(NewServerInput{Name: "test", Tools: []Tool{echoTool(), echoTool()}})
+	assert.ErrorContains(t, err, "duplicate tool")
+
+	_, err = NewServer(NewServerInput{Name: "test", Tools: []Tool{{Name: "nothing"}}})
+	assert.Error(t, err)
+}
@@ -3,6 +3,9 @@ package notion
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/jomei/notionapi"
@@ -15,13 +18,18 @@ type SearchInput struct {
 	Filter      string // "page", "database", or "" for all
 	PageSize    int
 	StartCursor string
+	// SortLastEdited orders results by last edit, most recent first,
+	// instead of by relevance.
+	SortLastEdited bool
 }
 
 // SearchResult represents a unified search result.
 type SearchResult struct {
 	ID         string
 	Title      string
+	Icon       string // emoji icon, if it has one
 	ObjectType string // "page" or "database"
+	Created    time.Time
 	LastEdited time.Time
 	ParentType string // "workspace", "database_id", "page_id"
 	ParentID   string
@@ -35,25 +43,54 @@ type SearchResponse struct {
 }
 
 // Client wraps the Notion API client with rate limiting support.
+// Requests are prioritized by the context they are made with; see WithPriority.
+// Every request is traced to the client's logger at debug level and kept
+// in a short request log; see RecentRequests. Stats aggregates them.
 type Client struct {
-	api     *notionapi.Client
-	limiter *rate.Limiter
+	api         *notionapi.Client
+	limiter     *rate.Limiter
+	logger      *slog.Logger
+	requests    requestLog
+	stats       *apiStats
+	foreground  atomic.Int32 // foreground requests waiting or in flight
+	pausedUntil atomic.Int64 // unix nanoseconds until which idle requests are held back
 }
 
-// NewClient creates a new rate-limited Notion API client.
+// NewClient creates a new rate-limited Notion API client that logs to
+// slog.Default().
 // Rate limit: 2.5 requests/second with burst of 3.
 func NewClient(token string) *Client {
-	return &Client{
-		api:     notionapi.NewClient(notionapi.Token(token), notionapi.WithRetry(5)),
+	c := &Client{
 		limiter: rate.NewLimiter(rate.Limit(2.5), 3),
+		logger:  slog.Default(),
+		stats:   newAPIStats(),
 	}
+
+	httpClient := &http.Client{
+		Transport: &rateLimitTransport{base: http.DefaultTransport, client: c},
+	}
+	c.api = notionapi.NewClient(
+		notionapi.Token(token),
+		notionapi.WithRetry(5),
+		notionapi.WithHTTPClient(httpClient),
+	)
+	return c
+}
+
+// SetLogger replaces the logger requests are traced to. It must be called
+// before the client is used.
+func (c *Client) SetLogger(l *slog.Logger) {
+	c.logger = l
 }
 
 // GetPage retrieves a page from Notion by ID.
 func (c *Client) GetPage(ctx context.Context, id string) (*notionapi.Page, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	page, err := c.api.Page.Get(ctx, notionapi.PageID(id))
 	if err != nil {
 		return nil, fmt.Errorf("get page %s: %w", id, err)
@@ -61,12 +98,61 @@ func (c *Client) GetPage(ctx context.Context, id string) (*notionapi.Page, error
 	return page, nil
 }
 
+// CreatePage creates a page, such as a new row in a database.
+func (c *Client) CreatePage(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	page, err := c.api.Page.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("create page: %w", err)
+	}
+	return page, nil
+}
+
+// Me returns the bot user of the integration the token belongs to. It is the
+// cheapest call that proves a token is valid.
+func (c *Client) Me(ctx context.Context) (*notionapi.User, error) {
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	user, err := c.api.User.Me(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get integration user: %w", err)
+	}
+	return user, nil
+}
+
+// GetDatabase retrieves a database, including its property schema, by ID.
+func (c *Client) GetDatabase(ctx context.Context, id string) (*notionapi.Database, error) {
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	db, err := c.api.Database.Get(ctx, notionapi.DatabaseID(id))
+	if err != nil {
+		return nil, fmt.Errorf("get database %s: %w", id, err)
+	}
+	return db, nil
+}
+
 // QueryDatabase queries a Notion database with optional filters and sorting.
 func (c *Client) QueryDatabase(ctx context.Context, id string,
 	req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	resp, err := c.api.Database.Query(ctx, notionapi.DatabaseID(id), req)
 	if err != nil {
 		return nil, fmt.Errorf("query database %s: %w", id, err)
@@ -77,9 +163,12 @@ func (c *Client) QueryDatabase(ctx context.Context, id string,
 // GetBlocks retrieves child blocks of a page or block.
 func (c *Client) GetBlocks(ctx context.Context, id string,
 	pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	blocks, err := c.api.Block.GetChildren(ctx, notionapi.BlockID(id), pagination)
 	if err != nil {
 		return nil, fmt.Errorf("get blocks for %s: %w", id, err)
@@ -90,9 +179,12 @@ func (c *Client) GetBlocks(ctx context.Context, id string,
 // AppendBlocks appends blocks to a page or block.
 func (c *Client) AppendBlocks(ctx context.Context, id string,
 	req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	resp, err := c.api.Block.AppendChildren(ctx, notionapi.BlockID(id), req)
 	if err != nil {
 		return nil, fmt.Errorf("append blocks to %s: %w", id, err)
@@ -103,9 +195,12 @@ func (c *Client) AppendBlocks(ctx context.Context, id string,
 // UpdatePage updates a page's properties.
 func (c *Client) UpdatePage(ctx context.Context, id string,
 	req *notionapi.PageUpdateRequest) (*notionapi.Page, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	page, err := c.api.Page.Update(ctx, notionapi.PageID(id), req)
 	if err != nil {
 		return nil, fmt.Errorf("update page %s: %w", id, err)
@@ -115,9 +210,12 @@ func (c *Client) UpdatePage(ctx context.Context, id string,
 
 // DeleteBlock archives a block (Notion API soft-deletes via archive).
 func (c *Client) DeleteBlock(ctx context.Context, id string) (notionapi.Block, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	block, err := c.api.Block.Delete(ctx, notionapi.BlockID(id))
 	if err != nil {
 		return nil, fmt.Errorf("delete block %s: %w", id, err)
@@ -127,9 +225,12 @@ func (c *Client) DeleteBlock(ctx context.Context, id string) (notionapi.Block, e
 
 // GetBlock retrieves a single block by ID.
 func (c *Client) GetBlock(ctx context.Context, id string) (notionapi.Block, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	block, err := c.api.Block.Get(ctx, notionapi.BlockID(id))
 	if err != nil {
 		return nil, fmt.Errorf("get block %s: %w", id, err)
@@ -140,9 +241,12 @@ func (c *Client) GetBlock(ctx context.Context, id string) (notionapi.Block, erro
 // UpdateBlock updates a block's properties.
 func (c *Client) UpdateBlock(ctx context.Context, id string,
 	req *notionapi.BlockUpdateRequest) (notionapi.Block, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
 	block, err := c.api.Block.Update(ctx, notionapi.BlockID(id), req)
 	if err != nil {
 		return nil, fmt.Errorf("update block %s: %w", id, err)
@@ -154,9 +258,11 @@ func (c *Client) UpdateBlock(ctx context.Context, id string,
 // When no filter is specified, it searches for both pages and databases
 // by making separate API calls (due to library serialization constraints).
 func (c *Client) Search(ctx context.Context, input SearchInput) (*SearchResponse, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	release, err := c.wait(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	// If a specific filter is provided, use it directly
 	if input.Filter == "page" || input.Filter == "database" {
@@ -207,6 +313,12 @@ func (c *Client) searchWithFilter(ctx context.Context, input SearchInput, filter
 	if input.StartCursor != "" {
 		req.StartCursor = notionapi.Cursor(input.StartCursor)
 	}
+	if input.SortLastEdited {
+		req.Sort = &notionapi.SortObject{
+			Timestamp: notionapi.TimestampLastEdited,
+			Direction: notionapi.SortOrderDESC,
+		}
+	}
 
 	resp, err := c.api.Search.Do(ctx, req)
 	if err != nil {
@@ -226,7 +338,9 @@ func (c *Client) convertSearchResponse(resp *notionapi.SearchResponse) *SearchRe
 			results = append(results, SearchResult{
 				ID:         string(v.ID),
 				Title:      extractPageTitle(v),
+				Icon:       iconEmoji(v.Icon),
 				ObjectType: "page",
+				Created:    v.CreatedTime,
 				LastEdited: v.LastEditedTime,
 				ParentType: getParentType(v.Parent),
 				ParentID:   getParentID(v.Parent),
@@ -235,7 +349,9 @@ func (c *Client) convertSearchResponse(resp *notionapi.SearchResponse) *SearchRe
 			results = append(results, SearchResult{
 				ID:         string(v.ID),
 				Title:      extractDatabaseTitle(v),
+				Icon:       iconEmoji(v.Icon),
 				ObjectType: "database",
+				Created:    v.CreatedTime,
 				LastEdited: v.LastEditedTime,
 				ParentType: getDatabaseParentType(v.Parent),
 				ParentID:   getDatabaseParentID(v.Parent),
@@ -250,6 +366,17 @@ func (c *Client) convertSearchResponse(resp *notionapi.SearchResponse) *SearchRe
 	}
 }
 
+// PageTitle returns the plain-text title of a page, or "Untitled".
+func PageTitle(page *notionapi.Page) string {
+	return extractPageTitle(page)
+}
+
+// PageParentID returns the ID of the database, page or block containing a page.
+// It is empty for workspace-level pages.
+func PageParentID(page *notionapi.Page) string {
+	return getParentID(page.Parent)
+}
+
 // extractPageTitle gets the title from a page's properties.
 func extractPageTitle(page *notionapi.Page) string {
 	for _, prop := range page.Properties {
@@ -270,6 +397,14 @@ func extractDatabaseTitle(db *notionapi.Database) string {
 	return "Untitled Database"
 }
 
+// iconEmoji returns an emoji icon, or "" for none or an image icon.
+func iconEmoji(icon *notionapi.Icon) string {
+	if icon == nil || icon.Emoji == nil {
+		return ""
+	}
+	return string(*icon.Emoji)
+}
+
 // getParentType returns the parent type for a page.
 func getParentType(parent notionapi.Parent) string {
 	switch parent.Type {
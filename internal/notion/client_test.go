@@ -8,6 +8,7 @@ import (
 
 	"github.com/jomei/notionapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestNewClient tests client initialization.
@@ -420,3 +421,22 @@ func TestErrorWrapping(t *testing.T) {
 func containsString(msg, substr string) bool {
 	return len(msg) >= len(substr)
 }
+
+func TestConvertSearchResponseIcons(t *testing.T) {
+	t.Parallel()
+
+	emoji := notionapi.Emoji("✅")
+	client := NewClient("test-token")
+	resp := client.convertSearchResponse(&notionapi.SearchResponse{
+		Results: []notionapi.Object{
+			&notionapi.Database{ID: "db-1", Icon: &notionapi.Icon{Type: "emoji", Emoji: &emoji}},
+			&notionapi.Database{ID: "db-2", Icon: &notionapi.Icon{Type: "external"}},
+			&notionapi.Page{ID: "page-1"},
+		},
+	})
+
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, "✅", resp.Results[0].Icon)
+	assert.Empty(t, resp.Results[1].Icon)
+	assert.Empty(t, resp.Results[2].Icon)
+}
@@ -2,6 +2,7 @@ package notion
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/jomei/notionapi"
@@ -9,14 +10,22 @@ import (
 
 // ConvertBlocksToMarkdown converts a slice of Notion blocks to Markdown string.
 func ConvertBlocksToMarkdown(blocks []notionapi.Block) (string, error) {
-	if len(blocks) == 0 {
+	return ConvertBlockTreeToMarkdown(NewBlockNodes(blocks))
+}
+
+// ConvertBlockTreeToMarkdown converts blocks and their nested children to a
+// Markdown string. Children of list items are indented under their parent and
+// children of quotes and callouts stay inside the blockquote.
+func ConvertBlockTreeToMarkdown(nodes []BlockNode) (string, error) {
+	if len(nodes) == 0 {
 		return "", nil
 	}
 
 	var result strings.Builder
 	var listContext *listState = nil
 
-	for i, block := range blocks {
+	for i, node := range nodes {
+		block := node.Block
 		if block == nil {
 			continue
 		}
@@ -36,12 +45,27 @@ func ConvertBlocksToMarkdown(blocks []notionapi.Block) (string, error) {
 			return "", fmt.Errorf("convert block %d: %w", i, err)
 		}
 
+		if len(node.Children) > 0 {
+			children, err := ConvertBlockTreeToMarkdown(node.Children)
+			if err != nil {
+				return "", fmt.Errorf("convert children of block %d: %w", i, err)
+			}
+			if children != "" {
+				children = indentChildren(children, blockType)
+				if md != "" {
+					md += "\n" + children
+				} else {
+					md = children
+				}
+			}
+		}
+
 		if md != "" {
 			result.WriteString(md)
 			result.WriteString("\n")
 
 			// Add extra newline after certain block types for better readability
-			if shouldAddExtraNewline(blockType) {
+			if shouldAddExtraNewline(blockType) || endsList(nodes, i) {
 				result.WriteString("\n")
 			}
 		}
@@ -55,6 +79,31 @@ func ConvertBlocksToMarkdown(blocks []notionapi.Block) (string, error) {
 	return strings.TrimRight(result.String(), "\n"), nil
 }
 
+// indentChildren nests the Markdown of a block's children under the block.
+func indentChildren(md string, parent notionapi.BlockType) string {
+	var prefix, blank string
+	switch parent {
+	case notionapi.BlockTypeBulletedListItem, notionapi.BlockTypeToDo:
+		prefix = "  "
+	case notionapi.BlockTypeNumberedListItem:
+		prefix = "   "
+	case notionapi.BlockTypeQuote, notionapi.BlockTypeCallout:
+		prefix, blank = "> ", ">"
+	default:
+		return md
+	}
+
+	lines := strings.Split(md, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blank
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // listState tracks the state for numbered list items.
 type listState struct {
 	counter int
@@ -71,6 +120,10 @@ func shouldAddExtraNewline(blockType notionapi.BlockType) bool {
 		notionapi.BlockTypeQuote,
 		notionapi.BlockTypeDivider,
 		notionapi.BlockTypeImage,
+		notionapi.BlockTypeFile,
+		notionapi.BlockTypePdf,
+		notionapi.BlockTypeChildPage,
+		notionapi.BlockTypeChildDatabase,
 		notionapi.BlockTypeCallout:
 		return true
 	default:
@@ -78,6 +131,39 @@ func shouldAddExtraNewline(blockType notionapi.BlockType) bool {
 	}
 }
 
+// endsList reports whether the block at i is the last item of a list followed
+// by a block that would be read back as a continuation of the item's text,
+// such as a paragraph. Those need a blank line in between.
+func endsList(nodes []BlockNode, i int) bool {
+	if !isListItem(nodes[i].Block.GetType()) || i+1 >= len(nodes) || nodes[i+1].Block == nil {
+		return false
+	}
+	switch next := nodes[i+1].Block.GetType(); {
+	case isListItem(next):
+		return false
+	case next == notionapi.BlockTypeHeading1, next == notionapi.BlockTypeHeading2,
+		next == notionapi.BlockTypeHeading3, next == notionapi.BlockTypeCode,
+		next == notionapi.BlockTypeQuote, next == notionapi.BlockTypeCallout,
+		next == notionapi.BlockTypeDivider:
+		// These start with markup that ends the list on its own
+		return false
+	default:
+		return true
+	}
+}
+
+// isListItem reports whether blocks of a type are rendered as list items.
+func isListItem(blockType notionapi.BlockType) bool {
+	switch blockType {
+	case notionapi.BlockTypeBulletedListItem,
+		notionapi.BlockTypeNumberedListItem,
+		notionapi.BlockTypeToDo:
+		return true
+	default:
+		return false
+	}
+}
+
 // convertBlock converts a single Notion block to Markdown.
 func convertBlock(block notionapi.Block, listCtx *listState) (string, error) {
 	if block == nil {
@@ -127,6 +213,18 @@ func convertBlock(block notionapi.Block, listCtx *listState) (string, error) {
 	case *notionapi.CalloutBlock:
 		return convertCallout(b), nil
 
+	case *notionapi.FileBlock:
+		return convertFile(b.File.Caption, b.File.Type, b.File.File, b.File.External), nil
+
+	case *notionapi.PdfBlock:
+		return convertFile(b.Pdf.Caption, b.Pdf.Type, b.Pdf.File, b.Pdf.External), nil
+
+	case *notionapi.ChildPageBlock:
+		return pageLink(b.ChildPage.Title, string(b.ID)), nil
+
+	case *notionapi.ChildDatabaseBlock:
+		return pageLink(b.ChildDatabase.Title, string(b.ID)), nil
+
 	default:
 		// Return empty string for unsupported block types
 		// This allows graceful handling without errors
@@ -238,18 +336,7 @@ func convertImage(block *notionapi.ImageBlock) string {
 		return ""
 	}
 
-	var url string
-	switch block.Image.Type {
-	case notionapi.FileTypeExternal:
-		if block.Image.External != nil {
-			url = block.Image.External.URL
-		}
-	case notionapi.FileTypeFile:
-		if block.Image.File != nil {
-			url = block.Image.File.URL
-		}
-	}
-
+	url := FileURL(block.Image.Type, block.Image.File, block.Image.External)
 	caption := ""
 	if len(block.Image.Caption) > 0 {
 		caption = GetRichTextString(block.Image.Caption)
@@ -262,6 +349,44 @@ func convertImage(block *notionapi.ImageBlock) string {
 	return fmt.Sprintf("![%s](%s)", caption, url)
 }
 
+// convertFile converts a file or PDF block to a Markdown link named by its
+// caption, or by the file name when there is no caption.
+func convertFile(caption []notionapi.RichText, fileType notionapi.FileType, file, external *notionapi.FileObject) string {
+	url := FileURL(fileType, file, external)
+	if url == "" {
+		return ""
+	}
+
+	name := GetRichTextString(caption)
+	if name == "" {
+		name = path.Base(strings.SplitN(url, "?", 2)[0])
+	}
+	return fmt.Sprintf("[%s](%s)", name, url)
+}
+
+// FileURL returns the URL of a file uploaded to Notion or hosted elsewhere.
+func FileURL(fileType notionapi.FileType, file, external *notionapi.FileObject) string {
+	switch fileType {
+	case notionapi.FileTypeExternal:
+		if external != nil {
+			return external.URL
+		}
+	case notionapi.FileTypeFile:
+		if file != nil {
+			return file.URL
+		}
+	}
+	return ""
+}
+
+// pageLink converts a child page or database block to a link to it.
+func pageLink(title, id string) string {
+	if title == "" {
+		title = "Untitled"
+	}
+	return fmt.Sprintf("[%s](https://www.notion.so/%s)", title, NormalizeID(id))
+}
+
 // convertBookmark converts a bookmark block to Markdown link.
 func convertBookmark(block *notionapi.BookmarkBlock) string {
 	if block == nil {
@@ -299,3 +424,89 @@ func convertCallout(block *notionapi.CalloutBlock) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// ConvertBlockTreeToText converts blocks and their nested children to plain
+// text without Markdown formatting. List markers are kept and children are
+// indented by two spaces.
+func ConvertBlockTreeToText(nodes []BlockNode) string {
+	var result strings.Builder
+	counter := 0
+
+	for _, node := range nodes {
+		block := node.Block
+		if block == nil {
+			continue
+		}
+
+		blockType := block.GetType()
+		if blockType == notionapi.BlockTypeNumberedListItem {
+			counter++
+		} else {
+			counter = 0
+		}
+
+		text := blockPlainText(block, counter)
+		if len(node.Children) > 0 {
+			if children := ConvertBlockTreeToText(node.Children); children != "" {
+				lines := strings.Split(children, "\n")
+				for i, line := range lines {
+					if line != "" {
+						lines[i] = "  " + line
+					}
+				}
+				if text != "" {
+					text += "\n"
+				}
+				text += strings.Join(lines, "\n")
+			}
+		}
+
+		if text != "" {
+			result.WriteString(text)
+			result.WriteString("\n")
+			if shouldAddExtraNewline(blockType) {
+				result.WriteString("\n")
+			}
+		}
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
+
+// blockPlainText returns the text of a single block. counter is the position
+// of a numbered list item in its list.
+func blockPlainText(block notionapi.Block, counter int) string {
+	switch b := block.(type) {
+	case *notionapi.ParagraphBlock:
+		return GetRichTextString(b.Paragraph.RichText)
+	case *notionapi.Heading1Block:
+		return GetRichTextString(b.Heading1.RichText)
+	case *notionapi.Heading2Block:
+		return GetRichTextString(b.Heading2.RichText)
+	case *notionapi.Heading3Block:
+		return GetRichTextString(b.Heading3.RichText)
+	case *notionapi.BulletedListItemBlock:
+		return "- " + GetRichTextString(b.BulletedListItem.RichText)
+	case *notionapi.NumberedListItemBlock:
+		return fmt.Sprintf("%d. %s", counter, GetRichTextString(b.NumberedListItem.RichText))
+	case *notionapi.ToDoBlock:
+		if b.ToDo.Checked {
+			return "[x] " + GetRichTextString(b.ToDo.RichText)
+		}
+		return "[ ] " + GetRichTextString(b.ToDo.RichText)
+	case *notionapi.ToggleBlock:
+		return GetRichTextString(b.Toggle.RichText)
+	case *notionapi.QuoteBlock:
+		return GetRichTextString(b.Quote.RichText)
+	case *notionapi.CalloutBlock:
+		return GetRichTextString(b.Callout.RichText)
+	case *notionapi.CodeBlock:
+		return GetRichTextString(b.Code.RichText)
+	case *notionapi.BookmarkBlock:
+		return b.Bookmark.URL
+	case *notionapi.ImageBlock:
+		return GetRichTextString(b.Image.Caption)
+	default:
+		return ""
+	}
+}
@@ -550,6 +550,62 @@ func TestConvertImage(t *testing.T) {
 	}
 }
 
+func TestConvertFilesAndChildPages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		block    notionapi.Block
+		expected string
+	}{
+		{
+			name: "uploaded file without caption",
+			block: &notionapi.FileBlock{
+				BasicBlock: notionapi.BasicBlock{Type: notionapi.BlockTypeFile},
+				File: notionapi.BlockFile{
+					Type: notionapi.FileTypeFile,
+					File: &notionapi.FileObject{URL: "https://files.example.com/a/report.xlsx?X-Amz-Expires=3600"},
+				},
+			},
+			expected: "[report.xlsx](https://files.example.com/a/report.xlsx?X-Amz-Expires=3600)",
+		},
+		{
+			name: "pdf with caption",
+			block: &notionapi.PdfBlock{
+				BasicBlock: notionapi.BasicBlock{Type: notionapi.BlockTypePdf},
+				Pdf: notionapi.Pdf{
+					Type:     notionapi.FileTypeExternal,
+					External: &notionapi.FileObject{URL: "https://example.com/spec.pdf"},
+					Caption:  []notionapi.RichText{{PlainText: "Spec"}},
+				},
+			},
+			expected: "[Spec](https://example.com/spec.pdf)",
+		},
+		{
+			name: "child page",
+			block: &notionapi.ChildPageBlock{
+				BasicBlock: notionapi.BasicBlock{
+					ID:   "12345678-90ab-cdef-1234-567890abcdef",
+					Type: notionapi.BlockTypeChildPage,
+				},
+				ChildPage: struct {
+					Title string `json:"title"`
+				}{Title: "Meeting notes"},
+			},
+			expected: "[Meeting notes](https://www.notion.so/1234567890abcdef1234567890abcdef)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := convertBlock(tt.block, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestConvertNesting(t *testing.T) {
 	t.Parallel()
 
@@ -702,6 +758,26 @@ First paragraph.
 	assert.Equal(t, expected, result)
 }
 
+func TestConvertListFollowedByParagraph(t *testing.T) {
+	t.Parallel()
+
+	blocks := []notionapi.Block{
+		&notionapi.BulletedListItemBlock{
+			BasicBlock:       notionapi.BasicBlock{Type: notionapi.BlockTypeBulletedListItem},
+			BulletedListItem: notionapi.ListItem{RichText: []notionapi.RichText{{PlainText: "Item"}}},
+		},
+		&notionapi.ParagraphBlock{
+			BasicBlock: notionapi.BasicBlock{Type: notionapi.BlockTypeParagraph},
+			Paragraph:  notionapi.Paragraph{RichText: []notionapi.RichText{{PlainText: "After the list."}}},
+		},
+	}
+
+	result, err := ConvertBlocksToMarkdown(blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, "- Item\n\nAfter the list.", result)
+	assert.Len(t, ParseMarkdown(result), 2, "the paragraph is not read back as part of the item")
+}
+
 func TestConvertBookmark(t *testing.T) {
 	t.Parallel()
 
@@ -928,3 +1004,88 @@ func TestConvertTableOfContents(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "[Table of Contents]", result)
 }
+
+func TestConvertBlockTree(t *testing.T) {
+	t.Parallel()
+
+	paragraph := func(text string) notionapi.Block {
+		return &notionapi.ParagraphBlock{
+			BasicBlock: notionapi.BasicBlock{Type: notionapi.BlockTypeParagraph},
+			Paragraph:  notionapi.Paragraph{RichText: []notionapi.RichText{{PlainText: text}}},
+		}
+	}
+	bullet := func(text string) notionapi.Block {
+		return &notionapi.BulletedListItemBlock{
+			BasicBlock:       notionapi.BasicBlock{Type: notionapi.BlockTypeBulletedListItem},
+			BulletedListItem: notionapi.ListItem{RichText: []notionapi.RichText{{PlainText: text}}},
+		}
+	}
+	numbered := func(text string) notionapi.Block {
+		return &notionapi.NumberedListItemBlock{
+			BasicBlock:       notionapi.BasicBlock{Type: notionapi.BlockTypeNumberedListItem},
+			NumberedListItem: notionapi.ListItem{RichText: []notionapi.RichText{{PlainText: text}}},
+		}
+	}
+	quote := func(text string) notionapi.Block {
+		return &notionapi.QuoteBlock{
+			BasicBlock: notionapi.BasicBlock{Type: notionapi.BlockTypeQuote},
+			Quote:      notionapi.Quote{RichText: []notionapi.RichText{{PlainText: text}}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		nodes    []BlockNode
+		markdown string
+		text     string
+	}{
+		{
+			name: "nested bullets",
+			nodes: []BlockNode{
+				{Block: bullet("Parent"), Children: []BlockNode{
+					{Block: bullet("Child"), Children: []BlockNode{{Block: bullet("Grandchild")}}},
+				}},
+				{Block: bullet("Sibling")},
+			},
+			markdown: "- Parent\n  - Child\n    - Grandchild\n- Sibling",
+			text:     "- Parent\n  - Child\n    - Grandchild\n- Sibling",
+		},
+		{
+			name: "numbered list keeps counting across children",
+			nodes: []BlockNode{
+				{Block: numbered("One"), Children: []BlockNode{{Block: paragraph("Detail")}}},
+				{Block: numbered("Two")},
+			},
+			markdown: "1. One\n   Detail\n2. Two",
+			text:     "1. One\n  Detail\n2. Two",
+		},
+		{
+			name: "quote children stay quoted",
+			nodes: []BlockNode{
+				{Block: quote("Quoted"), Children: []BlockNode{
+					{Block: paragraph("First")},
+					{Block: paragraph("Second")},
+				}},
+			},
+			markdown: "> Quoted\n> First\n>\n> Second",
+			text:     "Quoted\n  First\n\n  Second",
+		},
+		{
+			name:     "blocks without children",
+			nodes:    []BlockNode{{Block: paragraph("A")}, {Block: paragraph("B")}},
+			markdown: "A\n\nB",
+			text:     "A\n\nB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			md, err := ConvertBlockTreeToMarkdown(tt.nodes)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.markdown, md)
+			assert.Equal(t, tt.text, ConvertBlockTreeToText(tt.nodes))
+		})
+	}
+}
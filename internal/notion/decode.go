@@ -0,0 +1,28 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jomei/notionapi"
+)
+
+// DecodePage decodes a page object from JSON, such as a cached API response.
+// The notionapi property decoder panics on properties without a type, so that
+// case is reported as an error instead.
+func DecodePage(data []byte) (page *notionapi.Page, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			page, err = nil, fmt.Errorf("decode page: malformed properties: %v", r)
+		}
+	}()
+
+	var p notionapi.Page
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("decode page: %w", err)
+	}
+	if p.ID == "" {
+		return nil, fmt.Errorf("decode page: missing id")
+	}
+	return &p, nil
+}
@@ -0,0 +1,55 @@
+package notion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantID  string
+		wantErr bool
+	}{
+		{
+			name:   "valid page",
+			data:   `{"object":"page","id":"page-1","properties":{"Name":{"id":"title","type":"title","title":[{"plain_text":"Hello"}]}}}`,
+			wantID: "page-1",
+		},
+		{
+			name:    "property without type",
+			data:    `{"object":"page","id":"page-1","properties":{"Name":{"title":[]}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing id",
+			data:    `{"object":"page"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			data:    `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page, err := DecodePage([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, page)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, string(page.ID))
+		})
+	}
+}
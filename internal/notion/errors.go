@@ -0,0 +1,65 @@
+package notion
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jomei/notionapi"
+)
+
+// ErrorClass groups Notion API errors by how a caller should react to them.
+type ErrorClass int
+
+const (
+	// ErrorClassOther is any error that does not fall into another class.
+	ErrorClassOther ErrorClass = iota
+	// ErrorClassAuth is an invalid token or a missing permission (401, 403).
+	ErrorClassAuth
+	// ErrorClassNotFound is a missing object, or one not shared with the integration (404).
+	ErrorClassNotFound
+	// ErrorClassRateLimit is a request rejected by the rate limiter (429).
+	ErrorClassRateLimit
+)
+
+// String returns the name of the error class.
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassAuth:
+		return "auth"
+	case ErrorClassNotFound:
+		return "not_found"
+	case ErrorClassRateLimit:
+		return "rate_limit"
+	default:
+		return "other"
+	}
+}
+
+// ClassifyError returns the class of an error returned by the Client.
+// Wrapped errors are unwrapped.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassOther
+	}
+
+	var rateErr *notionapi.RateLimitedError
+	if errors.As(err, &rateErr) {
+		return ErrorClassRateLimit
+	}
+
+	var apiErr *notionapi.Error
+	if !errors.As(err, &apiErr) {
+		return ErrorClassOther
+	}
+
+	switch apiErr.Status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorClassAuth
+	case http.StatusNotFound:
+		return ErrorClassNotFound
+	case http.StatusTooManyRequests:
+		return ErrorClassRateLimit
+	default:
+		return ErrorClassOther
+	}
+}
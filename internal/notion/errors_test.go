@@ -0,0 +1,41 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{name: "nil", err: nil, want: ErrorClassOther},
+		{name: "unauthorized", err: &notionapi.Error{Status: 401}, want: ErrorClassAuth},
+		{name: "forbidden", err: &notionapi.Error{Status: 403}, want: ErrorClassAuth},
+		{name: "not found", err: &notionapi.Error{Status: 404}, want: ErrorClassNotFound},
+		{name: "too many requests", err: &notionapi.Error{Status: 429}, want: ErrorClassRateLimit},
+		{name: "retries exhausted", err: &notionapi.RateLimitedError{Message: "failed"}, want: ErrorClassRateLimit},
+		{name: "server error", err: &notionapi.Error{Status: 502}, want: ErrorClassOther},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("get page abc: %w", &notionapi.Error{Status: 404}),
+			want: ErrorClassNotFound,
+		},
+		{name: "plain error", err: errors.New("boom"), want: ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ClassifyError(tt.err))
+		})
+	}
+}
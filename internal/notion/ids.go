@@ -0,0 +1,129 @@
+package notion
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NormalizeID returns the canonical form of a Notion ID: 32 lowercase hex
+// characters without dashes. Values that are not Notion IDs are returned
+// trimmed and lowercased so they can still be compared consistently.
+func NormalizeID(id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	compact := strings.ReplaceAll(id, "-", "")
+	if !isCompactID(compact) {
+		return id
+	}
+	return compact
+}
+
+// FormatID returns a Notion ID in the dashed form used by the API, such as
+// "12345678-90ab-cdef-1234-567890abcdef". Values that are not Notion IDs are
+// returned unchanged.
+func FormatID(id string) string {
+	compact := NormalizeID(id)
+	if !isCompactID(compact) {
+		return id
+	}
+	return compact[0:8] + "-" + compact[8:12] + "-" + compact[12:16] + "-" + compact[16:20] + "-" + compact[20:]
+}
+
+// ParseID extracts a Notion ID from an ID or a Notion URL, such as one copied
+// with "Copy link". URLs are returned in canonical form; anything else that is
+// not a URL is passed through NormalizeID.
+func ParseID(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("parse id: empty id")
+	}
+	if !strings.Contains(s, "/") {
+		return NormalizeID(s), nil
+	}
+
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("parse id %q: %w", s, err)
+	}
+
+	// A page opened as a peek from a database view is named by the p parameter
+	if id := idSuffix(u.Query().Get("p")); id != "" {
+		return id, nil
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	if id := idSuffix(path[strings.LastIndex(path, "/")+1:]); id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("parse id %q: no Notion ID in URL", s)
+}
+
+// idSuffix returns the Notion ID at the end of a URL path segment such as
+// "Roadmap-0123...cdef", or "" when there is none.
+func idSuffix(segment string) string {
+	segment = strings.ToLower(segment)
+	for _, n := range []int{36, 32} {
+		if len(segment) < n {
+			continue
+		}
+		compact := strings.ReplaceAll(segment[len(segment)-n:], "-", "")
+		if isCompactID(compact) {
+			return compact
+		}
+	}
+	return ""
+}
+
+// isCompactID reports whether s is 32 lowercase hex characters.
+func isCompactID(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Link is what a Notion URL or ID points at.
+type Link struct {
+	ID      string // the page or database, in the form of NormalizeID
+	BlockID string // the block of a "#" anchor, if any
+	ViewID  string // the database view of a "?v=" parameter, if any
+}
+
+// ParseLink parses a Notion ID or a URL of any shape the web and desktop apps
+// produce: with or without a workspace slug and title, notion:// links, "?v="
+// database views, "?p=" peeks at a row and "#" anchors naming a block. Unlike
+// ParseID, a value that holds no Notion ID is an error.
+func ParseLink(s string) (Link, error) {
+	id, err := ParseID(s)
+	if err != nil {
+		return Link{}, err
+	}
+	if !isCompactID(id) {
+		return Link{}, fmt.Errorf("parse link %q: not a Notion ID or URL", strings.TrimSpace(s))
+	}
+
+	link := Link{ID: id}
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "/") {
+		return link, nil
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	// ParseID has parsed the URL already
+	u, _ := url.Parse(s)
+	link.BlockID = idSuffix(u.Fragment)
+	if u.Query().Get("p") == "" {
+		link.ViewID = idSuffix(u.Query().Get("v"))
+	}
+	return link, nil
+}
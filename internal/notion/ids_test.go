@@ -0,0 +1,143 @@
+package notion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{
+			name: "dashed uuid",
+			id:   "12345678-90ab-cdef-1234-567890abcdef",
+			want: "1234567890abcdef1234567890abcdef",
+		},
+		{
+			name: "compact uuid",
+			id:   "1234567890abcdef1234567890abcdef",
+			want: "1234567890abcdef1234567890abcdef",
+		},
+		{
+			name: "uppercase with whitespace",
+			id:   "  1234567890ABCDEF1234567890ABCDEF ",
+			want: "1234567890abcdef1234567890abcdef",
+		},
+		{
+			name: "non-hex id left as is",
+			id:   "page-1",
+			want: "page-1",
+		},
+		{
+			name: "empty",
+			id:   "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, NormalizeID(tt.id))
+		})
+	}
+}
+
+func TestParseID(t *testing.T) {
+	t.Parallel()
+
+	const id = "1234567890abcdef1234567890abcdef"
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "compact id", input: id, want: id},
+		{name: "dashed id", input: "12345678-90ab-cdef-1234-567890abcdef", want: id},
+		{name: "non-hex id passed through", input: "page-1", want: "page-1"},
+		{name: "page url", input: "https://www.notion.so/acme/Roadmap-" + id, want: id},
+		{name: "url with query and fragment", input: "https://www.notion.so/Roadmap-" + id + "?pvs=4#abc", want: id},
+		{name: "url without title", input: "https://notion.so/" + id, want: id},
+		{name: "url with dashed id", input: "https://www.notion.so/12345678-90ab-cdef-1234-567890abcdef", want: id},
+		{name: "url without scheme", input: "www.notion.so/acme/Roadmap-" + id, want: id},
+		{
+			name:  "peek url",
+			input: "https://www.notion.so/acme/ffffffffffffffffffffffffffffffff?v=eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee&p=" + id,
+			want:  id,
+		},
+		{name: "url without id", input: "https://www.notion.so/acme/Roadmap", wantErr: true},
+		{name: "empty", input: "  ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseID(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatID(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "12345678-90ab-cdef-1234-567890abcdef", FormatID("1234567890ABCDEF1234567890abcdef"))
+	assert.Equal(t, "12345678-90ab-cdef-1234-567890abcdef", FormatID("12345678-90ab-cdef-1234-567890abcdef"))
+	assert.Equal(t, "page-1", FormatID("page-1"))
+}
+
+func TestParseLink(t *testing.T) {
+	t.Parallel()
+
+	const (
+		id    = "1234567890abcdef1234567890abcdef"
+		db    = "ffffffffffffffffffffffffffffffff"
+		view  = "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+		block = "dddddddddddddddddddddddddddddddd"
+	)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Link
+		wantErr bool
+	}{
+		{name: "id", input: "12345678-90ab-cdef-1234-567890abcdef", want: Link{ID: id}},
+		{name: "workspace slug and title", input: "https://www.notion.so/acme/Roadmap-" + id, want: Link{ID: id}},
+		{name: "desktop link", input: "notion://www.notion.so/Roadmap-" + id, want: Link{ID: id}},
+		{name: "database view", input: "https://www.notion.so/acme/" + db + "?v=" + view, want: Link{ID: db, ViewID: view}},
+		{name: "peek", input: "https://www.notion.so/acme/" + db + "?v=" + view + "&p=" + id + "&pm=s", want: Link{ID: id}},
+		{name: "block anchor", input: "https://www.notion.so/Roadmap-" + id + "?pvs=4#" + block, want: Link{ID: id, BlockID: block}},
+		{name: "other anchor", input: "https://www.notion.so/Roadmap-" + id + "#heading", want: Link{ID: id}},
+		{name: "not an id", input: "page-1", wantErr: true},
+		{name: "url without id", input: "https://www.notion.so/acme/Roadmap", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseLink(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package notion
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jomei/notionapi"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// maxRichTextLength is the longest text the API accepts in one rich text object.
+const maxRichTextLength = 2000
+
+// codeLanguages are the code block languages the API accepts, keyed by the
+// names and aliases used in fenced code blocks.
+var codeLanguages = map[string]string{
+	"bash": "bash", "sh": "shell", "shell": "shell", "zsh": "shell", "console": "shell",
+	"c": "c", "cpp": "c++", "c++": "c++", "cs": "c#", "csharp": "c#", "c#": "c#",
+	"clojure": "clojure", "css": "css", "dart": "dart", "diff": "diff",
+	"docker": "docker", "dockerfile": "docker", "elixir": "elixir", "elm": "elm",
+	"erlang": "erlang", "go": "go", "golang": "go", "graphql": "graphql",
+	"groovy": "groovy", "haskell": "haskell", "html": "html", "java": "java",
+	"javascript": "javascript", "js": "javascript", "json": "json", "julia": "julia",
+	"kotlin": "kotlin", "latex": "latex", "tex": "latex", "less": "less", "lisp": "lisp",
+	"lua": "lua", "makefile": "makefile", "make": "makefile", "markdown": "markdown",
+	"md": "markdown", "matlab": "matlab", "mermaid": "mermaid", "nix": "nix",
+	"objective-c": "objective-c", "objc": "objective-c", "ocaml": "ocaml",
+	"pascal": "pascal", "perl": "perl", "php": "php", "powershell": "powershell",
+	"ps1": "powershell", "protobuf": "protobuf", "proto": "protobuf",
+	"python": "python", "py": "python", "r": "r", "ruby": "ruby", "rb": "ruby",
+	"rust": "rust", "rs": "rust", "sass": "sass", "scala": "scala", "scheme": "scheme",
+	"scss": "scss", "sql": "sql", "swift": "swift", "typescript": "typescript",
+	"ts": "typescript", "toml": "plain text", "vhdl": "vhdl", "verilog": "verilog",
+	"xml": "xml", "yaml": "yaml", "yml": "yaml",
+}
+
+// ParseMarkdown converts markdown into blocks that can be appended to a page.
+// It understands headings, paragraphs, nested bulleted, numbered and task
+// lists, fenced code, quotes, dividers, tables and images on a line of their
+// own, with bold, italic, strikethrough, code and link formatting. Other
+// constructs are kept as plain text.
+func ParseMarkdown(source string) []BlockNode {
+	src := []byte(source)
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	p := &markdownParser{src: src}
+	return p.blocks(doc)
+}
+
+// markdownParser converts a goldmark syntax tree into blocks.
+type markdownParser struct {
+	src []byte
+}
+
+// blocks converts the block-level children of n.
+func (p *markdownParser) blocks(n ast.Node) []BlockNode {
+	var nodes []BlockNode
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		nodes = append(nodes, p.block(c)...)
+	}
+	return nodes
+}
+
+// block converts a single block-level node.
+func (p *markdownParser) block(n ast.Node) []BlockNode {
+	switch n := n.(type) {
+	case *ast.Heading:
+		heading := notionapi.Heading{RichText: p.richText(n)}
+		switch n.Level {
+		case 1:
+			return leaf(&notionapi.Heading1Block{BasicBlock: basicBlock(notionapi.BlockTypeHeading1), Heading1: heading})
+		case 2:
+			return leaf(&notionapi.Heading2Block{BasicBlock: basicBlock(notionapi.BlockTypeHeading2), Heading2: heading})
+		default:
+			// The API has no headings below level 3
+			return leaf(&notionapi.Heading3Block{BasicBlock: basicBlock(notionapi.BlockTypeHeading3), Heading3: heading})
+		}
+
+	case *ast.Paragraph, *ast.TextBlock:
+		if img, ok := n.FirstChild().(*ast.Image); ok && n.ChildCount() == 1 && isAbsoluteURL(string(img.Destination)) {
+			return leaf(&notionapi.ImageBlock{
+				BasicBlock: basicBlock(notionapi.BlockTypeImage),
+				Image: notionapi.Image{
+					Type:     notionapi.FileTypeExternal,
+					External: &notionapi.FileObject{URL: string(img.Destination)},
+					Caption:  p.richText(img),
+				},
+			})
+		}
+		return leaf(paragraphBlock(p.richText(n)))
+
+	case *ast.List:
+		var nodes []BlockNode
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			nodes = append(nodes, p.listItem(item, n.IsOrdered()))
+		}
+		return nodes
+
+	case *ast.FencedCodeBlock:
+		language := codeLanguages[strings.ToLower(string(n.Language(p.src)))]
+		return leaf(codeBlock(p.lines(n), language))
+
+	case *ast.CodeBlock:
+		return leaf(codeBlock(p.lines(n), ""))
+
+	case *ast.Blockquote:
+		quote := &notionapi.QuoteBlock{BasicBlock: basicBlock(notionapi.BlockTypeQuote)}
+		rest := n.FirstChild()
+		if first, ok := rest.(*ast.Paragraph); ok {
+			quote.Quote.RichText = p.richText(first)
+			rest = first.NextSibling()
+		}
+		var children []BlockNode
+		for ; rest != nil; rest = rest.NextSibling() {
+			children = append(children, p.block(rest)...)
+		}
+		return []BlockNode{{Block: quote, Children: children}}
+
+	case *ast.ThematicBreak:
+		return leaf(&notionapi.DividerBlock{BasicBlock: basicBlock(notionapi.BlockTypeDivider)})
+
+	case *east.Table:
+		return leaf(p.table(n))
+
+	case *ast.HTMLBlock:
+		return leaf(paragraphBlock(plainRichText(strings.TrimRight(p.lines(n), "\n"))))
+
+	default:
+		if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			return leaf(paragraphBlock(plainRichText(strings.TrimRight(p.lines(n), "\n"))))
+		}
+		return p.blocks(n)
+	}
+}
+
+// listItem converts a list item. Its first paragraph becomes the item text and
+// anything after it becomes nested children.
+func (p *markdownParser) listItem(item ast.Node, ordered bool) BlockNode {
+	var richText []notionapi.RichText
+	var checkbox *east.TaskCheckBox
+
+	rest := item.FirstChild()
+	switch first := rest.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		checkbox, _ = first.FirstChild().(*east.TaskCheckBox)
+		richText = p.richText(first)
+		rest = first.NextSibling()
+	}
+
+	var children []BlockNode
+	for ; rest != nil; rest = rest.NextSibling() {
+		children = append(children, p.block(rest)...)
+	}
+
+	var block notionapi.Block
+	switch {
+	case checkbox != nil:
+		block = &notionapi.ToDoBlock{
+			BasicBlock: basicBlock(notionapi.BlockTypeToDo),
+			ToDo:       notionapi.ToDo{RichText: trimLeadingSpace(richText), Checked: checkbox.IsChecked},
+		}
+	case ordered:
+		block = &notionapi.NumberedListItemBlock{
+			BasicBlock:       basicBlock(notionapi.BlockTypeNumberedListItem),
+			NumberedListItem: notionapi.ListItem{RichText: richText},
+		}
+	default:
+		block = &notionapi.BulletedListItemBlock{
+			BasicBlock:       basicBlock(notionapi.BlockTypeBulletedListItem),
+			BulletedListItem: notionapi.ListItem{RichText: richText},
+		}
+	}
+	return BlockNode{Block: block, Children: children}
+}
+
+// table converts a table. The rows are part of the table block because the
+// API only creates tables together with their rows.
+func (p *markdownParser) table(n *east.Table) notionapi.Block {
+	table := &notionapi.TableBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeTableBlock),
+		Table:      notionapi.Table{HasColumnHeader: true},
+	}
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells [][]notionapi.RichText
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, p.richText(cell))
+		}
+		if len(cells) > table.Table.TableWidth {
+			table.Table.TableWidth = len(cells)
+		}
+		table.Table.Children = append(table.Table.Children, &notionapi.TableRowBlock{
+			BasicBlock: basicBlock(notionapi.BlockTypeTableRowBlock),
+			TableRow:   notionapi.TableRow{Cells: cells},
+		})
+	}
+	return table
+}
+
+// lines returns the raw source lines of a block.
+func (p *markdownParser) lines(n ast.Node) string {
+	var sb strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		sb.Write(segment.Value(p.src))
+	}
+	return sb.String()
+}
+
+// inlineStyle is the formatting applied to a run of inline text.
+type inlineStyle struct {
+	bold, italic, strikethrough, code bool
+	link                              string
+}
+
+// richText converts the inline children of n.
+func (p *markdownParser) richText(n ast.Node) []notionapi.RichText {
+	var runs richTextRuns
+	p.inlines(n, inlineStyle{}, &runs)
+	return runs.build()
+}
+
+func (p *markdownParser) inlines(n ast.Node, style inlineStyle, runs *richTextRuns) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			runs.add(string(c.Segment.Value(p.src)), style)
+			if c.HardLineBreak() || c.SoftLineBreak() {
+				runs.add("\n", style)
+			}
+		case *ast.String:
+			runs.add(string(c.Value), style)
+		case *ast.CodeSpan:
+			s := style
+			s.code = true
+			p.inlines(c, s, runs)
+		case *ast.Emphasis:
+			s := style
+			if c.Level >= 2 {
+				s.bold = true
+			} else {
+				s.italic = true
+			}
+			p.inlines(c, s, runs)
+		case *east.Strikethrough:
+			s := style
+			s.strikethrough = true
+			p.inlines(c, s, runs)
+		case *ast.Link:
+			s := style
+			if isAbsoluteURL(string(c.Destination)) {
+				s.link = string(c.Destination)
+			}
+			p.inlines(c, s, runs)
+		case *ast.AutoLink:
+			s := style
+			s.link = string(c.URL(p.src))
+			if c.AutoLinkType == ast.AutoLinkEmail {
+				s.link = "mailto:" + s.link
+			}
+			runs.add(string(c.Label(p.src)), s)
+		case *ast.Image:
+			s := style
+			if isAbsoluteURL(string(c.Destination)) {
+				s.link = string(c.Destination)
+			}
+			p.inlines(c, s, runs)
+		case *ast.RawHTML:
+			for i := 0; i < c.Segments.Len(); i++ {
+				segment := c.Segments.At(i)
+				runs.add(string(segment.Value(p.src)), style)
+			}
+		case *east.TaskCheckBox:
+			// Handled by listItem
+		default:
+			p.inlines(c, style, runs)
+		}
+	}
+}
+
+// richTextRun is a run of text with one style.
+type richTextRun struct {
+	text  string
+	style inlineStyle
+}
+
+// richTextRuns collects runs of text, merging neighbours with the same style.
+type richTextRuns []richTextRun
+
+func (r *richTextRuns) add(s string, style inlineStyle) {
+	if s == "" {
+		return
+	}
+	if n := len(*r); n > 0 && (*r)[n-1].style == style {
+		(*r)[n-1].text += s
+		return
+	}
+	*r = append(*r, richTextRun{text: s, style: style})
+}
+
+// build converts the runs to rich text, trimming a trailing line break and
+// splitting runs longer than the API allows.
+func (r richTextRuns) build() []notionapi.RichText {
+	if n := len(r); n > 0 {
+		r[n-1].text = strings.TrimRight(r[n-1].text, "\n")
+	}
+
+	var out []notionapi.RichText
+	for _, run := range r {
+		for _, chunk := range splitText(run.text, maxRichTextLength) {
+			rt := notionapi.RichText{
+				Type:      notionapi.ObjectTypeText,
+				Text:      &notionapi.Text{Content: chunk},
+				PlainText: chunk,
+				Annotations: &notionapi.Annotations{
+					Bold:          run.style.bold,
+					Italic:        run.style.italic,
+					Strikethrough: run.style.strikethrough,
+					Code:          run.style.code,
+					Color:         notionapi.ColorDefault,
+				},
+			}
+			if run.style.link != "" {
+				rt.Text.Link = &notionapi.Link{Url: run.style.link}
+				rt.Href = run.style.link
+			}
+			out = append(out, rt)
+		}
+	}
+	return out
+}
+
+// splitText splits s into chunks of at most n runes.
+func splitText(s string, n int) []string {
+	if s == "" {
+		return nil
+	}
+	runes := []rune(s)
+	var chunks []string
+	for len(runes) > n {
+		chunks = append(chunks, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return append(chunks, string(runes))
+}
+
+// plainRichText returns unformatted rich text.
+func plainRichText(s string) []notionapi.RichText {
+	return richTextRuns{{text: s}}.build()
+}
+
+// trimLeadingSpace removes the space left between a task checkbox and its text.
+func trimLeadingSpace(rt []notionapi.RichText) []notionapi.RichText {
+	if len(rt) > 0 && rt[0].Text != nil {
+		rt[0].Text.Content = strings.TrimLeft(rt[0].Text.Content, " ")
+		rt[0].PlainText = rt[0].Text.Content
+	}
+	return rt
+}
+
+// isAbsoluteURL reports whether s is a URL the API accepts as a link.
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "mailto")
+}
+
+func basicBlock(blockType notionapi.BlockType) notionapi.BasicBlock {
+	return notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, Type: blockType}
+}
+
+func leaf(block notionapi.Block) []BlockNode {
+	return []BlockNode{{Block: block}}
+}
+
+func paragraphBlock(rt []notionapi.RichText) *notionapi.ParagraphBlock {
+	return &notionapi.ParagraphBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeParagraph),
+		Paragraph:  notionapi.Paragraph{RichText: rt},
+	}
+}
+
+func codeBlock(code, language string) *notionapi.CodeBlock {
+	if language == "" {
+		language = "plain text"
+	}
+	return &notionapi.CodeBlock{
+		BasicBlock: basicBlock(notionapi.BlockTypeCode),
+		Code: notionapi.Code{
+			RichText: plainRichText(strings.TrimRight(code, "\n")),
+			Language: language,
+		},
+	}
+}
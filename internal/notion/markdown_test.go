@@ -0,0 +1,156 @@
+package notion
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outline describes nodes as indented "type: text" lines.
+func outline(nodes []BlockNode, depth int) []string {
+	var lines []string
+	for _, node := range nodes {
+		text := blockPlainText(node.Block, 0)
+		switch b := node.Block.(type) {
+		case *notionapi.CodeBlock:
+			text = b.Code.Language + ": " + GetRichTextString(b.Code.RichText)
+		case *notionapi.BulletedListItemBlock:
+			text = GetRichTextString(b.BulletedListItem.RichText)
+		case *notionapi.NumberedListItemBlock:
+			text = GetRichTextString(b.NumberedListItem.RichText)
+		case *notionapi.ToDoBlock:
+			text = fmt.Sprintf("%v %s", b.ToDo.Checked, GetRichTextString(b.ToDo.RichText))
+		case *notionapi.ImageBlock:
+			text = b.Image.External.URL
+		case *notionapi.TableBlock:
+			text = fmt.Sprintf("%d columns, %d rows", b.Table.TableWidth, len(b.Table.Children))
+		}
+		line := strings.Repeat("  ", depth) + string(node.Block.GetType())
+		if text != "" {
+			line += ": " + text
+		}
+		lines = append(lines, line)
+		lines = append(lines, outline(node.Children, depth+1)...)
+	}
+	return lines
+}
+
+func TestParseMarkdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "empty",
+			input: "  \n\n",
+			want:  nil,
+		},
+		{
+			name:  "headings and paragraphs",
+			input: "# Plan\n\nFirst line\nsecond line\n\n#### Deep",
+			want: []string{
+				"heading_1: Plan",
+				"paragraph: First line\nsecond line",
+				"heading_3: Deep",
+			},
+		},
+		{
+			name:  "nested lists and to-dos",
+			input: "- One\n  1. Step\n  2. Step two\n- [x] Done\n- [ ] Open\n",
+			want: []string{
+				"bulleted_list_item: One",
+				"  numbered_list_item: Step",
+				"  numbered_list_item: Step two",
+				"to_do: true Done",
+				"to_do: false Open",
+			},
+		},
+		{
+			name:  "code quote and divider",
+			input: "```golang\nfmt.Println()\n```\n\n> Quoted\n\n---\n\n```\nplain\n```",
+			want: []string{
+				"code: go: fmt.Println()",
+				"quote: Quoted",
+				"divider",
+				"code: plain text: plain",
+			},
+		},
+		{
+			name:  "image and table",
+			input: "![Chart](https://example.com/chart.png)\n\n| a | b |\n|---|---|\n| 1 | 2 |\n",
+			want: []string{
+				"image: https://example.com/chart.png",
+				"table: 2 columns, 2 rows",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, outline(ParseMarkdown(tt.input), 0))
+		})
+	}
+}
+
+func TestParseMarkdownRichText(t *testing.T) {
+	t.Parallel()
+
+	nodes := ParseMarkdown("Plain **bold** *it* ~~gone~~ `code` [link](https://example.com) [local](./x.md)")
+	require.Len(t, nodes, 1)
+	paragraph, ok := nodes[0].Block.(*notionapi.ParagraphBlock)
+	require.True(t, ok)
+
+	type run struct {
+		text                              string
+		bold, italic, strikethrough, code bool
+		link                              string
+	}
+	var got []run
+	for _, rt := range paragraph.Paragraph.RichText {
+		r := run{
+			text:          rt.Text.Content,
+			bold:          rt.Annotations.Bold,
+			italic:        rt.Annotations.Italic,
+			strikethrough: rt.Annotations.Strikethrough,
+			code:          rt.Annotations.Code,
+		}
+		if rt.Text.Link != nil {
+			r.link = rt.Text.Link.Url
+		}
+		got = append(got, r)
+	}
+
+	assert.Equal(t, []run{
+		{text: "Plain "},
+		{text: "bold", bold: true},
+		{text: " "},
+		{text: "it", italic: true},
+		{text: " "},
+		{text: "gone", strikethrough: true},
+		{text: " "},
+		{text: "code", code: true},
+		{text: " "},
+		{text: "link", link: "https://example.com"},
+		{text: " local"},
+	}, got, "relative links are kept as text")
+}
+
+func TestParseMarkdownLongText(t *testing.T) {
+	t.Parallel()
+
+	nodes := ParseMarkdown(strings.Repeat("a", maxRichTextLength+10))
+	require.Len(t, nodes, 1)
+	rt := nodes[0].Block.(*notionapi.ParagraphBlock).Paragraph.RichText
+	require.Len(t, rt, 2)
+	assert.Len(t, rt[0].Text.Content, maxRichTextLength)
+	assert.Len(t, rt[1].Text.Content, 10)
+}
@@ -0,0 +1,199 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Panandika/notion-tui/internal/logging"
+)
+
+// Priority orders requests competing for the client's rate limit.
+type Priority int
+
+const (
+	// PriorityForeground is used for user-initiated requests. It is the default.
+	PriorityForeground Priority = iota
+	// PriorityIdle is used for background work such as cache warming. Idle requests
+	// only run when no foreground request is pending, never consume the last
+	// limiter token and are held back while the API is rate limiting us.
+	PriorityIdle
+)
+
+const (
+	// idleReserveTokens is the number of limiter tokens idle requests leave for
+	// foreground requests.
+	idleReserveTokens = 1
+	// idlePollInterval is how often a waiting idle request re-checks the client state.
+	idlePollInterval = 100 * time.Millisecond
+	// minRateLimitPause is the shortest pause applied after a 429 response.
+	minRateLimitPause = 5 * time.Second
+	// minLoggedWait is the shortest limiter wait worth logging.
+	minLoggedWait = time.Millisecond
+)
+
+// priorityKey is the context key for request priority.
+type priorityKey struct{}
+
+// WithPriority returns a context whose requests run at the given priority.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the request priority stored in ctx, or PriorityForeground.
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityForeground
+}
+
+// String returns the name of the priority as it appears in the debug log.
+func (p Priority) String() string {
+	if p == PriorityIdle {
+		return "idle"
+	}
+	return "foreground"
+}
+
+// wait blocks until a request with the context's priority may be sent.
+// The returned release function must be called once the request has finished.
+func (c *Client) wait(ctx context.Context) (func(), error) {
+	priority := PriorityFrom(ctx)
+	start := time.Now()
+	defer func() {
+		if waited := time.Since(start); waited >= minLoggedWait {
+			c.requests.add(RequestRecord{Time: start, Kind: RecordWait, Priority: priority, Duration: waited})
+			c.stats.addWait(waited, ViewFrom(ctx))
+			c.logger.DebugContext(ctx, "rate limiter wait", "priority", priority.String(), "waited", waited)
+		}
+	}()
+
+	if priority == PriorityIdle {
+		return c.waitIdle(ctx)
+	}
+
+	c.foreground.Add(1)
+	if err := c.limiter.Wait(ctx); err != nil {
+		c.foreground.Add(-1)
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+	return func() { c.foreground.Add(-1) }, nil
+}
+
+// waitIdle waits until no foreground request is pending, the client is not paused
+// and the limiter has spare capacity, then takes a token.
+func (c *Client) waitIdle(ctx context.Context) (func(), error) {
+	for {
+		if time.Now().After(c.RateLimitedUntil()) && c.foreground.Load() == 0 &&
+			c.limiter.Tokens() >= 1+idleReserveTokens && c.limiter.Allow() {
+			return func() {}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rate limiter wait: %w", ctx.Err())
+		case <-time.After(idlePollInterval):
+		}
+	}
+}
+
+// Pause holds back idle requests for at least d. Foreground requests are not affected.
+func (c *Client) Pause(d time.Duration) {
+	until := time.Now().Add(d).UnixNano()
+	for {
+		current := c.pausedUntil.Load()
+		if current >= until || c.pausedUntil.CompareAndSwap(current, until) {
+			return
+		}
+	}
+}
+
+// RateLimitedUntil returns the time until which idle requests are paused.
+// It is in the past when the client is not paused.
+func (c *Client) RateLimitedUntil() time.Time {
+	return time.Unix(0, c.pausedUntil.Load())
+}
+
+// rateLimitTransport observes 429 responses and pauses idle requests on the
+// client. It also traces every request, including the retries notionapi
+// makes, to the client's logger and counts it in the client's Stats.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	object, id := requestObject(req.URL.Path)
+	record := RequestRecord{
+		Time:     start,
+		Kind:     RecordRequest,
+		Method:   req.Method,
+		Object:   object,
+		ObjectID: id,
+		Priority: PriorityFrom(req.Context()),
+		Duration: latency,
+	}
+	attrs := []any{
+		"method", req.Method,
+		"object", object,
+		logging.KeyObjectID, id,
+		"priority", record.Priority.String(),
+		"latency", latency,
+	}
+	endpoint, view := endpoint(req.Method, req.URL.Path), ViewFrom(req.Context())
+	if err != nil {
+		record.Err = err.Error()
+		t.client.requests.add(record)
+		t.client.stats.addRequest(record, endpoint, view, false)
+		t.client.logger.WarnContext(req.Context(), "notion request failed", append(attrs, "error", err)...)
+		return resp, err
+	}
+	record.Status = resp.StatusCode
+	t.client.requests.add(record)
+	attrs = append(attrs, "status", resp.StatusCode)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		pause := minRateLimitPause
+		// notionapi sends the request again after Retry-After when it parses
+		seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err == nil {
+			if d := time.Duration(seconds) * time.Second; d > pause {
+				pause = d
+			}
+		}
+		t.client.stats.addRequest(record, endpoint, view, err == nil)
+		t.client.Pause(pause)
+		t.client.logger.WarnContext(req.Context(), "notion rate limited", append(attrs, "pause", pause)...)
+		return resp, nil
+	}
+
+	level := slog.LevelDebug
+	if resp.StatusCode >= http.StatusInternalServerError {
+		level = slog.LevelWarn
+	}
+	t.client.stats.addRequest(record, endpoint, view, false)
+	t.client.logger.Log(req.Context(), level, "notion request", attrs...)
+	return resp, nil
+}
+
+// requestObject returns the kind of object an API path addresses and its ID,
+// such as "pages" and the page ID for /v1/pages/<id>. The ID is empty for
+// paths such as /v1/search.
+func requestObject(path string) (object, id string) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/v1"), "/"), "/")
+	object = parts[0]
+	if len(parts) > 1 {
+		id = parts[1]
+	}
+	return object, id
+}
@@ -0,0 +1,240 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityFrom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Equal(t, PriorityForeground, PriorityFrom(ctx))
+	assert.Equal(t, PriorityIdle, PriorityFrom(WithPriority(ctx, PriorityIdle)))
+	assert.Equal(t, PriorityForeground, PriorityFrom(WithPriority(ctx, PriorityForeground)))
+}
+
+func TestWaitIdle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		setup   func(c *Client)
+		wantErr bool
+	}{
+		{
+			name:    "runs when client is idle",
+			setup:   func(c *Client) {},
+			wantErr: false,
+		},
+		{
+			name: "yields to pending foreground request",
+			setup: func(c *Client) {
+				c.foreground.Add(1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "held back while paused",
+			setup: func(c *Client) {
+				c.Pause(time.Minute)
+			},
+			wantErr: true,
+		},
+		{
+			name: "leaves reserve token for foreground",
+			setup: func(c *Client) {
+				require.True(t, c.limiter.AllowN(time.Now(), c.limiter.Burst()-idleReserveTokens))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := NewClient("secret_test_token")
+			tt.setup(client)
+
+			ctx, cancel := context.WithTimeout(WithPriority(context.Background(), PriorityIdle), 3*idlePollInterval)
+			defer cancel()
+
+			release, err := client.wait(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			release()
+		})
+	}
+}
+
+func TestWaitForegroundNotBlockedByIdleState(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("secret_test_token")
+	client.Pause(time.Minute)
+	require.True(t, client.limiter.AllowN(time.Now(), client.limiter.Burst()-idleReserveTokens))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	release, err := client.wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), client.foreground.Load())
+
+	release()
+	assert.Equal(t, int32(0), client.foreground.Load())
+}
+
+func TestIdleResumesAfterForegroundFinishes(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("secret_test_token")
+	client.foreground.Add(1)
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(WithPriority(context.Background(), PriorityIdle), 5*time.Second)
+		defer cancel()
+		_, err := client.wait(ctx)
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("idle request ran while foreground request was pending")
+	case <-time.After(2 * idlePollInterval):
+	}
+
+	client.foreground.Add(-1)
+	assert.NoError(t, <-done)
+}
+
+func TestPauseKeepsLatestDeadline(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("secret_test_token")
+	assert.False(t, client.RateLimitedUntil().After(time.Now()))
+
+	client.Pause(time.Minute)
+	later := client.RateLimitedUntil()
+	client.Pause(time.Second)
+
+	assert.Equal(t, later, client.RateLimitedUntil())
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		wantPause  time.Duration
+	}{
+		{name: "success does not pause", status: http.StatusOK, wantPause: 0},
+		{name: "429 honors Retry-After", status: http.StatusTooManyRequests, retryAfter: "30", wantPause: 30 * time.Second},
+		{name: "429 uses minimum pause", status: http.StatusTooManyRequests, retryAfter: "1", wantPause: minRateLimitPause},
+		{name: "429 without header", status: http.StatusTooManyRequests, wantPause: minRateLimitPause},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClient("secret_test_token")
+			transport := &rateLimitTransport{base: http.DefaultTransport, client: client}
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+
+			start := time.Now()
+			resp, err := transport.RoundTrip(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			if tt.wantPause == 0 {
+				assert.False(t, client.RateLimitedUntil().After(time.Now()))
+				return
+			}
+			assert.WithinDuration(t, start.Add(tt.wantPause), client.RateLimitedUntil(), 2*time.Second)
+		})
+	}
+}
+
+func TestRateLimitTransportTracesRequests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("secret_test_token")
+	client.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	transport := &rateLimitTransport{base: http.DefaultTransport, client: client}
+
+	ctx := WithPriority(context.Background(), PriorityIdle)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/pages/page-1", nil)
+	require.NoError(t, err)
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "notion request", record["msg"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, "pages", record["object"])
+	assert.Equal(t, "page-1", record["object_id"])
+	assert.Equal(t, "idle", record["priority"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Contains(t, record, "latency")
+}
+
+func TestRequestObject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path       string
+		wantObject string
+		wantID     string
+	}{
+		{path: "/v1/pages/page-1", wantObject: "pages", wantID: "page-1"},
+		{path: "/v1/databases/db-1/query", wantObject: "databases", wantID: "db-1"},
+		{path: "/v1/blocks/block-1/children", wantObject: "blocks", wantID: "block-1"},
+		{path: "/v1/search", wantObject: "search", wantID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			object, id := requestObject(tt.path)
+			assert.Equal(t, tt.wantObject, object)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package notion
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// PropertyString renders a page property value as plain text, for example
+// "Done" for a status or "urgent, backend" for a multi-select. Empty values
+// and unsupported property types render as "".
+func PropertyString(prop notionapi.Property) string {
+	switch p := prop.(type) {
+	case *notionapi.TitleProperty:
+		return GetRichTextString(p.Title)
+	case *notionapi.RichTextProperty:
+		return GetRichTextString(p.RichText)
+	case *notionapi.TextProperty:
+		return GetRichTextString(p.Text)
+	case *notionapi.NumberProperty:
+		return formatNumber(p.Number)
+	case *notionapi.SelectProperty:
+		return p.Select.Name
+	case *notionapi.StatusProperty:
+		return p.Status.Name
+	case *notionapi.MultiSelectProperty:
+		names := make([]string, 0, len(p.MultiSelect))
+		for _, option := range p.MultiSelect {
+			names = append(names, option.Name)
+		}
+		return strings.Join(names, ", ")
+	case *notionapi.DateProperty:
+		return formatDateObject(p.Date)
+	case *notionapi.CheckboxProperty:
+		return strconv.FormatBool(p.Checkbox)
+	case *notionapi.URLProperty:
+		return p.URL
+	case *notionapi.EmailProperty:
+		return p.Email
+	case *notionapi.PhoneNumberProperty:
+		return p.PhoneNumber
+	case *notionapi.PeopleProperty:
+		names := make([]string, 0, len(p.People))
+		for _, user := range p.People {
+			names = append(names, userName(user))
+		}
+		return strings.Join(names, ", ")
+	case *notionapi.FilesProperty:
+		names := make([]string, 0, len(p.Files))
+		for _, file := range p.Files {
+			names = append(names, file.Name)
+		}
+		return strings.Join(names, ", ")
+	case *notionapi.RelationProperty:
+		ids := make([]string, 0, len(p.Relation))
+		for _, rel := range p.Relation {
+			ids = append(ids, string(rel.ID))
+		}
+		return strings.Join(ids, ", ")
+	case *notionapi.FormulaProperty:
+		switch p.Formula.Type {
+		case notionapi.FormulaTypeString:
+			return p.Formula.String
+		case notionapi.FormulaTypeNumber:
+			return formatNumber(p.Formula.Number)
+		case notionapi.FormulaTypeBoolean:
+			return strconv.FormatBool(p.Formula.Boolean)
+		case notionapi.FormulaTypeDate:
+			return formatDateObject(p.Formula.Date)
+		}
+		return ""
+	case *notionapi.RollupProperty:
+		switch {
+		case p.Rollup.Date != nil:
+			return formatDateObject(p.Rollup.Date)
+		case len(p.Rollup.Array) > 0:
+			values := make([]string, 0, len(p.Rollup.Array))
+			for _, item := range p.Rollup.Array {
+				if v := PropertyString(item); v != "" {
+					values = append(values, v)
+				}
+			}
+			return strings.Join(values, ", ")
+		case p.Rollup.Type == notionapi.RollupTypeNumber:
+			return formatNumber(p.Rollup.Number)
+		}
+		return ""
+	case *notionapi.CreatedTimeProperty:
+		return p.CreatedTime.Format(time.RFC3339)
+	case *notionapi.LastEditedTimeProperty:
+		return p.LastEditedTime.Format(time.RFC3339)
+	case *notionapi.CreatedByProperty:
+		return userName(p.CreatedBy)
+	case *notionapi.LastEditedByProperty:
+		return userName(p.LastEditedBy)
+	case *notionapi.UniqueIDProperty:
+		return p.UniqueID.String()
+	default:
+		return ""
+	}
+}
+
+// PropertyNames returns the property names of a page sorted alphabetically,
+// with the title property first.
+func PropertyNames(props notionapi.Properties) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		iTitle := isTitleProperty(props[names[i]])
+		jTitle := isTitleProperty(props[names[j]])
+		if iTitle != jTitle {
+			return iTitle
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// isTitleProperty reports whether prop is a page's title.
+func isTitleProperty(prop notionapi.Property) bool {
+	_, ok := prop.(*notionapi.TitleProperty)
+	return ok
+}
+
+// formatNumber renders a number without trailing zeros.
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// formatDateObject renders a date, or a range as start/end. Dates without a time of day
+// are rendered as YYYY-MM-DD.
+func formatDateObject(d *notionapi.DateObject) string {
+	if d == nil || d.Start == nil {
+		return ""
+	}
+	s := formatDate(*d.Start)
+	if d.End != nil {
+		s += "/" + formatDate(*d.End)
+	}
+	return s
+}
+
+// formatDate renders a single Notion date.
+func formatDate(d notionapi.Date) string {
+	t := time.Time(d)
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// userName returns a user's name, falling back to their ID.
+func userName(user notionapi.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return string(user.ID)
+}
@@ -0,0 +1,79 @@
+package notion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyString(t *testing.T) {
+	t.Parallel()
+
+	day := notionapi.Date(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	moment := notionapi.Date(time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC))
+	prefix := "TASK"
+
+	tests := []struct {
+		name string
+		prop notionapi.Property
+		want string
+	}{
+		{
+			name: "title",
+			prop: &notionapi.TitleProperty{Title: []notionapi.RichText{{PlainText: "Road"}, {PlainText: "map"}}},
+			want: "Roadmap",
+		},
+		{name: "number", prop: &notionapi.NumberProperty{Number: 2.50}, want: "2.5"},
+		{name: "select", prop: &notionapi.SelectProperty{Select: notionapi.Option{Name: "High"}}, want: "High"},
+		{name: "status", prop: &notionapi.StatusProperty{Status: notionapi.Option{Name: "Done"}}, want: "Done"},
+		{
+			name: "multi select",
+			prop: &notionapi.MultiSelectProperty{MultiSelect: []notionapi.Option{{Name: "a"}, {Name: "b"}}},
+			want: "a, b",
+		},
+		{name: "date", prop: &notionapi.DateProperty{Date: &notionapi.DateObject{Start: &day}}, want: "2024-03-01"},
+		{
+			name: "date range with time",
+			prop: &notionapi.DateProperty{Date: &notionapi.DateObject{Start: &day, End: &moment}},
+			want: "2024-03-01/2024-03-01T09:30:00Z",
+		},
+		{name: "empty date", prop: &notionapi.DateProperty{}, want: ""},
+		{name: "checkbox", prop: &notionapi.CheckboxProperty{Checkbox: true}, want: "true"},
+		{
+			name: "people",
+			prop: &notionapi.PeopleProperty{People: []notionapi.User{{Name: "Ada"}, {ID: "user-2"}}},
+			want: "Ada, user-2",
+		},
+		{
+			name: "formula",
+			prop: &notionapi.FormulaProperty{Formula: notionapi.Formula{Type: notionapi.FormulaTypeNumber, Number: 3}},
+			want: "3",
+		},
+		{
+			name: "unique id",
+			prop: &notionapi.UniqueIDProperty{UniqueID: notionapi.UniqueID{Prefix: &prefix, Number: 7}},
+			want: "TASK-7",
+		},
+		{name: "nil", prop: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, PropertyString(tt.prop))
+		})
+	}
+}
+
+func TestPropertyNames(t *testing.T) {
+	t.Parallel()
+
+	props := notionapi.Properties{
+		"Status": &notionapi.StatusProperty{},
+		"Name":   &notionapi.TitleProperty{},
+		"Due":    &notionapi.DateProperty{},
+	}
+	assert.Equal(t, []string{"Name", "Due", "Status"}, PropertyNames(props))
+}
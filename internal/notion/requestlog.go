@@ -0,0 +1,69 @@
+package notion
+
+import (
+	"sync"
+	"time"
+)
+
+// requestLogSize is how many records the client keeps for RecentRequests.
+const requestLogSize = 200
+
+// RecordKind tells requests and rate limiter waits apart in the request log.
+type RecordKind int
+
+const (
+	// RecordRequest is an HTTP request to the Notion API, including the
+	// retries notionapi makes.
+	RecordRequest RecordKind = iota
+	// RecordWait is time a call spent waiting for the rate limiter.
+	RecordWait
+)
+
+// RequestRecord is an entry of the client's request log.
+type RequestRecord struct {
+	Time     time.Time // when the request or wait started
+	Kind     RecordKind
+	Method   string // requests only
+	Object   string // requests only, such as "pages" or "search"
+	ObjectID string // requests only; empty for paths such as /v1/search
+	Status   int    // requests only; 0 when no response arrived
+	Priority Priority
+	Duration time.Duration // latency of a request, or how long a wait took
+	Err      string        // requests only; why no response arrived
+}
+
+// requestLog keeps the most recent records in a ring buffer.
+type requestLog struct {
+	mu      sync.Mutex
+	records []RequestRecord
+	next    int
+}
+
+// add records r, dropping the oldest record once the log is full.
+func (l *requestLog) add(r RequestRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.records) < requestLogSize {
+		l.records = append(l.records, r)
+		return
+	}
+	l.records[l.next] = r
+	l.next = (l.next + 1) % requestLogSize
+}
+
+// list returns a copy of the records, oldest first.
+func (l *requestLog) list() []RequestRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]RequestRecord, 0, len(l.records))
+	out = append(out, l.records[l.next:]...)
+	return append(out, l.records[:l.next]...)
+}
+
+// RecentRequests returns the client's most recent requests and rate limiter
+// waits, oldest first.
+func (c *Client) RecentRequests() []RequestRecord {
+	return c.requests.list()
+}
@@ -0,0 +1,80 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		add       int
+		wantLen   int
+		wantFirst int
+	}{
+		{name: "empty", add: 0, wantLen: 0},
+		{name: "partly filled", add: 3, wantLen: 3, wantFirst: 0},
+		{name: "full", add: requestLogSize, wantLen: requestLogSize, wantFirst: 0},
+		{name: "wrapped", add: requestLogSize + 5, wantLen: requestLogSize, wantFirst: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var log requestLog
+			for i := 0; i < tt.add; i++ {
+				log.add(RequestRecord{Status: i})
+			}
+
+			records := log.list()
+			require.Len(t, records, tt.wantLen)
+			for i, r := range records {
+				assert.Equal(t, tt.wantFirst+i, r.Status, "records are oldest first")
+			}
+		})
+	}
+}
+
+func TestRecentRequests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("secret_test_token")
+	transport := &rateLimitTransport{base: http.DefaultTransport, client: client}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/databases/db-1/query", nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// Drain the limiter so the next call has to wait for a token
+	require.True(t, client.limiter.AllowN(time.Now(), client.limiter.Burst()))
+	release, err := client.wait(context.Background())
+	require.NoError(t, err)
+	release()
+
+	records := client.RecentRequests()
+	require.Len(t, records, 2)
+	assert.Equal(t, RecordRequest, records[0].Kind)
+	assert.Equal(t, "POST", records[0].Method)
+	assert.Equal(t, "databases", records[0].Object)
+	assert.Equal(t, "db-1", records[0].ObjectID)
+	assert.Equal(t, http.StatusOK, records[0].Status)
+	assert.Equal(t, RecordWait, records[1].Kind)
+	assert.Equal(t, PriorityForeground, records[1].Priority)
+	assert.Greater(t, records[1].Duration, time.Duration(0))
+}
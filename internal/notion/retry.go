@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 	"strconv"
@@ -22,6 +23,8 @@ type RetryConfig struct {
 	MaxBackoff time.Duration
 	// BackoffMultiplier is the multiplier for exponential backoff (default: 2.0).
 	BackoffMultiplier float64
+	// Logger receives a record for each retry; optional, slog.Default() when nil.
+	Logger *slog.Logger
 }
 
 // DefaultRetryConfig returns the default retry configuration.
@@ -47,6 +50,10 @@ type RetryableFunc func(ctx context.Context) error
 func RetryWithBackoff(ctx context.Context, config RetryConfig, fn RetryableFunc) error {
 	var lastErr error
 	backoff := config.InitialBackoff
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		// Execute the function
@@ -81,6 +88,9 @@ func RetryWithBackoff(ctx context.Context, config RetryConfig, fn RetryableFunc)
 			}
 		}
 
+		logger.DebugContext(ctx, "retrying after error",
+			"attempt", attempt+1, "max_retries", config.MaxRetries, "wait", waitDuration, "error", err)
+
 		// Wait before retrying
 		select {
 		case <-ctx.Done():
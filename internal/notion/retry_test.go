@@ -1,8 +1,11 @@
 package notion
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -70,6 +73,31 @@ func TestRetryWithBackoff_SuccessAfterRetries(t *testing.T) {
 	assert.GreaterOrEqual(t, duration, 30*time.Millisecond)
 }
 
+func TestRetryWithBackoff_LogsRetries(t *testing.T) {
+	var buf bytes.Buffer
+	config := RetryConfig{
+		MaxRetries:        3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        time.Millisecond,
+		BackoffMultiplier: 2.0,
+		Logger:            slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	attempts := 0
+	err := RetryWithBackoff(context.Background(), config, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return &mockNetError{msg: "temporary error", temporary: true}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(buf.String(), "retrying after error"))
+	assert.Contains(t, buf.String(), "attempt=2")
+	assert.Contains(t, buf.String(), `error="temporary error"`)
+}
+
 func TestRetryWithBackoff_MaxRetriesExceeded(t *testing.T) {
 	config := RetryConfig{
 		MaxRetries:        2,
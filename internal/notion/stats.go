@@ -0,0 +1,216 @@
+package notion
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples is how many latencies an endpoint keeps for its
+// percentiles. Older samples are replaced once it is full.
+const maxLatencySamples = 1000
+
+// viewKey is the context key for the view a request is made for.
+type viewKey struct{}
+
+// WithView returns a context whose requests are counted against view in the
+// client's Stats, such as "list" or "detail".
+func WithView(ctx context.Context, view string) context.Context {
+	return context.WithValue(ctx, viewKey{}, view)
+}
+
+// ViewFrom returns the view stored in ctx, or "other".
+func ViewFrom(ctx context.Context) string {
+	if v, ok := ctx.Value(viewKey{}).(string); ok && v != "" {
+		return v
+	}
+	return "other"
+}
+
+// APIStats aggregates the client's requests since it was created.
+// Durations are in nanoseconds when written as JSON.
+type APIStats struct {
+	Since       time.Time       `json:"since"`
+	Requests    int             `json:"requests"`
+	Errors      int             `json:"errors"`       // Failed requests and responses with status 400 or above
+	RateLimited int             `json:"rate_limited"` // 429 responses
+	Retries     int             `json:"retries"`      // 429 responses retried after Retry-After
+	Waits       int             `json:"waits"`        // Calls that waited for the rate limiter
+	Waited      time.Duration   `json:"waited"`       // Total time spent waiting for the rate limiter
+	Endpoints   []EndpointStats `json:"endpoints"`    // Most requested first
+	Views       []ViewStats     `json:"views"`        // Most time spent first
+}
+
+// EndpointStats aggregates the requests to one endpoint, such as
+// "GET /pages/{id}".
+type EndpointStats struct {
+	Endpoint    string        `json:"endpoint"`
+	Requests    int           `json:"requests"`
+	Errors      int           `json:"errors"`
+	RateLimited int           `json:"rate_limited"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+}
+
+// ViewStats aggregates the requests made for one view.
+type ViewStats struct {
+	View     string        `json:"view"`
+	Requests int           `json:"requests"`
+	Latency  time.Duration `json:"latency"` // Total latency of the view's requests
+	Waited   time.Duration `json:"waited"`  // Total time the view's calls waited for the rate limiter
+}
+
+// Total returns the time the view spent on the API.
+func (v ViewStats) Total() time.Duration {
+	return v.Latency + v.Waited
+}
+
+// apiStats accumulates APIStats as requests finish.
+type apiStats struct {
+	mu        sync.Mutex
+	since     time.Time
+	totals    APIStats
+	endpoints map[string]*endpointStats
+	views     map[string]*ViewStats
+}
+
+// endpointStats is an endpoint's counters and its latency samples.
+type endpointStats struct {
+	EndpointStats
+	latencies []time.Duration
+	next      int
+}
+
+func newAPIStats() *apiStats {
+	return &apiStats{
+		since:     time.Now(),
+		endpoints: make(map[string]*endpointStats),
+		views:     make(map[string]*ViewStats),
+	}
+}
+
+// addRequest counts a finished request. retried is set for 429 responses
+// notionapi sends again.
+func (s *apiStats) addRequest(r RequestRecord, endpoint, view string, retried bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.endpoints[endpoint]
+	if !ok {
+		e = &endpointStats{EndpointStats: EndpointStats{Endpoint: endpoint}}
+		s.endpoints[endpoint] = e
+	}
+	e.Requests++
+	s.totals.Requests++
+	if r.Err != "" || r.Status >= 400 {
+		e.Errors++
+		s.totals.Errors++
+	}
+	if r.Status == 429 {
+		e.RateLimited++
+		s.totals.RateLimited++
+	}
+	if retried {
+		s.totals.Retries++
+	}
+	if len(e.latencies) < maxLatencySamples {
+		e.latencies = append(e.latencies, r.Duration)
+	} else {
+		e.latencies[e.next] = r.Duration
+		e.next = (e.next + 1) % maxLatencySamples
+	}
+
+	v := s.view(view)
+	v.Requests++
+	v.Latency += r.Duration
+}
+
+// addWait counts time a call for view spent waiting for the rate limiter.
+func (s *apiStats) addWait(d time.Duration, view string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.totals.Waits++
+	s.totals.Waited += d
+	s.view(view).Waited += d
+}
+
+// view returns the counters of a view, creating them on first use.
+// Callers must hold the lock.
+func (s *apiStats) view(name string) *ViewStats {
+	v, ok := s.views[name]
+	if !ok {
+		v = &ViewStats{View: name}
+		s.views[name] = v
+	}
+	return v
+}
+
+// snapshot returns a copy of the aggregates with percentiles computed.
+func (s *apiStats) snapshot() APIStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := s.totals
+	out.Since = s.since
+	out.Endpoints = make([]EndpointStats, 0, len(s.endpoints))
+	for _, e := range s.endpoints {
+		stats := e.EndpointStats
+		stats.P50 = percentile(e.latencies, 50)
+		stats.P95 = percentile(e.latencies, 95)
+		out.Endpoints = append(out.Endpoints, stats)
+	}
+	sort.Slice(out.Endpoints, func(i, j int) bool {
+		a, b := out.Endpoints[i], out.Endpoints[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Endpoint < b.Endpoint
+	})
+
+	out.Views = make([]ViewStats, 0, len(s.views))
+	for _, v := range s.views {
+		out.Views = append(out.Views, *v)
+	}
+	sort.Slice(out.Views, func(i, j int) bool {
+		a, b := out.Views[i], out.Views[j]
+		if a.Total() != b.Total() {
+			return a.Total() > b.Total()
+		}
+		return a.View < b.View
+	})
+	return out
+}
+
+// percentile returns the nearest-rank percentile p of samples, or 0 when
+// there are none.
+func percentile(samples []time.Duration, p int) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// endpoint returns the method and API path of a request with object IDs
+// replaced, such as "POST /databases/{id}/query".
+func endpoint(method, path string) string {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/v1"), "/"), "/")
+	if len(parts) > 1 {
+		parts[1] = "{id}"
+	}
+	return method + " /" + strings.Join(parts, "/")
+}
+
+// Stats returns the requests, rate limiter waits and errors of the client
+// since it was created.
+func (c *Client) Stats() APIStats {
+	return c.stats.snapshot()
+}
@@ -0,0 +1,135 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	ms := func(n ...int) []time.Duration {
+		out := make([]time.Duration, 0, len(n))
+		for _, v := range n {
+			out = append(out, time.Duration(v)*time.Millisecond)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		p       int
+		want    time.Duration
+	}{
+		{name: "no samples", samples: nil, p: 50, want: 0},
+		{name: "one sample", samples: ms(7), p: 95, want: 7 * time.Millisecond},
+		{name: "median", samples: ms(50, 10, 40, 20, 30), p: 50, want: 30 * time.Millisecond},
+		{name: "p95 of twenty", samples: ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 200), p: 95, want: 19 * time.Millisecond},
+		{name: "p95 of few is the slowest", samples: ms(10, 300, 20), p: 95, want: 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, percentile(tt.samples, tt.p))
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: "GET", path: "/v1/pages/page-1", want: "GET /pages/{id}"},
+		{method: "POST", path: "/v1/databases/db-1/query", want: "POST /databases/{id}/query"},
+		{method: "GET", path: "/v1/blocks/block-1/children", want: "GET /blocks/{id}/children"},
+		{method: "POST", path: "/v1/search", want: "POST /search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, endpoint(tt.method, tt.path))
+		})
+	}
+}
+
+func TestAPIStats(t *testing.T) {
+	t.Parallel()
+
+	s := newAPIStats()
+	s.addRequest(RequestRecord{Status: 200, Duration: 100 * time.Millisecond}, "GET /pages/{id}", "detail", false)
+	s.addRequest(RequestRecord{Status: 429, Duration: 10 * time.Millisecond}, "GET /blocks/{id}/children", "detail", true)
+	s.addRequest(RequestRecord{Status: 200, Duration: 300 * time.Millisecond}, "GET /blocks/{id}/children", "detail", false)
+	s.addRequest(RequestRecord{Err: "connection reset", Duration: time.Second}, "POST /search", "search", false)
+	s.addWait(2*time.Second, "list")
+
+	stats := s.snapshot()
+	assert.Equal(t, 4, stats.Requests)
+	assert.Equal(t, 2, stats.Errors, "a 429 and a failed request")
+	assert.Equal(t, 1, stats.RateLimited)
+	assert.Equal(t, 1, stats.Retries)
+	assert.Equal(t, 1, stats.Waits)
+	assert.Equal(t, 2*time.Second, stats.Waited)
+
+	require.Len(t, stats.Endpoints, 3)
+	blocks := stats.Endpoints[0]
+	assert.Equal(t, "GET /blocks/{id}/children", blocks.Endpoint, "most requested first")
+	assert.Equal(t, 2, blocks.Requests)
+	assert.Equal(t, 1, blocks.RateLimited)
+	assert.Equal(t, 10*time.Millisecond, blocks.P50)
+	assert.Equal(t, 300*time.Millisecond, blocks.P95)
+
+	require.Len(t, stats.Views, 3)
+	assert.Equal(t, "list", stats.Views[0].View, "most time spent first")
+	assert.Equal(t, "search", stats.Views[1].View)
+	assert.Equal(t, ViewStats{View: "detail", Requests: 3, Latency: 410 * time.Millisecond}, stats.Views[2])
+}
+
+func TestClientStats(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("secret_test_token")
+	transport := &rateLimitTransport{base: http.DefaultTransport, client: client}
+
+	ctx := WithView(context.Background(), "list")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/databases/db-1/query", nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	stats := client.Stats()
+	assert.Equal(t, 1, stats.Requests)
+	assert.Equal(t, 1, stats.RateLimited)
+	assert.Equal(t, 1, stats.Retries)
+	require.Len(t, stats.Endpoints, 1)
+	assert.Equal(t, "POST /databases/{id}/query", stats.Endpoints[0].Endpoint)
+	require.Len(t, stats.Views, 1)
+	assert.Equal(t, "list", stats.Views[0].View)
+	assert.False(t, stats.Since.IsZero())
+}
+
+func TestViewFrom(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "other", ViewFrom(context.Background()))
+	assert.Equal(t, "search", ViewFrom(WithView(context.Background(), "search")))
+}
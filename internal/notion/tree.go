@@ -0,0 +1,151 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jomei/notionapi"
+)
+
+// blockPageSize is the largest page size accepted by the blocks endpoint.
+const blockPageSize = 100
+
+// BlockFetcher is the subset of the Client used to read block children.
+type BlockFetcher interface {
+	GetBlocks(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error)
+}
+
+// BlockNode is a block together with its nested children.
+type BlockNode struct {
+	Block    notionapi.Block `json:"block"`
+	Children []BlockNode     `json:"children,omitempty"`
+}
+
+// NewBlockNodes wraps blocks in nodes without children.
+func NewBlockNodes(blocks []notionapi.Block) []BlockNode {
+	nodes := make([]BlockNode, 0, len(blocks))
+	for _, block := range blocks {
+		nodes = append(nodes, BlockNode{Block: block})
+	}
+	return nodes
+}
+
+// FetchBlocks returns all children of a page or block, following pagination.
+func FetchBlocks(ctx context.Context, f BlockFetcher, id string) ([]notionapi.Block, error) {
+	var blocks []notionapi.Block
+	var cursor notionapi.Cursor
+	for {
+		resp, err := f.GetBlocks(ctx, id, &notionapi.Pagination{
+			StartCursor: cursor,
+			PageSize:    blockPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == "" {
+			return blocks, nil
+		}
+		cursor = notionapi.Cursor(resp.NextCursor)
+	}
+}
+
+// FetchBlockTree returns the blocks of a page with their nested children.
+// maxDepth limits how many levels are fetched: 1 returns the top-level blocks
+// only and 0 or less fetches the whole tree.
+func FetchBlockTree(ctx context.Context, f BlockFetcher, id string, maxDepth int) ([]BlockNode, error) {
+	blocks, err := FetchBlocks(ctx, f, id)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandBlockTree(ctx, f, blocks, maxDepth)
+}
+
+// ExpandBlockTree fetches the nested children of already fetched top-level
+// blocks, such as blocks read from the cache. maxDepth counts the given level.
+func ExpandBlockTree(ctx context.Context, f BlockFetcher, blocks []notionapi.Block, maxDepth int) ([]BlockNode, error) {
+	nodes := NewBlockNodes(blocks)
+	if maxDepth == 1 {
+		return nodes, nil
+	}
+
+	for i := range nodes {
+		if !descendInto(nodes[i].Block) {
+			continue
+		}
+
+		id := string(nodes[i].Block.GetID())
+		children, err := FetchBlockTree(ctx, f, id, maxDepth-1)
+		if err != nil {
+			return nil, fmt.Errorf("fetch children of block %s: %w", id, err)
+		}
+		nodes[i].Children = children
+	}
+	return nodes, nil
+}
+
+// descendInto reports whether the children of a block belong to the page.
+// Child pages and databases have children of their own that are separate pages.
+func descendInto(block notionapi.Block) bool {
+	if !HasChildren(block) {
+		return false
+	}
+	switch block.GetType() {
+	case notionapi.BlockTypeChildPage, notionapi.BlockTypeChildDatabase:
+		return false
+	default:
+		return true
+	}
+}
+
+// BlockAppender is the subset of the Client used to add blocks.
+type BlockAppender interface {
+	AppendBlocks(ctx context.Context, id string, req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error)
+}
+
+// AppendBlockTree appends nodes to a page or block and returns the created
+// top-level blocks. The API accepts at most 100 children per request, so
+// larger lists are sent in chunks; nested children are appended to their
+// parent once it has been created.
+func AppendBlockTree(ctx context.Context, a BlockAppender, id string, nodes []BlockNode) ([]notionapi.Block, error) {
+	return InsertBlockTree(ctx, a, id, "", nodes)
+}
+
+// InsertBlockTree is AppendBlockTree for nodes that go after an existing
+// child of the page or block instead of at the end. An empty after appends.
+func InsertBlockTree(ctx context.Context, a BlockAppender, id, after string, nodes []BlockNode) ([]notionapi.Block, error) {
+	var created []notionapi.Block
+	for start := 0; start < len(nodes); start += blockPageSize {
+		chunk := nodes[start:min(start+blockPageSize, len(nodes))]
+
+		blocks := make([]notionapi.Block, len(chunk))
+		for i, node := range chunk {
+			blocks[i] = node.Block
+		}
+		resp, err := a.AppendBlocks(ctx, id, &notionapi.AppendBlockChildrenRequest{
+			After:    notionapi.BlockID(after),
+			Children: blocks,
+		})
+		if err != nil {
+			return created, err
+		}
+		if len(resp.Results) != len(chunk) {
+			return created, fmt.Errorf("append blocks to %s: sent %d blocks, got %d back", id, len(chunk), len(resp.Results))
+		}
+		created = append(created, resp.Results...)
+		if after != "" {
+			after = string(resp.Results[len(resp.Results)-1].GetID())
+		}
+
+		for i, node := range chunk {
+			if len(node.Children) == 0 {
+				continue
+			}
+			if _, err := AppendBlockTree(ctx, a, string(resp.Results[i].GetID()), node.Children); err != nil {
+				return created, err
+			}
+		}
+	}
+	return created, nil
+}
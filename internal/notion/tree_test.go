@@ -0,0 +1,199 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlockFetcher serves block children from a map, two blocks per page of
+// results, and records the IDs it was asked for.
+type fakeBlockFetcher struct {
+	children map[string][]notionapi.Block
+	calls    []string
+	err      error
+}
+
+func (f *fakeBlockFetcher) GetBlocks(_ context.Context, id string,
+	pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
+	f.calls = append(f.calls, id)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	blocks := f.children[id]
+	start := 0
+	if pagination != nil && pagination.StartCursor == "more" {
+		start = 2
+	}
+	end := min(start+2, len(blocks))
+
+	resp := &notionapi.GetChildrenResponse{Results: blocks[start:end]}
+	if end < len(blocks) {
+		resp.HasMore = true
+		resp.NextCursor = "more"
+	}
+	return resp, nil
+}
+
+func treeBlock(id string, blockType notionapi.BlockType, hasChildren bool) notionapi.Block {
+	basic := notionapi.BasicBlock{ID: notionapi.BlockID(id), Type: blockType, HasChildren: hasChildren}
+	switch blockType {
+	case notionapi.BlockTypeChildPage:
+		return &notionapi.ChildPageBlock{BasicBlock: basic}
+	default:
+		return &notionapi.ParagraphBlock{BasicBlock: basic}
+	}
+}
+
+func TestFetchBlockTree(t *testing.T) {
+	t.Parallel()
+
+	newFetcher := func() *fakeBlockFetcher {
+		return &fakeBlockFetcher{children: map[string][]notionapi.Block{
+			"page": {
+				treeBlock("a", notionapi.BlockTypeParagraph, true),
+				treeBlock("b", notionapi.BlockTypeParagraph, false),
+				treeBlock("c", notionapi.BlockTypeChildPage, true),
+			},
+			"a":  {treeBlock("a1", notionapi.BlockTypeParagraph, true)},
+			"a1": {treeBlock("a11", notionapi.BlockTypeParagraph, false)},
+		}}
+	}
+
+	t.Run("whole tree", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFetcher()
+		nodes, err := FetchBlockTree(context.Background(), f, "page", 0)
+		require.NoError(t, err)
+
+		require.Len(t, nodes, 3, "pagination should return all top-level blocks")
+		require.Len(t, nodes[0].Children, 1)
+		require.Len(t, nodes[0].Children[0].Children, 1)
+		assert.Empty(t, nodes[2].Children, "child pages are not descended into")
+		assert.NotContains(t, f.calls, "c")
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFetcher()
+		nodes, err := FetchBlockTree(context.Background(), f, "page", 2)
+		require.NoError(t, err)
+
+		require.Len(t, nodes[0].Children, 1)
+		assert.Empty(t, nodes[0].Children[0].Children)
+		assert.NotContains(t, f.calls, "a1")
+	})
+
+	t.Run("top level only", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFetcher()
+		nodes, err := FetchBlockTree(context.Background(), f, "page", 1)
+		require.NoError(t, err)
+
+		assert.Empty(t, nodes[0].Children)
+		assert.Equal(t, []string{"page", "page"}, f.calls)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		f := &fakeBlockFetcher{err: errors.New("boom")}
+		_, err := FetchBlockTree(context.Background(), f, "page", 0)
+		assert.Error(t, err)
+	})
+}
+
+// appendCall records a call to fakeBlockAppender.
+type appendCall struct {
+	id    string
+	after string
+	count int
+}
+
+// fakeBlockAppender echoes appended blocks back with IDs derived from the
+// parent, such as "page-0", and records each call.
+type fakeBlockAppender struct {
+	calls []appendCall
+	err   error
+}
+
+func (a *fakeBlockAppender) AppendBlocks(_ context.Context, id string,
+	req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+	a.calls = append(a.calls, appendCall{id: id, after: string(req.After), count: len(req.Children)})
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	resp := &notionapi.AppendBlockChildrenResponse{}
+	for i := range req.Children {
+		resp.Results = append(resp.Results, treeBlock(fmt.Sprintf("%s-%d", id, i), notionapi.BlockTypeParagraph, false))
+	}
+	return resp, nil
+}
+
+func TestAppendBlockTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chunks and nested children", func(t *testing.T) {
+		t.Parallel()
+
+		nodes := make([]BlockNode, 150)
+		for i := range nodes {
+			nodes[i] = BlockNode{Block: treeBlock("", notionapi.BlockTypeParagraph, false)}
+		}
+		nodes[1].Children = []BlockNode{{
+			Block:    treeBlock("", notionapi.BlockTypeParagraph, false),
+			Children: []BlockNode{{Block: treeBlock("", notionapi.BlockTypeParagraph, false)}},
+		}}
+
+		a := &fakeBlockAppender{}
+		created, err := AppendBlockTree(context.Background(), a, "page", nodes)
+		require.NoError(t, err)
+
+		assert.Len(t, created, 150)
+		assert.Equal(t, []appendCall{
+			{id: "page", count: 100},
+			{id: "page-1", count: 1},
+			{id: "page-1-0", count: 1},
+			{id: "page", count: 50},
+		}, a.calls)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		a := &fakeBlockAppender{err: errors.New("boom")}
+		_, err := AppendBlockTree(context.Background(), a, "page", NewBlockNodes([]notionapi.Block{
+			treeBlock("", notionapi.BlockTypeParagraph, false),
+		}))
+		assert.Error(t, err)
+	})
+}
+
+func TestInsertBlockTree(t *testing.T) {
+	t.Parallel()
+
+	nodes := make([]BlockNode, 150)
+	for i := range nodes {
+		nodes[i] = BlockNode{Block: treeBlock("", notionapi.BlockTypeParagraph, false)}
+	}
+
+	a := &fakeBlockAppender{}
+	created, err := InsertBlockTree(context.Background(), a, "page", "block-1", nodes)
+	require.NoError(t, err)
+
+	assert.Len(t, created, 150)
+	assert.Equal(t, []appendCall{
+		{id: "page", after: "block-1", count: 100},
+		{id: "page", after: "page-99", count: 50},
+	}, a.calls, "later chunks go after the last block created")
+}
@@ -0,0 +1,150 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// ErrUnsupportedProperty is returned by ParsePropertyValue for property types
+// whose values cannot be set, such as formulas and rollups.
+var ErrUnsupportedProperty = errors.New("not supported")
+
+// ParseDate parses today, yesterday, tomorrow, now, YYYY-MM-DD and RFC 3339
+// values. Dates without a time are midnight in now's time zone.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(value) {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (want YYYY-MM-DD, RFC 3339, today, yesterday, tomorrow or now)", value)
+}
+
+// ParsePropertyValue converts text into a value for a property of the given
+// type, the reverse of PropertyString. Multi-select, relation and people
+// values are separated by commas, relations are page IDs or URLs, people are
+// user IDs and date ranges are written "start/end".
+func ParsePropertyValue(propType notionapi.PropertyConfigType, value string, now time.Time) (notionapi.Property, error) {
+	switch propType {
+	case notionapi.PropertyConfigTypeTitle:
+		return &notionapi.TitleProperty{Title: plainRichText(value)}, nil
+
+	case notionapi.PropertyConfigTypeRichText:
+		return &notionapi.RichTextProperty{RichText: plainRichText(value)}, nil
+
+	case notionapi.PropertyConfigTypeNumber:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return &notionapi.NumberProperty{Number: n}, nil
+
+	case notionapi.PropertyConfigTypeCheckbox:
+		b, err := parseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return &notionapi.CheckboxProperty{Checkbox: b}, nil
+
+	case notionapi.PropertyConfigTypeSelect:
+		return &notionapi.SelectProperty{Select: notionapi.Option{Name: value}}, nil
+
+	case notionapi.PropertyConfigStatus:
+		return &notionapi.StatusProperty{Status: notionapi.Option{Name: value}}, nil
+
+	case notionapi.PropertyConfigTypeMultiSelect:
+		options := []notionapi.Option{}
+		for _, name := range splitList(value) {
+			options = append(options, notionapi.Option{Name: name})
+		}
+		return &notionapi.MultiSelectProperty{MultiSelect: options}, nil
+
+	case notionapi.PropertyConfigTypeDate:
+		startText, endText, isRange := strings.Cut(value, "/")
+		start, err := ParseDate(startText, now)
+		if err != nil {
+			return nil, err
+		}
+		date := &notionapi.DateObject{Start: (*notionapi.Date)(&start)}
+		if isRange {
+			end, err := ParseDate(endText, now)
+			if err != nil {
+				return nil, err
+			}
+			date.End = (*notionapi.Date)(&end)
+		}
+		return &notionapi.DateProperty{Date: date}, nil
+
+	case notionapi.PropertyConfigTypeURL:
+		return &notionapi.URLProperty{URL: value}, nil
+
+	case notionapi.PropertyConfigTypeEmail:
+		return &notionapi.EmailProperty{Email: value}, nil
+
+	case notionapi.PropertyConfigTypePhoneNumber:
+		return &notionapi.PhoneNumberProperty{PhoneNumber: value}, nil
+
+	case notionapi.PropertyConfigTypeRelation:
+		relations := []notionapi.Relation{}
+		for _, item := range splitList(value) {
+			id, err := ParseID(item)
+			if err != nil {
+				return nil, err
+			}
+			relations = append(relations, notionapi.Relation{ID: notionapi.PageID(id)})
+		}
+		return &notionapi.RelationProperty{Relation: relations}, nil
+
+	case notionapi.PropertyConfigTypePeople:
+		people := []notionapi.User{}
+		for _, id := range splitList(value) {
+			people = append(people, notionapi.User{Object: notionapi.ObjectTypeUser, ID: notionapi.UserID(id)})
+		}
+		return &notionapi.PeopleProperty{People: people}, nil
+
+	default:
+		return nil, fmt.Errorf("setting %s properties is %w", propType, ErrUnsupportedProperty)
+	}
+}
+
+// parseBool parses checkbox values.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "checked", "1":
+		return true, nil
+	case "false", "no", "unchecked", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%q is not a checkbox value (want true or false)", value)
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,109 @@
+package notion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+7", 7*60*60)
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, loc)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "now", want: now},
+		{value: "Today", want: time.Date(2024, 3, 15, 0, 0, 0, 0, loc)},
+		{value: "yesterday", want: time.Date(2024, 3, 14, 0, 0, 0, 0, loc)},
+		{value: " tomorrow ", want: time.Date(2024, 3, 16, 0, 0, 0, 0, loc)},
+		{value: "2024-04-01", want: time.Date(2024, 4, 1, 0, 0, 0, 0, loc)},
+		{value: "2024-04-01T09:00:00Z", want: time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDate(tt.value, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestParsePropertyValue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		propType notionapi.PropertyConfigType
+		value    string
+		want     string // PropertyString of the result
+	}{
+		{name: "title", propType: notionapi.PropertyConfigTypeTitle, value: "Renew passport", want: "Renew passport"},
+		{name: "number", propType: notionapi.PropertyConfigTypeNumber, value: "2.5", want: "2.5"},
+		{name: "checkbox", propType: notionapi.PropertyConfigTypeCheckbox, value: "yes", want: "true"},
+		{name: "select", propType: notionapi.PropertyConfigTypeSelect, value: "High", want: "High"},
+		{name: "status", propType: notionapi.PropertyConfigStatus, value: "Todo", want: "Todo"},
+		{name: "multi-select", propType: notionapi.PropertyConfigTypeMultiSelect, value: "a, b,,c", want: "a, b, c"},
+		{name: "date", propType: notionapi.PropertyConfigTypeDate, value: "tomorrow", want: "2024-03-16"},
+		{name: "date range", propType: notionapi.PropertyConfigTypeDate, value: "2024-04-01/2024-04-03", want: "2024-04-01/2024-04-03"},
+		{name: "url", propType: notionapi.PropertyConfigTypeURL, value: "https://example.com", want: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prop, err := ParsePropertyValue(tt.propType, tt.value, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, PropertyString(prop))
+		})
+	}
+}
+
+func TestParsePropertyValueErrors(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		name     string
+		propType notionapi.PropertyConfigType
+		value    string
+		wantErr  string
+	}{
+		{name: "number", propType: notionapi.PropertyConfigTypeNumber, value: "high", wantErr: "not a number"},
+		{name: "checkbox", propType: notionapi.PropertyConfigTypeCheckbox, value: "maybe", wantErr: "not a checkbox value"},
+		{name: "date", propType: notionapi.PropertyConfigTypeDate, value: "soon", wantErr: "not a date"},
+		{name: "relation", propType: notionapi.PropertyConfigTypeRelation, value: "https://example.com/nothing", wantErr: "no Notion ID"},
+		{name: "formula", propType: notionapi.PropertyConfigTypeFormula, value: "1", wantErr: "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParsePropertyValue(tt.propType, tt.value, now)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := ParsePropertyValue(notionapi.PropertyConfigTypeRollup, "3", now)
+	assert.ErrorIs(t, err, ErrUnsupportedProperty)
+}
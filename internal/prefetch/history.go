@@ -0,0 +1,102 @@
+package prefetch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// History is a persisted, most-recent-first list of opened page IDs.
+type History struct {
+	mu   sync.Mutex
+	path string
+	size int
+	ids  []string
+}
+
+// NewHistoryInput contains the parameters for loading a History.
+type NewHistoryInput struct {
+	Path string // empty keeps the history in memory only
+	Size int    // maximum number of remembered pages
+}
+
+// NewHistory loads the history stored at Path. A missing or unreadable file
+// starts an empty history.
+func NewHistory(input NewHistoryInput) *History {
+	h := &History{path: input.Path, size: input.Size}
+	if h.path == "" {
+		return h
+	}
+
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		return h
+	}
+
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return h
+	}
+	if len(ids) > h.size {
+		ids = ids[:h.size]
+	}
+	h.ids = ids
+	return h
+}
+
+// Record moves a page to the front of the history and persists it.
+func (h *History) Record(pageID string) error {
+	if pageID == "" || h.size <= 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := notion.NormalizeID(pageID)
+	ids := make([]string, 0, len(h.ids)+1)
+	ids = append(ids, pageID)
+	for _, id := range h.ids {
+		if notion.NormalizeID(id) != key {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > h.size {
+		ids = ids[:h.size]
+	}
+	h.ids = ids
+
+	return h.saveLocked()
+}
+
+// IDs returns the remembered page IDs, most recent first.
+func (h *History) IDs() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]string(nil), h.ids...)
+}
+
+// saveLocked writes the history to disk. Callers must hold the lock.
+func (h *History) saveLocked() error {
+	if h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h.ids)
+	if err != nil {
+		return fmt.Errorf("marshal history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0600); err != nil {
+		return fmt.Errorf("write history file %s: %w", h.path, err)
+	}
+	return nil
+}
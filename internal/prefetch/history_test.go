@@ -0,0 +1,49 @@
+package prefetch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "prefetch", "history.json")
+	h := NewHistory(NewHistoryInput{Path: path, Size: 3})
+
+	for _, id := range []string{"a", "b", "c", "a", "d"} {
+		require.NoError(t, h.Record(id))
+	}
+	assert.Equal(t, []string{"d", "a", "c"}, h.IDs())
+
+	// Reloaded from disk.
+	reloaded := NewHistory(NewHistoryInput{Path: path, Size: 2})
+	assert.Equal(t, []string{"d", "a"}, reloaded.IDs())
+}
+
+func TestHistoryNormalizesIDs(t *testing.T) {
+	t.Parallel()
+
+	h := NewHistory(NewHistoryInput{Size: 5})
+	require.NoError(t, h.Record("12345678-90ab-cdef-1234-567890abcdef"))
+	require.NoError(t, h.Record("1234567890abcdef1234567890abcdef"))
+
+	assert.Equal(t, []string{"1234567890abcdef1234567890abcdef"}, h.IDs())
+}
+
+func TestHistoryDisabledOrCorrupt(t *testing.T) {
+	t.Parallel()
+
+	disabled := NewHistory(NewHistoryInput{Size: 0})
+	require.NoError(t, disabled.Record("a"))
+	assert.Empty(t, disabled.IDs())
+
+	path := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0600))
+	corrupt := NewHistory(NewHistoryInput{Path: path, Size: 5})
+	assert.Empty(t, corrupt.IDs())
+}
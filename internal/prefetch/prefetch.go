@@ -0,0 +1,472 @@
+// Package prefetch warms the page cache in the background so that opening a page
+// rarely waits on the Notion API. Work runs at idle priority on the shared client,
+// so user-initiated requests always go first, and pauses while rate limited.
+package prefetch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+const (
+	// initialBackoff is the first pause applied after a rate-limit error.
+	initialBackoff = 30 * time.Second
+	// maxBackoff caps the pause applied after repeated rate-limit errors.
+	maxBackoff = 5 * time.Minute
+	// idleWait is how long the worker sleeps when there is nothing to do.
+	idleWait = time.Minute
+)
+
+// Fetcher is the subset of the Notion client used to warm the cache.
+type Fetcher interface {
+	GetPage(ctx context.Context, id string) (*notionapi.Page, error)
+	GetBlocks(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error)
+	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
+}
+
+// Source identifies why work was queued. Lower values are processed first.
+type Source int
+
+const (
+	// SourceVisible is a row currently visible in a list.
+	SourceVisible Source = iota
+	// SourceHistory is a page the user opened recently.
+	SourceHistory
+	// SourceRecent is a recently edited page or database listing.
+	SourceRecent
+)
+
+// job is a single unit of prefetch work: a page or a database listing.
+type job struct {
+	pageID     string
+	databaseID string
+	limit      int
+	source     Source
+}
+
+// key identifies a job for de-duplication.
+func (j job) key() string {
+	if j.databaseID != "" {
+		return "db:" + notion.NormalizeID(j.databaseID)
+	}
+	return notion.NormalizeID(j.pageID)
+}
+
+// Stats summarizes prefetch activity.
+type Stats struct {
+	Warmed      int
+	Skipped     int
+	Failed      int
+	Queued      int
+	PausedUntil time.Time
+}
+
+// Prefetcher warms the cache for visible rows, recent history and recently
+// edited pages of configured databases.
+type Prefetcher struct {
+	fetcher Fetcher
+	cache   *cache.PageCache
+	ttl     time.Duration
+	history *History
+
+	mu          sync.Mutex
+	queue       []job
+	queued      map[string]bool
+	pausedUntil time.Time
+	backoff     time.Duration
+	stats       Stats
+	wake        chan struct{}
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewPrefetcherInput contains the parameters for creating a Prefetcher.
+type NewPrefetcherInput struct {
+	Fetcher     Fetcher
+	Cache       *cache.PageCache
+	TTL         time.Duration // default cache.DefaultPageTTL
+	HistorySize int           // recently opened pages to remember; 0 disables history
+}
+
+// NewPrefetcher creates a Prefetcher. The history of opened pages is persisted
+// inside the cache directory.
+func NewPrefetcher(input NewPrefetcherInput) (*Prefetcher, error) {
+	if input.Fetcher == nil {
+		return nil, fmt.Errorf("fetcher cannot be nil")
+	}
+	if input.Cache == nil {
+		return nil, fmt.Errorf("cache cannot be nil")
+	}
+
+	ttl := input.TTL
+	if ttl <= 0 {
+		ttl = cache.DefaultPageTTL
+	}
+
+	return &Prefetcher{
+		fetcher: input.Fetcher,
+		cache:   input.Cache,
+		ttl:     ttl,
+		history: NewHistory(NewHistoryInput{
+			Path: filepath.Join(input.Cache.Dir(), "prefetch", "history.json"),
+			Size: input.HistorySize,
+		}),
+		queued: make(map[string]bool),
+		wake:   make(chan struct{}, 1),
+	}, nil
+}
+
+// QueueVisible replaces the set of visible rows to warm. Visible rows that are no
+// longer on screen and have not been processed yet are dropped.
+func (p *Prefetcher) QueueVisible(pageIDs []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	kept := p.queue[:0]
+	for _, j := range p.queue {
+		if j.source == SourceVisible {
+			delete(p.queued, j.key())
+			continue
+		}
+		kept = append(kept, j)
+	}
+	p.queue = kept
+
+	for _, id := range pageIDs {
+		p.pushLocked(job{pageID: id, source: SourceVisible})
+	}
+	p.signal()
+}
+
+// QueueDatabase queues a listing of the most recently edited pages of a database.
+// The pages found are then queued for warming themselves.
+func (p *Prefetcher) QueueDatabase(databaseID string, limit int) {
+	if databaseID == "" || limit <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pushLocked(job{databaseID: databaseID, limit: limit, source: SourceRecent})
+	p.signal()
+}
+
+// QueueHistory queues every page in the recent history.
+func (p *Prefetcher) QueueHistory() {
+	ids := p.history.IDs()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, id := range ids {
+		p.pushLocked(job{pageID: id, source: SourceHistory})
+	}
+	p.signal()
+}
+
+// RecordVisit adds a page to the recent history.
+func (p *Prefetcher) RecordVisit(pageID string) error {
+	if err := p.history.Record(pageID); err != nil {
+		return fmt.Errorf("record visit: %w", err)
+	}
+	return nil
+}
+
+// History returns the recently opened page IDs, most recent first.
+func (p *Prefetcher) History() []string {
+	return p.history.IDs()
+}
+
+// pushLocked inserts a job after all queued jobs of equal or higher priority.
+// Callers must hold the lock.
+func (p *Prefetcher) pushLocked(j job) {
+	if p.queued[j.key()] {
+		return
+	}
+	p.queued[j.key()] = true
+
+	pos := len(p.queue)
+	for i, queued := range p.queue {
+		if queued.source > j.source {
+			pos = i
+			break
+		}
+	}
+	p.queue = append(p.queue, job{})
+	copy(p.queue[pos+1:], p.queue[pos:])
+	p.queue[pos] = j
+}
+
+// signal wakes the worker if it is sleeping.
+func (p *Prefetcher) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the background worker until Stop is called. Calling Start on a
+// running Prefetcher has no effect.
+func (p *Prefetcher) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+		p.run(ctx)
+	}()
+}
+
+// Stop stops the background worker and waits for it to exit.
+func (p *Prefetcher) Stop() {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.cancel, p.done = nil, nil
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// run processes queued work until ctx is done.
+func (p *Prefetcher) run(ctx context.Context) {
+	for {
+		j, wait, ok := p.next()
+		if ok {
+			p.process(ctx, j)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Drain processes queued work in the calling goroutine until the queue is empty.
+// Rate-limit pauses are waited out. It is used for headless cache warming.
+func (p *Prefetcher) Drain(ctx context.Context) error {
+	for {
+		j, wait, ok := p.next()
+		if ok {
+			p.process(ctx, j)
+			continue
+		}
+
+		p.mu.Lock()
+		empty := len(p.queue) == 0
+		p.mu.Unlock()
+		if empty {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("drain prefetch queue: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// next pops the next job. When none can run it returns how long to wait.
+func (p *Prefetcher) next() (job, time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if wait := time.Until(p.pausedUntil); wait > 0 {
+		return job{}, wait, false
+	}
+	if len(p.queue) == 0 {
+		return job{}, idleWait, false
+	}
+
+	j := p.queue[0]
+	p.queue = p.queue[1:]
+	delete(p.queued, j.key())
+	return j, 0, true
+}
+
+// process runs a single job at idle priority and records the outcome.
+func (p *Prefetcher) process(ctx context.Context, j job) {
+	ctx = notion.WithView(notion.WithPriority(ctx, notion.PriorityIdle), "prefetch")
+
+	var err error
+	warmed := false
+	if j.databaseID != "" {
+		var ids []string
+		ids, err = p.WarmDatabase(ctx, j.databaseID, j.limit)
+		if err == nil {
+			warmed = true
+			p.mu.Lock()
+			for _, id := range ids {
+				p.pushLocked(job{pageID: id, source: SourceRecent})
+			}
+			p.mu.Unlock()
+		}
+	} else {
+		warmed, err = p.WarmPage(ctx, j.pageID)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case err == nil:
+		p.backoff = 0
+		if warmed {
+			p.stats.Warmed++
+		} else {
+			p.stats.Skipped++
+		}
+	case ctx.Err() != nil:
+		// Shutting down: keep the job so a later Drain or Start can finish it.
+		p.pushLocked(j)
+	case notion.IsRateLimitError(err):
+		p.pauseLocked()
+		p.pushLocked(j)
+	default:
+		p.stats.Failed++
+	}
+}
+
+// pauseLocked backs off exponentially after a rate-limit error.
+// Callers must hold the lock.
+func (p *Prefetcher) pauseLocked() {
+	if p.backoff == 0 {
+		p.backoff = initialBackoff
+	} else {
+		p.backoff *= 2
+		if p.backoff > maxBackoff {
+			p.backoff = maxBackoff
+		}
+	}
+	p.pausedUntil = time.Now().Add(p.backoff)
+}
+
+// Pause holds back all prefetch work for d.
+func (p *Prefetcher) Pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(p.pausedUntil) {
+		p.pausedUntil = until
+	}
+}
+
+// Resume clears any pause and wakes the worker.
+func (p *Prefetcher) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pausedUntil = time.Time{}
+	p.backoff = 0
+	p.signal()
+}
+
+// Stats returns a snapshot of prefetch activity.
+func (p *Prefetcher) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Queued = len(p.queue)
+	stats.PausedUntil = p.pausedUntil
+	return stats
+}
+
+// WarmPage caches a page's metadata and blocks unless both are already fresh.
+// It reports whether anything was fetched.
+func (p *Prefetcher) WarmPage(ctx context.Context, pageID string) (bool, error) {
+	hasMeta := p.cache.Has(ctx, cache.MetaKey(pageID))
+	hasBlocks := p.cache.Has(ctx, pageID)
+	if hasMeta && hasBlocks {
+		return false, nil
+	}
+
+	if !hasMeta {
+		page, err := p.fetcher.GetPage(ctx, pageID)
+		if err != nil {
+			return false, fmt.Errorf("warm page %s: %w", pageID, err)
+		}
+		if err := p.cache.Set(ctx, cache.SetInput{
+			PageID: cache.MetaKey(pageID),
+			Data:   page,
+			TTL:    p.ttl,
+		}); err != nil {
+			return false, fmt.Errorf("cache page %s: %w", pageID, err)
+		}
+	}
+
+	if !hasBlocks {
+		children, err := p.fetcher.GetBlocks(ctx, pageID, nil)
+		if err != nil {
+			return false, fmt.Errorf("warm blocks %s: %w", pageID, err)
+		}
+		if err := p.cache.Set(ctx, cache.SetInput{
+			PageID: pageID,
+			Data:   children,
+			TTL:    p.ttl,
+		}); err != nil {
+			return false, fmt.Errorf("cache blocks %s: %w", pageID, err)
+		}
+	}
+
+	return true, nil
+}
+
+// WarmDatabase lists the most recently edited pages of a database and caches
+// their metadata. It returns the IDs of the pages found, most recent first.
+func (p *Prefetcher) WarmDatabase(ctx context.Context, databaseID string, limit int) ([]string, error) {
+	resp, err := p.fetcher.QueryDatabase(ctx, databaseID, &notionapi.DatabaseQueryRequest{
+		Sorts: []notionapi.SortObject{
+			{
+				Timestamp: notionapi.TimestampLastEdited,
+				Direction: notionapi.SortOrderDESC,
+			},
+		},
+		PageSize: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("warm database %s: %w", databaseID, err)
+	}
+
+	ids := make([]string, 0, len(resp.Results))
+	for i := range resp.Results {
+		page := &resp.Results[i]
+		id := string(page.ID)
+		ids = append(ids, id)
+
+		if err := p.cache.Set(ctx, cache.SetInput{
+			PageID: cache.MetaKey(id),
+			Data:   page,
+			TTL:    p.ttl,
+		}); err != nil {
+			return nil, fmt.Errorf("cache page %s: %w", id, err)
+		}
+	}
+
+	return ids, nil
+}
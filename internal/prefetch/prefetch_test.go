@@ -0,0 +1,255 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+func newTestPrefetcher(t *testing.T, client *testhelpers.MockNotionClient) (*Prefetcher, *cache.PageCache) {
+	t.Helper()
+
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	p, err := NewPrefetcher(NewPrefetcherInput{
+		Fetcher:     client,
+		Cache:       pc,
+		HistorySize: 5,
+	})
+	require.NoError(t, err)
+	return p, pc
+}
+
+func newWarmClient() *testhelpers.MockNotionClient {
+	client := testhelpers.NewMockNotionClient()
+	client.GetPageFunc = func(ctx context.Context, id string) (*notionapi.Page, error) {
+		return testhelpers.NewTestPage(id, "Page "+id), nil
+	}
+	client.GetBlocksFunc = func(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
+		return testhelpers.NewGetChildrenResponse(testhelpers.NewTestBlockList(2)), nil
+	}
+	return client
+}
+
+func TestNewPrefetcher(t *testing.T) {
+	t.Parallel()
+
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		input   NewPrefetcherInput
+		wantErr bool
+	}{
+		{name: "valid", input: NewPrefetcherInput{Fetcher: testhelpers.NewMockNotionClient(), Cache: pc}},
+		{name: "nil fetcher", input: NewPrefetcherInput{Cache: pc}, wantErr: true},
+		{name: "nil cache", input: NewPrefetcherInput{Fetcher: testhelpers.NewMockNotionClient()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewPrefetcher(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, cache.DefaultPageTTL, p.ttl)
+		})
+	}
+}
+
+func TestWarmPage(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmClient()
+	p, pc := newTestPrefetcher(t, client)
+	ctx := context.Background()
+
+	warmed, err := p.WarmPage(ctx, "page-1")
+	require.NoError(t, err)
+	assert.True(t, warmed)
+	assert.True(t, pc.Has(ctx, "page-1"))
+	assert.True(t, pc.Has(ctx, cache.MetaKey("page-1")))
+
+	// Already fresh: no further API calls.
+	warmed, err = p.WarmPage(ctx, "page-1")
+	require.NoError(t, err)
+	assert.False(t, warmed)
+	assert.Equal(t, 1, client.GetPageCallCount())
+	assert.Equal(t, 1, client.GetBlocksCallCount())
+}
+
+func TestWarmPageError(t *testing.T) {
+	t.Parallel()
+
+	client := testhelpers.NewMockNotionClient().WithError(errors.New("boom"))
+	p, pc := newTestPrefetcher(t, client)
+
+	_, err := p.WarmPage(context.Background(), "page-1")
+	assert.Error(t, err)
+	assert.False(t, pc.Has(context.Background(), "page-1"))
+}
+
+func TestWarmDatabase(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmClient()
+	client.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		return &notionapi.DatabaseQueryResponse{
+			Results: []notionapi.Page{
+				*testhelpers.NewTestPage("page-new", "Newest"),
+				*testhelpers.NewTestPage("page-old", "Older"),
+			},
+		}, nil
+	}
+	p, pc := newTestPrefetcher(t, client)
+	ctx := context.Background()
+
+	ids, err := p.WarmDatabase(ctx, "db-1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page-new", "page-old"}, ids)
+	assert.True(t, pc.Has(ctx, cache.MetaKey("page-new")))
+
+	req := client.LastQueryDatabaseCall().Request
+	assert.Equal(t, 2, req.PageSize)
+	require.Len(t, req.Sorts, 1)
+	assert.Equal(t, notionapi.TimestampLastEdited, req.Sorts[0].Timestamp)
+	assert.Equal(t, notionapi.SortOrderDESC, req.Sorts[0].Direction)
+}
+
+func TestDrainOrdersBySourceAndUsesIdlePriority(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var order []string
+	client := newWarmClient()
+	client.GetBlocksFunc = func(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
+		assert.Equal(t, notion.PriorityIdle, notion.PriorityFrom(ctx))
+		mu.Lock()
+		order = append(order, id)
+		mu.Unlock()
+		return testhelpers.NewGetChildrenResponse(nil), nil
+	}
+	client.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		assert.Equal(t, notion.PriorityIdle, notion.PriorityFrom(ctx))
+		return &notionapi.DatabaseQueryResponse{
+			Results: []notionapi.Page{*testhelpers.NewTestPage("recent-1", "Recent")},
+		}, nil
+	}
+
+	p, _ := newTestPrefetcher(t, client)
+	require.NoError(t, p.RecordVisit("history-1"))
+
+	p.QueueDatabase("db-1", 5)
+	p.QueueHistory()
+	p.QueueVisible([]string{"visible-1", "visible-2"})
+
+	require.NoError(t, p.Drain(context.Background()))
+	assert.Equal(t, []string{"visible-1", "visible-2", "history-1", "recent-1"}, order)
+
+	stats := p.Stats()
+	assert.Equal(t, 5, stats.Warmed)
+	assert.Zero(t, stats.Queued)
+}
+
+func TestQueueVisibleReplacesPreviousRows(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newTestPrefetcher(t, newWarmClient())
+	p.QueueVisible([]string{"a", "b"})
+	p.QueueVisible([]string{"b", "c"})
+	p.QueueVisible([]string{"c"})
+
+	require.Len(t, p.queue, 1)
+	assert.Equal(t, "c", p.queue[0].pageID)
+}
+
+func TestRateLimitPausesAndRequeues(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmClient()
+	client.GetPageFunc = func(ctx context.Context, id string) (*notionapi.Page, error) {
+		return nil, &notionapi.RateLimitedError{Message: "Retry request with 429 response failed after 5 retries"}
+	}
+	p, _ := newTestPrefetcher(t, client)
+	p.QueueVisible([]string{"page-1"})
+
+	j, _, ok := p.next()
+	require.True(t, ok)
+	p.process(context.Background(), j)
+
+	stats := p.Stats()
+	assert.Equal(t, 1, stats.Queued, "rate-limited job is requeued")
+	assert.Zero(t, stats.Failed)
+	assert.WithinDuration(t, time.Now().Add(initialBackoff), stats.PausedUntil, time.Second)
+
+	_, wait, ok := p.next()
+	assert.False(t, ok, "paused prefetcher hands out no work")
+	assert.Greater(t, wait, time.Duration(0))
+
+	// Backoff grows on repeated rate limits.
+	p.Resume()
+	j, _, ok = p.next()
+	require.True(t, ok)
+	p.process(context.Background(), j)
+	p.process(context.Background(), j)
+	assert.Equal(t, 2*initialBackoff, p.backoff)
+}
+
+func TestFailedJobsAreCounted(t *testing.T) {
+	t.Parallel()
+
+	client := testhelpers.NewMockNotionClient().WithError(errors.New("not found"))
+	p, _ := newTestPrefetcher(t, client)
+	p.QueueVisible([]string{"page-1"})
+
+	require.NoError(t, p.Drain(context.Background()))
+	assert.Equal(t, 1, p.Stats().Failed)
+}
+
+func TestStartStop(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmClient()
+	p, pc := newTestPrefetcher(t, client)
+
+	p.Start()
+	p.Start() // idempotent
+	p.QueueVisible([]string{"page-1"})
+
+	assert.Eventually(t, func() bool {
+		return pc.Has(context.Background(), "page-1")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	p.Stop()
+	p.Stop() // idempotent
+}
+
+func TestDrainCancelledWhilePaused(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newTestPrefetcher(t, newWarmClient())
+	p.QueueVisible([]string{"page-1"})
+	p.Pause(time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, p.Drain(ctx))
+	assert.Equal(t, 1, p.Stats().Queued)
+}
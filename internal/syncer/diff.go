@@ -0,0 +1,345 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// blockHash identifies the content of a block tree by its Markdown. The
+// Markdown is read back and rendered again first, so that a block pulled
+// from Notion and the same block parsed from the file hash alike even when
+// the file spells it differently, such as "*" for "-" bullets. Blocks
+// without a Markdown form hash to "".
+func blockHash(node notion.BlockNode) string {
+	md, err := notion.ConvertBlockTreeToMarkdown([]notion.BlockNode{node})
+	if err != nil || strings.TrimSpace(md) == "" {
+		return ""
+	}
+	canonical, err := notion.ConvertBlockTreeToMarkdown(notion.ParseMarkdown(md))
+	if err != nil {
+		return ""
+	}
+	return hash([]byte(canonical))[:16]
+}
+
+// errPartEdited is returned by diffBlocks when the file changes the content
+// of a block that has no Markdown form of its own. Such a block cannot be
+// rewritten from the file without losing its own text, such as a toggle's
+// title.
+var errPartEdited = errors.New("the content of a toggle, column or synced block was edited; edit it in Notion")
+
+// blockStates records the top-level blocks of a page.
+func blockStates(nodes []notion.BlockNode) []blockState {
+	states := make([]blockState, 0, len(nodes))
+	for _, node := range nodes {
+		id, blockType := string(node.Block.GetID()), node.Block.GetType()
+		nested := len(node.Children) > 0 || notion.HasChildren(node.Block)
+
+		var parts []notion.BlockNode
+		own := true
+		if len(node.Children) > 0 {
+			parts, own = markdownParts(node)
+		}
+		if own || len(parts) == 0 {
+			h := blockHash(node)
+			states = append(states, blockState{
+				ID:     id,
+				Type:   blockType,
+				Hash:   h,
+				Nested: nested,
+				Keep:   h == "" || keepBlock(node.Block),
+			})
+			continue
+		}
+
+		// The block reads back as the blocks inside it. They are recorded
+		// one by one so that they match the file, and the block is kept.
+		for _, part := range parts {
+			states = append(states, blockState{
+				ID:     id,
+				Type:   blockType,
+				Hash:   blockHash(part),
+				Nested: nested,
+				Keep:   true,
+				Part:   true,
+			})
+		}
+	}
+	return states
+}
+
+// markdownParts returns the top-level blocks the Markdown of a block with
+// children reads back as, and whether they are the block itself. Blocks
+// without a Markdown form of their own, such as toggles, columns and synced
+// blocks, read back as the blocks inside them.
+func markdownParts(node notion.BlockNode) ([]notion.BlockNode, bool) {
+	md, err := notion.ConvertBlockTreeToMarkdown([]notion.BlockNode{node})
+	if err != nil || strings.TrimSpace(md) == "" {
+		return nil, false
+	}
+	parts := notion.ParseMarkdown(md)
+	return parts, len(parts) == 1 && parts[0].Block.GetType() == node.Block.GetType()
+}
+
+// keepBlock reports whether a push must leave a block alone even when its
+// line is gone from the file. Removing a child page or database would delete
+// it with everything below it, and uploaded files could not be put back.
+func keepBlock(block notionapi.Block) bool {
+	switch b := block.(type) {
+	case *notionapi.ChildPageBlock, *notionapi.ChildDatabaseBlock:
+		return true
+	case *notionapi.ImageBlock:
+		return b.Image.Type == notionapi.FileTypeFile
+	case *notionapi.FileBlock:
+		return b.File.Type == notionapi.FileTypeFile
+	case *notionapi.PdfBlock:
+		return b.Pdf.Type == notionapi.FileTypeFile
+	default:
+		return false
+	}
+}
+
+// opKind is what a push does with a block.
+type opKind int
+
+const (
+	opKeep   opKind = iota // the block is unchanged
+	opUpdate               // the block's text is replaced in place
+	opDelete               // the block is removed
+	opInsert               // a new block is added
+)
+
+// op is one step of a block diff. base is set for every kind but opInsert
+// and node for every kind but opDelete.
+type op struct {
+	kind opKind
+	base *blockState
+	node *notion.BlockNode
+}
+
+// diffBlocks returns the steps that turn the blocks of the last sync into
+// the blocks of the file. Unchanged blocks are found as the longest common
+// subsequence of hashes; in each stretch of changes in between, removed and
+// added blocks of the same type are paired into in-place updates. It returns
+// errPartEdited when a stretch replaces content of a block without a Markdown
+// form of its own.
+func diffBlocks(base []blockState, local []notion.BlockNode) ([]op, error) {
+	hashes := make([]string, len(local))
+	for i, node := range local {
+		hashes[i] = blockHash(node)
+	}
+
+	// lcs[i][j] is the length of the common subsequence of base[i:] and local[j:]
+	lcs := make([][]int, len(base)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(local)+1)
+	}
+	for i := len(base) - 1; i >= 0; i-- {
+		for j := len(local) - 1; j >= 0; j-- {
+			switch {
+			case base[i].Hash != "" && base[i].Hash == hashes[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []op
+	var removed []*blockState
+	var added []*notion.BlockNode
+	var partEdited bool
+	flush := func() {
+		// Blocks that must stay go after the changes around them
+		var kept []*blockState
+		n := 0
+		for _, b := range removed {
+			if b.Keep {
+				partEdited = partEdited || (b.Part && len(added) > 0)
+				kept = append(kept, b)
+			} else {
+				removed[n] = b
+				n++
+			}
+		}
+		removed = removed[:n]
+
+		for k := 0; k < max(len(removed), len(added)); k++ {
+			if k < len(removed) && k < len(added) && updatable(removed[k], added[k]) {
+				ops = append(ops, op{kind: opUpdate, base: removed[k], node: added[k]})
+				continue
+			}
+			if k < len(removed) {
+				ops = append(ops, op{kind: opDelete, base: removed[k]})
+			}
+			if k < len(added) {
+				ops = append(ops, op{kind: opInsert, node: added[k]})
+			}
+		}
+		for _, b := range kept {
+			ops = append(ops, op{kind: opKeep, base: b})
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(base) || j < len(local) {
+		switch {
+		case i < len(base) && j < len(local) && base[i].Hash != "" && base[i].Hash == hashes[j]:
+			flush()
+			ops = append(ops, op{kind: opKeep, base: &base[i], node: &local[j]})
+			i++
+			j++
+		case j == len(local) || (i < len(base) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, &base[i])
+			i++
+		default:
+			added = append(added, &local[j])
+			j++
+		}
+	}
+	flush()
+	if partEdited {
+		return nil, errPartEdited
+	}
+	return ops, nil
+}
+
+// updatable reports whether a block can be changed into node in place.
+func updatable(base *blockState, node *notion.BlockNode) bool {
+	return !base.Keep && !base.Nested && len(node.Children) == 0 &&
+		base.Type == node.Block.GetType() && updateRequest(node.Block) != nil
+}
+
+// updateRequest returns the request that gives a block the content of block,
+// or nil for types that are not updated in place.
+func updateRequest(block notionapi.Block) *notionapi.BlockUpdateRequest {
+	switch b := block.(type) {
+	case *notionapi.ParagraphBlock:
+		return &notionapi.BlockUpdateRequest{Paragraph: &b.Paragraph}
+	case *notionapi.Heading1Block:
+		return &notionapi.BlockUpdateRequest{Heading1: &b.Heading1}
+	case *notionapi.Heading2Block:
+		return &notionapi.BlockUpdateRequest{Heading2: &b.Heading2}
+	case *notionapi.Heading3Block:
+		return &notionapi.BlockUpdateRequest{Heading3: &b.Heading3}
+	case *notionapi.BulletedListItemBlock:
+		return &notionapi.BlockUpdateRequest{BulletedListItem: &b.BulletedListItem}
+	case *notionapi.NumberedListItemBlock:
+		return &notionapi.BlockUpdateRequest{NumberedListItem: &b.NumberedListItem}
+	case *notionapi.ToDoBlock:
+		return &notionapi.BlockUpdateRequest{ToDo: &b.ToDo}
+	case *notionapi.QuoteBlock:
+		return &notionapi.BlockUpdateRequest{Quote: &b.Quote}
+	case *notionapi.CodeBlock:
+		return &notionapi.BlockUpdateRequest{Code: &b.Code}
+	default:
+		return nil
+	}
+}
+
+// pushBlocks applies a block diff to a page. The API can only add blocks
+// after an existing one, so when new blocks come before every block that
+// stays, the page body is replaced instead.
+func (s *Syncer) pushBlocks(ctx context.Context, pageID string, ops []op) error {
+	if prependsBlocks(ops) {
+		return s.replaceBlocks(ctx, pageID, ops)
+	}
+
+	var anchor string
+	var pending []notion.BlockNode
+	insert := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		created, err := notion.InsertBlockTree(ctx, s.client, pageID, anchor, pending)
+		if err != nil {
+			return fmt.Errorf("add blocks: %w", err)
+		}
+		anchor = string(created[len(created)-1].GetID())
+		pending = nil
+		return nil
+	}
+
+	for _, o := range ops {
+		if o.kind == opInsert {
+			pending = append(pending, *o.node)
+			continue
+		}
+		if err := insert(); err != nil {
+			return err
+		}
+
+		switch o.kind {
+		case opKeep:
+			anchor = o.base.ID
+		case opUpdate:
+			if _, err := s.client.UpdateBlock(ctx, o.base.ID, updateRequest(o.node.Block)); err != nil {
+				return fmt.Errorf("update block: %w", err)
+			}
+			anchor = o.base.ID
+		case opDelete:
+			if err := s.deleteBlock(ctx, o.base.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return insert()
+}
+
+// prependsBlocks reports whether a diff adds blocks in front of the first
+// block that stays.
+func prependsBlocks(ops []op) bool {
+	inserting := false
+	for _, o := range ops {
+		switch o.kind {
+		case opInsert:
+			inserting = true
+		case opKeep, opUpdate:
+			return inserting
+		}
+	}
+	return false
+}
+
+// replaceBlocks removes every block of a page that may be removed and adds
+// the blocks of the file after those that remain.
+func (s *Syncer) replaceBlocks(ctx context.Context, pageID string, ops []op) error {
+	var nodes []notion.BlockNode
+	for _, o := range ops {
+		switch {
+		case o.kind == opKeep && o.base.Keep:
+			// The block stays, so its line must not be added a second time
+		case o.base != nil && !o.base.Keep:
+			if err := s.deleteBlock(ctx, o.base.ID); err != nil {
+				return err
+			}
+			if o.node != nil {
+				nodes = append(nodes, *o.node)
+			}
+		case o.node != nil:
+			nodes = append(nodes, *o.node)
+		}
+	}
+
+	if _, err := notion.AppendBlockTree(ctx, s.client, pageID, nodes); err != nil {
+		return fmt.Errorf("add blocks: %w", err)
+	}
+	return nil
+}
+
+// deleteBlock removes a block. A block that is already gone is not an error.
+func (s *Syncer) deleteBlock(ctx context.Context, id string) error {
+	if _, err := s.client.DeleteBlock(ctx, id); err != nil && notion.ClassifyError(err) != notion.ErrorClassNotFound {
+		return fmt.Errorf("remove block: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+// baseBlocks returns the state of a page with a heading, two paragraphs and a
+// child page, with IDs "b0" to "b3".
+func baseBlocks() []blockState {
+	heading := testhelpers.NewHeading1Block("Plan")
+	heading.ID = "b0"
+	first := testhelpers.NewParagraphBlock("First step.")
+	first.ID = "b1"
+	second := testhelpers.NewParagraphBlock("Second step.")
+	second.ID = "b2"
+	childPage := &notionapi.ChildPageBlock{
+		BasicBlock: notionapi.BasicBlock{ID: "b3", Type: notionapi.BlockTypeChildPage},
+	}
+	childPage.ChildPage.Title = "Notes"
+
+	return blockStates(notion.NewBlockNodes([]notionapi.Block{heading, first, second, childPage}))
+}
+
+// kinds returns the kind of each op with the ID of its base block, if any.
+func kinds(ops []op) []string {
+	names := map[opKind]string{opKeep: "keep", opUpdate: "update", opDelete: "delete", opInsert: "insert"}
+	var got []string
+	for _, o := range ops {
+		name := names[o.kind]
+		if o.base != nil {
+			name += " " + o.base.ID
+		}
+		got = append(got, name)
+	}
+	return got
+}
+
+func TestBlockStates(t *testing.T) {
+	t.Parallel()
+
+	states := baseBlocks()
+	require.Len(t, states, 4)
+	assert.Equal(t, notionapi.BlockTypeHeading1, states[0].Type)
+	assert.NotEmpty(t, states[1].Hash)
+	assert.False(t, states[1].Keep)
+	assert.True(t, states[3].Keep, "child pages are never removed")
+
+	bullets := blockStates(notion.ParseMarkdown("* one\n"))
+	dashes := blockStates(notion.ParseMarkdown("- one\n"))
+	assert.Equal(t, bullets[0].Hash, dashes[0].Hash, "hashes ignore how the Markdown is spelled")
+}
+
+func TestDiffBlocks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+		prepends bool
+	}{
+		{
+			name:     "unchanged",
+			markdown: "# Plan\n\nFirst step.\n\nSecond step.\n\n[Notes](https://www.notion.so/b3)\n",
+			want:     []string{"keep b0", "keep b1", "keep b2", "keep b3"},
+		},
+		{
+			name:     "edited paragraph",
+			markdown: "# Plan\n\nFirst step, revised.\n\nSecond step.\n",
+			want:     []string{"keep b0", "update b1", "keep b2", "keep b3"},
+		},
+		{
+			name:     "inserted and removed",
+			markdown: "# Plan\n\nSecond step.\n\n- a new item\n",
+			want:     []string{"keep b0", "delete b1", "keep b2", "insert", "keep b3"},
+		},
+		{
+			name:     "changed type",
+			markdown: "# Plan\n\n> First step.\n\nSecond step.\n",
+			want:     []string{"keep b0", "delete b1", "insert", "keep b2", "keep b3"},
+		},
+		{
+			name:     "new first block",
+			markdown: "Intro.\n\n# Plan\n\nFirst step.\n\nSecond step.\n",
+			want:     []string{"insert", "keep b0", "keep b1", "keep b2", "keep b3"},
+			prepends: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, err := diffBlocks(baseBlocks(), notion.ParseMarkdown(tt.markdown))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, kinds(ops))
+			assert.Equal(t, tt.prepends, prependsBlocks(ops))
+		})
+	}
+}
+
+func TestPushBlocks(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testhelpers.NewMockNotionClient()
+	s, err := NewSyncer(NewSyncerInput{Client: mockClient, Dir: t.TempDir(), DatabaseID: "db"})
+	require.NoError(t, err)
+
+	ops, err := diffBlocks(baseBlocks(), notion.ParseMarkdown("# Plan\n\nFirst step, revised.\n\n- new\n- items\n"))
+	require.NoError(t, err)
+	require.NoError(t, s.pushBlocks(context.Background(), "page", ops))
+
+	require.Equal(t, 1, mockClient.UpdateBlockCallCount())
+	update := mockClient.UpdateBlockCalls[0]
+	assert.Equal(t, "b1", update.ID)
+	assert.Equal(t, "First step, revised.", notion.GetRichTextString(update.Request.Paragraph.RichText))
+
+	require.Equal(t, 1, mockClient.DeleteBlockCallCount())
+	assert.Equal(t, "b2", mockClient.DeleteBlockCalls[0].ID)
+
+	require.Equal(t, 1, mockClient.AppendBlocksCallCount())
+	insert := mockClient.AppendBlocksCalls[0]
+	assert.Equal(t, notionapi.BlockID("b1"), insert.Request.After, "new blocks go where the removed ones were")
+	assert.Len(t, insert.Request.Children, 2)
+}
+
+func TestPushBlocksReplacesPrependedBody(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testhelpers.NewMockNotionClient()
+	s, err := NewSyncer(NewSyncerInput{Client: mockClient, Dir: t.TempDir(), DatabaseID: "db"})
+	require.NoError(t, err)
+
+	ops, err := diffBlocks(baseBlocks(), notion.ParseMarkdown("Intro.\n\n# Plan\n\nFirst step.\n\nSecond step.\n"))
+	require.NoError(t, err)
+	require.NoError(t, s.pushBlocks(context.Background(), "page", ops))
+
+	var deleted []string
+	for _, call := range mockClient.DeleteBlockCalls {
+		deleted = append(deleted, call.ID)
+	}
+	assert.Equal(t, []string{"b0", "b1", "b2"}, deleted, "the child page stays")
+	require.Equal(t, 1, mockClient.AppendBlocksCallCount())
+	assert.Empty(t, mockClient.AppendBlocksCalls[0].Request.After)
+	assert.Len(t, mockClient.AppendBlocksCalls[0].Request.Children, 4)
+}
+
+// toggleBlocks returns the state of a page with a paragraph "p1" and a toggle
+// "t1" holding two paragraphs. The toggle's title has no Markdown form.
+func toggleBlocks() []blockState {
+	intro := testhelpers.NewParagraphBlock("Intro.")
+	intro.ID = "p1"
+	toggle := testhelpers.NewToggleBlock("Details")
+	toggle.ID = "t1"
+	toggle.HasChildren = true
+
+	return blockStates([]notion.BlockNode{
+		{Block: intro},
+		{Block: toggle, Children: notion.NewBlockNodes([]notionapi.Block{
+			testhelpers.NewParagraphBlock("First detail."),
+			testhelpers.NewParagraphBlock("Second detail."),
+		})},
+	})
+}
+
+func TestPushBlocksKeepsToggles(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testhelpers.NewMockNotionClient()
+	s, err := NewSyncer(NewSyncerInput{Client: mockClient, Dir: t.TempDir(), DatabaseID: "db"})
+	require.NoError(t, err)
+
+	// Only the paragraph before the toggle was edited
+	ops, err := diffBlocks(toggleBlocks(), notion.ParseMarkdown("Intro, revised.\n\nFirst detail.\n\nSecond detail.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"update p1", "keep t1", "keep t1"}, kinds(ops))
+	require.NoError(t, s.pushBlocks(context.Background(), "page", ops))
+
+	assert.Equal(t, 1, mockClient.UpdateBlockCallCount())
+	assert.Zero(t, mockClient.DeleteBlockCallCount(), "the toggle stays")
+	assert.Zero(t, mockClient.AppendBlocksCallCount())
+}
+
+func TestDiffBlocksRefusesEditsInsideToggles(t *testing.T) {
+	t.Parallel()
+
+	_, err := diffBlocks(toggleBlocks(), notion.ParseMarkdown("Intro.\n\nFirst detail, revised.\n\nSecond detail.\n"))
+	assert.ErrorIs(t, err, errPartEdited)
+
+	// Removing the toggle's content leaves the toggle alone
+	ops, err := diffBlocks(toggleBlocks(), notion.ParseMarkdown("Intro.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keep p1", "keep t1", "keep t1"}, kinds(ops))
+}
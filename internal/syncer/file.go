@@ -0,0 +1,66 @@
+package syncer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Panandika/notion-tui/internal/export"
+)
+
+// conflictSuffix marks the copy of the remote version written beside a file
+// when both sides changed.
+const conflictSuffix = ".remote.md"
+
+// document is a synced file split into its front matter, in the format
+// written by `export`, and its Markdown body.
+type document struct {
+	export.FrontMatter
+
+	body string
+}
+
+// parseDocument parses a synced file. A file without front matter is all body.
+func parseDocument(data []byte) (document, error) {
+	front, body, err := export.ParseFrontMatter(data)
+	if err != nil {
+		return document{}, err
+	}
+	return document{FrontMatter: front, body: body}, nil
+}
+
+// conflictPath returns the path of the remote copy kept for a file in conflict.
+func conflictPath(rel string) string {
+	return strings.TrimSuffix(rel, ".md") + conflictSuffix
+}
+
+// localFiles returns the Markdown files at the top of dir, sorted, leaving
+// out hidden files and the remote copies of files in conflict.
+func localFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir(), strings.HasPrefix(name, "."):
+		case !strings.EqualFold(filepath.Ext(name), ".md"):
+		case strings.HasSuffix(name, conflictSuffix):
+		default:
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// exists reports whether a file exists.
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
@@ -0,0 +1,110 @@
+package syncer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+// stateName is the file in the sync directory that records the last sync.
+const stateName = ".notion-sync.json"
+
+// state records what each file looked like after the last sync, on both
+// sides, so the next sync can tell which side changed.
+type state struct {
+	DatabaseID string                `json:"database_id"`
+	Pages      map[string]*pageState `json:"pages"` // keyed by normalized page ID
+
+	path string
+}
+
+// pageState is a synced page. Path is relative to the sync directory and
+// uses forward slashes.
+type pageState struct {
+	Path           string       `json:"path"`
+	Hash           string       `json:"hash"` // of the file as last written or pushed
+	LastEditedTime time.Time    `json:"last_edited_time"`
+	Blocks         []blockState `json:"blocks"`
+	// Conflict is set while a copy of the remote version sits beside the file.
+	// The sync is resumed once that copy is deleted.
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// blockState is a top-level block of a page as of the last sync. Local edits
+// are found by comparing the hashes of the blocks in the file with these.
+type blockState struct {
+	ID     string              `json:"id"`
+	Type   notionapi.BlockType `json:"type"`
+	Hash   string              `json:"hash"`
+	Nested bool                `json:"nested,omitempty"` // the block has children
+	// Keep is set for blocks a push never removes: child pages and
+	// databases, uploaded files and blocks without a Markdown form.
+	Keep bool `json:"keep,omitempty"`
+	// Part is set when the block has no Markdown form of its own, such as a
+	// toggle, and the entry is one of the blocks its content reads back as.
+	// Such a block has an entry for each of them, all with its ID.
+	Part bool `json:"part,omitempty"`
+}
+
+// loadState reads the sync state of a directory. A missing state starts an
+// empty one; a corrupt one is an error, since every file would look new.
+func loadState(dir, databaseID string) (*state, error) {
+	s := &state{path: filepath.Join(dir, stateName)}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("parse sync state %s: %w", s.path, err)
+		}
+	}
+
+	if s.DatabaseID != "" && s.DatabaseID != databaseID {
+		return nil, fmt.Errorf("%s is synced with database %s, not %s", dir, s.DatabaseID, databaseID)
+	}
+	s.DatabaseID = databaseID
+	if s.Pages == nil {
+		s.Pages = make(map[string]*pageState)
+	}
+	return s, nil
+}
+
+// save writes the state. It is called after every file so that an
+// interrupted sync does not repeat work on the next run.
+func (s *state) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sync state: %w", err)
+	}
+	if err := fsutil.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	return nil
+}
+
+// tracked returns the page synced to a file, if any.
+func (s *state) tracked(rel string) (string, *pageState) {
+	for id, entry := range s.Pages {
+		if entry.Path == rel {
+			return id, entry
+		}
+	}
+	return "", nil
+}
+
+// hash returns the hex SHA-256 of data.
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,569 @@
+// Package syncer keeps a Notion database and a directory of Markdown files in
+// step. Each row of the database is a file in the format written by `export`.
+// A state file in the directory records, for every file, the hash it had and
+// the time its page was last edited after the previous sync, so each sync can
+// tell which side changed: remote edits are pulled, local edits are pushed as
+// a diff of the page's blocks, and when both sides changed the remote version
+// is kept beside the file for the user to merge.
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/export"
+	"github.com/Panandika/notion-tui/internal/fsutil"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// queryPageSize is the largest page size accepted by database queries.
+const queryPageSize = 100
+
+// Client is the subset of the Notion client used to sync a database.
+type Client interface {
+	notion.BlockFetcher
+	notion.BlockAppender
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
+	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
+	GetPage(ctx context.Context, id string) (*notionapi.Page, error)
+	CreatePage(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error)
+	UpdatePage(ctx context.Context, id string, req *notionapi.PageUpdateRequest) (*notionapi.Page, error)
+	UpdateBlock(ctx context.Context, id string, req *notionapi.BlockUpdateRequest) (notionapi.Block, error)
+	DeleteBlock(ctx context.Context, id string) (notionapi.Block, error)
+}
+
+// Cache receives the pages a sync fetches, so the TUI shows them without
+// fetching them again.
+type Cache interface {
+	Set(ctx context.Context, input cache.SetInput) error
+}
+
+// Action is what a sync did with a file.
+type Action string
+
+const (
+	ActionPulled   Action = "pulled"   // the file was written from Notion
+	ActionPushed   Action = "pushed"   // local edits were sent to Notion
+	ActionCreated  Action = "created"  // a new file became a new row
+	ActionRemoved  Action = "removed"  // the row is gone, so the file was removed
+	ActionConflict Action = "conflict" // both sides changed
+)
+
+// Change is a file a sync acted on.
+type Change struct {
+	Path   string // relative to the sync directory
+	Action Action
+}
+
+// Result summarizes a sync.
+type Result struct {
+	Changes  []Change
+	Warnings []string
+}
+
+// Count returns the number of changes with the given action.
+func (r Result) Count(action Action) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Syncer syncs one database with one directory.
+type Syncer struct {
+	client     Client
+	cache      Cache
+	dir        string
+	databaseID string
+	now        func() time.Time
+}
+
+// NewSyncerInput contains the parameters for creating a Syncer.
+type NewSyncerInput struct {
+	Client     Client
+	Cache      Cache // optional
+	Dir        string
+	DatabaseID string
+	// Now returns the time relative dates in front matter are read against.
+	// The default is time.Now.
+	Now func() time.Time
+}
+
+// NewSyncer creates a Syncer. The directory is created when missing.
+func NewSyncer(input NewSyncerInput) (*Syncer, error) {
+	if input.Client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	if input.Dir == "" {
+		return nil, fmt.Errorf("directory cannot be empty")
+	}
+	if input.DatabaseID == "" {
+		return nil, fmt.Errorf("database ID cannot be empty")
+	}
+	if err := os.MkdirAll(input.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	now := input.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Syncer{
+		client:     input.Client,
+		cache:      input.Cache,
+		dir:        input.Dir,
+		databaseID: notion.NormalizeID(input.DatabaseID),
+		now:        now,
+	}, nil
+}
+
+// Sync brings the directory and the database in step once.
+func (s *Syncer) Sync(ctx context.Context) (Result, error) {
+	var result Result
+
+	st, err := loadState(s.dir, s.databaseID)
+	if err != nil {
+		return result, err
+	}
+	db, err := s.client.GetDatabase(ctx, s.databaseID)
+	if err != nil {
+		return result, fmt.Errorf("get database: %w", err)
+	}
+	rows, err := s.queryRows(ctx)
+	if err != nil {
+		return result, fmt.Errorf("query database: %w", err)
+	}
+
+	remote := make(map[string]bool, len(rows))
+	for i := range rows {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		row := &rows[i]
+		remote[notion.NormalizeID(string(row.ID))] = true
+		if err := s.syncRow(ctx, st, db, row, &result); err != nil {
+			return result, err
+		}
+	}
+
+	if err := s.removeDeleted(st, remote, &result); err != nil {
+		return result, err
+	}
+	if err := s.createNew(ctx, st, db, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// queryRows returns every row of the database.
+func (s *Syncer) queryRows(ctx context.Context) ([]notionapi.Page, error) {
+	var rows []notionapi.Page
+	req := &notionapi.DatabaseQueryRequest{PageSize: queryPageSize}
+	for {
+		resp, err := s.client.QueryDatabase(ctx, s.databaseID, req)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == "" {
+			return rows, nil
+		}
+		req.StartCursor = resp.NextCursor
+	}
+}
+
+// syncRow compares a row with its file and pulls, pushes or reports a
+// conflict as needed.
+func (s *Syncer) syncRow(ctx context.Context, st *state, db *notionapi.Database, row *notionapi.Page, result *Result) error {
+	entry := st.Pages[notion.NormalizeID(string(row.ID))]
+	if entry == nil {
+		return s.pull(ctx, st, row, result)
+	}
+
+	data, err := os.ReadFile(s.abs(entry.Path))
+	if errors.Is(err, os.ErrNotExist) {
+		// Deleting a file does not delete the page; it is fetched again
+		return s.pull(ctx, st, row, result)
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", entry.Path, err)
+	}
+
+	if entry.Conflict {
+		if exists(s.abs(conflictPath(entry.Path))) {
+			result.Changes = append(result.Changes, Change{Path: entry.Path, Action: ActionConflict})
+			return nil
+		}
+		// The remote copy was deleted, so the file holds the merged version
+		entry.Conflict = false
+	}
+
+	localChanged := hash(data) != entry.Hash
+	remoteChanged := !row.LastEditedTime.Equal(entry.LastEditedTime)
+	switch {
+	case localChanged && remoteChanged:
+		return s.conflict(ctx, st, row, entry, result)
+	case localChanged:
+		return s.push(ctx, st, db, row, entry, data, result)
+	case remoteChanged:
+		return s.pull(ctx, st, row, result)
+	default:
+		return nil
+	}
+}
+
+// pull writes a row to its file.
+func (s *Syncer) pull(ctx context.Context, st *state, row *notionapi.Page, result *Result) error {
+	key := notion.NormalizeID(string(row.ID))
+	nodes, content, err := s.render(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	rel := fileName(row)
+	if err := fsutil.WriteFile(s.abs(rel), content, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", rel, err)
+	}
+	if previous := st.Pages[key]; previous != nil && previous.Path != rel {
+		// The page was renamed; the old file would be a stale duplicate
+		_ = os.Remove(s.abs(previous.Path))
+	}
+
+	st.Pages[key] = &pageState{
+		Path:           rel,
+		Hash:           hash(content),
+		LastEditedTime: row.LastEditedTime,
+		Blocks:         blockStates(nodes),
+	}
+	if err := st.save(); err != nil {
+		return err
+	}
+	result.Changes = append(result.Changes, Change{Path: rel, Action: ActionPulled})
+	return nil
+}
+
+// conflict keeps the remote version of a row beside its file. The state now
+// refers to that version, so once the copy is deleted the file is pushed as
+// an edit of it.
+func (s *Syncer) conflict(ctx context.Context, st *state, row *notionapi.Page, entry *pageState, result *Result) error {
+	nodes, content, err := s.render(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	copyPath := conflictPath(entry.Path)
+	if err := fsutil.WriteFile(s.abs(copyPath), content, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", copyPath, err)
+	}
+	entry.Conflict = true
+	entry.Hash = "" // whatever the file holds once resolved is pushed
+	entry.LastEditedTime = row.LastEditedTime
+	entry.Blocks = blockStates(nodes)
+	if err := st.save(); err != nil {
+		return err
+	}
+
+	result.Changes = append(result.Changes, Change{Path: entry.Path, Action: ActionConflict})
+	return nil
+}
+
+// push sends the local edits of a file to its row: changed properties and
+// title, and a diff of the blocks against those of the last sync.
+func (s *Syncer) push(ctx context.Context, st *state, db *notionapi.Database, row *notionapi.Page, entry *pageState, data []byte, result *Result) error {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", entry.Path, err)
+	}
+
+	// The blocks are compared first, so a file that cannot be pushed leaves
+	// the page as it was
+	ops, err := diffBlocks(entry.Blocks, notion.ParseMarkdown(doc.body))
+	if err != nil {
+		return fmt.Errorf("push %s: %w", entry.Path, err)
+	}
+
+	properties, err := s.properties(db, row, doc, entry.Path, result)
+	if err != nil {
+		return err
+	}
+	if len(properties) > 0 {
+		if _, err := s.client.UpdatePage(ctx, string(row.ID), &notionapi.PageUpdateRequest{Properties: properties}); err != nil {
+			return fmt.Errorf("push %s: update properties: %w", entry.Path, err)
+		}
+	}
+	if err := s.pushBlocks(ctx, string(row.ID), ops); err != nil {
+		return fmt.Errorf("push %s: %w", entry.Path, err)
+	}
+
+	// Record the page as it is now; the file stays as the user wrote it
+	page, err := s.client.GetPage(ctx, string(row.ID))
+	if err != nil {
+		return fmt.Errorf("push %s: get page: %w", entry.Path, err)
+	}
+	nodes, err := notion.FetchBlockTree(ctx, s.client, string(page.ID), 0)
+	if err != nil {
+		return fmt.Errorf("push %s: fetch blocks: %w", entry.Path, err)
+	}
+	s.cachePage(ctx, page, nodes)
+
+	entry.Hash = hash(data)
+	entry.LastEditedTime = page.LastEditedTime
+	entry.Blocks = blockStates(nodes)
+	if err := st.save(); err != nil {
+		return err
+	}
+	result.Changes = append(result.Changes, Change{Path: entry.Path, Action: ActionPushed})
+	return nil
+}
+
+// removeDeleted removes the files of rows that are no longer in the database.
+// Files with local edits are kept.
+func (s *Syncer) removeDeleted(st *state, remote map[string]bool, result *Result) error {
+	ids := make([]string, 0, len(st.Pages))
+	for id := range st.Pages {
+		if !remote[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		entry := st.Pages[id]
+		delete(st.Pages, id)
+
+		data, err := os.ReadFile(s.abs(entry.Path))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("read %s: %w", entry.Path, err)
+		case hash(data) != entry.Hash:
+			// The edits are kept; createNew explains how to add the file again
+		default:
+			if err := os.Remove(s.abs(entry.Path)); err != nil {
+				return fmt.Errorf("remove %s: %w", entry.Path, err)
+			}
+			result.Changes = append(result.Changes, Change{Path: entry.Path, Action: ActionRemoved})
+		}
+		_ = os.Remove(s.abs(conflictPath(entry.Path)))
+
+		if err := st.save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createNew adds a row for every file that is not synced yet.
+func (s *Syncer) createNew(ctx context.Context, st *state, db *notionapi.Database, result *Result) error {
+	files, err := localFiles(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, entry := st.tracked(rel); entry != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(s.abs(rel))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", rel, err)
+		}
+		doc, err := parseDocument(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if doc.ID != "" {
+			// The file was pulled from a page: a copy of a synced file, or
+			// one whose page was deleted. Neither is added without being asked.
+			reason := "is for a page that is no longer in the database"
+			if other := st.Pages[notion.NormalizeID(doc.ID)]; other != nil {
+				reason = "has the id of " + other.Path
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"%s %s; remove the id from its front matter to add it as a new page", rel, reason))
+			continue
+		}
+
+		if err := s.create(ctx, st, db, rel, doc, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create adds a row for a new file and rewrites the file as pulled, so that
+// it carries the ID of its page.
+func (s *Syncer) create(ctx context.Context, st *state, db *notionapi.Database, rel string, doc document, result *Result) error {
+	properties, err := s.properties(db, nil, doc, rel, result)
+	if err != nil {
+		return err
+	}
+	title := doc.Title
+	if title == "" {
+		title = strings.TrimSuffix(rel, path.Ext(rel))
+	}
+	titleName := titleProperty(db)
+	properties[titleName], _ = notion.ParsePropertyValue(notionapi.PropertyConfigTypeTitle, title, s.now())
+
+	page, err := s.client.CreatePage(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: notionapi.DatabaseID(s.databaseID)},
+		Properties: properties,
+	})
+	if err != nil {
+		return fmt.Errorf("create page for %s: %w", rel, err)
+	}
+	if _, err := notion.AppendBlockTree(ctx, s.client, string(page.ID), notion.ParseMarkdown(doc.body)); err != nil {
+		// Without its blocks the page would be pushed as a copy on the next run
+		_ = s.deleteBlock(ctx, string(page.ID))
+		return fmt.Errorf("create page for %s: add blocks: %w", rel, err)
+	}
+
+	page, err = s.client.GetPage(ctx, string(page.ID))
+	if err != nil {
+		return fmt.Errorf("create page for %s: get page: %w", rel, err)
+	}
+	nodes, content, err := s.render(ctx, page)
+	if err != nil {
+		return err
+	}
+	target := fileName(page)
+	if err := fsutil.WriteFile(s.abs(target), content, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", target, err)
+	}
+	if target != rel {
+		if err := os.Remove(s.abs(rel)); err != nil {
+			return fmt.Errorf("remove %s: %w", rel, err)
+		}
+	}
+
+	st.Pages[notion.NormalizeID(string(page.ID))] = &pageState{
+		Path:           target,
+		Hash:           hash(content),
+		LastEditedTime: page.LastEditedTime,
+		Blocks:         blockStates(nodes),
+	}
+	if err := st.save(); err != nil {
+		return err
+	}
+	result.Changes = append(result.Changes, Change{Path: target, Action: ActionCreated})
+	return nil
+}
+
+// properties returns the properties of a file that differ from those of row,
+// or all of them for a new file when row is nil. The title is handled
+// separately: it comes from the "title" key rather than the properties.
+func (s *Syncer) properties(db *notionapi.Database, row *notionapi.Page, doc document, rel string, result *Result) (notionapi.Properties, error) {
+	properties := notionapi.Properties{}
+	if row != nil && doc.Title != "" && doc.Title != notion.PageTitle(row) {
+		properties[titleProperty(db)], _ = notion.ParsePropertyValue(notionapi.PropertyConfigTypeTitle, doc.Title, s.now())
+	}
+
+	names := make([]string, 0, len(doc.Properties))
+	for name := range doc.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := doc.Properties[name]
+		config, ok := db.Properties[name]
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: property %q is not in the database and is ignored", rel, name))
+			continue
+		}
+		if config.GetType() == notionapi.PropertyConfigTypeTitle {
+			continue
+		}
+		if row != nil && value == notion.PropertyString(row.Properties[name]) {
+			continue
+		}
+		if row == nil && value == "" {
+			continue
+		}
+
+		prop, err := notion.ParsePropertyValue(config.GetType(), value, s.now())
+		if errors.Is(err, notion.ErrUnsupportedProperty) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: property %q is ignored: %v", rel, name, err))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: property %s: %w", rel, name, err)
+		}
+		properties[name] = prop
+	}
+	return properties, nil
+}
+
+// render fetches the blocks of a page and returns them with the file content.
+// The page is cached on the way.
+func (s *Syncer) render(ctx context.Context, page *notionapi.Page) ([]notion.BlockNode, []byte, error) {
+	title := notion.PageTitle(page)
+	nodes, err := notion.FetchBlockTree(ctx, s.client, string(page.ID), 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch blocks of %s: %w", title, err)
+	}
+	content, err := export.RenderPage(page, nodes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("render %s: %w", title, err)
+	}
+	s.cachePage(ctx, page, nodes)
+	return nodes, content, nil
+}
+
+// cachePage stores a page and its top-level blocks in the cache.
+func (s *Syncer) cachePage(ctx context.Context, page *notionapi.Page, nodes []notion.BlockNode) {
+	if s.cache == nil {
+		return
+	}
+	id := notion.FormatID(string(page.ID))
+	blocks := make([]notionapi.Block, 0, len(nodes))
+	for _, node := range nodes {
+		blocks = append(blocks, node.Block)
+	}
+
+	// Errors are ignored: caching is an optimization only
+	_ = s.cache.Set(ctx, cache.SetInput{PageID: cache.MetaKey(id), Data: page, TTL: cache.DefaultPageTTL})
+	_ = s.cache.Set(ctx, cache.SetInput{
+		PageID: id,
+		Data:   &notionapi.GetChildrenResponse{Object: notionapi.ObjectTypeList, Results: blocks},
+		TTL:    cache.DefaultPageTTL,
+	})
+}
+
+// abs returns the path of a file relative to the sync directory.
+func (s *Syncer) abs(rel string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(rel))
+}
+
+// fileName returns the file a row is synced to.
+func fileName(page *notionapi.Page) string {
+	return export.BaseName(notion.PageTitle(page), string(page.ID)) + ".md"
+}
+
+// titleProperty returns the name of the title property of a database.
+func titleProperty(db *notionapi.Database) string {
+	for name, config := range db.Properties {
+		if config.GetType() == notionapi.PropertyConfigTypeTitle {
+			return name
+		}
+	}
+	return "title"
+}
@@ -0,0 +1,393 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+const (
+	tasksID = "dddddddd-0000-0000-0000-000000000001"
+	planID  = "aaaaaaaa-0000-0000-0000-000000000002"
+	shipID  = "bbbbbbbb-0000-0000-0000-000000000003"
+
+	planFile = "write-plan-aaaaaaaa.md"
+	shipFile = "ship-it-bbbbbbbb.md"
+)
+
+// workspace is a fake database of tasks that the mock client reads and
+// writes. Every write moves the page's last edited time forward.
+type workspace struct {
+	rows   []*notionapi.Page
+	blocks map[string][]notionapi.Block // page ID to top-level blocks
+	clock  time.Time
+	nextID int
+}
+
+func newWorkspace() *workspace {
+	w := &workspace{
+		blocks: make(map[string][]notionapi.Block),
+		clock:  time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+	}
+	w.addRow(planID, "Write plan", "Todo", testhelpers.NewParagraphBlock("Draft the plan."))
+	w.addRow(shipID, "Ship it", "Done", testhelpers.NewParagraphBlock("Release notes."))
+	return w
+}
+
+// addRow adds a row with the given title, status and blocks.
+func (w *workspace) addRow(id, title, status string, blocks ...notionapi.Block) *notionapi.Page {
+	page := testhelpers.NewTestPage(id, "")
+	page.Properties = notionapi.Properties{
+		"Name":   &notionapi.TitleProperty{Type: notionapi.PropertyTypeTitle, Title: testhelpers.NewTestRichText(title)},
+		"Status": &notionapi.SelectProperty{Type: notionapi.PropertyTypeSelect, Select: notionapi.Option{Name: status}},
+	}
+	for _, block := range blocks {
+		w.setID(block)
+	}
+	w.rows = append(w.rows, page)
+	w.blocks[id] = blocks
+	w.touch(page)
+	return page
+}
+
+func (w *workspace) setID(block notionapi.Block) {
+	w.nextID++
+	id := notionapi.BlockID(fmt.Sprintf("block-%d", w.nextID))
+	switch b := block.(type) {
+	case *notionapi.ParagraphBlock:
+		b.ID = id
+	case *notionapi.BulletedListItemBlock:
+		b.ID = id
+	case *notionapi.Heading1Block:
+		b.ID = id
+	}
+}
+
+func (w *workspace) touch(page *notionapi.Page) {
+	w.clock = w.clock.Add(time.Minute)
+	page.LastEditedTime = w.clock
+}
+
+func (w *workspace) row(id string) *notionapi.Page {
+	for _, row := range w.rows {
+		if notion.NormalizeID(string(row.ID)) == notion.NormalizeID(id) {
+			return row
+		}
+	}
+	return nil
+}
+
+// owner returns the page a top-level block belongs to and its index.
+func (w *workspace) owner(blockID string) (string, int) {
+	for pageID, blocks := range w.blocks {
+		for i, block := range blocks {
+			if string(block.GetID()) == blockID {
+				return pageID, i
+			}
+		}
+	}
+	return "", -1
+}
+
+// editBlock replaces the text of a paragraph, as a remote edit.
+func (w *workspace) editBlock(pageID string, i int, text string) {
+	w.blocks[pageID][i].(*notionapi.ParagraphBlock).Paragraph.RichText = testhelpers.NewTestRichText(text)
+	w.touch(w.row(pageID))
+}
+
+func (w *workspace) client() *testhelpers.MockNotionClient {
+	schema := testhelpers.NewTestDatabaseSchema(tasksID)
+	schema.Properties = notionapi.PropertyConfigs{
+		"Name":   &notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+		"Status": &notionapi.SelectPropertyConfig{Type: notionapi.PropertyConfigTypeSelect},
+	}
+	mockClient := testhelpers.NewMockNotionClient().WithSchema(schema)
+
+	mockClient.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		resp := &notionapi.DatabaseQueryResponse{}
+		for _, row := range w.rows {
+			resp.Results = append(resp.Results, *row)
+		}
+		return resp, nil
+	}
+	mockClient.GetPageFunc = func(ctx context.Context, id string) (*notionapi.Page, error) {
+		if row := w.row(id); row != nil {
+			return row, nil
+		}
+		return nil, testhelpers.ErrNotFound
+	}
+	mockClient.GetBlocksFunc = func(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error) {
+		return testhelpers.NewGetChildrenResponse(w.blocks[id]), nil
+	}
+	mockClient.CreatePageFunc = func(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+		id := fmt.Sprintf("cccccccc-0000-0000-0000-%012d", len(w.rows)+1)
+		page := w.addRow(id, "", "")
+		page.Properties = req.Properties
+		return page, nil
+	}
+	mockClient.UpdatePageFunc = func(ctx context.Context, id string, req *notionapi.PageUpdateRequest) (*notionapi.Page, error) {
+		row := w.row(id)
+		for name, prop := range req.Properties {
+			row.Properties[name] = prop
+		}
+		w.touch(row)
+		return row, nil
+	}
+	mockClient.UpdateBlockFunc = func(ctx context.Context, id string, req *notionapi.BlockUpdateRequest) (notionapi.Block, error) {
+		pageID, i := w.owner(id)
+		block := w.blocks[pageID][i].(*notionapi.ParagraphBlock)
+		block.Paragraph.RichText = req.Paragraph.RichText
+		w.touch(w.row(pageID))
+		return block, nil
+	}
+	mockClient.DeleteBlockFunc = func(ctx context.Context, id string) (notionapi.Block, error) {
+		pageID, i := w.owner(id)
+		if i < 0 {
+			return nil, testhelpers.ErrNotFound
+		}
+		block := w.blocks[pageID][i]
+		w.blocks[pageID] = append(w.blocks[pageID][:i], w.blocks[pageID][i+1:]...)
+		w.touch(w.row(pageID))
+		return block, nil
+	}
+	mockClient.AppendBlocksFunc = func(ctx context.Context, id string, req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error) {
+		at := len(w.blocks[id])
+		if req.After != "" {
+			_, i := w.owner(string(req.After))
+			at = i + 1
+		}
+		for _, block := range req.Children {
+			w.setID(block)
+		}
+		blocks := append([]notionapi.Block{}, w.blocks[id][:at]...)
+		blocks = append(blocks, req.Children...)
+		w.blocks[id] = append(blocks, w.blocks[id][at:]...)
+		w.touch(w.row(id))
+		return &notionapi.AppendBlockChildrenResponse{Results: req.Children}, nil
+	}
+	return mockClient
+}
+
+// recordingCache records the keys a sync caches.
+type recordingCache struct {
+	keys []string
+}
+
+func (c *recordingCache) Set(ctx context.Context, input cache.SetInput) error {
+	c.keys = append(c.keys, input.PageID)
+	return nil
+}
+
+func newSyncer(t *testing.T, client Client, dir string) *Syncer {
+	t.Helper()
+	s, err := NewSyncer(NewSyncerInput{Client: client, Dir: dir, DatabaseID: tasksID})
+	require.NoError(t, err)
+	return s
+}
+
+func readFile(t *testing.T, file string) string {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// editFile replaces old with new in a synced file, as a local edit.
+func editFile(t *testing.T, file, old, new string) {
+	t.Helper()
+	content := readFile(t, file)
+	require.Contains(t, content, old)
+	require.NoError(t, os.WriteFile(file, []byte(strings.Replace(content, old, new, 1)), 0644))
+}
+
+func TestSyncPull(t *testing.T) {
+	t.Parallel()
+
+	w := newWorkspace()
+	dir := t.TempDir()
+	c := &recordingCache{}
+	s, err := NewSyncer(NewSyncerInput{Client: w.client(), Cache: c, Dir: dir, DatabaseID: tasksID})
+	require.NoError(t, err)
+
+	result, err := s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: planFile, Action: ActionPulled}, {Path: shipFile, Action: ActionPulled}}, result.Changes)
+	assert.Contains(t, c.keys, cache.MetaKey(planID))
+	assert.Contains(t, c.keys, planID)
+
+	content := readFile(t, filepath.Join(dir, planFile))
+	assert.Contains(t, content, "title: Write plan\n")
+	assert.Contains(t, content, "Status: Todo\n")
+	assert.True(t, strings.HasSuffix(content, "---\n\nDraft the plan.\n"))
+	assert.FileExists(t, filepath.Join(dir, stateName))
+
+	// Nothing changed on either side
+	result, err = s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.Changes)
+
+	// A remote edit is pulled, keeping the mode the user gave the file
+	require.NoError(t, os.Chmod(filepath.Join(dir, planFile), 0600))
+	w.editBlock(planID, 0, "Draft the plan by Friday.")
+	result, err = s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: planFile, Action: ActionPulled}}, result.Changes)
+	assert.Contains(t, readFile(t, filepath.Join(dir, planFile)), "Draft the plan by Friday.")
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dir, planFile))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestSyncPush(t *testing.T) {
+	t.Parallel()
+
+	w := newWorkspace()
+	mockClient := w.client()
+	dir := t.TempDir()
+	s := newSyncer(t, mockClient, dir)
+	_, err := s.Sync(context.Background())
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, planFile)
+	editFile(t, file, "Status: Todo", "Status: Doing")
+	editFile(t, file, "Draft the plan.", "Draft the plan.\n\n- Ask the team")
+
+	result, err := s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: planFile, Action: ActionPushed}}, result.Changes)
+
+	require.Equal(t, 1, mockClient.UpdatePageCallCount())
+	assert.Equal(t, "Doing", notion.PropertyString(mockClient.UpdatePageCalls[0].Request.Properties["Status"]))
+	require.Len(t, w.blocks[planID], 2)
+	assert.Equal(t, "Draft the plan.", notion.GetRichTextString(w.blocks[planID][0].(*notionapi.ParagraphBlock).Paragraph.RichText),
+		"the unchanged block stays")
+	assert.IsType(t, &notionapi.BulletedListItemBlock{}, w.blocks[planID][1])
+	assert.Zero(t, mockClient.DeleteBlockCallCount())
+
+	// The push is not pulled back
+	result, err = s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.Changes)
+}
+
+func TestSyncConflict(t *testing.T) {
+	t.Parallel()
+
+	w := newWorkspace()
+	dir := t.TempDir()
+	s := newSyncer(t, w.client(), dir)
+	_, err := s.Sync(context.Background())
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, planFile)
+	editFile(t, file, "Draft the plan.", "Draft the plan locally.")
+	w.editBlock(planID, 0, "Draft the plan remotely.")
+
+	result, err := s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: planFile, Action: ActionConflict}}, result.Changes)
+	assert.Contains(t, readFile(t, file), "Draft the plan locally.", "the local version is kept")
+	remoteCopy := filepath.Join(dir, "write-plan-aaaaaaaa.remote.md")
+	assert.Contains(t, readFile(t, remoteCopy), "Draft the plan remotely.", "so is the remote one")
+
+	// Still in conflict until the remote copy is deleted
+	result, err = s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: planFile, Action: ActionConflict}}, result.Changes)
+
+	editFile(t, file, "Draft the plan locally.", "Draft the plan together.")
+	require.NoError(t, os.Remove(remoteCopy))
+	result, err = s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: planFile, Action: ActionPushed}}, result.Changes)
+	assert.Equal(t, "Draft the plan together.", notion.GetRichTextString(w.blocks[planID][0].(*notionapi.ParagraphBlock).Paragraph.RichText))
+}
+
+func TestSyncCreateAndRemove(t *testing.T) {
+	t.Parallel()
+
+	w := newWorkspace()
+	mockClient := w.client()
+	dir := t.TempDir()
+	s := newSyncer(t, mockClient, dir)
+	_, err := s.Sync(context.Background())
+	require.NoError(t, err)
+
+	// A new file becomes a row; a copy of a synced file does not
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "idea.md"),
+		[]byte("---\ntitle: New idea\nproperties:\n  Status: Todo\n  Owner: sam\n---\n\nThink it over.\n"), 0644))
+	copied := readFile(t, filepath.Join(dir, shipFile))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copy.md"), []byte(copied), 0644))
+
+	// A row deleted in Notion is removed locally
+	w.rows = w.rows[:1]
+
+	result, err := s.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Path: shipFile, Action: ActionRemoved},
+		{Path: "new-idea-cccccccc.md", Action: ActionCreated},
+	}, result.Changes)
+	assert.Equal(t, []string{
+		"copy.md is for a page that is no longer in the database; remove the id from its front matter to add it as a new page",
+		`idea.md: property "Owner" is not in the database and is ignored`,
+	}, result.Warnings)
+
+	require.Equal(t, 1, mockClient.CreatePageCallCount())
+	req := mockClient.CreatePageCalls[0].Request
+	assert.Equal(t, "New idea", notion.PropertyString(req.Properties["Name"]))
+	assert.Equal(t, "Todo", notion.PropertyString(req.Properties["Status"]))
+
+	assert.NoFileExists(t, filepath.Join(dir, shipFile))
+	assert.NoFileExists(t, filepath.Join(dir, "idea.md"), "the file is renamed after its page")
+	created := readFile(t, filepath.Join(dir, "new-idea-cccccccc.md"))
+	assert.Contains(t, created, "id: cccccccc-0000-0000-0000-000000000002\n")
+	assert.Contains(t, created, "Think it over.")
+}
+
+func TestSyncOtherDatabase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := newSyncer(t, newWorkspace().client(), dir).Sync(context.Background())
+	require.NoError(t, err)
+
+	other, err := NewSyncer(NewSyncerInput{Client: newWorkspace().client(), Dir: dir, DatabaseID: "other"})
+	require.NoError(t, err)
+	_, err = other.Sync(context.Background())
+	assert.ErrorContains(t, err, "is synced with database")
+}
+
+func TestParseDocument(t *testing.T) {
+	t.Parallel()
+
+	doc, err := parseDocument([]byte("---\r\nid: abc\r\ntitle: Plan\r\nproperties:\r\n  Score: 3\r\n---\r\n\r\nBody\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", doc.ID)
+	assert.Equal(t, "Plan", doc.Title)
+	assert.Equal(t, map[string]string{"Score": "3"}, doc.Properties)
+	assert.Equal(t, "\nBody\n", doc.body)
+
+	doc, err = parseDocument([]byte("Just text\n"))
+	require.NoError(t, err)
+	assert.Empty(t, doc.ID)
+	assert.Equal(t, "Just text\n", doc.body)
+
+	_, err = parseDocument([]byte("---\nproperties:\n  Tags: [a, b]\n---\n"))
+	assert.Error(t, err)
+}
@@ -19,6 +19,7 @@ func NewTestPage(id, title string) *notionapi.Page {
 		Archived:       false,
 		Properties: notionapi.Properties{
 			"title": &notionapi.TitleProperty{
+				Type:  notionapi.PropertyTypeTitle,
 				Title: NewTestRichText(title),
 			},
 		},
@@ -113,6 +114,22 @@ func NewTestDatabase(dbID string) *notionapi.DatabaseQueryResponse {
 	}
 }
 
+// NewTestDatabaseSchema creates a database whose only property is the
+// "title" property used by NewTestPage.
+func NewTestDatabaseSchema(dbID string) *notionapi.Database {
+	return &notionapi.Database{
+		Object: notionapi.ObjectTypeDatabase,
+		ID:     notionapi.ObjectID(dbID),
+		Title:  NewTestRichText("Test Database"),
+		Properties: notionapi.PropertyConfigs{
+			"title": &notionapi.TitlePropertyConfig{
+				Type: notionapi.PropertyConfigTypeTitle,
+			},
+		},
+		URL: "https://www.notion.so/" + dbID,
+	}
+}
+
 // NewTestDatabaseEmpty creates an empty database query response.
 func NewTestDatabaseEmpty(dbID string) *notionapi.DatabaseQueryResponse {
 	return &notionapi.DatabaseQueryResponse{
@@ -17,9 +17,11 @@ type MockNotionClient struct {
 
 	// Configurable return values for each method
 	GetPageFunc       func(ctx context.Context, id string) (*notionapi.Page, error)
+	GetDatabaseFunc   func(ctx context.Context, id string) (*notionapi.Database, error)
 	QueryDatabaseFunc func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
 	GetBlocksFunc     func(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error)
 	GetBlockFunc      func(ctx context.Context, id string) (notionapi.Block, error)
+	CreatePageFunc    func(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error)
 	UpdatePageFunc    func(ctx context.Context, id string, req *notionapi.PageUpdateRequest) (*notionapi.Page, error)
 	UpdateBlockFunc   func(ctx context.Context, id string, req *notionapi.BlockUpdateRequest) (notionapi.Block, error)
 	AppendBlocksFunc  func(ctx context.Context, id string, req *notionapi.AppendBlockChildrenRequest) (*notionapi.AppendBlockChildrenResponse, error)
@@ -28,9 +30,11 @@ type MockNotionClient struct {
 
 	// Call tracking for assertions
 	GetPageCalls       []GetPageCall
+	GetDatabaseCalls   []GetDatabaseCall
 	QueryDatabaseCalls []QueryDatabaseCall
 	GetBlocksCalls     []GetBlocksCall
 	GetBlockCalls      []GetBlockCall
+	CreatePageCalls    []CreatePageCall
 	UpdatePageCalls    []UpdatePageCall
 	UpdateBlockCalls   []UpdateBlockCall
 	AppendBlocksCalls  []AppendBlocksCall
@@ -40,6 +44,7 @@ type MockNotionClient struct {
 	// Simple return values for common scenarios
 	PageToReturn         *notionapi.Page
 	DatabaseToReturn     *notionapi.DatabaseQueryResponse
+	SchemaToReturn       *notionapi.Database
 	BlocksToReturn       *notionapi.GetChildrenResponse
 	BlockToReturn        notionapi.Block
 	AppendResponseReturn *notionapi.AppendBlockChildrenResponse
@@ -54,6 +59,12 @@ type GetPageCall struct {
 	ID  string
 }
 
+// GetDatabaseCall records a call to GetDatabase.
+type GetDatabaseCall struct {
+	Ctx context.Context
+	ID  string
+}
+
 // QueryDatabaseCall records a call to QueryDatabase.
 type QueryDatabaseCall struct {
 	Ctx     context.Context
@@ -74,6 +85,12 @@ type GetBlockCall struct {
 	ID  string
 }
 
+// CreatePageCall records a call to CreatePage.
+type CreatePageCall struct {
+	Ctx     context.Context
+	Request *notionapi.PageCreateRequest
+}
+
 // UpdatePageCall records a call to UpdatePage.
 type UpdatePageCall struct {
 	Ctx     context.Context
@@ -111,9 +128,11 @@ type SearchCall struct {
 func NewMockNotionClient() *MockNotionClient {
 	return &MockNotionClient{
 		GetPageCalls:       make([]GetPageCall, 0),
+		GetDatabaseCalls:   make([]GetDatabaseCall, 0),
 		QueryDatabaseCalls: make([]QueryDatabaseCall, 0),
 		GetBlocksCalls:     make([]GetBlocksCall, 0),
 		GetBlockCalls:      make([]GetBlockCall, 0),
+		CreatePageCalls:    make([]CreatePageCall, 0),
 		UpdatePageCalls:    make([]UpdatePageCall, 0),
 		UpdateBlockCalls:   make([]UpdateBlockCall, 0),
 		AppendBlocksCalls:  make([]AppendBlocksCall, 0),
@@ -143,6 +162,27 @@ func (m *MockNotionClient) GetPage(ctx context.Context, id string) (*notionapi.P
 	return NewTestPage(id, "Test Page"), nil
 }
 
+// GetDatabase retrieves a database schema. Returns configured values or error.
+func (m *MockNotionClient) GetDatabase(ctx context.Context, id string) (*notionapi.Database, error) {
+	m.mu.Lock()
+	m.GetDatabaseCalls = append(m.GetDatabaseCalls, GetDatabaseCall{Ctx: ctx, ID: id})
+	m.mu.Unlock()
+
+	if m.GetDatabaseFunc != nil {
+		return m.GetDatabaseFunc(ctx, id)
+	}
+
+	if m.ErrorToReturn != nil {
+		return nil, m.ErrorToReturn
+	}
+
+	if m.SchemaToReturn != nil {
+		return m.SchemaToReturn, nil
+	}
+
+	return NewTestDatabaseSchema(id), nil
+}
+
 // QueryDatabase queries a database. Returns configured values or error.
 func (m *MockNotionClient) QueryDatabase(ctx context.Context, id string,
 	req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
@@ -220,6 +260,34 @@ func (m *MockNotionClient) GetBlock(ctx context.Context, id string) (notionapi.B
 	return NewParagraphBlock("Test block content"), nil
 }
 
+// CreatePage creates a page. Returns configured values or error.
+func (m *MockNotionClient) CreatePage(ctx context.Context,
+	req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+	m.mu.Lock()
+	m.CreatePageCalls = append(m.CreatePageCalls, CreatePageCall{
+		Ctx:     ctx,
+		Request: req,
+	})
+	m.mu.Unlock()
+
+	if m.CreatePageFunc != nil {
+		return m.CreatePageFunc(ctx, req)
+	}
+
+	if m.ErrorToReturn != nil {
+		return nil, m.ErrorToReturn
+	}
+
+	if m.PageToReturn != nil {
+		return m.PageToReturn, nil
+	}
+
+	page := NewTestPage("new-page", "New Page")
+	page.Parent = req.Parent
+	page.Properties = req.Properties
+	return page, nil
+}
+
 // UpdatePage updates a page's properties.
 func (m *MockNotionClient) UpdatePage(ctx context.Context, id string,
 	req *notionapi.PageUpdateRequest) (*notionapi.Page, error) {
@@ -360,8 +428,8 @@ func (m *MockNotionClient) Search(ctx context.Context, input notion.SearchInput)
 func (m *MockNotionClient) CallCount() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return len(m.GetPageCalls) + len(m.QueryDatabaseCalls) + len(m.GetBlocksCalls) +
-		len(m.GetBlockCalls) + len(m.UpdatePageCalls) + len(m.UpdateBlockCalls) +
+	return len(m.GetPageCalls) + len(m.GetDatabaseCalls) + len(m.QueryDatabaseCalls) + len(m.GetBlocksCalls) +
+		len(m.GetBlockCalls) + len(m.CreatePageCalls) + len(m.UpdatePageCalls) + len(m.UpdateBlockCalls) +
 		len(m.AppendBlocksCalls) + len(m.DeleteBlockCalls) + len(m.SearchCalls)
 }
 
@@ -372,6 +440,13 @@ func (m *MockNotionClient) GetPageCallCount() int {
 	return len(m.GetPageCalls)
 }
 
+// GetDatabaseCallCount returns the number of calls to GetDatabase.
+func (m *MockNotionClient) GetDatabaseCallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.GetDatabaseCalls)
+}
+
 // QueryDatabaseCallCount returns the number of calls to QueryDatabase.
 func (m *MockNotionClient) QueryDatabaseCallCount() int {
 	m.mu.Lock()
@@ -393,6 +468,13 @@ func (m *MockNotionClient) GetBlockCallCount() int {
 	return len(m.GetBlockCalls)
 }
 
+// CreatePageCallCount returns the number of calls to CreatePage.
+func (m *MockNotionClient) CreatePageCallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.CreatePageCalls)
+}
+
 // UpdatePageCallCount returns the number of calls to UpdatePage.
 func (m *MockNotionClient) UpdatePageCallCount() int {
 	m.mu.Lock()
@@ -468,6 +550,16 @@ func (m *MockNotionClient) LastGetBlockCall() *GetBlockCall {
 	return &m.GetBlockCalls[len(m.GetBlockCalls)-1]
 }
 
+// LastCreatePageCall returns the most recent CreatePage call, or nil if none.
+func (m *MockNotionClient) LastCreatePageCall() *CreatePageCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.CreatePageCalls) == 0 {
+		return nil
+	}
+	return &m.CreatePageCalls[len(m.CreatePageCalls)-1]
+}
+
 // LastUpdatePageCall returns the most recent UpdatePage call, or nil if none.
 func (m *MockNotionClient) LastUpdatePageCall() *UpdatePageCall {
 	m.mu.Lock()
@@ -524,9 +616,11 @@ func (m *MockNotionClient) Reset() {
 	defer m.mu.Unlock()
 
 	m.GetPageCalls = make([]GetPageCall, 0)
+	m.GetDatabaseCalls = make([]GetDatabaseCall, 0)
 	m.QueryDatabaseCalls = make([]QueryDatabaseCall, 0)
 	m.GetBlocksCalls = make([]GetBlocksCall, 0)
 	m.GetBlockCalls = make([]GetBlockCall, 0)
+	m.CreatePageCalls = make([]CreatePageCall, 0)
 	m.UpdatePageCalls = make([]UpdatePageCall, 0)
 	m.UpdateBlockCalls = make([]UpdateBlockCall, 0)
 	m.AppendBlocksCalls = make([]AppendBlocksCall, 0)
@@ -534,9 +628,11 @@ func (m *MockNotionClient) Reset() {
 	m.SearchCalls = make([]SearchCall, 0)
 
 	m.GetPageFunc = nil
+	m.GetDatabaseFunc = nil
 	m.QueryDatabaseFunc = nil
 	m.GetBlocksFunc = nil
 	m.GetBlockFunc = nil
+	m.CreatePageFunc = nil
 	m.UpdatePageFunc = nil
 	m.UpdateBlockFunc = nil
 	m.AppendBlocksFunc = nil
@@ -545,6 +641,7 @@ func (m *MockNotionClient) Reset() {
 
 	m.PageToReturn = nil
 	m.DatabaseToReturn = nil
+	m.SchemaToReturn = nil
 	m.BlocksToReturn = nil
 	m.BlockToReturn = nil
 	m.AppendResponseReturn = nil
@@ -574,6 +671,13 @@ func (m *MockNotionClient) WithDatabase(db *notionapi.DatabaseQueryResponse) *Mo
 	return m
 }
 
+// WithSchema configures the mock to return a specific database schema.
+// Returns the mock for chaining.
+func (m *MockNotionClient) WithSchema(db *notionapi.Database) *MockNotionClient {
+	m.SchemaToReturn = db
+	return m
+}
+
 // WithBlocks configures the mock to return specific blocks.
 // Returns the mock for chaining.
 func (m *MockNotionClient) WithBlocks(resp *notionapi.GetChildrenResponse) *MockNotionClient {
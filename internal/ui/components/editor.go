@@ -1,9 +1,15 @@
 package components
 
 import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // SaveDraftMsg is sent when the user requests to save the draft (Ctrl+S).
@@ -25,6 +31,7 @@ type BlockEditor struct {
 	blockType   string
 	dirty       bool
 	styles      EditorStyles
+	keys        keymap.EditKeys
 	width       int
 	height      int
 	initialText string
@@ -39,16 +46,21 @@ type EditorStyles struct {
 
 // DefaultEditorStyles returns the default styles for the editor.
 func DefaultEditorStyles() EditorStyles {
+	return NewEditorStyles(theme.Default())
+}
+
+// NewEditorStyles returns the editor styles for a theme.
+func NewEditorStyles(t theme.Theme) EditorStyles {
 	return EditorStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7C3AED")).
+			BorderForeground(t.Primary).
 			Padding(1, 2),
 		DirtyMarker: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")).
+			Foreground(t.Warning).
 			Bold(true),
 		HelpText: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(t.Muted).
 			Italic(true),
 	}
 }
@@ -60,6 +72,8 @@ type NewBlockEditorInput struct {
 	Content   string
 	Width     int
 	Height    int
+	Keys      *keymap.KeyMap // optional; the default bindings when nil
+	Theme     *theme.Theme   // optional; the default theme when nil
 }
 
 // NewBlockEditor creates a new block editor component.
@@ -73,26 +87,32 @@ func NewBlockEditor(input NewBlockEditorInput) BlockEditor {
 	ta.SetValue(input.Content)
 	ta.ShowLineNumbers = true
 
-	// Style the textarea
-	ta.FocusedStyle.CursorLine = lipgloss.NewStyle().
-		Background(lipgloss.Color("#374151"))
-	ta.FocusedStyle.Base = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#F3F4F6"))
-	ta.BlurredStyle.Base = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6B7280"))
+	t := theme.OrDefault(input.Theme)
+	styleTextarea(&ta, t)
 
 	return BlockEditor{
 		textarea:    ta,
 		blockID:     input.BlockID,
 		blockType:   input.BlockType,
 		dirty:       false,
-		styles:      DefaultEditorStyles(),
+		styles:      NewEditorStyles(t),
+		keys:        keymap.OrDefault(input.Keys).Edit,
 		width:       input.Width,
 		height:      input.Height,
 		initialText: input.Content,
 	}
 }
 
+// styleTextarea colors the textarea's cursor line and text for a theme.
+func styleTextarea(ta *textarea.Model, t theme.Theme) {
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle().
+		Background(t.Secondary)
+	ta.FocusedStyle.Base = lipgloss.NewStyle().
+		Foreground(t.Text)
+	ta.BlurredStyle.Base = lipgloss.NewStyle().
+		Foreground(t.Muted)
+}
+
 // Init initializes the block editor component.
 func (e BlockEditor) Init() tea.Cmd {
 	return textarea.Blink
@@ -104,8 +124,8 @@ func (e BlockEditor) Update(msg tea.Msg) (BlockEditor, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+s":
+		switch {
+		case key.Matches(msg, e.keys.Save):
 			// Save the draft
 			return e, func() tea.Msg {
 				return SaveDraftMsg{
@@ -114,7 +134,7 @@ func (e BlockEditor) Update(msg tea.Msg) (BlockEditor, tea.Cmd) {
 					Content:   e.textarea.Value(),
 				}
 			}
-		case "esc":
+		case key.Matches(msg, e.keys.Cancel):
 			// Cancel editing
 			return e, func() tea.Msg {
 				return CancelEditMsg{
@@ -150,7 +170,8 @@ func (e BlockEditor) View() string {
 		header = "Ready"
 	}
 
-	helpText := e.styles.HelpText.Render("Ctrl+S: Save | Esc: Cancel")
+	helpText := e.styles.HelpText.Render(fmt.Sprintf("%s: Save | %s: Cancel",
+		keymap.Label(e.keys.Save), keymap.Label(e.keys.Cancel)))
 
 	content := e.textarea.View()
 
@@ -215,6 +236,18 @@ func (e BlockEditor) Height() int {
 	return e.height
 }
 
+// SetKeys sets the bindings the editor responds to. Nil restores the
+// default bindings.
+func (e *BlockEditor) SetKeys(k *keymap.KeyMap) {
+	e.keys = keymap.OrDefault(k).Edit
+}
+
+// SetTheme restyles the editor for a theme.
+func (e *BlockEditor) SetTheme(t theme.Theme) {
+	styleTextarea(&e.textarea, t)
+	e.styles = NewEditorStyles(t)
+}
+
 // SetSize updates the editor dimensions.
 func (e *BlockEditor) SetSize(width, height int) {
 	e.width = width
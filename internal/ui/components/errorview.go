@@ -10,6 +10,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // ErrorType represents different categories of errors.
@@ -61,33 +63,38 @@ type ErrorViewStyles struct {
 
 // DefaultErrorViewStyles returns the default styles for the error view.
 func DefaultErrorViewStyles() ErrorViewStyles {
+	return NewErrorViewStyles(theme.Default())
+}
+
+// NewErrorViewStyles returns the error view styles for a theme.
+func NewErrorViewStyles(t theme.Theme) ErrorViewStyles {
 	return ErrorViewStyles{
 		Container: lipgloss.NewStyle().
 			Padding(2, 4),
 		Icon: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
+			Foreground(t.Error).
 			Bold(true),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
+			Foreground(t.Error).
 			Bold(true).
 			MarginTop(1).
 			MarginBottom(1),
 		Message: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F3F4F6")).
+			Foreground(t.Text).
 			MarginBottom(1),
 		Context: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")).
+			Foreground(t.Muted).
 			Italic(true).
 			MarginBottom(2),
 		Actions: lipgloss.NewStyle().
 			MarginTop(1),
 		Action: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(t.Accent).
 			Bold(true).
 			MarginRight(2),
 		Border: lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#EF4444")).
+			BorderForeground(t.Error).
 			Padding(1, 2),
 	}
 }
@@ -112,6 +119,7 @@ type NewErrorViewInput struct {
 	Width      int
 	Height     int
 	ShowBorder bool
+	Theme      *theme.Theme // optional; the default theme when nil
 }
 
 // NewErrorView creates a new ErrorView instance.
@@ -120,7 +128,7 @@ func NewErrorView(input NewErrorViewInput) ErrorView {
 		err:        input.Err,
 		width:      input.Width,
 		height:     input.Height,
-		styles:     DefaultErrorViewStyles(),
+		styles:     NewErrorViewStyles(theme.OrDefault(input.Theme)),
 		showBorder: input.ShowBorder,
 	}
 
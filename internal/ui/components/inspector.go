@@ -0,0 +1,439 @@
+package components
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// inspectorRefreshInterval is how often an open inspector re-reads the
+// request log.
+const inspectorRefreshInterval = time.Second
+
+// InspectorItem is an object the inspector shows as Notion returned it: a
+// page, a database row or a block.
+type InspectorItem struct {
+	Kind      string   // "page", "database row" or "block"
+	ID        string   // the object's ID
+	Label     string   // the title of a page or row, the type of a block
+	Raw       any      // shown as JSON
+	Markdown  string   // what a block converts to; empty for pages and rows
+	CacheKeys []string // the cache entries the object is stored in
+}
+
+// InspectorTab is a section of the inspector.
+type InspectorTab int
+
+// Inspector sections, in the order the focus key cycles through them.
+const (
+	InspectorObject InspectorTab = iota
+	InspectorMarkdown
+	InspectorCache
+	InspectorRequests
+)
+
+// inspectorTabNames are the section titles, indexed by InspectorTab.
+var inspectorTabNames = []string{"Object", "Markdown", "Cache", "Requests"}
+
+// inspectorRefreshMsg ticks while the inspector is open, so the request log
+// follows requests made in the background. Ticks of an earlier opening are
+// dropped.
+type inspectorRefreshMsg struct {
+	opened int
+}
+
+// Inspector is a developer overlay showing the raw objects behind the
+// current page, their cache entries and the recent Notion API calls.
+type Inspector struct {
+	isOpen   bool
+	opened   int // counts openings, to tell their ticks apart
+	items    []InspectorItem
+	selected int
+	tab      InspectorTab
+	viewport viewport.Model
+	requests func() []notion.RequestRecord
+	lookup   func(key string) (cache.CacheEntry, error)
+	now      func() time.Time
+	width    int
+	height   int
+	keys     *keymap.KeyMap
+	styles   InspectorStyles
+}
+
+// InspectorStyles holds the styles for the inspector.
+type InspectorStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Tab       lipgloss.Style
+	ActiveTab lipgloss.Style
+	Header    lipgloss.Style
+	Muted     lipgloss.Style
+	Error     lipgloss.Style
+}
+
+// DefaultInspectorStyles returns the default styles for the inspector.
+func DefaultInspectorStyles() InspectorStyles {
+	return NewInspectorStyles(theme.Default())
+}
+
+// NewInspectorStyles returns the inspector styles for a theme.
+func NewInspectorStyles(t theme.Theme) InspectorStyles {
+	return InspectorStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(t.Accent).
+			Padding(0, 1),
+		Title:     lipgloss.NewStyle().Foreground(t.Accent).Bold(true),
+		Tab:       lipgloss.NewStyle().Foreground(t.Muted).Padding(0, 1),
+		ActiveTab: lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Underline(true).Padding(0, 1),
+		Header:    lipgloss.NewStyle().Foreground(t.Text),
+		Muted:     lipgloss.NewStyle().Foreground(t.Muted),
+		Error:     lipgloss.NewStyle().Foreground(t.Error),
+	}
+}
+
+// NewInspectorInput contains the parameters for creating an Inspector.
+type NewInspectorInput struct {
+	// Requests returns the recent Notion API calls; optional, the request
+	// log is empty when nil
+	Requests func() []notion.RequestRecord
+	// LookupCache reads a cache entry; optional, nothing is shown as cached
+	// when nil
+	LookupCache func(key string) (cache.CacheEntry, error)
+	Keys        *keymap.KeyMap   // optional; the default bindings when nil
+	Theme       *theme.Theme     // optional; the default theme when nil
+	Now         func() time.Time // optional; time.Now when nil
+}
+
+// NewInspector creates a closed inspector.
+func NewInspector(input NewInspectorInput) Inspector {
+	in := Inspector{
+		viewport: viewport.New(0, 0),
+		requests: input.Requests,
+		lookup:   input.LookupCache,
+		now:      input.Now,
+	}
+	if in.now == nil {
+		in.now = time.Now
+	}
+	in.SetKeys(input.Keys)
+	in.SetTheme(theme.OrDefault(input.Theme))
+	return in
+}
+
+// SetKeys sets the bindings the inspector responds to. Nil restores the
+// default bindings.
+func (in *Inspector) SetKeys(k *keymap.KeyMap) {
+	in.keys = keymap.OrDefault(k)
+	keys := in.keys.Detail
+	in.viewport.KeyMap.Up = keys.Up
+	in.viewport.KeyMap.Down = keys.Down
+	in.viewport.KeyMap.PageUp = keys.PageUp
+	in.viewport.KeyMap.PageDown = keys.PageDown
+	in.viewport.KeyMap.HalfPageUp = keys.HalfPageUp
+	in.viewport.KeyMap.HalfPageDown = keys.HalfPageDown
+}
+
+// SetTheme restyles the inspector for a theme.
+func (in *Inspector) SetTheme(t theme.Theme) {
+	in.styles = NewInspectorStyles(t)
+}
+
+// SetSize sets the screen size the inspector is drawn on. It takes most of
+// the screen.
+func (in *Inspector) SetSize(width, height int) {
+	in.width = width
+	in.height = height
+	// Border and padding take 4 columns; the border, title, tabs, object
+	// line and help take 7 rows
+	in.viewport.Width = max(width*9/10-4, 20)
+	in.viewport.Height = max(height*9/10-7, 3)
+	in.refresh(false)
+}
+
+// Open shows items, starting at selected. The section shown last stays
+// selected.
+func (in *Inspector) Open(items []InspectorItem, selected int) tea.Cmd {
+	in.isOpen = true
+	in.opened++
+	in.items = items
+	in.selected = min(max(selected, 0), max(len(items)-1, 0))
+	in.refresh(true)
+	return inspectorTick(in.opened)
+}
+
+// Close hides the inspector.
+func (in *Inspector) Close() {
+	in.isOpen = false
+	in.items = nil
+}
+
+// IsOpen reports whether the inspector is shown.
+func (in Inspector) IsOpen() bool {
+	return in.isOpen
+}
+
+// Tab returns the section shown.
+func (in Inspector) Tab() InspectorTab {
+	return in.tab
+}
+
+// Selected returns the object shown, if there is one.
+func (in Inspector) Selected() (InspectorItem, bool) {
+	if in.selected < 0 || in.selected >= len(in.items) {
+		return InspectorItem{}, false
+	}
+	return in.items[in.selected], true
+}
+
+// inspectorTick schedules the next request log refresh.
+func inspectorTick(opened int) tea.Cmd {
+	return tea.Tick(inspectorRefreshInterval, func(time.Time) tea.Msg {
+		return inspectorRefreshMsg{opened: opened}
+	})
+}
+
+// Init implements tea.Model.
+func (in Inspector) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keys while the inspector is open. The inspector key and
+// back close it, the sidebar focus key moves to the next section and the
+// list's left and right keys move between objects.
+func (in Inspector) Update(msg tea.Msg) (Inspector, tea.Cmd) {
+	if !in.isOpen {
+		return in, nil
+	}
+
+	switch msg := msg.(type) {
+	case inspectorRefreshMsg:
+		if msg.opened != in.opened {
+			return in, nil
+		}
+		if in.tab == InspectorRequests {
+			in.refresh(false)
+		}
+		return in, inspectorTick(in.opened)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, in.keys.Global.Inspector), key.Matches(msg, in.keys.Global.Back):
+			in.Close()
+			return in, nil
+		case key.Matches(msg, in.keys.Global.FocusSidebar):
+			in.tab = (in.tab + 1) % InspectorTab(len(inspectorTabNames))
+			in.refresh(true)
+			return in, nil
+		case key.Matches(msg, in.keys.List.Collapse):
+			if in.selected > 0 {
+				in.selected--
+				in.refresh(true)
+			}
+			return in, nil
+		case key.Matches(msg, in.keys.List.Expand):
+			if in.selected < len(in.items)-1 {
+				in.selected++
+				in.refresh(true)
+			}
+			return in, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	in.viewport, cmd = in.viewport.Update(msg)
+	return in, cmd
+}
+
+// refresh renders the section shown into the viewport. The request log
+// keeps following new requests while it is scrolled to the bottom.
+func (in *Inspector) refresh(reset bool) {
+	if !in.isOpen {
+		return
+	}
+	follow := in.tab == InspectorRequests && (reset || in.viewport.AtBottom())
+	in.viewport.SetContent(in.content())
+	switch {
+	case follow:
+		in.viewport.GotoBottom()
+	case reset:
+		in.viewport.GotoTop()
+	}
+}
+
+// content returns the text of the section shown.
+func (in Inspector) content() string {
+	if in.tab == InspectorRequests {
+		return in.requestLog()
+	}
+
+	item, ok := in.Selected()
+	if !ok {
+		return in.styles.Muted.Render("Nothing on this page to inspect.")
+	}
+
+	switch in.tab {
+	case InspectorMarkdown:
+		if item.Kind != "block" {
+			return in.styles.Muted.Render("Only blocks convert to markdown; move to a block with " +
+				keymap.Label(in.keys.List.Expand) + ".")
+		}
+		if item.Markdown == "" {
+			return in.styles.Muted.Render("The block converts to no markdown.")
+		}
+		return item.Markdown
+	case InspectorCache:
+		return in.cacheEntries(item)
+	default:
+		data, err := json.MarshalIndent(item.Raw, "", "  ")
+		if err != nil {
+			return in.styles.Error.Render(fmt.Sprintf("encode object: %v", err))
+		}
+		return string(data)
+	}
+}
+
+// cacheEntries describes the cache entries holding item.
+func (in Inspector) cacheEntries(item InspectorItem) string {
+	if in.lookup == nil || len(item.CacheKeys) == 0 {
+		return in.styles.Muted.Render("The cache is not in use.")
+	}
+
+	now := in.now()
+	var b strings.Builder
+	for i, k := range item.CacheKeys {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(in.styles.Header.Render(k) + "\n")
+
+		entry, err := in.lookup(k)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				b.WriteString(in.styles.Muted.Render("  not cached") + "\n")
+			} else {
+				b.WriteString(in.styles.Error.Render("  "+err.Error()) + "\n")
+			}
+			continue
+		}
+
+		fmt.Fprintf(&b, "  timestamp  %s (%s ago)\n",
+			entry.Timestamp.Local().Format(time.DateTime), now.Sub(entry.Timestamp).Round(time.Second))
+		if entry.TTL > 0 {
+			expires := entry.Timestamp.Add(entry.TTL)
+			state := fmt.Sprintf("expires in %s", expires.Sub(now).Round(time.Second))
+			if now.After(expires) {
+				state = "expired"
+			}
+			fmt.Fprintf(&b, "  ttl        %s (%s)\n", entry.TTL, state)
+		} else {
+			b.WriteString("  ttl        none (never expires)\n")
+		}
+		fmt.Fprintf(&b, "  hash       %s\n", entry.Hash)
+		fmt.Fprintf(&b, "  size       %d bytes\n", len(entry.Data))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// requestLog lists the recent API calls, oldest first.
+func (in Inspector) requestLog() string {
+	var records []notion.RequestRecord
+	if in.requests != nil {
+		records = in.requests()
+	}
+	if len(records) == 0 {
+		return in.styles.Muted.Render("No Notion API calls yet.")
+	}
+
+	lines := make([]string, 0, len(records))
+	for _, r := range records {
+		lines = append(lines, in.formatRecord(r))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatRecord returns the request log line of a record.
+func (in Inspector) formatRecord(r notion.RequestRecord) string {
+	at := r.Time.Local().Format("15:04:05.000")
+	duration := r.Duration.Round(time.Millisecond)
+
+	if r.Kind == notion.RecordWait {
+		return in.styles.Muted.Render(fmt.Sprintf("%s  wait   %-10s %8s  rate limiter (%s)", at, "", duration, r.Priority))
+	}
+
+	target := r.Object
+	if r.ObjectID != "" {
+		target += "/" + r.ObjectID
+	}
+	status := fmt.Sprint(r.Status)
+	if r.Status == 0 {
+		status = "---"
+	}
+	line := fmt.Sprintf("%s  %-6s %-10s %8s  %s %s", at, r.Method, status, duration, target, priorityNote(r.Priority))
+	if r.Err != "" || r.Status >= 400 {
+		line = strings.TrimRight(line, " ")
+		if r.Err != "" {
+			line += "  " + r.Err
+		}
+		return in.styles.Error.Render(line)
+	}
+	return strings.TrimRight(line, " ")
+}
+
+// priorityNote marks idle requests, such as cache warming, in the log.
+func priorityNote(p notion.Priority) string {
+	if p == notion.PriorityIdle {
+		return "(idle)"
+	}
+	return ""
+}
+
+// View renders the inspector, or nothing when it is closed.
+func (in Inspector) View() string {
+	if !in.isOpen {
+		return ""
+	}
+
+	tabs := make([]string, len(inspectorTabNames))
+	for i, name := range inspectorTabNames {
+		style := in.styles.Tab
+		if InspectorTab(i) == in.tab {
+			style = in.styles.ActiveTab
+		}
+		tabs[i] = style.Render(name)
+	}
+
+	object := in.styles.Muted.Render("no object")
+	if item, ok := in.Selected(); ok {
+		object = in.styles.Header.Render(fmt.Sprintf("%s %d/%d · %s · %s",
+			item.Kind, in.selected+1, len(in.items), item.Label, item.ID))
+	}
+
+	help := in.styles.Muted.Render(fmt.Sprintf("%s: section | %s/%s: object | %s/%s: scroll | %s: close",
+		keymap.Label(in.keys.Global.FocusSidebar),
+		keymap.Label(in.keys.List.Collapse), keymap.Label(in.keys.List.Expand),
+		keymap.Label(in.keys.Detail.Up), keymap.Label(in.keys.Detail.Down),
+		keymap.Label(in.keys.Global.Back)))
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		in.styles.Title.Render("Inspector"),
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		object,
+		in.viewport.View(),
+		help,
+	)
+	return in.styles.Container.Width(in.viewport.Width + 2).Render(body)
+}
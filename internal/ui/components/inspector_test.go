@@ -0,0 +1,143 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// inspectorItems are a page and one of its blocks.
+func inspectorItems() []InspectorItem {
+	return []InspectorItem{
+		{Kind: "page", ID: "page-1", Label: "Roadmap", Raw: map[string]string{"object": "page"}, CacheKeys: []string{"meta:page-1", "page-1"}},
+		{Kind: "block", ID: "block-1", Label: "paragraph", Raw: map[string]string{"object": "block"}, Markdown: "Hello **world**"},
+	}
+}
+
+func newTestInspector(requests []notion.RequestRecord) Inspector {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	in := NewInspector(NewInspectorInput{
+		Requests: func() []notion.RequestRecord { return requests },
+		LookupCache: func(key string) (cache.CacheEntry, error) {
+			if key != "page-1" {
+				return cache.CacheEntry{}, fmt.Errorf("read cache entry %s: %w", key, os.ErrNotExist)
+			}
+			return cache.CacheEntry{
+				PageID:    key,
+				Data:      []byte(`{"results":[]}`),
+				Timestamp: now.Add(-10 * time.Minute),
+				TTL:       time.Hour,
+				Hash:      "abc123",
+			}, nil
+		},
+		Now: func() time.Time { return now },
+	})
+	in.SetSize(120, 40)
+	return in
+}
+
+func TestInspectorSections(t *testing.T) {
+	t.Parallel()
+
+	in := newTestInspector(nil)
+	assert.False(t, in.IsOpen())
+	assert.Empty(t, in.View())
+
+	require.NotNil(t, in.Open(inspectorItems(), 0))
+	assert.True(t, in.IsOpen())
+	assert.Equal(t, InspectorObject, in.Tab())
+	assert.Contains(t, in.View(), `"object": "page"`)
+	assert.Contains(t, in.View(), "page 1/2 · Roadmap · page-1")
+
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	in, _ = in.Update(tab)
+	assert.Equal(t, InspectorMarkdown, in.Tab())
+	assert.Contains(t, in.View(), "Only blocks convert to markdown")
+
+	// Moving to the block shows its markdown
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyRight})
+	item, ok := in.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "block-1", item.ID)
+	assert.Contains(t, in.View(), "Hello **world**")
+
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	in, _ = in.Update(tab)
+	assert.Equal(t, InspectorCache, in.Tab())
+	view := in.View()
+	assert.Contains(t, view, "meta:page-1")
+	assert.Contains(t, view, "not cached")
+	assert.Contains(t, view, "ttl        1h0m0s (expires in 50m0s)")
+	assert.Contains(t, view, "hash       abc123")
+	assert.Contains(t, view, "size       14 bytes")
+
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, in.IsOpen())
+}
+
+func TestInspectorRequestLog(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 3, 15, 12, 0, 0, 0, time.Local)
+	in := newTestInspector([]notion.RequestRecord{
+		{Time: at, Kind: notion.RecordWait, Priority: notion.PriorityForeground, Duration: 400 * time.Millisecond},
+		{Time: at, Kind: notion.RecordRequest, Method: "GET", Object: "pages", ObjectID: "page-1", Status: 200, Duration: 183 * time.Millisecond},
+		{Time: at, Kind: notion.RecordRequest, Method: "POST", Object: "search", Status: 429, Duration: 20 * time.Millisecond, Priority: notion.PriorityIdle},
+		{Time: at, Kind: notion.RecordRequest, Method: "GET", Object: "blocks", ObjectID: "block-1", Duration: time.Second, Err: "connection reset"},
+	})
+	in.Open(nil, 0)
+	for i := 0; i < 3; i++ {
+		in, _ = in.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	require.Equal(t, InspectorRequests, in.Tab())
+
+	view := in.View()
+	assert.Contains(t, view, "12:00:00.000  wait")
+	assert.Contains(t, view, "400ms  rate limiter (foreground)")
+	assert.Contains(t, view, "GET    200           183ms  pages/page-1")
+	assert.Contains(t, view, "search (idle)")
+	assert.Contains(t, view, "blocks/block-1  connection reset")
+	assert.Contains(t, view, "no object", "no object is selected")
+}
+
+func TestInspectorRefresh(t *testing.T) {
+	t.Parallel()
+
+	in := newTestInspector(nil)
+	first := in.Open(nil, 0)
+	in.Close()
+	second := in.Open(nil, 0)
+
+	// Only the ticks of the latest opening keep refreshing
+	_, cmd := in.Update(inspectorRefreshMsg{opened: 1})
+	assert.Nil(t, cmd)
+	_, cmd = in.Update(inspectorRefreshMsg{opened: 2})
+	assert.NotNil(t, cmd)
+	assert.NotNil(t, first)
+	assert.NotNil(t, second)
+}
+
+func TestInspectorCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	in := NewInspector(NewInspectorInput{
+		LookupCache: func(key string) (cache.CacheEntry, error) {
+			return cache.CacheEntry{}, errors.New("cache entry is corrupt")
+		},
+	})
+	in.SetSize(120, 40)
+	in.Open(inspectorItems(), 0)
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyTab})
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyTab})
+
+	assert.Contains(t, in.View(), "cache entry is corrupt")
+}
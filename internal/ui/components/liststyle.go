@@ -0,0 +1,39 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// NewListDelegate returns a list delegate that shows descriptions, with its
+// items colored for a theme.
+func NewListDelegate(t theme.Theme) list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	d.ShowDescription = true
+
+	d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(t.Text)
+	d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(t.Muted)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
+		Foreground(t.Accent).
+		BorderLeftForeground(t.Accent)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.
+		Foreground(t.Accent).
+		BorderLeftForeground(t.Accent)
+	d.Styles.DimmedTitle = d.Styles.DimmedTitle.Foreground(t.Muted)
+	d.Styles.DimmedDesc = d.Styles.DimmedDesc.Foreground(t.Subtle)
+	return d
+}
+
+// StyleList colors a list's items, filter prompt and status lines for a
+// theme. The title is left to the caller, as each list styles its own.
+func StyleList(l *list.Model, t theme.Theme) {
+	l.SetDelegate(NewListDelegate(t))
+	l.Styles.FilterPrompt = l.Styles.FilterPrompt.Foreground(t.Accent)
+	l.Styles.FilterCursor = l.Styles.FilterCursor.Foreground(t.Primary)
+	l.Styles.StatusBar = l.Styles.StatusBar.Foreground(t.Muted)
+	l.Styles.StatusEmpty = l.Styles.StatusEmpty.Foreground(t.Muted)
+	l.Styles.NoItems = l.Styles.NoItems.Foreground(t.Muted)
+	l.Styles.ActivePaginationDot = l.Styles.ActivePaginationDot.Foreground(t.Text)
+	l.Styles.InactivePaginationDot = l.Styles.InactivePaginationDot.Foreground(t.Subtle)
+}
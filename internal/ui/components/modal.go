@@ -1,8 +1,12 @@
 package components
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // ModalAction represents an action button in the modal.
@@ -33,28 +37,33 @@ type ModalStyles struct {
 
 // DefaultModalStyles returns the default styles for the modal.
 func DefaultModalStyles() ModalStyles {
+	return NewModalStyles(theme.Default())
+}
+
+// NewModalStyles returns the modal styles for a theme.
+func NewModalStyles(t theme.Theme) ModalStyles {
 	return ModalStyles{
 		Overlay: lipgloss.NewStyle().
-			Background(lipgloss.Color("#000000")).
-			Foreground(lipgloss.Color("#FFFFFF")),
+			Background(t.Secondary).
+			Foreground(t.Text),
 		Container: lipgloss.NewStyle().
 			Padding(2, 4),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")).
+			Foreground(t.Warning).
 			Bold(true).
 			MarginBottom(1),
 		Message: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F3F4F6")).
+			Foreground(t.Text).
 			MarginBottom(2),
 		Actions: lipgloss.NewStyle().
 			MarginTop(1),
 		Action: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(t.Accent).
 			Bold(true).
 			MarginRight(2),
 		Border: lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#F59E0B")).
+			BorderForeground(t.Warning).
 			Padding(1, 2),
 	}
 }
@@ -67,6 +76,7 @@ type Modal struct {
 	width   int
 	height  int
 	styles  ModalStyles
+	dismiss key.Binding
 }
 
 // NewModalInput contains parameters for creating a new Modal.
@@ -76,6 +86,8 @@ type NewModalInput struct {
 	Actions []ModalAction
 	Width   int
 	Height  int
+	Keys    *keymap.KeyMap // optional; the default bindings when nil
+	Theme   *theme.Theme   // optional; the default theme when nil
 }
 
 // NewModal creates a new Modal instance.
@@ -86,7 +98,8 @@ func NewModal(input NewModalInput) Modal {
 		actions: input.Actions,
 		width:   input.Width,
 		height:  input.Height,
-		styles:  DefaultModalStyles(),
+		styles:  NewModalStyles(theme.OrDefault(input.Theme)),
+		dismiss: keymap.OrDefault(input.Keys).Global.Back,
 	}
 }
 
@@ -103,8 +116,8 @@ func (m Modal) Update(msg tea.Msg) (Modal, tea.Cmd) {
 			}
 		}
 
-		// Handle escape to dismiss
-		if msg.String() == "esc" {
+		// Handle back (escape by default) to dismiss
+		if key.Matches(msg, m.dismiss) {
 			return m, func() tea.Msg {
 				return ModalDismissMsg{}
 			}
@@ -191,6 +204,12 @@ func (m Modal) Actions() []ModalAction {
 	return m.actions
 }
 
+// SetKeys sets the bindings the modal responds to. Nil restores the
+// default bindings.
+func (m *Modal) SetKeys(k *keymap.KeyMap) {
+	m.dismiss = keymap.OrDefault(k).Global.Back
+}
+
 // SetStyles updates the modal styles.
 func (m *Modal) SetStyles(styles ModalStyles) {
 	m.styles = styles
@@ -1,9 +1,13 @@
 package components
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // commandItem represents a single command in the palette.
@@ -42,6 +46,7 @@ type CommandPalette struct {
 	width  int
 	height int
 	styles CommandPaletteStyles
+	keys   keymap.PaletteKeys
 }
 
 // CommandPaletteStyles holds the styles for the command palette.
@@ -52,13 +57,18 @@ type CommandPaletteStyles struct {
 
 // DefaultCommandPaletteStyles returns the default styles for the command palette.
 func DefaultCommandPaletteStyles() CommandPaletteStyles {
+	return NewCommandPaletteStyles(theme.Default())
+}
+
+// NewCommandPaletteStyles returns the command palette styles for a theme.
+func NewCommandPaletteStyles(t theme.Theme) CommandPaletteStyles {
 	return CommandPaletteStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7C3AED")).
+			BorderForeground(t.Primary).
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7C3AED")).
+			Foreground(t.Primary).
 			Bold(true).
 			MarginBottom(1),
 	}
@@ -66,26 +76,21 @@ func DefaultCommandPaletteStyles() CommandPaletteStyles {
 
 // NewCommandPalette creates a new command palette with built-in commands.
 func NewCommandPalette() CommandPalette {
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true
-
-	l := list.New([]list.Item{}, delegate, 60, 15)
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 60, 15)
 	l.Title = "Command Palette"
 	l.SetFilteringEnabled(true)
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
 	l.DisableQuitKeybindings()
 
-	styles := DefaultCommandPaletteStyles()
-	l.Styles.Title = styles.Title
-
 	palette := CommandPalette{
 		list:   l,
 		isOpen: false,
 		width:  60,
 		height: 15,
-		styles: styles,
 	}
+	palette.SetKeys(nil)
+	palette.SetTheme(theme.Default())
 
 	// Add built-in commands
 	palette.addBuiltInCommands()
@@ -93,6 +98,21 @@ func NewCommandPalette() CommandPalette {
 	return palette
 }
 
+// SetKeys sets the bindings the palette responds to. Nil restores the
+// default bindings.
+func (p *CommandPalette) SetKeys(k *keymap.KeyMap) {
+	p.keys = keymap.OrDefault(k).Palette
+	p.list.KeyMap.CursorUp = p.keys.Up
+	p.list.KeyMap.CursorDown = p.keys.Down
+}
+
+// SetTheme restyles the palette for a theme.
+func (p *CommandPalette) SetTheme(t theme.Theme) {
+	p.styles = NewCommandPaletteStyles(t)
+	StyleList(&p.list, t)
+	p.list.Styles.Title = p.styles.Title
+}
+
 // addBuiltInCommands adds the default built-in commands.
 func (p *CommandPalette) addBuiltInCommands() {
 	builtInCommands := []commandItem{
@@ -122,7 +142,7 @@ func (p *CommandPalette) addBuiltInCommands() {
 		},
 		{
 			name:        "Export Page",
-			description: "Export current page (coming soon)",
+			description: "Export current page or database to Markdown files",
 			actionType:  "export",
 			action:      func() tea.Cmd { return nil },
 		},
@@ -156,8 +176,8 @@ func (p CommandPalette) Update(msg tea.Msg) (CommandPalette, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, p.keys.Select):
 			if !p.list.SettingFilter() {
 				if item, ok := p.list.SelectedItem().(commandItem); ok {
 					p.isOpen = false
@@ -176,7 +196,7 @@ func (p CommandPalette) Update(msg tea.Msg) (CommandPalette, tea.Cmd) {
 					)
 				}
 			}
-		case "esc":
+		case key.Matches(msg, p.keys.Close):
 			p.isOpen = false
 			p.list.ResetFilter()
 			return p, nil
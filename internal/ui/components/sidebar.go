@@ -1,9 +1,13 @@
 package components
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // Item represents a single item in the sidebar list.
@@ -56,6 +60,7 @@ type Sidebar struct {
 	height     int
 	selectedID string
 	styles     SidebarStyles
+	keys       keymap.ListKeys
 }
 
 // SidebarStyles holds the styles for the sidebar.
@@ -68,17 +73,22 @@ type SidebarStyles struct {
 
 // DefaultSidebarStyles returns the default styles for the sidebar.
 func DefaultSidebarStyles() SidebarStyles {
+	return NewSidebarStyles(theme.Default())
+}
+
+// NewSidebarStyles returns the sidebar styles for a theme.
+func NewSidebarStyles(t theme.Theme) SidebarStyles {
 	return SidebarStyles{
 		List: lipgloss.NewStyle().
 			Padding(1, 0),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7C3AED")).
+			Foreground(t.Primary).
 			Bold(true).
 			MarginBottom(1),
 		Item: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F3F4F6")),
+			Foreground(t.Text),
 		SelectedID: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(t.Accent).
 			Bold(true),
 	}
 }
@@ -89,6 +99,8 @@ type NewSidebarInput struct {
 	Width  int
 	Height int
 	Title  string
+	Keys   *keymap.KeyMap // optional; the default bindings when nil
+	Theme  *theme.Theme   // optional; the default theme when nil
 }
 
 // NewSidebar creates a new sidebar component.
@@ -98,10 +110,8 @@ func NewSidebar(input NewSidebarInput) Sidebar {
 		items[i] = item
 	}
 
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true
-
-	l := list.New(items, delegate, input.Width, input.Height)
+	t := theme.OrDefault(input.Theme)
+	l := list.New(items, NewListDelegate(t), input.Width, input.Height)
 	l.Title = input.Title
 	if l.Title == "" {
 		l.Title = "Pages"
@@ -111,15 +121,13 @@ func NewSidebar(input NewSidebarInput) Sidebar {
 	l.SetShowHelp(true)
 	l.DisableQuitKeybindings()
 
-	styles := DefaultSidebarStyles()
-	l.Styles.Title = styles.Title
-
 	sidebar := Sidebar{
 		list:   l,
 		width:  input.Width,
 		height: input.Height,
-		styles: styles,
 	}
+	sidebar.SetKeys(input.Keys)
+	sidebar.SetTheme(t)
 
 	if len(input.Items) > 0 {
 		sidebar.selectedID = input.Items[0].id
@@ -142,7 +150,7 @@ func (s Sidebar) Update(msg tea.Msg) (Sidebar, tea.Cmd) {
 		// Note: '/' key will be handled by the list itself to activate filtering
 		// We just need to ensure filtering is enabled (done in NewSidebar)
 
-		if msg.String() == "enter" && !s.list.SettingFilter() {
+		if key.Matches(msg, s.keys.Select) && !s.list.SettingFilter() {
 			if item, ok := s.list.SelectedItem().(Item); ok {
 				s.selectedID = item.id
 				return s, func() tea.Msg {
@@ -204,6 +212,21 @@ func (s Sidebar) SelectedIndex() int {
 	return s.list.Index()
 }
 
+// SetKeys sets the bindings the sidebar responds to. Nil restores the
+// default bindings.
+func (s *Sidebar) SetKeys(k *keymap.KeyMap) {
+	s.keys = keymap.OrDefault(k).List
+	s.list.KeyMap.CursorUp = s.keys.Up
+	s.list.KeyMap.CursorDown = s.keys.Down
+}
+
+// SetTheme restyles the sidebar for a theme.
+func (s *Sidebar) SetTheme(t theme.Theme) {
+	s.styles = NewSidebarStyles(t)
+	StyleList(&s.list, t)
+	s.list.Styles.Title = s.styles.Title
+}
+
 // SetSize updates the sidebar dimensions.
 func (s *Sidebar) SetSize(width, height int) {
 	s.width = width
@@ -252,3 +275,17 @@ func (s Sidebar) FilterValue() string {
 func (s Sidebar) VisibleItemCount() int {
 	return len(s.list.VisibleItems())
 }
+
+// OnScreenIDs returns the IDs of the items on the currently displayed list page.
+func (s Sidebar) OnScreenIDs() []string {
+	visible := s.list.VisibleItems()
+	start, end := s.list.Paginator.GetSliceBounds(len(visible))
+
+	ids := make([]string, 0, end-start)
+	for _, li := range visible[start:end] {
+		if item, ok := li.(Item); ok {
+			ids = append(ids, item.id)
+		}
+	}
+	return ids
+}
@@ -504,3 +504,27 @@ func TestSidebarItems(t *testing.T) {
 		assert.Equal(t, originalItems[i].ID(), item.ID())
 	}
 }
+
+func TestSidebarOnScreenIDs(t *testing.T) {
+	t.Parallel()
+
+	items := make([]Item, 0, 50)
+	for i := 0; i < 50; i++ {
+		id := string(rune('A' + i%26))
+		items = append(items, NewItem("Item "+id, "", id+string(rune('0'+i/26))))
+	}
+
+	sidebar := NewSidebar(NewSidebarInput{
+		Items:  items,
+		Width:  40,
+		Height: 12,
+	})
+
+	ids := sidebar.OnScreenIDs()
+	require.NotEmpty(t, ids)
+	assert.Less(t, len(ids), len(items), "only the current list page is on screen")
+	assert.Equal(t, items[0].ID(), ids[0])
+
+	empty := NewSidebar(NewSidebarInput{Width: 40, Height: 12})
+	assert.Empty(t, empty.OnScreenIDs())
+}
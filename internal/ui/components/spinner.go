@@ -4,6 +4,8 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // Spinner wraps the bubbles spinner component.
@@ -16,12 +18,18 @@ type Spinner struct {
 func NewSpinner(message string) Spinner {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED"))
 
-	return Spinner{
+	sp := Spinner{
 		spinner: s,
 		message: message,
 	}
+	sp.SetTheme(theme.Default())
+	return sp
+}
+
+// SetTheme colors the spinner for a theme.
+func (s *Spinner) SetTheme(t theme.Theme) {
+	s.spinner.Style = lipgloss.NewStyle().Foreground(t.Primary)
 }
 
 // Init initializes the spinner.
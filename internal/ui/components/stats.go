@@ -0,0 +1,264 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// statsRefreshInterval is how often an open stats panel re-reads the
+// aggregates.
+const statsRefreshInterval = time.Second
+
+// statsRefreshMsg ticks while the stats panel is open. Ticks of an earlier
+// opening are dropped.
+type statsRefreshMsg struct {
+	opened int
+}
+
+// StatsPanel is an overlay showing how the session used the Notion API and
+// the cache: requests per endpoint with their latency, rate limiter waits,
+// 429s and retries, the views that spent most time on the API and the
+// cache hit ratio.
+type StatsPanel struct {
+	isOpen   bool
+	opened   int // counts openings, to tell their ticks apart
+	viewport viewport.Model
+	api      func() notion.APIStats
+	cache    func() cache.CacheStats
+	now      func() time.Time
+	keys     *keymap.KeyMap
+	styles   InspectorStyles
+}
+
+// NewStatsPanelInput contains the parameters for creating a StatsPanel.
+type NewStatsPanelInput struct {
+	// API returns the client's aggregates; optional, no requests are shown
+	// when nil
+	API func() notion.APIStats
+	// Cache returns the cache counters; optional, the cache is shown as not
+	// in use when nil
+	Cache func() cache.CacheStats
+	Keys  *keymap.KeyMap   // optional; the default bindings when nil
+	Theme *theme.Theme     // optional; the default theme when nil
+	Now   func() time.Time // optional; time.Now when nil
+}
+
+// NewStatsPanel creates a closed stats panel.
+func NewStatsPanel(input NewStatsPanelInput) StatsPanel {
+	s := StatsPanel{
+		viewport: viewport.New(0, 0),
+		api:      input.API,
+		cache:    input.Cache,
+		now:      input.Now,
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	s.SetKeys(input.Keys)
+	s.SetTheme(theme.OrDefault(input.Theme))
+	return s
+}
+
+// SetKeys sets the bindings the panel responds to. Nil restores the
+// default bindings.
+func (s *StatsPanel) SetKeys(k *keymap.KeyMap) {
+	s.keys = keymap.OrDefault(k)
+	keys := s.keys.Detail
+	s.viewport.KeyMap.Up = keys.Up
+	s.viewport.KeyMap.Down = keys.Down
+	s.viewport.KeyMap.PageUp = keys.PageUp
+	s.viewport.KeyMap.PageDown = keys.PageDown
+	s.viewport.KeyMap.HalfPageUp = keys.HalfPageUp
+	s.viewport.KeyMap.HalfPageDown = keys.HalfPageDown
+}
+
+// SetTheme restyles the panel for a theme. It shares the inspector's styles.
+func (s *StatsPanel) SetTheme(t theme.Theme) {
+	s.styles = NewInspectorStyles(t)
+}
+
+// SetSize sets the screen size the panel is drawn on. It takes most of the
+// screen.
+func (s *StatsPanel) SetSize(width, height int) {
+	// Border and padding take 4 columns; the border, title and help take
+	// 4 rows
+	s.viewport.Width = max(width*9/10-4, 20)
+	s.viewport.Height = max(height*9/10-4, 3)
+	s.refresh()
+}
+
+// Open shows the panel and starts refreshing it.
+func (s *StatsPanel) Open() tea.Cmd {
+	s.isOpen = true
+	s.opened++
+	s.refresh()
+	s.viewport.GotoTop()
+	return statsTick(s.opened)
+}
+
+// Close hides the panel.
+func (s *StatsPanel) Close() {
+	s.isOpen = false
+}
+
+// IsOpen reports whether the panel is shown.
+func (s StatsPanel) IsOpen() bool {
+	return s.isOpen
+}
+
+// statsTick schedules the next refresh.
+func statsTick(opened int) tea.Cmd {
+	return tea.Tick(statsRefreshInterval, func(time.Time) tea.Msg {
+		return statsRefreshMsg{opened: opened}
+	})
+}
+
+// Init implements tea.Model.
+func (s StatsPanel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keys while the panel is open. Back closes it; the page
+// scrolling keys scroll it.
+func (s StatsPanel) Update(msg tea.Msg) (StatsPanel, tea.Cmd) {
+	if !s.isOpen {
+		return s, nil
+	}
+
+	switch msg := msg.(type) {
+	case statsRefreshMsg:
+		if msg.opened != s.opened {
+			return s, nil
+		}
+		s.refresh()
+		return s, statsTick(s.opened)
+
+	case tea.KeyMsg:
+		if key.Matches(msg, s.keys.Global.Back) {
+			s.Close()
+			return s, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+	return s, cmd
+}
+
+// refresh renders the current aggregates into the viewport, keeping the
+// scroll position.
+func (s *StatsPanel) refresh() {
+	if !s.isOpen {
+		return
+	}
+	offset := s.viewport.YOffset
+	s.viewport.SetContent(s.content())
+	s.viewport.SetYOffset(offset)
+}
+
+// content returns the text of the panel.
+func (s StatsPanel) content() string {
+	var api notion.APIStats
+	if s.api != nil {
+		api = s.api()
+	}
+
+	var b strings.Builder
+	b.WriteString(s.styles.Header.Render("API") + "\n")
+	if !api.Since.IsZero() {
+		fmt.Fprintf(&b, "  session      %s\n", s.now().Sub(api.Since).Round(time.Second))
+	}
+	fmt.Fprintf(&b, "  requests     %d (%d errors)\n", api.Requests, api.Errors)
+	fmt.Fprintf(&b, "  rate limited %d (%d retried)\n", api.RateLimited, api.Retries)
+	fmt.Fprintf(&b, "  limiter wait %s over %d calls\n", api.Waited.Round(time.Millisecond), api.Waits)
+
+	b.WriteString("\n" + s.styles.Header.Render("Endpoints") + "\n")
+	if len(api.Endpoints) == 0 {
+		b.WriteString(s.styles.Muted.Render("  No Notion API calls yet.") + "\n")
+	} else {
+		b.WriteString(s.styles.Muted.Render(fmt.Sprintf("  %-32s %8s %6s %6s %8s %8s",
+			"endpoint", "requests", "errors", "429s", "p50", "p95")) + "\n")
+		for _, e := range api.Endpoints {
+			line := fmt.Sprintf("  %-32s %8d %6d %6d %8s %8s", e.Endpoint, e.Requests, e.Errors,
+				e.RateLimited, e.P50.Round(time.Millisecond), e.P95.Round(time.Millisecond))
+			if e.Errors > 0 {
+				line = s.styles.Error.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if len(api.Views) > 0 {
+		b.WriteString("\n" + s.styles.Header.Render("Views") + "\n")
+		b.WriteString(s.styles.Muted.Render(fmt.Sprintf("  %-12s %8s %10s %10s",
+			"view", "requests", "latency", "waited")) + "\n")
+		for _, v := range api.Views {
+			fmt.Fprintf(&b, "  %-12s %8d %10s %10s\n", v.View, v.Requests,
+				v.Latency.Round(time.Millisecond), v.Waited.Round(time.Millisecond))
+		}
+	}
+
+	b.WriteString("\n" + s.styles.Header.Render("Cache") + "\n")
+	if s.cache == nil {
+		b.WriteString(s.styles.Muted.Render("  The cache is not in use."))
+		return b.String()
+	}
+	c := s.cache()
+	ratio := "n/a"
+	if lookups := c.HitCount + c.MissCount; lookups > 0 {
+		ratio = fmt.Sprintf("%.0f%%", float64(c.HitCount)*100/float64(lookups))
+	}
+	fmt.Fprintf(&b, "  hit ratio    %s (%d hits, %d misses)\n", ratio, c.HitCount, c.MissCount)
+	fmt.Fprintf(&b, "  size         %s written this session\n", formatBytes(c.Size))
+	if c.Quarantined > 0 {
+		b.WriteString(s.styles.Error.Render(fmt.Sprintf("  quarantined  %d corrupt entries", c.Quarantined)))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatBytes renders a byte count for humans. The cache size counts down
+// when entries are deleted, so it can be negative.
+func formatBytes(n int64) string {
+	if n < 0 {
+		return "-" + formatBytes(-n)
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// View renders the panel, or nothing when it is closed.
+func (s StatsPanel) View() string {
+	if !s.isOpen {
+		return ""
+	}
+
+	help := s.styles.Muted.Render(fmt.Sprintf("%s/%s: scroll | %s: close",
+		keymap.Label(s.keys.Detail.Up), keymap.Label(s.keys.Detail.Down),
+		keymap.Label(s.keys.Global.Back)))
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		s.styles.Title.Render("API Stats"),
+		s.viewport.View(),
+		help,
+	)
+	return s.styles.Container.Width(s.viewport.Width + 2).Render(body)
+}
@@ -0,0 +1,108 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+func TestStatsPanel(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	s := NewStatsPanel(NewStatsPanelInput{
+		API: func() notion.APIStats {
+			return notion.APIStats{
+				Since:       now.Add(-5 * time.Minute),
+				Requests:    12,
+				Errors:      1,
+				RateLimited: 1,
+				Retries:     1,
+				Waits:       3,
+				Waited:      1500 * time.Millisecond,
+				Endpoints: []notion.EndpointStats{
+					{Endpoint: "GET /blocks/{id}/children", Requests: 8, P50: 180 * time.Millisecond, P95: 420 * time.Millisecond},
+					{Endpoint: "POST /databases/{id}/query", Requests: 4, Errors: 1, RateLimited: 1, P50: 300 * time.Millisecond, P95: 900 * time.Millisecond},
+				},
+				Views: []notion.ViewStats{{View: "detail", Requests: 8, Latency: 2 * time.Second, Waited: time.Second}},
+			}
+		},
+		Cache: func() cache.CacheStats {
+			return cache.CacheStats{HitCount: 3, MissCount: 1, Size: 2048}
+		},
+		Now: func() time.Time { return now },
+	})
+	s.SetSize(140, 50)
+
+	assert.False(t, s.IsOpen())
+	assert.Empty(t, s.View())
+	assert.NotNil(t, s.Open())
+
+	view := s.View()
+	assert.Contains(t, view, "API Stats")
+	assert.Contains(t, view, "session      5m0s")
+	assert.Contains(t, view, "requests     12 (1 errors)")
+	assert.Contains(t, view, "rate limited 1 (1 retried)")
+	assert.Contains(t, view, "limiter wait 1.5s over 3 calls")
+	assert.Contains(t, view, "GET /blocks/{id}/children               8      0      0    180ms    420ms")
+	assert.Contains(t, view, "detail              8         2s         1s")
+	assert.Contains(t, view, "hit ratio    75% (3 hits, 1 misses)")
+	assert.Contains(t, view, "size         2.0 KB written this session")
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, s.IsOpen())
+}
+
+func TestStatsPanelEmpty(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatsPanel(NewStatsPanelInput{})
+	s.SetSize(120, 40)
+	s.Open()
+
+	view := s.View()
+	assert.Contains(t, view, "No Notion API calls yet.")
+	assert.Contains(t, view, "The cache is not in use.")
+}
+
+func TestStatsPanelRefresh(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatsPanel(NewStatsPanelInput{})
+	s.Open()
+	s.Close()
+	s.Open()
+
+	// Only the ticks of the latest opening keep refreshing
+	_, cmd := s.Update(statsRefreshMsg{opened: 1})
+	assert.Nil(t, cmd)
+	_, cmd = s.Update(statsRefreshMsg{opened: 2})
+	assert.NotNil(t, cmd)
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{n: 0, want: "0 B"},
+		{n: 1023, want: "1023 B"},
+		{n: 1536, want: "1.5 KB"},
+		{n: 3 << 20, want: "3.0 MB"},
+		{n: -2048, want: "-2.0 KB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatBytes(tt.n))
+		})
+	}
+}
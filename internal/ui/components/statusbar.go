@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // Mode constants for the status bar display.
@@ -46,30 +48,35 @@ type StatusBarStyles struct {
 
 // DefaultStatusBarStyles returns the default styles for the status bar.
 func DefaultStatusBarStyles() StatusBarStyles {
+	return NewStatusBarStyles(theme.Default())
+}
+
+// NewStatusBarStyles returns the status bar styles for a theme.
+func NewStatusBarStyles(t theme.Theme) StatusBarStyles {
 	return StatusBarStyles{
 		Container: lipgloss.NewStyle().
-			Background(lipgloss.Color("#374151")).
-			Foreground(lipgloss.Color("#F3F4F6")),
+			Background(t.Secondary).
+			Foreground(t.Text),
 		Mode: lipgloss.NewStyle().
 			Bold(true).
 			Padding(0, 1),
 		SyncStatus: lipgloss.NewStyle().
 			Padding(0, 1),
 		HelpText: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(t.Muted).
 			Italic(true).
 			Padding(0, 1),
 
 		// Mode colors
-		ModeBrowseColor:  lipgloss.Color("#10B981"),
-		ModeEditColor:    lipgloss.Color("#F59E0B"),
-		ModeCommandColor: lipgloss.Color("#7C3AED"),
+		ModeBrowseColor:  t.Accent,
+		ModeEditColor:    t.Warning,
+		ModeCommandColor: t.Primary,
 
 		// Sync status colors
-		SyncedColor:  lipgloss.Color("#10B981"),
-		SyncingColor: lipgloss.Color("#F59E0B"),
-		OfflineColor: lipgloss.Color("#6B7280"),
-		ErrorColor:   lipgloss.Color("#EF4444"),
+		SyncedColor:  t.Success,
+		SyncingColor: t.Warning,
+		OfflineColor: t.Muted,
+		ErrorColor:   t.Error,
 	}
 }
 
@@ -97,6 +104,7 @@ type StatusBar struct {
 	connectionState ConnectionState
 	lastSyncTime    time.Time
 	showSyncTime    bool
+	profile         string // active config profile; not shown when empty
 }
 
 // NewStatusBar creates a new status bar with default values.
@@ -127,6 +135,17 @@ func (s *StatusBar) SetHelpText(text string) {
 	s.helpText = text
 }
 
+// SetProfile sets the config profile shown after the sync status. An empty
+// name hides it.
+func (s *StatusBar) SetProfile(name string) {
+	s.profile = name
+}
+
+// Profile returns the config profile shown.
+func (s StatusBar) Profile() string {
+	return s.profile
+}
+
 // SetWidth updates the status bar width.
 func (s *StatusBar) SetWidth(width int) {
 	s.width = width
@@ -311,6 +330,9 @@ func (s StatusBar) View() string {
 	}
 
 	leftContent := modeText + separator + s.styles.Container.Render(syncContent)
+	if s.profile != "" {
+		leftContent += separator + s.styles.Container.Render(s.profile)
+	}
 
 	// Build right side: help text
 	rightContent := s.styles.HelpText.Render(s.helpText)
@@ -352,6 +374,11 @@ func (s *StatusBar) SetStyles(styles StatusBarStyles) {
 	s.styles = styles
 }
 
+// SetTheme restyles the status bar for a theme.
+func (s *StatusBar) SetTheme(t theme.Theme) {
+	s.styles = NewStatusBarStyles(t)
+}
+
 // Styles returns the current styles.
 func (s StatusBar) Styles() StatusBarStyles {
 	return s.styles
@@ -7,6 +7,9 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 func TestNewStatusBar(t *testing.T) {
@@ -556,6 +559,33 @@ func TestStatusBarSetStyles(t *testing.T) {
 	assert.Equal(t, customStyles.ModeCommandColor, retrievedStyles.ModeCommandColor)
 }
 
+func TestStatusBarSetTheme(t *testing.T) {
+	t.Parallel()
+
+	light, ok := theme.Builtin(theme.Light)
+	require.True(t, ok)
+
+	sb := NewStatusBar()
+	sb.SetTheme(light)
+
+	styles := sb.Styles()
+	assert.Equal(t, light.Accent, styles.ModeBrowseColor)
+	assert.Equal(t, light.Error, styles.ErrorColor)
+	assert.Equal(t, lipgloss.TerminalColor(light.Secondary), styles.Container.GetBackground())
+}
+
+func TestStatusBarProfile(t *testing.T) {
+	t.Parallel()
+
+	sb := NewStatusBar()
+	sb.SetWidth(120)
+	assert.NotContains(t, sb.View(), "work")
+
+	sb.SetProfile("work")
+	assert.Equal(t, "work", sb.Profile())
+	assert.Contains(t, sb.View(), "| work")
+}
+
 func TestStatusBarViewLayout(t *testing.T) {
 	t.Parallel()
 
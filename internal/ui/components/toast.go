@@ -0,0 +1,132 @@
+package components
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// ToastLevel is how a toast is styled.
+type ToastLevel int
+
+const (
+	// ToastInfo reports something that went as expected.
+	ToastInfo ToastLevel = iota
+	// ToastError reports a problem that did not stop the TUI.
+	ToastError
+)
+
+// DefaultToastDuration is how long a toast is shown.
+const DefaultToastDuration = 5 * time.Second
+
+// ToastExpiredMsg is sent when a toast's time is up.
+type ToastExpiredMsg struct {
+	id int
+}
+
+// ToastStyles holds the styles for a toast.
+type ToastStyles struct {
+	Info  lipgloss.Style
+	Error lipgloss.Style
+}
+
+// DefaultToastStyles returns the default styles for a toast.
+func DefaultToastStyles() ToastStyles {
+	return NewToastStyles(theme.Default())
+}
+
+// NewToastStyles returns the toast styles for a theme.
+func NewToastStyles(t theme.Theme) ToastStyles {
+	return ToastStyles{
+		Info: lipgloss.NewStyle().
+			Background(t.Secondary).
+			Foreground(t.Success).
+			Bold(true).
+			Padding(0, 1),
+		Error: lipgloss.NewStyle().
+			Background(t.Secondary).
+			Foreground(t.Error).
+			Bold(true).
+			Padding(0, 1),
+	}
+}
+
+// Toast is a short message shown for a while in place of the status bar.
+type Toast struct {
+	message string
+	level   ToastLevel
+	id      int // tells the expiry of the current toast from earlier ones
+	visible bool
+	width   int
+	styles  ToastStyles
+}
+
+// NewToast creates a hidden toast.
+func NewToast() Toast {
+	return Toast{styles: DefaultToastStyles()}
+}
+
+// Show displays a message and returns a command that hides it after d.
+// A toast shown before it is replaced.
+func (t *Toast) Show(message string, level ToastLevel, d time.Duration) tea.Cmd {
+	t.id++
+	t.message = message
+	t.level = level
+	t.visible = true
+
+	id := t.id
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return ToastExpiredMsg{id: id}
+	})
+}
+
+// Update hides the toast when its time is up.
+func (t Toast) Update(msg tea.Msg) (Toast, tea.Cmd) {
+	if msg, ok := msg.(ToastExpiredMsg); ok && msg.id == t.id {
+		t.visible = false
+	}
+	return t, nil
+}
+
+// View renders the toast, or nothing when it is hidden.
+func (t Toast) View() string {
+	if !t.visible {
+		return ""
+	}
+	style := t.styles.Info
+	if t.level == ToastError {
+		style = t.styles.Error
+	}
+	if t.width > 0 {
+		style = style.Width(t.width)
+	}
+	return style.Render(t.message)
+}
+
+// Visible reports whether the toast is shown.
+func (t Toast) Visible() bool {
+	return t.visible
+}
+
+// Message returns the message of the current toast.
+func (t Toast) Message() string {
+	return t.message
+}
+
+// Level returns the level of the current toast.
+func (t Toast) Level() ToastLevel {
+	return t.level
+}
+
+// SetWidth sets the width the toast fills.
+func (t *Toast) SetWidth(width int) {
+	t.width = width
+}
+
+// SetTheme restyles the toast for a theme.
+func (t *Toast) SetTheme(th theme.Theme) {
+	t.styles = NewToastStyles(th)
+}
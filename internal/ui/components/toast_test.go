@@ -0,0 +1,33 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToast(t *testing.T) {
+	t.Parallel()
+
+	toast := NewToast()
+	assert.False(t, toast.Visible())
+	assert.Empty(t, toast.View())
+
+	first := toast.Show("config reloaded", ToastInfo, time.Millisecond)
+	require.NotNil(t, first)
+	second := toast.Show("invalid config", ToastError, time.Millisecond)
+	require.NotNil(t, second)
+
+	assert.True(t, toast.Visible())
+	assert.Equal(t, ToastError, toast.Level())
+	assert.Contains(t, toast.View(), "invalid config")
+
+	// The first toast's expiry must not hide the one that replaced it
+	toast, _ = toast.Update(first())
+	assert.True(t, toast.Visible())
+
+	toast, _ = toast.Update(second())
+	assert.False(t, toast.Visible())
+}
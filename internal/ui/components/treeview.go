@@ -3,8 +3,12 @@ package components
 import (
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // TreeViewStyles holds the styles for the tree view.
@@ -21,26 +25,31 @@ type TreeViewStyles struct {
 
 // DefaultTreeViewStyles returns the default styles for the tree view.
 func DefaultTreeViewStyles() TreeViewStyles {
+	return NewTreeViewStyles(theme.Default())
+}
+
+// NewTreeViewStyles returns the tree view styles for a theme.
+func NewTreeViewStyles(t theme.Theme) TreeViewStyles {
 	return TreeViewStyles{
 		Container: lipgloss.NewStyle().
 			Padding(1, 1),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7C3AED")).
+			Foreground(t.Primary).
 			Bold(true).
 			MarginBottom(1),
 		Node: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E5E7EB")),
+			Foreground(t.Text),
 		SelectedNode: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(t.Accent).
 			Bold(true),
 		DatabaseIcon: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")),
+			Foreground(t.Warning),
 		PageIcon: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#60A5FA")),
+			Foreground(t.Info),
 		Indent: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4B5563")),
+			Foreground(t.Subtle),
 		ExpandIcon: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")),
+			Foreground(t.Muted),
 	}
 }
 
@@ -57,6 +66,7 @@ type TreeView struct {
 	width   int
 	height  int
 	styles  TreeViewStyles
+	keys    keymap.ListKeys
 	focused bool
 	loading bool
 	err     error
@@ -67,6 +77,8 @@ type NewTreeViewInput struct {
 	Title  string
 	Width  int
 	Height int
+	Keys   *keymap.KeyMap // optional; the default bindings when nil
+	Theme  *theme.Theme   // optional; the default theme when nil
 }
 
 // NewTreeView creates a new tree view component.
@@ -81,7 +93,8 @@ func NewTreeView(input NewTreeViewInput) TreeView {
 		title:   title,
 		width:   input.Width,
 		height:  input.Height,
-		styles:  DefaultTreeViewStyles(),
+		styles:  NewTreeViewStyles(theme.OrDefault(input.Theme)),
+		keys:    keymap.OrDefault(input.Keys).List,
 		focused: false,
 		loading: true,
 		err:     nil,
@@ -101,16 +114,16 @@ func (tv TreeView) Update(msg tea.Msg) (TreeView, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, tv.keys.Up):
 			tv.tree.MoveUp()
-		case "down", "j":
+		case key.Matches(msg, tv.keys.Down):
 			tv.tree.MoveDown()
-		case "left", "h":
+		case key.Matches(msg, tv.keys.Collapse):
 			tv.tree.Collapse()
-		case "right", "l":
+		case key.Matches(msg, tv.keys.Expand):
 			tv.tree.Expand()
-		case "enter":
+		case key.Matches(msg, tv.keys.Select):
 			// If has children and not expanded, expand first
 			if node := tv.tree.Selected(); node != nil {
 				if node.HasChildren() && !node.Expanded {
@@ -125,7 +138,7 @@ func (tv TreeView) Update(msg tea.Msg) (TreeView, tea.Cmd) {
 					}
 				}
 			}
-		case " ":
+		case key.Matches(msg, tv.keys.Toggle):
 			// Space toggles expand/collapse
 			tv.tree.Toggle()
 		}
@@ -256,6 +269,17 @@ func (tv *TreeView) SetError(err error) {
 	tv.loading = false
 }
 
+// SetKeys sets the bindings the tree view responds to. Nil restores the
+// default bindings.
+func (tv *TreeView) SetKeys(k *keymap.KeyMap) {
+	tv.keys = keymap.OrDefault(k).List
+}
+
+// SetTheme restyles the tree view for a theme.
+func (tv *TreeView) SetTheme(t theme.Theme) {
+	tv.styles = NewTreeViewStyles(t)
+}
+
 // SetFocused sets whether the tree view has focus.
 func (tv *TreeView) SetFocused(focused bool) {
 	tv.focused = focused
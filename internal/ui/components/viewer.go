@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,11 +11,14 @@ import (
 	"github.com/jomei/notionapi"
 
 	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // ContentLoadedMsg contains loaded content or error.
 type ContentLoadedMsg struct {
 	content string
+	offset  int // line to scroll to
 	err     error
 }
 
@@ -49,11 +53,13 @@ type PageViewer struct {
 	content  string
 	pageID   string
 	blocks   []notionapi.Block
+	scrollTo string
 	ready    bool
 	loading  bool
 	err      error
 	width    int
 	height   int
+	theme    theme.Theme
 }
 
 // NewPageViewerInput contains parameters for creating a new PageViewer.
@@ -61,6 +67,11 @@ type PageViewer struct {
 type NewPageViewerInput struct {
 	Width  int
 	Height int
+	// ScrollTo is the ID of a top-level block to scroll into view once the
+	// content is rendered. Optional.
+	ScrollTo string
+	Keys     *keymap.KeyMap // optional; the default bindings when nil
+	Theme    *theme.Theme   // optional; the default theme when nil
 }
 
 // NewPageViewer creates a new PageViewer component with the given dimensions.
@@ -70,13 +81,17 @@ func NewPageViewer(input NewPageViewerInput) PageViewer {
 	vp.MouseWheelEnabled = true
 	vp.MouseWheelDelta = 3
 
-	return PageViewer{
+	pv := PageViewer{
 		viewport: vp,
+		scrollTo: input.ScrollTo,
 		width:    input.Width,
 		height:   input.Height,
+		theme:    theme.OrDefault(input.Theme),
 		ready:    false,
 		loading:  false,
 	}
+	pv.SetKeys(input.Keys)
+	return pv
 }
 
 // Init initializes the PageViewer component.
@@ -110,6 +125,7 @@ func (pv *PageViewer) Update(msg tea.Msg) (ViewerInterface, tea.Cmd) {
 
 		pv.content = msg.Content()
 		pv.viewport.SetContent(msg.Content())
+		pv.viewport.SetYOffset(msg.offset)
 		pv.ready = true
 		pv.loading = false
 		return pv, nil
@@ -144,11 +160,11 @@ func (pv *PageViewer) Update(msg tea.Msg) (ViewerInterface, tea.Cmd) {
 // View renders the PageViewer component.
 // Shows loading state, error state, or the viewport with content.
 func (pv PageViewer) View() string {
-	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
-	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(pv.theme.Muted)
+	errorStyle := lipgloss.NewStyle().Foreground(pv.theme.Error).Bold(true)
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#374151")).
+		BorderForeground(pv.theme.Border).
 		Padding(1, 2).
 		Width(pv.width).
 		Height(pv.height)
@@ -183,9 +199,15 @@ func (pv PageViewer) SetBlocks(blocks []notionapi.Block) tea.Cmd {
 			return ErrorMsg{message: "failed to convert blocks", err: err}
 		}
 
-		// Render with glamour using dark theme
+		// Render with glamour using the theme's colors, reduced to what the
+		// terminal supports
+		style, err := pv.theme.GlamourStyle()
+		if err != nil {
+			return ErrorMsg{message: "failed to build markdown style", err: err}
+		}
 		renderer, err := glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
+			glamour.WithStylesFromJSONBytes(style),
+			glamour.WithColorProfile(lipgloss.ColorProfile()),
 			glamour.WithWordWrap(pv.width-4), // Account for padding
 		)
 		if err != nil {
@@ -197,10 +219,37 @@ func (pv PageViewer) SetBlocks(blocks []notionapi.Block) tea.Cmd {
 			return ErrorMsg{message: "failed to render markdown", err: err}
 		}
 
-		return ContentLoadedMsg{content: rendered, err: nil}
+		return ContentLoadedMsg{content: rendered, offset: pv.scrollOffset(renderer, blocks), err: nil}
 	}
 }
 
+// scrollOffset returns the line of the rendered content where the block to
+// scroll to starts, found by rendering the blocks before it on their own.
+func (pv PageViewer) scrollOffset(renderer *glamour.TermRenderer, blocks []notionapi.Block) int {
+	if pv.scrollTo == "" {
+		return 0
+	}
+	target := notion.NormalizeID(pv.scrollTo)
+	for i, block := range blocks {
+		if notion.NormalizeID(string(block.GetID())) != target {
+			continue
+		}
+		if i == 0 {
+			return 0
+		}
+		markdown, err := notion.ConvertBlocksToMarkdown(blocks[:i])
+		if err != nil {
+			return 0
+		}
+		rendered, err := renderer.Render(markdown)
+		if err != nil {
+			return 0
+		}
+		return strings.Count(strings.TrimRight(rendered, "\n"), "\n") + 1
+	}
+	return 0
+}
+
 // SetContent directly sets the viewport content.
 // Use this for pre-rendered content, or SetBlocks for Notion blocks.
 func (pv *PageViewer) SetContent(content string) {
@@ -211,6 +260,24 @@ func (pv *PageViewer) SetContent(content string) {
 	pv.err = nil
 }
 
+// SetKeys sets the bindings that scroll the viewer. Nil restores the
+// default bindings.
+func (pv *PageViewer) SetKeys(k *keymap.KeyMap) {
+	keys := keymap.OrDefault(k).Detail
+	pv.viewport.KeyMap.Up = keys.Up
+	pv.viewport.KeyMap.Down = keys.Down
+	pv.viewport.KeyMap.PageUp = keys.PageUp
+	pv.viewport.KeyMap.PageDown = keys.PageDown
+	pv.viewport.KeyMap.HalfPageUp = keys.HalfPageUp
+	pv.viewport.KeyMap.HalfPageDown = keys.HalfPageDown
+}
+
+// SetTheme sets the theme the viewer's chrome and markdown use. Content
+// already rendered keeps its colors until blocks are set again.
+func (pv *PageViewer) SetTheme(t theme.Theme) {
+	pv.theme = t
+}
+
 // SetSize updates the viewport dimensions.
 // Useful for responsive layout changes.
 func (pv *PageViewer) SetSize(width, height int) {
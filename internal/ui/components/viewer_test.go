@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -517,6 +518,26 @@ func TestPageViewer_ScrollState(t *testing.T) {
 	assert.LessOrEqual(t, percent, 1.0, "scroll percent should be <= 1")
 }
 
+func TestPageViewer_ScrollTo(t *testing.T) {
+	t.Parallel()
+
+	var blocks []notionapi.Block
+	for i := 0; i < 30; i++ {
+		blocks = append(blocks, testhelpers.NewParagraphBlock(fmt.Sprintf("Paragraph %d.", i)))
+	}
+	target := blocks[20].(*notionapi.ParagraphBlock)
+	target.ID = "target-block"
+
+	pv := NewPageViewer(NewPageViewerInput{Width: 80, Height: 10, ScrollTo: "target-block"})
+	msg, ok := pv.SetBlocks(blocks)().(ContentLoadedMsg)
+	require.True(t, ok)
+
+	_, _ = pv.Update(msg)
+	assert.False(t, pv.AtTop(), "should scroll down to the block")
+	assert.Contains(t, pv.viewport.View(), "Paragraph 20.")
+	assert.NotContains(t, pv.viewport.View(), "Paragraph 19.", "blocks before it are scrolled past")
+}
+
 func TestPageViewer_GettersAndSetters(t *testing.T) {
 	t.Parallel()
 
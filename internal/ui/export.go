@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Panandika/notion-tui/internal/export"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/pages"
+)
+
+// exportProgressMsg reports a page written by an export started from the
+// command palette.
+type exportProgressMsg struct {
+	progress export.Progress
+	updates  <-chan tea.Msg
+}
+
+// exportDoneMsg is sent when an export started from the command palette ends.
+type exportDoneMsg struct {
+	dir    string
+	result export.Result
+	err    error
+}
+
+// startExport exports the open page with the pages below it, or every row of
+// the current database when a list is shown, to the configured export
+// directory. Progress is shown in the status bar.
+func (m *AppModel) startExport() tea.Cmd {
+	if m.exporting {
+		m.statusBar.SetHelpText("An export is already running")
+		return nil
+	}
+
+	var pageID, databaseID string
+	if detail, ok := m.pages[PageDetail].(*pages.DetailPage); ok && m.currentPage == PageDetail {
+		pageID = detail.PageID()
+	} else {
+		databaseID = m.currentDBID
+	}
+	if pageID == "" && databaseID == "" {
+		m.statusBar.SetHelpText("Open a page or database to export")
+		return nil
+	}
+
+	dir := m.config.ExportDir
+	if dir == "" {
+		m.statusBar.SetHelpText("Set export_dir in the config file to export")
+		return nil
+	}
+
+	// Sends give up once the model is closed, as nothing reads them any more
+	ctx := m.ctx
+	updates := make(chan tea.Msg)
+	send := func(msg tea.Msg) {
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case updates <- msg:
+		case <-ctx.Done():
+		}
+	}
+	exporter, err := export.NewExporter(export.NewExporterInput{
+		Client: m.notionClient,
+		Dir:    dir,
+		Progress: func(p export.Progress) {
+			send(exportProgressMsg{progress: p, updates: updates})
+		},
+	})
+	if err != nil {
+		m.statusBar.SetSyncStatus(components.StatusError)
+		m.statusBar.SetHelpText(fmt.Sprintf("Export failed: %v", err))
+		return nil
+	}
+
+	m.exporting = true
+	m.statusBar.SetSyncStatus(components.StatusSyncing)
+	m.statusBar.SetHelpText("Exporting to " + dir)
+
+	go func() {
+		defer close(updates)
+
+		var result export.Result
+		var err error
+		viewCtx := notion.WithView(ctx, "export")
+		if pageID != "" {
+			result, err = exporter.ExportPage(viewCtx, pageID)
+		} else {
+			result, err = exporter.ExportDatabase(viewCtx, databaseID)
+		}
+		send(exportDoneMsg{dir: dir, result: result, err: err})
+	}()
+
+	return waitForExport(updates)
+}
+
+// waitForExport returns a command that delivers the next message of a running
+// export.
+func waitForExport(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// handleExportProgress shows the progress of a running export.
+func (m *AppModel) handleExportProgress(msg exportProgressMsg) tea.Cmd {
+	m.statusBar.SetHelpText(fmt.Sprintf("Exporting %d/%d: %s", msg.progress.Done, msg.progress.Total, msg.progress.Title))
+	return waitForExport(msg.updates)
+}
+
+// handleExportDone shows the outcome of an export.
+func (m *AppModel) handleExportDone(msg exportDoneMsg) tea.Cmd {
+	m.exporting = false
+	if msg.err != nil {
+		m.statusBar.SetSyncStatus(components.StatusError)
+		m.statusBar.SetHelpText(fmt.Sprintf("Export failed after %d pages: %v", msg.result.Written, msg.err))
+		return nil
+	}
+
+	m.statusBar.SetSyncStatus(components.StatusSynced)
+	text := fmt.Sprintf("Exported %d pages to %s", msg.result.Written+msg.result.Skipped, msg.dir)
+	if n := len(msg.result.Warnings); n > 0 {
+		text += fmt.Sprintf(" (%d files could not be downloaded)", n)
+	}
+	m.statusBar.SetHelpText(text)
+	return nil
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/export"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+)
+
+func TestStartExportWithoutTarget(t *testing.T) {
+	model := NewModel(NewModelInput{
+		Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir(), ExportDir: t.TempDir()},
+	})
+
+	cmd := model.handleCommandExecution(components.CommandExecutedMsg{ActionType: "export"})
+
+	assert.Nil(t, cmd)
+	assert.False(t, model.exporting)
+	assert.Equal(t, "Open a page or database to export", model.statusBar.HelpText())
+}
+
+func TestExportStopsWhenModelCloses(t *testing.T) {
+	model := NewModel(NewModelInput{
+		Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir(), ExportDir: t.TempDir()},
+	})
+	model.currentDBID = "db-1"
+
+	cmd := model.startExport()
+	require.NotNil(t, cmd)
+	require.NoError(t, model.Close())
+
+	// Nothing reads the export's messages once the TUI has quit; the export
+	// must end and close its channel rather than block on a send
+	got := make(chan tea.Msg, 1)
+	go func() { got <- cmd() }()
+	select {
+	case msg := <-got:
+		assert.Nil(t, msg, "no message is delivered after Close")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the export did not stop after Close")
+	}
+}
+
+func TestHandleExportDone(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        exportDoneMsg
+		wantStatus string
+		wantText   string
+	}{
+		{
+			name:       "success",
+			msg:        exportDoneMsg{dir: "/tmp/out", result: export.Result{Written: 2, Skipped: 1, Warnings: []string{"x"}}},
+			wantStatus: components.StatusSynced,
+			wantText:   "Exported 3 pages to /tmp/out (1 files could not be downloaded)",
+		},
+		{
+			name:       "failure",
+			msg:        exportDoneMsg{dir: "/tmp/out", result: export.Result{Written: 4}, err: errors.New("rate limited")},
+			wantStatus: components.StatusError,
+			wantText:   "Export failed after 4 pages: rate limited",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(NewModelInput{Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()}})
+			model.exporting = true
+
+			updated, cmd := model.Update(tt.msg)
+			m := updated.(AppModel)
+
+			assert.Nil(t, cmd)
+			assert.False(t, m.exporting)
+			assert.Equal(t, tt.wantStatus, m.statusBar.SyncStatus())
+			assert.Equal(t, tt.wantText, m.statusBar.HelpText())
+		})
+	}
+}
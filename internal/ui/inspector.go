@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/pages"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// inspectorActionType is the palette action that toggles the inspector.
+const inspectorActionType = "inspector"
+
+// addInspectorCommand adds the palette command that toggles the inspector.
+func addInspectorCommand(p *components.CommandPalette) {
+	p.AddCommand("Inspector", "Show raw Notion objects, their cache entries and recent API calls",
+		inspectorActionType, func() tea.Cmd { return nil })
+}
+
+// newInspector creates the inspector for the services of a profile.
+func newInspector(svc services, keys *keymap.KeyMap, t theme.Theme) components.Inspector {
+	input := components.NewInspectorInput{
+		Requests: svc.notionClient.RecentRequests,
+		Keys:     keys,
+		Theme:    &t,
+	}
+	if svc.cache != nil {
+		pageCache := svc.cache
+		input.LookupCache = func(key string) (cache.CacheEntry, error) {
+			return pageCache.Entry(context.Background(), key)
+		}
+	}
+	return components.NewInspector(input)
+}
+
+// toggleInspector opens the inspector on the objects of the current page,
+// or closes it. Pages without objects still show the request log.
+func (m *AppModel) toggleInspector() tea.Cmd {
+	if m.inspector.IsOpen() {
+		m.inspector.Close()
+		return nil
+	}
+
+	var items []components.InspectorItem
+	selected := 0
+	if page, ok := m.pages[m.currentPage].(pages.Inspectable); ok {
+		items, selected = page.InspectItems()
+	}
+	m.inspector.SetSize(m.width, m.height)
+	return m.inspector.Open(items, selected)
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+)
+
+func TestToggleInspector(t *testing.T) {
+	model := NewModel(NewModelInput{
+		Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()},
+	})
+	model.initializePages()
+	updated, cmd := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updated.(AppModel)
+	f12 := tea.KeyMsg{Type: tea.KeyF12}
+
+	updated, cmd = model.Update(f12)
+	model = updated.(AppModel)
+	assert.True(t, model.inspector.IsOpen())
+	assert.NotNil(t, cmd, "the inspector refreshes while open")
+	assert.Contains(t, model.View(), "Inspector")
+
+	updated, _ = model.Update(f12)
+	model = updated.(AppModel)
+	assert.False(t, model.inspector.IsOpen())
+
+	model.handleCommandExecution(components.CommandExecutedMsg{ActionType: inspectorActionType})
+	assert.True(t, model.inspector.IsOpen())
+}
@@ -0,0 +1,513 @@
+// Package keymap holds every key binding of the TUI. Bindings are grouped by
+// the context they apply in, start from a preset and can be overridden per
+// action from the config file. Pages and components match keys against a
+// KeyMap rather than literal strings, so a rebinding applies everywhere.
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Contexts a binding applies in.
+const (
+	ContextGlobal  = "global"  // everywhere in the TUI
+	ContextList    = "list"    // the dashboard, page and database lists and the sidebar tree
+	ContextDetail  = "detail"  // the page viewer
+	ContextEdit    = "edit"    // the block editor
+	ContextSearch  = "search"  // the search page
+	ContextPalette = "palette" // the command palette
+)
+
+// Contexts lists every context, in the order they are documented.
+var Contexts = []string{ContextGlobal, ContextList, ContextDetail, ContextEdit, ContextSearch, ContextPalette}
+
+// Presets that bindings start from.
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+	PresetEmacs   = "emacs"
+)
+
+// Presets lists every preset.
+var Presets = []string{PresetDefault, PresetVim, PresetEmacs}
+
+// GlobalKeys are handled before any page sees a key.
+type GlobalKeys struct {
+	Quit          key.Binding
+	Help          key.Binding
+	Palette       key.Binding
+	ToggleSidebar key.Binding
+	FocusSidebar  key.Binding
+	Back          key.Binding
+	NewPage       key.Binding
+	Inspector     key.Binding
+}
+
+// ListKeys move through and act on lists and the sidebar tree.
+type ListKeys struct {
+	Up       key.Binding
+	Down     key.Binding
+	Collapse key.Binding
+	Expand   key.Binding
+	Select   key.Binding
+	Toggle   key.Binding
+	Refresh  key.Binding
+	LoadMore key.Binding
+	View     key.Binding
+}
+
+// DetailKeys scroll and act on the page viewer.
+type DetailKeys struct {
+	Up           key.Binding
+	Down         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Refresh      key.Binding
+	Edit         key.Binding
+}
+
+// EditKeys save, leave and transform blocks in the editor.
+type EditKeys struct {
+	Save         key.Binding
+	Refresh      key.Binding
+	Cancel       key.Binding
+	Retry        key.Binding
+	Dismiss      key.Binding
+	Heading1     key.Binding
+	Heading2     key.Binding
+	Heading3     key.Binding
+	Paragraph    key.Binding
+	BulletedList key.Binding
+	NumberedList key.Binding
+	Quote        key.Binding
+	Code         key.Binding
+}
+
+// SearchKeys run searches and move between the input and the results.
+type SearchKeys struct {
+	Select     key.Binding
+	Results    key.Binding
+	Input      key.Binding
+	ToggleMode key.Binding
+}
+
+// PaletteKeys move through and run palette commands.
+type PaletteKeys struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Close  key.Binding
+}
+
+// KeyMap is the complete set of bindings.
+type KeyMap struct {
+	Global  GlobalKeys
+	List    ListKeys
+	Detail  DetailKeys
+	Edit    EditKeys
+	Search  SearchKeys
+	Palette PaletteKeys
+}
+
+// NewInput contains the parameters for building a KeyMap.
+type NewInput struct {
+	Preset string // one of Presets; PresetDefault when empty
+	// Overrides maps a context to action names and the keys that replace
+	// the preset's. An empty key list unbinds the action.
+	Overrides map[string]map[string][]string
+}
+
+// New builds a KeyMap from a preset and overrides. Unknown presets,
+// contexts and actions are errors, as are conflicts: a key bound to two
+// actions of one context, or to a global action and an action of a context
+// the global keys are checked before.
+func New(input NewInput) (*KeyMap, error) {
+	k := Default()
+	switch input.Preset {
+	case "", PresetDefault:
+	case PresetVim:
+		k.applyVim()
+	case PresetEmacs:
+		k.applyEmacs()
+	default:
+		return nil, fmt.Errorf("keys.preset: unknown preset %q (want one of %s)", input.Preset, strings.Join(Presets, ", "))
+	}
+
+	for _, context := range sortedKeys(input.Overrides) {
+		actions, ok := k.actions()[context]
+		if !ok {
+			return nil, fmt.Errorf("keys: unknown context %q (want one of %s)", context, strings.Join(Contexts, ", "))
+		}
+		for _, name := range sortedKeys(input.Overrides[context]) {
+			binding := findAction(actions, name)
+			if binding == nil {
+				return nil, fmt.Errorf("keys.%s: unknown action %q (want one of %s)", context, name, strings.Join(actionNames(actions), ", "))
+			}
+			keys, err := normalizeKeys(input.Overrides[context][name])
+			if err != nil {
+				return nil, fmt.Errorf("keys.%s.%s: %w", context, name, err)
+			}
+			rebind(binding, keys)
+		}
+	}
+
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// OrDefault returns k, or the default bindings when k is nil.
+func OrDefault(k *KeyMap) *KeyMap {
+	if k == nil {
+		return Default()
+	}
+	return k
+}
+
+// Default returns the default bindings.
+func Default() *KeyMap {
+	return &KeyMap{
+		Global: GlobalKeys{
+			Quit:          binding("quit", "ctrl+c", "q"),
+			Help:          binding("help", "?"),
+			Palette:       binding("command palette", "ctrl+p"),
+			ToggleSidebar: binding("toggle tree", "ctrl+b"),
+			FocusSidebar:  binding("focus tree", "tab"),
+			Back:          binding("back", "esc"),
+			NewPage:       binding("new page", "ctrl+n"),
+			Inspector:     binding("inspector", "f12"),
+		},
+		List: ListKeys{
+			Up:       binding("move up", "up", "k"),
+			Down:     binding("move down", "down", "j"),
+			Collapse: binding("move left", "left", "h"),
+			Expand:   binding("move right", "right", "l"),
+			Select:   binding("select", "enter"),
+			Toggle:   binding("expand/collapse", " "),
+			Refresh:  binding("refresh", "r"),
+			LoadMore: binding("load more", "m"),
+			View:     binding("switch view", "v"),
+		},
+		Detail: DetailKeys{
+			Up:           binding("scroll up", "up", "k"),
+			Down:         binding("scroll down", "down", "j"),
+			PageUp:       binding("page up", "pgup", "b"),
+			PageDown:     binding("page down", "pgdown", " ", "f"),
+			HalfPageUp:   binding("half page up", "u", "ctrl+u"),
+			HalfPageDown: binding("half page down", "d", "ctrl+d"),
+			Refresh:      binding("refresh", "r"),
+			Edit:         binding("edit", "e"),
+		},
+		Edit: EditKeys{
+			Save:         binding("save", "ctrl+s"),
+			Refresh:      binding("discard and refresh", "ctrl+r"),
+			Cancel:       binding("cancel", "esc"),
+			Retry:        binding("retry", "r"),
+			Dismiss:      binding("dismiss error", "d"),
+			Heading1:     binding("heading 1", "ctrl+1"),
+			Heading2:     binding("heading 2", "ctrl+2"),
+			Heading3:     binding("heading 3", "ctrl+3"),
+			Paragraph:    binding("paragraph", "ctrl+p"),
+			BulletedList: binding("bulleted list", "ctrl+l"),
+			NumberedList: binding("numbered list", "ctrl+o"),
+			Quote:        binding("quote", "ctrl+q"),
+			Code:         binding("code", "ctrl+k"),
+		},
+		Search: SearchKeys{
+			Select:     binding("search/select", "enter"),
+			Results:    binding("results", "down", "j"),
+			Input:      binding("input", "up", "k"),
+			ToggleMode: binding("switch mode", "ctrl+t"),
+		},
+		Palette: PaletteKeys{
+			Up:     binding("move up", "up", "k"),
+			Down:   binding("move down", "down", "j"),
+			Select: binding("run", "enter"),
+			Close:  binding("close", "esc"),
+		},
+	}
+}
+
+// applyVim keeps letters out of text inputs and moves the sidebar toggle
+// off ctrl+b, which pages up.
+func (k *KeyMap) applyVim() {
+	rebind(&k.Global.ToggleSidebar, []string{"ctrl+w"})
+	rebind(&k.Detail.PageUp, []string{"pgup", "ctrl+b"})
+	rebind(&k.Detail.PageDown, []string{"pgdown", "ctrl+f"})
+	rebind(&k.Detail.HalfPageUp, []string{"ctrl+u"})
+	rebind(&k.Detail.HalfPageDown, []string{"ctrl+d"})
+	rebind(&k.Search.Results, []string{"down", "ctrl+j"})
+	rebind(&k.Search.Input, []string{"up", "ctrl+k"})
+	rebind(&k.Palette.Up, []string{"up", "ctrl+k"})
+	rebind(&k.Palette.Down, []string{"down", "ctrl+j"})
+}
+
+// applyEmacs moves through lists with ctrl+p and ctrl+n, so the palette
+// moves to alt+x, and cancels with ctrl+g.
+func (k *KeyMap) applyEmacs() {
+	rebind(&k.Global.Palette, []string{"alt+x"})
+	rebind(&k.Global.ToggleSidebar, []string{"alt+s"})
+	rebind(&k.Global.Back, []string{"esc", "ctrl+g"})
+	rebind(&k.Global.NewPage, []string{"alt+n"})
+	rebind(&k.List.Up, []string{"up", "ctrl+p"})
+	rebind(&k.List.Down, []string{"down", "ctrl+n"})
+	rebind(&k.List.Collapse, []string{"left", "ctrl+b"})
+	rebind(&k.List.Expand, []string{"right", "ctrl+f"})
+	rebind(&k.List.Refresh, []string{"g", "r"})
+	rebind(&k.Detail.Up, []string{"up", "ctrl+p"})
+	rebind(&k.Detail.Down, []string{"down", "ctrl+n"})
+	rebind(&k.Detail.PageUp, []string{"pgup", "alt+v"})
+	rebind(&k.Detail.PageDown, []string{"pgdown", "ctrl+v"})
+	rebind(&k.Detail.Refresh, []string{"g", "r"})
+	rebind(&k.Edit.Cancel, []string{"esc", "ctrl+g"})
+	rebind(&k.Search.Results, []string{"down", "ctrl+n"})
+	rebind(&k.Search.Input, []string{"up", "ctrl+p"})
+	rebind(&k.Palette.Up, []string{"up", "ctrl+p"})
+	rebind(&k.Palette.Down, []string{"down", "ctrl+n"})
+	rebind(&k.Palette.Close, []string{"esc", "ctrl+g"})
+}
+
+// action is a named binding of a context.
+type action struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions returns the bindings of every context by their config names.
+func (k *KeyMap) actions() map[string][]action {
+	return map[string][]action{
+		ContextGlobal: {
+			{"quit", &k.Global.Quit},
+			{"help", &k.Global.Help},
+			{"palette", &k.Global.Palette},
+			{"toggle_sidebar", &k.Global.ToggleSidebar},
+			{"focus_sidebar", &k.Global.FocusSidebar},
+			{"back", &k.Global.Back},
+			{"new_page", &k.Global.NewPage},
+			{"inspector", &k.Global.Inspector},
+		},
+		ContextList: {
+			{"up", &k.List.Up},
+			{"down", &k.List.Down},
+			{"collapse", &k.List.Collapse},
+			{"expand", &k.List.Expand},
+			{"select", &k.List.Select},
+			{"toggle", &k.List.Toggle},
+			{"refresh", &k.List.Refresh},
+			{"load_more", &k.List.LoadMore},
+			{"view", &k.List.View},
+		},
+		ContextDetail: {
+			{"up", &k.Detail.Up},
+			{"down", &k.Detail.Down},
+			{"page_up", &k.Detail.PageUp},
+			{"page_down", &k.Detail.PageDown},
+			{"half_page_up", &k.Detail.HalfPageUp},
+			{"half_page_down", &k.Detail.HalfPageDown},
+			{"refresh", &k.Detail.Refresh},
+			{"edit", &k.Detail.Edit},
+		},
+		ContextEdit: {
+			{"save", &k.Edit.Save},
+			{"refresh", &k.Edit.Refresh},
+			{"cancel", &k.Edit.Cancel},
+			{"retry", &k.Edit.Retry},
+			{"dismiss", &k.Edit.Dismiss},
+			{"heading_1", &k.Edit.Heading1},
+			{"heading_2", &k.Edit.Heading2},
+			{"heading_3", &k.Edit.Heading3},
+			{"paragraph", &k.Edit.Paragraph},
+			{"bulleted_list", &k.Edit.BulletedList},
+			{"numbered_list", &k.Edit.NumberedList},
+			{"quote", &k.Edit.Quote},
+			{"code", &k.Edit.Code},
+		},
+		ContextSearch: {
+			{"select", &k.Search.Select},
+			{"results", &k.Search.Results},
+			{"input", &k.Search.Input},
+			{"toggle_mode", &k.Search.ToggleMode},
+		},
+		ContextPalette: {
+			{"up", &k.Palette.Up},
+			{"down", &k.Palette.Down},
+			{"select", &k.Palette.Select},
+			{"close", &k.Palette.Close},
+		},
+	}
+}
+
+// shadowedByGlobal lists the contexts whose keys are only seen after the
+// global keys. The editor and the palette take text input, so they see
+// keys first and may reuse global ones.
+var shadowedByGlobal = []string{ContextList, ContextDetail, ContextSearch}
+
+// Validate reports the first conflict between bindings, if any.
+func (k *KeyMap) Validate() error {
+	all := k.actions()
+	for _, context := range Contexts {
+		if err := conflicts(context, all[context], context, all[context]); err != nil {
+			return err
+		}
+	}
+	for _, context := range shadowedByGlobal {
+		if err := conflicts(ContextGlobal, all[ContextGlobal], context, all[context]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflicts returns an error for the first key bound in both a and b. When
+// a and b are the same context an action is not compared with itself.
+func conflicts(contextA string, a []action, contextB string, b []action) error {
+	for i, x := range a {
+		for j, y := range b {
+			if contextA == contextB && j <= i {
+				continue
+			}
+			for _, keyX := range x.binding.Keys() {
+				for _, keyY := range y.binding.Keys() {
+					if keyX == keyY {
+						return fmt.Errorf("keys: %q is bound to both %s.%s and %s.%s",
+							displayKey(keyX), contextA, x.name, contextB, y.name)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// binding returns an enabled binding with help generated from its keys.
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), desc))
+}
+
+// rebind replaces the keys of a binding and its help. No keys disables it.
+func rebind(b *key.Binding, keys []string) {
+	b.SetKeys(keys...)
+	b.SetHelp(helpKeys(keys), b.Help().Desc)
+	b.SetEnabled(len(keys) > 0)
+}
+
+// helpKeys joins keys for display.
+func helpKeys(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		shown[i] = displayKey(k)
+	}
+	return strings.Join(shown, "/")
+}
+
+// keyLabels are how Label shows keys whose names read poorly in help text.
+var keyLabels = map[string]string{
+	" ":     "Space",
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// Label returns the first key of a binding as help text shows it, such as
+// "Ctrl+S", "Esc", "↓" or "q", or "unbound" when the binding has no keys.
+func Label(b key.Binding) string {
+	keys := b.Keys()
+	if len(keys) == 0 || !b.Enabled() {
+		return "unbound"
+	}
+	if label, ok := keyLabels[keys[0]]; ok {
+		return label
+	}
+
+	parts := strings.Split(keys[0], "+")
+	if len(keys[0]) == 1 {
+		parts = []string{keys[0]}
+	}
+	for i, part := range parts {
+		// Letters after a modifier are capitalised like Ctrl+S; a bare
+		// letter stays as typed, since "G" and "g" are different keys
+		if len(parts) > 1 || len([]rune(part)) > 1 {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// displayKey names a key the way the config file spells it.
+func displayKey(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+// keyAliases are accepted spellings of keys that Bubble Tea names otherwise.
+var keyAliases = map[string]string{
+	"space":    " ",
+	"escape":   "esc",
+	"return":   "enter",
+	"pageup":   "pgup",
+	"pagedown": "pgdown",
+}
+
+// normalizeKeys turns config spellings into Bubble Tea key names. Names of
+// more than one character are case-insensitive; single characters are not,
+// so "G" and "g" stay different keys.
+func normalizeKeys(keys []string) ([]string, error) {
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == " " {
+			out = append(out, k)
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if k == "" {
+			return nil, fmt.Errorf("empty key name")
+		}
+		if len([]rune(k)) > 1 {
+			k = strings.ToLower(k)
+		}
+		if alias, ok := keyAliases[k]; ok {
+			k = alias
+		}
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+// findAction returns the binding of the named action.
+func findAction(actions []action, name string) *key.Binding {
+	for _, a := range actions {
+		if a.name == name {
+			return a.binding
+		}
+	}
+	return nil
+}
+
+// actionNames returns the names of actions, in order.
+func actionNames(actions []action) []string {
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = a.name
+	}
+	return names
+}
+
+// sortedKeys returns the keys of a map in order, for stable errors.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package keymap
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	t.Parallel()
+
+	for _, preset := range Presets {
+		t.Run(preset, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(NewInput{Preset: preset})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     NewInput
+		wantErr   string
+		checkKeys func(t *testing.T, k *KeyMap)
+	}{
+		{
+			name:  "override replaces the preset keys",
+			input: NewInput{Overrides: map[string]map[string][]string{"global": {"palette": {"Ctrl+K"}}}},
+			checkKeys: func(t *testing.T, k *KeyMap) {
+				assert.Equal(t, []string{"ctrl+k"}, k.Global.Palette.Keys())
+				assert.Equal(t, "ctrl+k", k.Global.Palette.Help().Key)
+				assert.Equal(t, "command palette", k.Global.Palette.Help().Desc)
+			},
+		},
+		{
+			name:  "aliases and case",
+			input: NewInput{Overrides: map[string]map[string][]string{"list": {"toggle": {"space"}, "down": {"G", "PageDown"}}}},
+			checkKeys: func(t *testing.T, k *KeyMap) {
+				assert.Equal(t, []string{" "}, k.List.Toggle.Keys())
+				assert.Equal(t, "space", k.List.Toggle.Help().Key)
+				assert.Equal(t, []string{"G", "pgdown"}, k.List.Down.Keys())
+			},
+		},
+		{
+			name:  "empty list unbinds",
+			input: NewInput{Overrides: map[string]map[string][]string{"list": {"load_more": {}}}},
+			checkKeys: func(t *testing.T, k *KeyMap) {
+				assert.False(t, k.List.LoadMore.Enabled())
+				assert.False(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")}, k.List.LoadMore))
+			},
+		},
+		{
+			name:  "vim preset",
+			input: NewInput{Preset: PresetVim},
+			checkKeys: func(t *testing.T, k *KeyMap) {
+				assert.Equal(t, []string{"ctrl+w"}, k.Global.ToggleSidebar.Keys())
+				assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyCtrlD}, k.Detail.HalfPageDown))
+			},
+		},
+		{
+			name:  "emacs preset",
+			input: NewInput{Preset: PresetEmacs},
+			checkKeys: func(t *testing.T, k *KeyMap) {
+				assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, k.List.Down))
+				assert.Equal(t, []string{"alt+x"}, k.Global.Palette.Keys())
+			},
+		},
+		{
+			name:    "unknown preset",
+			input:   NewInput{Preset: "helix"},
+			wantErr: `keys.preset: unknown preset "helix"`,
+		},
+		{
+			name:    "unknown context",
+			input:   NewInput{Overrides: map[string]map[string][]string{"sidebar": {"up": {"k"}}}},
+			wantErr: `keys: unknown context "sidebar"`,
+		},
+		{
+			name:    "unknown action",
+			input:   NewInput{Overrides: map[string]map[string][]string{"detail": {"delete": {"x"}}}},
+			wantErr: `keys.detail: unknown action "delete"`,
+		},
+		{
+			name:    "conflict within a context",
+			input:   NewInput{Overrides: map[string]map[string][]string{"list": {"refresh": {"m"}}}},
+			wantErr: `keys: "m" is bound to both list.refresh and list.load_more`,
+		},
+		{
+			name:    "conflict with a global key",
+			input:   NewInput{Overrides: map[string]map[string][]string{"detail": {"edit": {"q"}}}},
+			wantErr: `keys: "q" is bound to both global.quit and detail.edit`,
+		},
+		{
+			name:  "the editor may reuse global keys",
+			input: NewInput{Overrides: map[string]map[string][]string{"edit": {"save": {"ctrl+b"}}}},
+			checkKeys: func(t *testing.T, k *KeyMap) {
+				assert.Equal(t, []string{"ctrl+b"}, k.Edit.Save.Keys())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := New(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.checkKeys(t, k)
+		})
+	}
+}
+
+func TestOrDefault(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Default().Global.Quit.Keys(), OrDefault(nil).Global.Quit.Keys())
+
+	k, err := New(NewInput{Preset: PresetEmacs})
+	require.NoError(t, err)
+	assert.Same(t, k, OrDefault(k))
+}
+
+func TestLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{keys: []string{"ctrl+s"}, want: "Ctrl+S"},
+		{keys: []string{"esc", "ctrl+g"}, want: "Esc"},
+		{keys: []string{"down", "j"}, want: "↓"},
+		{keys: []string{"q"}, want: "q"},
+		{keys: []string{"G"}, want: "G"},
+		{keys: []string{"+"}, want: "+"},
+		{keys: []string{" "}, want: "Space"},
+		{keys: []string{"alt+x"}, want: "Alt+X"},
+		{keys: nil, want: "unbound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+
+			b := key.NewBinding(key.WithKeys(tt.keys...))
+			if len(tt.keys) == 0 {
+				b.SetEnabled(false)
+			}
+			assert.Equal(t, tt.want, Label(b))
+		})
+	}
+}
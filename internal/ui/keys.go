@@ -2,6 +2,8 @@ package ui
 
 import (
 	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
 )
 
 // KeyMap defines the key bindings for the application.
@@ -22,55 +24,26 @@ type KeyMap struct {
 
 // DefaultKeyMap returns the default key bindings.
 func DefaultKeyMap() KeyMap {
+	return NewKeyMap(keymap.Default())
+}
+
+// NewKeyMap returns the bindings of k that the KeyMap summarises. Nil
+// means the default bindings.
+func NewKeyMap(k *keymap.KeyMap) KeyMap {
+	k = keymap.OrDefault(k)
 	return KeyMap{
-		Up: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("up/k", "move up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("down/j", "move down"),
-		),
-		Left: key.NewBinding(
-			key.WithKeys("left", "h"),
-			key.WithHelp("left/h", "move left"),
-		),
-		Right: key.NewBinding(
-			key.WithKeys("right", "l"),
-			key.WithHelp("right/l", "move right"),
-		),
-		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
-		),
-		Back: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "back"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("ctrl+c", "q"),
-			key.WithHelp("ctrl+c/q", "quit"),
-		),
-		ShowHelp: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
-		Refresh: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "refresh"),
-		),
-		NewPage: key.NewBinding(
-			key.WithKeys("ctrl+n"),
-			key.WithHelp("ctrl+n", "new page"),
-		),
-		Edit: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "edit"),
-		),
-		Command: key.NewBinding(
-			key.WithKeys("ctrl+p"),
-			key.WithHelp("ctrl+p", "command palette"),
-		),
+		Up:       k.List.Up,
+		Down:     k.List.Down,
+		Left:     k.List.Collapse,
+		Right:    k.List.Expand,
+		Enter:    k.List.Select,
+		Back:     k.Global.Back,
+		Quit:     k.Global.Quit,
+		ShowHelp: k.Global.Help,
+		Refresh:  k.List.Refresh,
+		NewPage:  k.Global.NewPage,
+		Edit:     k.Detail.Edit,
+		Command:  k.Global.Palette,
 	}
 }
 
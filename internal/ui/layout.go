@@ -2,6 +2,8 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // LayoutSidebarMainInput contains parameters for LayoutSidebarMain.
@@ -11,11 +13,14 @@ type LayoutSidebarMainInput struct {
 	SidebarWidth int
 	TotalWidth   int
 	TotalHeight  int
+	Theme        *Theme // optional; the default theme when nil
 }
 
 // LayoutSidebarMain creates a horizontal layout with a sidebar on the left and main content on the right.
 // The sidebar has a border on the right side. Returns empty string if dimensions are invalid.
 func LayoutSidebarMain(input LayoutSidebarMainInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	// Validate dimensions
 	if input.TotalWidth <= 0 || input.TotalHeight <= 0 || input.SidebarWidth <= 0 {
 		return ""
@@ -30,7 +35,7 @@ func LayoutSidebarMain(input LayoutSidebarMainInput) string {
 		Height(input.TotalHeight).
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderRight(true).
-		BorderForeground(darkTheme.Secondary)
+		BorderForeground(t.Secondary)
 
 	mainWidth := input.TotalWidth - input.SidebarWidth - 1 // -1 for border
 	if mainWidth <= 0 {
@@ -53,11 +58,14 @@ type LayoutWithStatusBarInput struct {
 	Content   string
 	StatusBar string
 	Height    int
+	Theme     *Theme // optional; the default theme when nil
 }
 
 // LayoutWithStatusBar creates a vertical layout with content above and a status bar at the bottom.
 // Returns empty string if height is invalid.
 func LayoutWithStatusBar(input LayoutWithStatusBarInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	if input.Height <= 0 {
 		return ""
 	}
@@ -72,8 +80,8 @@ func LayoutWithStatusBar(input LayoutWithStatusBarInput) string {
 		Height(contentHeight)
 
 	statusStyle := lipgloss.NewStyle().
-		Foreground(darkTheme.Muted).
-		Background(darkTheme.Secondary).
+		Foreground(t.Muted).
+		Background(t.Secondary).
 		Padding(0, 1)
 
 	return lipgloss.JoinVertical(
@@ -89,12 +97,15 @@ type LayoutCommandPaletteInput struct {
 	Palette    string
 	Width      int
 	Height     int
+	Theme      *Theme // optional; the default theme when nil
 }
 
 // LayoutCommandPalette overlays a command palette in the center of the screen.
 // The background is dimmed with semi-transparent characters.
 // Returns the palette centered if dimensions are valid, otherwise returns the palette as-is.
 func LayoutCommandPalette(input LayoutCommandPaletteInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	if input.Width <= 0 || input.Height <= 0 {
 		return input.Palette
 	}
@@ -105,7 +116,7 @@ func LayoutCommandPalette(input LayoutCommandPaletteInput) string {
 		lipgloss.Center, lipgloss.Center,
 		input.Palette,
 		lipgloss.WithWhitespaceChars("░"),
-		lipgloss.WithWhitespaceForeground(lipgloss.Color("240")),
+		lipgloss.WithWhitespaceForeground(t.Subtle),
 	)
 
 	return overlay
@@ -116,11 +127,14 @@ type CenterOverlayInput struct {
 	Overlay string
 	Width   int
 	Height  int
+	Theme   *Theme // optional; the default theme when nil
 }
 
 // CenterOverlay centers content in the available space with a semi-transparent background effect.
 // Returns the overlay centered if dimensions are valid, otherwise returns the overlay as-is.
 func CenterOverlay(input CenterOverlayInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	if input.Width <= 0 || input.Height <= 0 {
 		return input.Overlay
 	}
@@ -130,7 +144,7 @@ func CenterOverlay(input CenterOverlayInput) string {
 		lipgloss.Center, lipgloss.Center,
 		input.Overlay,
 		lipgloss.WithWhitespaceChars("▓"),
-		lipgloss.WithWhitespaceForeground(lipgloss.Color("236")),
+		lipgloss.WithWhitespaceForeground(t.Secondary),
 	)
 }
 
@@ -141,11 +155,14 @@ type LayoutThreeColumnInput struct {
 	Right  string
 	Widths [3]int
 	Height int
+	Theme  *Theme // optional; the default theme when nil
 }
 
 // LayoutThreeColumn creates a three-column horizontal layout: [Left] | [Center] | [Right].
 // Each column is separated by a border. Returns empty string if dimensions are invalid.
 func LayoutThreeColumn(input LayoutThreeColumnInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	// Validate dimensions
 	if input.Height <= 0 {
 		return ""
@@ -168,14 +185,14 @@ func LayoutThreeColumn(input LayoutThreeColumnInput) string {
 		Height(input.Height).
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderRight(true).
-		BorderForeground(darkTheme.Secondary)
+		BorderForeground(t.Secondary)
 
 	centerStyle := lipgloss.NewStyle().
 		Width(input.Widths[1]).
 		Height(input.Height).
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderRight(true).
-		BorderForeground(darkTheme.Secondary)
+		BorderForeground(t.Secondary)
 
 	rightStyle := lipgloss.NewStyle().
 		Width(input.Widths[2]).
@@ -195,18 +212,21 @@ type LayoutBoxedContentInput struct {
 	Title   string
 	Width   int
 	Height  int
+	Theme   *Theme // optional; the default theme when nil
 }
 
 // LayoutBoxedContent creates a bordered box with optional title at the top.
 // Uses the application's theme for styling. Returns empty string if dimensions are invalid.
 func LayoutBoxedContent(input LayoutBoxedContentInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	if input.Width <= 0 || input.Height <= 0 {
 		return ""
 	}
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(darkTheme.Secondary).
+		BorderForeground(t.Secondary).
 		Width(input.Width).
 		Height(input.Height).
 		Padding(1, 2)
@@ -214,7 +234,7 @@ func LayoutBoxedContent(input LayoutBoxedContentInput) string {
 	content := input.Content
 	if input.Title != "" {
 		titleStyle := lipgloss.NewStyle().
-			Foreground(darkTheme.Primary).
+			Foreground(t.Primary).
 			Bold(true)
 
 		content = titleStyle.Render(input.Title) + "\n\n" + content
@@ -229,12 +249,15 @@ type LayoutSplitVerticalInput struct {
 	Bottom string
 	Height int
 	Split  float64 // 0.0 to 1.0, percentage of height for top section
+	Theme  *Theme  // optional; the default theme when nil
 }
 
 // LayoutSplitVertical creates a vertical split layout with configurable split ratio.
 // Split should be between 0.0 and 1.0, representing the percentage of height for the top section.
 // Returns empty string if height is invalid or split is out of range.
 func LayoutSplitVertical(input LayoutSplitVerticalInput) string {
+	t := theme.OrDefault(input.Theme)
+
 	if input.Height <= 0 {
 		return ""
 	}
@@ -260,7 +283,7 @@ func LayoutSplitVertical(input LayoutSplitVerticalInput) string {
 		Height(topHeight).
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderBottom(true).
-		BorderForeground(darkTheme.Secondary)
+		BorderForeground(t.Secondary)
 
 	bottomStyle := lipgloss.NewStyle().
 		Height(bottomHeight)
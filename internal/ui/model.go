@@ -2,15 +2,22 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Panandika/notion-tui/internal/cache"
 	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
 	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/prefetch"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
 	"github.com/Panandika/notion-tui/internal/ui/pages"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // workspaceTreeMsg is sent when workspace tree data is fetched.
@@ -31,6 +38,9 @@ type AppModel struct {
 	treeView   components.TreeView
 	statusBar  components.StatusBar
 	cmdPalette components.CommandPalette
+	toast      components.Toast // shown in place of the status bar
+	inspector  components.Inspector
+	stats      components.StatsPanel
 
 	// State
 	width        int
@@ -43,7 +53,19 @@ type AppModel struct {
 	// Services
 	notionClient *notion.Client
 	cache        *cache.PageCache
+	index        *index.Index
+	prefetcher   *prefetch.Prefetcher
 	config       *config.Config
+	keys         *keymap.KeyMap
+	theme        theme.Theme
+	// configTheme is the theme from the config file, offered in the palette
+	// when it is not one of the built-in themes
+	configTheme theme.Theme
+	loadProfile ProfileLoader // nil when profiles cannot be switched
+	// retired are the services of profiles switched away from. Commands
+	// started under those profiles may still be using them, so their caches
+	// are only closed by Close.
+	retired []services
 
 	// Data
 	pageList     []pages.Page
@@ -54,32 +76,42 @@ type AppModel struct {
 
 	// Help state
 	showHelp bool
+
+	// exporting is set while an export started from the palette runs
+	exporting bool
+	// ctx is cancelled by Close so that work running outside the UI, such as
+	// an export, stops rather than waiting on a TUI that has quit
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// start is what was opened instead of the dashboard, if anything
+	start StartTarget
 }
 
 // NewModelInput contains the parameters for creating a new AppModel.
 type NewModelInput struct {
 	Config *config.Config
 	Cache  *cache.PageCache
+	Start  StartTarget    // optional; the dashboard when empty
+	Keys   *keymap.KeyMap // optional; the default bindings when nil
+	Theme  *theme.Theme   // optional; the default theme when nil
+	// LoadProfile opens a profile picked from the palette; optional,
+	// profiles cannot be switched when nil
+	LoadProfile ProfileLoader
+}
+
+// StartTarget is a page or database to open on start instead of the
+// dashboard, such as from a Notion link given on the command line.
+type StartTarget struct {
+	PageID     string // a page to open in the detail view
+	BlockID    string // a top-level block of the page to scroll into view
+	DatabaseID string // a database to open in the list view
 }
 
 // NewModel creates a new root TUI model with page orchestration.
 // Initializes the navigator, components, and page registry.
 func NewModel(input NewModelInput) AppModel {
-	// Initialize services
-	notionClient := notion.NewClient(input.Config.NotionToken)
-
-	// Initialize cache if not provided
-	cacheInstance := input.Cache
-	if cacheInstance == nil {
-		var err error
-		cacheInstance, err = cache.NewPageCache(cache.NewPageCacheInput{
-			Dir: input.Config.CacheDir,
-		})
-		if err != nil {
-			// Fall back to no cache if initialization fails
-			cacheInstance = nil
-		}
-	}
+	svc := openServices(input.Config, input.Cache)
 
 	// Determine initial page based on config
 	// Start with Dashboard
@@ -91,41 +123,192 @@ func NewModel(input NewModelInput) AppModel {
 		MaxHistory:  DefaultMaxHistory,
 	})
 
+	// A start target opens on top of the dashboard, so back leads there
+	currentDBID := input.Config.GetDatabaseID()
+	switch {
+	case input.Start.DatabaseID != "":
+		currentDBID = input.Start.DatabaseID
+		nav.NavigateTo(PageList)
+	case input.Start.PageID != "":
+		nav.NavigateTo(PageDetail)
+	}
+
+	keys := keymap.OrDefault(input.Keys)
+	t := theme.OrDefault(input.Theme)
+
 	// Initialize tree view for navigation sidebar
 	treeView := components.NewTreeView(components.NewTreeViewInput{
 		Title:  "Workspace",
 		Width:  25, // Will be adjusted on first WindowSizeMsg
 		Height: 20,
+		Keys:   keys,
+		Theme:  &t,
 	})
 
 	statusBar := components.NewStatusBar()
 	statusBar.SetMode(components.ModeBrowse)
 	statusBar.SetSyncStatus(components.StatusSynced)
-	statusBar.SetHelpText("? for help")
+	statusBar.SetHelpText(fmt.Sprintf("%s for help", keymap.Label(keys.Global.Help)))
+	statusBar.SetTheme(t)
+	statusBar.SetProfile(input.Config.Profile)
 
 	cmdPalette := components.NewCommandPalette()
+	cmdPalette.SetKeys(keys)
+	cmdPalette.SetTheme(t)
+	addThemeCommands(&cmdPalette, t)
+	addProfileCommands(&cmdPalette, input.LoadProfile, input.Config.ProfileNames())
+	addInspectorCommand(&cmdPalette)
+	addStatsCommand(&cmdPalette)
+
+	toast := components.NewToast()
+	toast.SetTheme(t)
+
+	inspector := newInspector(svc, keys, t)
+	stats := newStatsPanel(svc, keys, t)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return AppModel{
-		currentPage:  initialPage,
+		currentPage:  nav.CurrentPage(),
 		pages:        make(map[PageID]tea.Model),
 		navigator:    &nav,
 		treeView:     treeView,
 		statusBar:    statusBar,
 		cmdPalette:   cmdPalette,
+		toast:        toast,
+		inspector:    inspector,
+		stats:        stats,
 		width:        0,
 		height:       0,
 		showSidebar:  true, // Always show sidebar by default
 		sidebarFocus: false,
 		showPalette:  false,
 		mode:         ViewModeBrowse,
-		notionClient: notionClient,
-		cache:        cacheInstance,
+		notionClient: svc.notionClient,
+		cache:        svc.cache,
+		index:        svc.index,
+		prefetcher:   svc.prefetcher,
 		config:       input.Config,
+		keys:         keys,
+		theme:        t,
+		configTheme:  t,
+		loadProfile:  input.LoadProfile,
 		pageList:     []pages.Page{},
 		ready:        false,
 		err:          nil,
 		selectedPage: nil,
-		currentDBID:  input.Config.GetDatabaseID(),
+		currentDBID:  currentDBID,
+		start:        input.Start,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// services are the Notion client and the local state built on it for one
+// config.
+type services struct {
+	notionClient *notion.Client
+	cache        *cache.PageCache
+	index        *index.Index
+	prefetcher   *prefetch.Prefetcher
+}
+
+// openServices creates the services for a config, opening a cache in its
+// cache directory when pageCache is nil.
+func openServices(cfg *config.Config, pageCache *cache.PageCache) services {
+	notionClient := notion.NewClient(cfg.NotionToken)
+
+	// Initialize cache if not provided
+	cacheInstance := pageCache
+	if cacheInstance == nil {
+		var err error
+		cacheInstance, err = cache.NewPageCache(cache.NewPageCacheInput{
+			Dir: cfg.CacheDir,
+		})
+		if err != nil {
+			// Fall back to no cache if initialization fails
+			cacheInstance = nil
+		}
+	}
+
+	// Open the local search index beside the cache, or keep one in memory
+	searchIndex := index.NewIndex(index.NewIndexInput{})
+	if cacheInstance != nil {
+		if opened, err := index.Open(context.Background(), index.OpenInput{Cache: cacheInstance}); err == nil {
+			searchIndex = opened
+		}
+	}
+
+	// Warm the cache in the background when enabled
+	var prefetcher *prefetch.Prefetcher
+	if cacheInstance != nil && cfg.PrefetchEnabled() {
+		if p, err := prefetch.NewPrefetcher(prefetch.NewPrefetcherInput{
+			Fetcher:     notionClient,
+			Cache:       cacheInstance,
+			HistorySize: cfg.PrefetchHistorySize(),
+		}); err == nil {
+			prefetcher = p
+		}
+	}
+
+	return services{
+		notionClient: notionClient,
+		cache:        cacheInstance,
+		index:        searchIndex,
+		prefetcher:   prefetcher,
+	}
+}
+
+// Close releases resources held by the model, writes the session's stats,
+// persists the local search index and releases the cache, along with the
+// caches of profiles switched away from.
+// It should be called once the program has exited. Every step runs even
+// when an earlier one fails; their errors are joined.
+func (m AppModel) Close() error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	errs := []error{m.retireServices()}
+	for _, svc := range append(m.retired, m.services()) {
+		if svc.index != nil {
+			// Commands of a retired profile may have changed it since
+			if err := svc.index.Save(); err != nil {
+				errs = append(errs, fmt.Errorf("save search index: %w", err))
+			}
+		}
+		if svc.cache != nil {
+			if err := svc.cache.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close cache: %w", err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retireServices stops prefetching, persists the local search index and
+// writes the session's stats for the active profile. Its cache is left open.
+func (m AppModel) retireServices() error {
+	if m.prefetcher != nil {
+		m.prefetcher.Stop()
+	}
+	var errs []error
+	if m.index != nil {
+		if err := m.index.Save(); err != nil {
+			errs = append(errs, fmt.Errorf("save search index: %w", err))
+		}
+	}
+	if err := m.writeStats(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// services returns the services of the active profile.
+func (m AppModel) services() services {
+	return services{
+		notionClient: m.notionClient,
+		cache:        m.cache,
+		index:        m.index,
+		prefetcher:   m.prefetcher,
 	}
 }
 
@@ -145,13 +328,51 @@ func (m AppModel) Init() tea.Cmd {
 		pageInitCmd,
 		m.cmdPalette.Init(),
 		m.fetchWorkspaceTreeCmd(), // Fetch workspace tree on startup
+		m.startPrefetchCmd(),
 	)
 }
 
+// startPrefetchCmd starts warming the cache for recently opened pages and the
+// recently edited pages of each configured database.
+func (m *AppModel) startPrefetchCmd() tea.Cmd {
+	if m.prefetcher == nil {
+		return nil
+	}
+
+	p := m.prefetcher
+	cfg := m.config
+	return func() tea.Msg {
+		p.QueueHistory()
+		for _, db := range cfg.Databases {
+			p.QueueDatabase(db.ID, cfg.PrefetchRecentPages(db.ID))
+		}
+		p.Start()
+		return nil
+	}
+}
+
+// listPrefetcher returns the prefetcher to use for a database's list page,
+// or nil when prefetching is disabled for it.
+func (m *AppModel) listPrefetcher(databaseID string) *prefetch.Prefetcher {
+	if m.prefetcher == nil || !m.config.PrefetchEnabledFor(databaseID) {
+		return nil
+	}
+	return m.prefetcher
+}
+
+// databaseSettings returns a database's entry in the config, or no settings
+// for a database opened without one, such as from a link.
+func (m *AppModel) databaseSettings(databaseID string) config.DatabaseConfig {
+	if db := m.config.GetDatabase(databaseID); db != nil {
+		return *db
+	}
+	return config.DatabaseConfig{ID: databaseID}
+}
+
 // fetchWorkspaceTreeCmd returns a command that fetches the workspace tree.
 func (m *AppModel) fetchWorkspaceTreeCmd() tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "sidebar")
 
 		// Fetch all workspace items
 		resp, err := m.notionClient.Search(ctx, notion.SearchInput{
@@ -172,18 +393,28 @@ func (m *AppModel) fetchWorkspaceTreeCmd() tea.Cmd {
 
 // initializePages creates and registers all page instances.
 func (m *AppModel) initializePages() {
-	// If databases are configured, create ListPage
-	if m.config.HasDatabases() {
+	// If databases are configured or one was opened, create ListPage
+	if m.config.HasDatabases() || m.start.DatabaseID != "" {
 		listPage := pages.NewListPage(pages.NewListPageInput{
 			Width:        m.width,
 			Height:       m.height,
 			NotionClient: m.notionClient,
 			Cache:        m.cache,
-			DatabaseID:   m.config.GetDatabaseID(),
+			Index:        m.index,
+			Prefetcher:   m.listPrefetcher(m.currentDBID),
+			DatabaseID:   m.currentDBID,
+			Database:     m.databaseSettings(m.currentDBID),
+			Keys:         m.keys,
+			Theme:        &m.theme,
 		})
 		m.pages[PageList] = &listPage
 	}
 
+	// A page opened on start is the only DetailPage created up front
+	if m.start.PageID != "" {
+		m.setDetailPage(m.start.PageID, m.start.BlockID)
+	}
+
 	// If no databases, create workspace search page as initial view
 	if !m.config.HasDatabases() {
 		searchPage := pages.NewSearchPage(pages.NewSearchPageInput{
@@ -191,8 +422,11 @@ func (m *AppModel) initializePages() {
 			Height:       m.height,
 			NotionClient: m.notionClient,
 			Cache:        m.cache,
+			Index:        m.index,
 			DatabaseID:   "",
 			Mode:         pages.SearchModeWorkspace,
+			Keys:         m.keys,
+			Theme:        &m.theme,
 		})
 		m.pages[PageWorkspaceSearch] = &searchPage
 	}
@@ -202,6 +436,8 @@ func (m *AppModel) initializePages() {
 		Width:  m.width,
 		Height: m.height,
 		Config: m.config,
+		Keys:   m.keys,
+		Theme:  &m.theme,
 	})
 	m.pages[PageDashboard] = &dashboardPage
 
@@ -231,6 +467,9 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.treeView.SetSize(sidebarWidth, m.height-1)
 		m.statusBar.SetWidth(m.width)
+		m.toast.SetWidth(m.width)
+		m.inspector.SetSize(m.width, m.height)
+		m.stats.SetSize(m.width, m.height)
 
 		// Update all pages with window size
 		for pageID, page := range m.pages {
@@ -243,6 +482,28 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, tea.Batch(cmds...)
 
+	case ConfigReloadedMsg:
+		return m, m.handleConfigReloaded(msg)
+
+	case ConfigReloadFailedMsg:
+		return m, m.handleConfigReloadFailed(msg)
+
+	case ProfileSwitchedMsg:
+		return m, m.handleProfileSwitched(msg)
+
+	case ProfileSwitchFailedMsg:
+		return m, m.handleProfileSwitchFailed(msg)
+
+	case components.ToastExpiredMsg:
+		m.toast, _ = m.toast.Update(msg)
+		return m, nil
+
+	case exportProgressMsg:
+		return m, m.handleExportProgress(msg)
+
+	case exportDoneMsg:
+		return m, m.handleExportDone(msg)
+
 	case workspaceTreeMsg:
 		// Workspace tree data received
 		if msg.err != nil {
@@ -262,23 +523,45 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.navigateToDetail(msg.ID)
 
 	case tea.KeyMsg:
+		// The inspector takes every key but quit while it is open
+		if m.inspector.IsOpen() {
+			if key.Matches(msg, m.keys.Global.Quit) {
+				return m, tea.Quit
+			}
+			var cmd tea.Cmd
+			m.inspector, cmd = m.inspector.Update(msg)
+			return m, cmd
+		}
+		if key.Matches(msg, m.keys.Global.Inspector) {
+			return m, m.toggleInspector()
+		}
+		// So does the stats panel
+		if m.stats.IsOpen() {
+			if key.Matches(msg, m.keys.Global.Quit) {
+				return m, tea.Quit
+			}
+			var cmd tea.Cmd
+			m.stats, cmd = m.stats.Update(msg)
+			return m, cmd
+		}
+
 		// Check if we're on the search page - it needs special key handling
 		isSearchPage := m.currentPage == PageWorkspaceSearch
 
 		// Handle mode-specific keys FIRST before global keys
-		switch msg.String() {
-		case "tab":
+		switch {
+		case key.Matches(msg, m.keys.Global.FocusSidebar):
 			// Toggle focus between sidebar and main content
 			m.sidebarFocus = !m.sidebarFocus
 			m.treeView.SetFocused(m.sidebarFocus)
 			return m, nil
 
-		case "ctrl+b":
+		case key.Matches(msg, m.keys.Global.ToggleSidebar):
 			// Toggle sidebar visibility
 			m.showSidebar = !m.showSidebar
 			return m, nil
 
-		case "ctrl+p":
+		case key.Matches(msg, m.keys.Global.Palette):
 			// Toggle command palette (works everywhere)
 			m.cmdPalette.Toggle()
 			m.showPalette = m.cmdPalette.IsOpen()
@@ -293,7 +576,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
-		// If palette is open, only palette handles keys (except ctrl+p which is handled above)
+		// If palette is open, only palette handles keys (except the palette key handled above)
 		if m.showPalette {
 			var cmd tea.Cmd
 			m.cmdPalette, cmd = m.cmdPalette.Update(msg)
@@ -303,16 +586,10 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
-		// Handle other mode-specific keys
-		switch msg.String() {
-
-		case "esc":
-			// On search page, let it handle Esc first (e.g., to clear filter)
-			// then fall through to page delegation which will handle back navigation
-			if isSearchPage {
-				break // Fall through to page delegation
-			}
-			// Handle back navigation for other pages
+		// Handle back navigation. On the search page, let it handle back first
+		// (e.g., to clear filter) through page delegation, which will then
+		// request back navigation
+		if key.Matches(msg, m.keys.Global.Back) && !isSearchPage {
 			if m.navigator.CanGoBack() {
 				previousPage, ok := m.navigator.Back()
 				if ok {
@@ -357,6 +634,23 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// An open inspector follows the request log as requests finish, and
+	// an open stats panel the aggregates
+	if m.inspector.IsOpen() {
+		var cmd tea.Cmd
+		m.inspector, cmd = m.inspector.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if m.stats.IsOpen() {
+		var cmd tea.Cmd
+		m.stats, cmd = m.stats.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+
 	// Delegate to current page
 	if page, ok := m.pages[m.currentPage]; ok {
 		updatedPage, cmd := page.Update(msg)
@@ -418,6 +712,7 @@ func (m AppModel) View() string {
 			SidebarWidth: m.width / 4,
 			TotalWidth:   m.width,
 			TotalHeight:  m.height - 1, // Reserve 1 line for status bar
+			Theme:        &m.theme,
 		})
 	} else {
 		mainContent = pageView
@@ -425,10 +720,14 @@ func (m AppModel) View() string {
 
 	// Add status bar at bottom
 	statusView := m.statusBar.View()
+	if m.toast.Visible() {
+		statusView = m.toast.View()
+	}
 	finalView := LayoutWithStatusBar(LayoutWithStatusBarInput{
 		Content:   mainContent,
 		StatusBar: statusView,
 		Height:    m.height,
+		Theme:     &m.theme,
 	})
 
 	// Overlay command palette if visible
@@ -439,6 +738,25 @@ func (m AppModel) View() string {
 			Palette:    paletteView,
 			Width:      m.width,
 			Height:     m.height,
+			Theme:      &m.theme,
+		})
+	}
+
+	// Overlay the inspector or the stats panel over everything else
+	if m.stats.IsOpen() {
+		finalView = CenterOverlay(CenterOverlayInput{
+			Overlay: m.stats.View(),
+			Width:   m.width,
+			Height:  m.height,
+			Theme:   &m.theme,
+		})
+	}
+	if m.inspector.IsOpen() {
+		finalView = CenterOverlay(CenterOverlayInput{
+			Overlay: m.inspector.View(),
+			Width:   m.width,
+			Height:  m.height,
+			Theme:   &m.theme,
 		})
 	}
 
@@ -448,16 +766,20 @@ func (m AppModel) View() string {
 // handleGlobalKeys processes global keyboard shortcuts.
 // Returns (handled, cmd) where handled indicates if the key was processed.
 func (m *AppModel) handleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
+	switch {
+	case key.Matches(msg, m.keys.Global.Quit):
 		return true, tea.Quit
-	case "?":
+	case key.Matches(msg, m.keys.Global.Help):
 		// Toggle help display
 		m.showHelp = !m.showHelp
 		if m.showHelp {
-			m.statusBar.SetHelpText("Tab:focus tree | Ctrl+B:toggle tree | ←/→:expand | Enter:open | Ctrl+P:cmd | q:quit | ?:close")
+			m.statusBar.SetHelpText(fmt.Sprintf("%s:focus tree | %s:toggle tree | %s/%s:expand | %s:open | %s:cmd | %s:quit | %s:close",
+				keymap.Label(m.keys.Global.FocusSidebar), keymap.Label(m.keys.Global.ToggleSidebar),
+				keymap.Label(m.keys.List.Collapse), keymap.Label(m.keys.List.Expand), keymap.Label(m.keys.List.Select),
+				keymap.Label(m.keys.Global.Palette), keymap.Label(m.keys.Global.Quit), keymap.Label(m.keys.Global.Help)))
 		} else {
-			m.statusBar.SetHelpText("? for help | Tab: focus tree")
+			m.statusBar.SetHelpText(fmt.Sprintf("%s for help | %s: focus tree",
+				keymap.Label(m.keys.Global.Help), keymap.Label(m.keys.Global.FocusSidebar)))
 		}
 		return true, nil
 	default:
@@ -487,10 +809,22 @@ func (m *AppModel) navigateTo(pageID PageID) tea.Cmd {
 
 // navigateToDetail navigates to the detail page for a specific Notion page.
 func (m *AppModel) navigateToDetail(notionPageID string) tea.Cmd {
+	m.setDetailPage(notionPageID, "")
+
+	// Navigate to detail page
+	return m.navigateTo(PageDetail)
+}
+
+// setDetailPage creates the detail page for a Notion page, scrolled to the
+// given top-level block when it is not empty.
+func (m *AppModel) setDetailPage(notionPageID, blockID string) {
 	// Create viewer for the detail page
 	viewer := components.NewPageViewer(components.NewPageViewerInput{
-		Width:  m.width,
-		Height: m.height - 2, // Reserve space for status bar
+		Width:    m.width,
+		Height:   m.height - 2, // Reserve space for status bar
+		ScrollTo: blockID,
+		Keys:     m.keys,
+		Theme:    &m.theme,
 	})
 
 	// Create or update detail page
@@ -500,12 +834,18 @@ func (m *AppModel) navigateToDetail(notionPageID string) tea.Cmd {
 		Viewer:       &viewer,
 		NotionClient: m.notionClient,
 		Cache:        m.cache,
+		Index:        m.index,
 		PageID:       notionPageID,
+		Keys:         m.keys,
+		Theme:        &m.theme,
 	})
 	m.pages[PageDetail] = &detailPage
 
-	// Navigate to detail page
-	return m.navigateTo(PageDetail)
+	// Remember the visit so the page is kept warm in later sessions
+	if m.prefetcher != nil {
+		// History is best effort; a failed write only loses warming hints
+		_ = m.prefetcher.RecordVisit(notionPageID)
+	}
 }
 
 // goBack navigates to the previous page in history.
@@ -526,7 +866,12 @@ func (m *AppModel) createPage(pageID PageID) {
 			Height:       m.height,
 			NotionClient: m.notionClient,
 			Cache:        m.cache,
+			Index:        m.index,
+			Prefetcher:   m.listPrefetcher(m.currentDBID),
 			DatabaseID:   m.currentDBID,
+			Database:     m.databaseSettings(m.currentDBID),
+			Keys:         m.keys,
+			Theme:        &m.theme,
 		})
 		m.pages[pageID] = &listPage
 
@@ -536,6 +881,8 @@ func (m *AppModel) createPage(pageID PageID) {
 		viewer := components.NewPageViewer(components.NewPageViewerInput{
 			Width:  m.width,
 			Height: m.height - 2,
+			Keys:   m.keys,
+			Theme:  &m.theme,
 		})
 		detailPage := pages.NewDetailPage(pages.NewDetailPageInput{
 			Width:        m.width,
@@ -543,7 +890,10 @@ func (m *AppModel) createPage(pageID PageID) {
 			Viewer:       &viewer,
 			NotionClient: m.notionClient,
 			Cache:        m.cache,
+			Index:        m.index,
 			PageID:       "",
+			Keys:         m.keys,
+			Theme:        &m.theme,
 		})
 		m.pages[pageID] = &detailPage
 
@@ -558,8 +908,12 @@ func (m *AppModel) createPage(pageID PageID) {
 			Height:       m.height,
 			NotionClient: m.notionClient,
 			Cache:        m.cache,
+			Index:        m.index,
 			DatabaseID:   m.currentDBID,
+			Database:     m.databaseSettings(m.currentDBID),
 			Mode:         pages.SearchModeDatabase,
+			Keys:         m.keys,
+			Theme:        &m.theme,
 		})
 		m.pages[pageID] = &searchPage
 
@@ -569,8 +923,12 @@ func (m *AppModel) createPage(pageID PageID) {
 			Height:       m.height,
 			NotionClient: m.notionClient,
 			Cache:        m.cache,
+			Index:        m.index,
 			DatabaseID:   m.currentDBID,
+			Database:     m.databaseSettings(m.currentDBID),
 			Mode:         pages.SearchModeWorkspace,
+			Keys:         m.keys,
+			Theme:        &m.theme,
 		})
 		m.pages[pageID] = &searchPage
 
@@ -580,6 +938,8 @@ func (m *AppModel) createPage(pageID PageID) {
 			Height:      m.height,
 			Databases:   m.config.Databases,
 			DefaultDBID: m.currentDBID,
+			Keys:        m.keys,
+			Theme:       &m.theme,
 		})
 		m.pages[pageID] = &dbListPage
 
@@ -588,6 +948,8 @@ func (m *AppModel) createPage(pageID PageID) {
 			Width:  m.width,
 			Height: m.height,
 			Config: m.config,
+			Keys:   m.keys,
+			Theme:  &m.theme,
 		})
 		m.pages[pageID] = &dashboardPage
 	}
@@ -638,10 +1000,21 @@ func (m *AppModel) handleCommandExecution(msg components.CommandExecutedMsg) tea
 		return nil
 
 	case "export":
-		// TODO: Implement export functionality
-		return nil
+		return m.startExport()
+
+	case inspectorActionType:
+		return m.toggleInspector()
+
+	case statsActionType:
+		return m.openStats()
 
 	default:
+		if name, ok := strings.CutPrefix(msg.ActionType, themeActionPrefix); ok {
+			return m.switchTheme(name)
+		}
+		if name, ok := strings.CutPrefix(msg.ActionType, profileActionPrefix); ok {
+			return m.switchProfile(name)
+		}
 		return nil
 	}
 }
@@ -654,8 +1027,11 @@ func (m *AppModel) navigateToSearch() tea.Cmd {
 		Height:       m.height,
 		NotionClient: m.notionClient,
 		Cache:        m.cache,
+		Index:        m.index,
 		DatabaseID:   m.currentDBID,
 		Mode:         pages.SearchModeWorkspace,
+		Keys:         m.keys,
+		Theme:        &m.theme,
 	})
 	m.pages[PageWorkspaceSearch] = &searchPage
 
@@ -671,6 +1047,8 @@ func (m *AppModel) navigateToDatabaseList() tea.Cmd {
 		Height:      m.height,
 		Databases:   m.config.Databases,
 		DefaultDBID: m.currentDBID,
+		Keys:        m.keys,
+		Theme:       &m.theme,
 	})
 	m.pages[PageDatabaseList] = &dbListPage
 
@@ -703,7 +1081,12 @@ func (m *AppModel) switchDatabase(databaseID string) tea.Cmd {
 		Height:       m.height,
 		NotionClient: m.notionClient,
 		Cache:        m.cache,
+		Index:        m.index,
+		Prefetcher:   m.listPrefetcher(databaseID),
 		DatabaseID:   databaseID,
+		Database:     m.databaseSettings(databaseID),
+		Keys:         m.keys,
+		Theme:        &m.theme,
 	})
 	m.pages[PageList] = &listPage
 
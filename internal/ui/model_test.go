@@ -57,6 +57,49 @@ func TestNewModel_NoDatabases(t *testing.T) {
 	assert.NotNil(t, model.notionClient)
 }
 
+func TestNewModel_Start(t *testing.T) {
+	cfg := &config.Config{
+		NotionToken: "test_token",
+		CacheDir:    t.TempDir(),
+	}
+
+	tests := []struct {
+		name     string
+		start    StartTarget
+		wantPage PageID
+	}{
+		{name: "dashboard", wantPage: PageDashboard},
+		{name: "page", start: StartTarget{PageID: "page-1", BlockID: "block-1"}, wantPage: PageDetail},
+		{name: "database", start: StartTarget{DatabaseID: "db-1"}, wantPage: PageList},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(NewModelInput{Config: cfg, Start: tt.start})
+			defer model.Close()
+			model.initializePages()
+
+			assert.Equal(t, tt.wantPage, model.currentPage)
+			assert.Contains(t, model.pages, tt.wantPage)
+			if tt.wantPage != PageDashboard {
+				assert.Equal(t, []PageID{PageDashboard}, model.navigator.History(), "back leads to the dashboard")
+			}
+		})
+	}
+
+	model := NewModel(NewModelInput{Config: cfg, Start: StartTarget{PageID: "page-1"}})
+	defer model.Close()
+	model.initializePages()
+	detail, ok := model.pages[PageDetail].(*pages.DetailPage)
+	if assert.True(t, ok) {
+		assert.Equal(t, "page-1", detail.PageID())
+	}
+
+	model = NewModel(NewModelInput{Config: cfg, Start: StartTarget{DatabaseID: "db-1"}})
+	defer model.Close()
+	assert.Equal(t, "db-1", model.currentDBID)
+}
+
 func TestModelUpdate_WindowSize(t *testing.T) {
 	cfg := &config.Config{
 		NotionToken: "test_token",
@@ -22,6 +22,7 @@ type NotionClient interface {
 	UpdateBlock(ctx context.Context, id string, req *notionapi.BlockUpdateRequest) (notionapi.Block, error)
 
 	// Database operations
+	GetDatabase(ctx context.Context, id string) (*notionapi.Database, error)
 	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
 
 	// Search operations
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Panandika/notion-tui/internal/config"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 const notionLogo = `
@@ -35,6 +38,8 @@ type DashboardPage struct {
 	config      *config.Config
 	selectedIdx int
 	menuItems   []dashboardItem
+	keys        keymap.ListKeys
+	theme       theme.Theme
 }
 
 type dashboardItem struct {
@@ -48,18 +53,34 @@ type NewDashboardPageInput struct {
 	Width  int
 	Height int
 	Config *config.Config
+	Keys   *keymap.KeyMap // optional; the default bindings when nil
+	Theme  *theme.Theme   // optional; the default theme when nil
 }
 
 // NewDashboardPage creates a new DashboardPage instance.
 func NewDashboardPage(input NewDashboardPageInput) DashboardPage {
+	return DashboardPage{
+		width:       input.Width,
+		height:      input.Height,
+		config:      input.Config,
+		selectedIdx: 0,
+		menuItems:   dashboardItems(input.Config),
+		keys:        keymap.OrDefault(input.Keys).List,
+		theme:       theme.OrDefault(input.Theme),
+	}
+}
+
+// dashboardItems returns the menu: the fixed actions, then one entry per
+// configured database.
+func dashboardItems(cfg *config.Config) []dashboardItem {
 	items := []dashboardItem{
 		{label: "Search Workspace", actionType: "search"},
 		{label: "Switch Database", actionType: "switch-db"},
 	}
 
 	// Add configured databases to the menu
-	if input.Config != nil && len(input.Config.Databases) > 0 {
-		for _, db := range input.Config.Databases {
+	if cfg != nil && len(cfg.Databases) > 0 {
+		for _, db := range cfg.Databases {
 			label := fmt.Sprintf("Open %s", db.Name)
 			if db.Icon != "" {
 				label = fmt.Sprintf("Open %s %s", db.Icon, db.Name)
@@ -72,15 +93,25 @@ func NewDashboardPage(input NewDashboardPageInput) DashboardPage {
 		}
 	}
 
-	return DashboardPage{
-		width:       input.Width,
-		height:      input.Height,
-		config:      input.Config,
-		selectedIdx: 0,
-		menuItems:   items,
+	return items
+}
+
+// SetConfig rebuilds the menu from a reloaded config, keeping the selection
+// where it still fits.
+func (d *DashboardPage) SetConfig(cfg *config.Config) {
+	d.config = cfg
+	d.menuItems = dashboardItems(cfg)
+	if d.selectedIdx >= len(d.menuItems) {
+		d.selectedIdx = len(d.menuItems) - 1
 	}
 }
 
+// SetKeys sets the bindings the dashboard responds to. Nil restores the
+// default bindings.
+func (d *DashboardPage) SetKeys(k *keymap.KeyMap) {
+	d.keys = keymap.OrDefault(k).List
+}
+
 // Init initializes the dashboard page.
 func (d *DashboardPage) Init() tea.Cmd {
 	return nil
@@ -90,16 +121,16 @@ func (d *DashboardPage) Init() tea.Cmd {
 func (d *DashboardPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, d.keys.Up):
 			if d.selectedIdx > 0 {
 				d.selectedIdx--
 			}
-		case "down", "j":
+		case key.Matches(msg, d.keys.Down):
 			if d.selectedIdx < len(d.menuItems)-1 {
 				d.selectedIdx++
 			}
-		case "enter":
+		case key.Matches(msg, d.keys.Select):
 			return d, d.executeAction()
 		}
 
@@ -110,6 +141,12 @@ func (d *DashboardPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return d, nil
 }
 
+// SetTheme restyles the dashboard for a theme.
+func (d *DashboardPage) SetTheme(t theme.Theme) tea.Cmd {
+	d.theme = t
+	return nil
+}
+
 // executeAction performs the action for the selected item.
 func (d *DashboardPage) executeAction() tea.Cmd {
 	item := d.menuItems[d.selectedIdx]
@@ -145,25 +182,25 @@ func (d *DashboardPage) executeAction() tea.Cmd {
 // View renders the dashboard page.
 func (d *DashboardPage) View() string {
 	logoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("63")). // Purple-ish
+		Foreground(d.theme.Primary).
 		Bold(true).
 		MarginBottom(1)
 
 	welcomeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
+		Foreground(d.theme.Text).
 		Bold(true).
 		MarginBottom(2)
 
 	itemStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(d.theme.Muted).
 		PaddingLeft(2)
 
 	selectedItemStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
+		Foreground(d.theme.Text).
 		Bold(true).
 		PaddingLeft(2).
 		Border(lipgloss.NormalBorder(), false, false, false, true).
-		BorderForeground(lipgloss.Color("63"))
+		BorderForeground(d.theme.Primary)
 
 	var menuView strings.Builder
 	for i, item := range d.menuItems {
@@ -3,12 +3,15 @@ package pages
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Panandika/notion-tui/internal/config"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // databaseItem represents a single database in the list.
@@ -36,6 +39,9 @@ func (d databaseItem) Description() string {
 	if d.isDefault {
 		desc += " (default)"
 	}
+	if d.db.Description != "" {
+		desc = d.db.Description + " | " + desc
+	}
 	return desc
 }
 
@@ -60,6 +66,7 @@ type DatabaseListPage struct {
 	width        int
 	height       int
 	styles       DatabaseListPageStyles
+	keys         keymap.ListKeys
 }
 
 // DatabaseListPageStyles holds the styles for the database list page.
@@ -71,15 +78,20 @@ type DatabaseListPageStyles struct {
 
 // DefaultDatabaseListPageStyles returns the default styles.
 func DefaultDatabaseListPageStyles() DatabaseListPageStyles {
+	return NewDatabaseListPageStyles(theme.Default())
+}
+
+// NewDatabaseListPageStyles returns the database list page styles for a theme.
+func NewDatabaseListPageStyles(t theme.Theme) DatabaseListPageStyles {
 	return DatabaseListPageStyles{
 		Container: lipgloss.NewStyle().
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7C3AED")).
+			Foreground(t.Primary).
 			Bold(true).
 			MarginBottom(1),
 		Default: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(t.Accent).
 			Bold(true),
 	}
 }
@@ -90,6 +102,8 @@ type NewDatabaseListPageInput struct {
 	Height      int
 	Databases   []config.DatabaseConfig
 	DefaultDBID string
+	Keys        *keymap.KeyMap // optional; the default bindings when nil
+	Theme       *theme.Theme   // optional; the default theme when nil
 }
 
 // NewDatabaseListPage creates a new DatabaseListPage instance.
@@ -103,10 +117,7 @@ func NewDatabaseListPage(input NewDatabaseListPageInput) DatabaseListPage {
 		})
 	}
 
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true
-
-	l := list.New(items, delegate, input.Width-4, input.Height-6)
+	l := list.New(items, list.NewDefaultDelegate(), input.Width-4, input.Height-6)
 	l.Title = "Select Database"
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(true)
@@ -117,12 +128,8 @@ func NewDatabaseListPage(input NewDatabaseListPageInput) DatabaseListPage {
 	statusBar.SetWidth(input.Width)
 	statusBar.SetMode(components.ModeBrowse)
 	statusBar.SetSyncStatus(components.StatusSynced)
-	statusBar.SetHelpText("Enter: select database | ESC: back")
-
-	styles := DefaultDatabaseListPageStyles()
-	l.Styles.Title = styles.Title
 
-	return DatabaseListPage{
+	dlp := DatabaseListPage{
 		list:         l,
 		statusBar:    statusBar,
 		databases:    input.Databases,
@@ -130,8 +137,30 @@ func NewDatabaseListPage(input NewDatabaseListPageInput) DatabaseListPage {
 		selectedDBID: input.DefaultDBID,
 		width:        input.Width,
 		height:       input.Height,
-		styles:       styles,
 	}
+	dlp.SetKeys(input.Keys)
+	dlp.SetTheme(theme.OrDefault(input.Theme))
+	return dlp
+}
+
+// SetKeys sets the bindings the page responds to and shows them in the
+// status bar. Nil restores the default bindings.
+func (dlp *DatabaseListPage) SetKeys(k *keymap.KeyMap) {
+	keys := keymap.OrDefault(k)
+	dlp.keys = keys.List
+	dlp.list.KeyMap.CursorUp = keys.List.Up
+	dlp.list.KeyMap.CursorDown = keys.List.Down
+	dlp.statusBar.SetHelpText(fmt.Sprintf("%s: select database | %s: back",
+		keymap.Label(keys.List.Select), keymap.Label(keys.Global.Back)))
+}
+
+// SetTheme restyles the page for a theme.
+func (dlp *DatabaseListPage) SetTheme(t theme.Theme) tea.Cmd {
+	dlp.styles = NewDatabaseListPageStyles(t)
+	components.StyleList(&dlp.list, t)
+	dlp.list.Styles.Title = dlp.styles.Title
+	dlp.statusBar.SetTheme(t)
+	return nil
 }
 
 // Init initializes the database list page component.
@@ -143,8 +172,8 @@ func (dlp *DatabaseListPage) Init() tea.Cmd {
 func (dlp *DatabaseListPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, dlp.keys.Select):
 			// Select database
 			if item, ok := dlp.list.SelectedItem().(databaseItem); ok {
 				dlp.selectedDBID = item.db.ID
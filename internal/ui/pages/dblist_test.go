@@ -209,6 +209,14 @@ func TestDatabaseItem_NoIcon(t *testing.T) {
 	assert.Contains(t, item.Title(), "Test Database")
 }
 
+func TestDatabaseItem_Description(t *testing.T) {
+	item := databaseItem{
+		db: config.DatabaseConfig{ID: "db-1", Name: "Tasks", Description: "Personal task tracker"},
+	}
+
+	assert.Equal(t, "Personal task tracker | ID: db-1", item.Description())
+}
+
 func TestDatabaseListPage_EmptyDatabases(t *testing.T) {
 	page := NewDatabaseListPage(NewDatabaseListPageInput{
 		Width:       80,
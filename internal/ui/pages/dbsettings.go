@@ -0,0 +1,134 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/filter"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// PageProperty is a property value shown with a page.
+type PageProperty struct {
+	Name  string
+	Value string
+}
+
+// databaseQuery builds the request listing a database's pages with the
+// filter and sorts from its settings. Both are resolved against the
+// database schema, so the database is fetched first. It returns nil when
+// neither is set.
+func databaseQuery(ctx context.Context, client NotionClient, databaseID string, settings config.DatabaseConfig, now time.Time) (*notionapi.DatabaseQueryRequest, error) {
+	if strings.TrimSpace(settings.Filter) == "" && len(settings.Sort) == 0 {
+		return nil, nil
+	}
+
+	db, err := client.GetDatabase(ctx, databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("get database schema: %w", err)
+	}
+	schema := filter.SchemaOf(db)
+
+	req := &notionapi.DatabaseQueryRequest{}
+	if strings.TrimSpace(settings.Filter) != "" {
+		expr, err := filter.Parse(settings.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("parse filter: %w", err)
+		}
+		if req.Filter, err = filter.Compile(expr, schema, now); err != nil {
+			return nil, fmt.Errorf("compile filter: %w", err)
+		}
+	}
+	if req.Sorts, err = filter.ParseSorts(settings.Sort, schema); err != nil {
+		return nil, fmt.Errorf("parse sort: %w", err)
+	}
+	return req, nil
+}
+
+// nextPageQuery returns a copy of req that continues from cursor.
+func nextPageQuery(req *notionapi.DatabaseQueryRequest, cursor string) *notionapi.DatabaseQueryRequest {
+	next := &notionapi.DatabaseQueryRequest{}
+	if req != nil {
+		*next = *req
+	}
+	next.StartCursor = notionapi.Cursor(cursor)
+	return next
+}
+
+// pageFromNotion converts a database row using the database's settings for
+// its title, status, date and visible properties.
+func pageFromNotion(p *notionapi.Page, settings config.DatabaseConfig) Page {
+	page := NewPage(string(p.ID), pageTitle(p, settings), extractStatusFrom(p, settings.StatusProperty), p.LastEditedTime)
+	if settings.DateProperty != "" {
+		if prop, ok := findPageProperty(p.Properties, settings.DateProperty); ok {
+			page.Date = notion.PropertyString(prop)
+		}
+	}
+	for _, name := range settings.Properties {
+		prop, ok := findPageProperty(p.Properties, name)
+		if !ok {
+			continue
+		}
+		if value := notion.PropertyString(prop); value != "" {
+			page.Properties = append(page.Properties, PageProperty{Name: name, Value: value})
+		}
+	}
+	return page
+}
+
+// pageTitle returns the value of the configured title property, falling
+// back to the page title when it is unset or empty.
+func pageTitle(p *notionapi.Page, settings config.DatabaseConfig) string {
+	if settings.TitleProperty != "" {
+		if prop, ok := findPageProperty(p.Properties, settings.TitleProperty); ok {
+			if title := notion.PropertyString(prop); title != "" {
+				return title
+			}
+		}
+	}
+	return extractTitle(p)
+}
+
+// extractStatusFrom returns the value of a page's status property. A status
+// or select property reads as its option name; any other type as text.
+// The default status property is used when name is empty.
+func extractStatusFrom(page *notionapi.Page, name string) string {
+	if page == nil {
+		return ""
+	}
+	if name == "" {
+		name = config.DefaultStatusProperty
+	}
+
+	prop, ok := findPageProperty(page.Properties, name)
+	if !ok {
+		return ""
+	}
+	switch p := prop.(type) {
+	case *notionapi.StatusProperty:
+		return p.Status.Name
+	case *notionapi.SelectProperty:
+		return p.Select.Name
+	default:
+		return notion.PropertyString(prop)
+	}
+}
+
+// findPageProperty resolves a property name, ignoring case when there is no
+// exact match.
+func findPageProperty(props notionapi.Properties, name string) (notionapi.Property, bool) {
+	if prop, ok := props[name]; ok {
+		return prop, true
+	}
+	for candidate, prop := range props {
+		if strings.EqualFold(candidate, name) {
+			return prop, true
+		}
+	}
+	return nil, false
+}
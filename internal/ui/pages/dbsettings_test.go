@@ -0,0 +1,287 @@
+package pages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/config"
+)
+
+// tasksDatabase is the schema of the database used by these tests.
+func tasksDatabase() *notionapi.Database {
+	return &notionapi.Database{
+		ID: "db-tasks",
+		Properties: notionapi.PropertyConfigs{
+			"Name":     &notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+			"Stage":    &notionapi.StatusPropertyConfig{Type: notionapi.PropertyConfigStatus},
+			"Due":      &notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate},
+			"Priority": &notionapi.SelectPropertyConfig{Type: notionapi.PropertyConfigTypeSelect},
+		},
+	}
+}
+
+// newTaskPage returns a row of the tasks database.
+func newTaskPage(id, title, stage, priority string) notionapi.Page {
+	due := notionapi.Date(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	return notionapi.Page{
+		ID:             notionapi.ObjectID(id),
+		LastEditedTime: time.Now().Add(-time.Hour),
+		Properties: notionapi.Properties{
+			"Name":     &notionapi.TitleProperty{Title: []notionapi.RichText{{PlainText: title}}},
+			"Stage":    &notionapi.StatusProperty{Status: notionapi.Status{Name: stage}},
+			"Due":      &notionapi.DateProperty{Date: &notionapi.DateObject{Start: &due}},
+			"Priority": &notionapi.SelectProperty{Select: notionapi.Option{Name: priority}},
+		},
+	}
+}
+
+func TestDatabaseQuery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	client := &MockNotionClient{
+		GetDatabaseFunc: func(ctx context.Context, id string) (*notionapi.Database, error) {
+			return tasksDatabase(), nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		settings    config.DatabaseConfig
+		client      *MockNotionClient
+		expectNil   bool
+		expectSorts []notionapi.SortObject
+		expectError string
+	}{
+		{
+			name:      "no filter or sort",
+			settings:  config.DatabaseConfig{StatusProperty: "Stage"},
+			client:    &MockNotionClient{},
+			expectNil: true,
+		},
+		{
+			name:     "filter and sorts",
+			settings: config.DatabaseConfig{Filter: `stage != "Done"`, Sort: []string{"-due,Priority"}},
+			client:   client,
+			expectSorts: []notionapi.SortObject{
+				{Property: "Due", Direction: notionapi.SortOrderDESC},
+				{Property: "Priority", Direction: notionapi.SortOrderASC},
+			},
+		},
+		{
+			name:        "unknown sort property",
+			settings:    config.DatabaseConfig{Sort: []string{"Owner"}},
+			client:      client,
+			expectError: `unknown sort property "Owner"`,
+		},
+		{
+			name:        "schema unavailable",
+			settings:    config.DatabaseConfig{Sort: []string{"Due"}},
+			client:      &MockNotionClient{},
+			expectError: "get database schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := databaseQuery(context.Background(), tt.client, "db-tasks", tt.settings, now)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectNil {
+				assert.Nil(t, req)
+				return
+			}
+			require.NotNil(t, req)
+			assert.Equal(t, tt.expectSorts, req.Sorts)
+			if tt.settings.Filter != "" {
+				assert.NotNil(t, req.Filter)
+			}
+		})
+	}
+}
+
+func TestPageFromNotion(t *testing.T) {
+	t.Parallel()
+
+	row := newTaskPage("page-1", "Write report", "In progress", "High")
+
+	tests := []struct {
+		name     string
+		settings config.DatabaseConfig
+		expect   Page
+	}{
+		{
+			name:     "default settings",
+			settings: config.DatabaseConfig{},
+			expect:   Page{ID: "page-1", Title: "Write report"},
+		},
+		{
+			name: "configured properties",
+			settings: config.DatabaseConfig{
+				StatusProperty: "stage",
+				DateProperty:   "Due",
+				Properties:     []string{"Priority", "Missing"},
+			},
+			expect: Page{
+				ID:         "page-1",
+				Title:      "Write report",
+				Status:     "In progress",
+				Date:       "2024-03-15",
+				Properties: []PageProperty{{Name: "Priority", Value: "High"}},
+			},
+		},
+		{
+			name:     "title property",
+			settings: config.DatabaseConfig{TitleProperty: "Priority"},
+			expect:   Page{ID: "page-1", Title: "High"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page := pageFromNotion(&row, tt.settings)
+			page.UpdatedAt = time.Time{}
+			assert.Equal(t, tt.expect, page)
+		})
+	}
+}
+
+func TestPageDescription(t *testing.T) {
+	t.Parallel()
+
+	page := Page{
+		Status:     "In progress",
+		Date:       "2024-03-15",
+		Properties: []PageProperty{{Name: "Priority", Value: "High"}},
+		UpdatedAt:  time.Now(),
+	}
+	assert.Equal(t, "In progress | 2024-03-15 | Priority: High | Updated: just now", pageDescription(page))
+}
+
+func TestListPage_DatabaseSettings(t *testing.T) {
+	t.Parallel()
+
+	var requests []*notionapi.DatabaseQueryRequest
+	mockClient := &MockNotionClient{
+		GetDatabaseFunc: func(ctx context.Context, id string) (*notionapi.Database, error) {
+			return tasksDatabase(), nil
+		},
+		QueryDatabaseFunc: func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+			requests = append(requests, req)
+			if req.StartCursor == "" {
+				return &notionapi.DatabaseQueryResponse{
+					Results:    []notionapi.Page{newTaskPage("page-1", "Write report", "In progress", "High")},
+					HasMore:    true,
+					NextCursor: "cursor-2",
+				}, nil
+			}
+			return &notionapi.DatabaseQueryResponse{
+				Results: []notionapi.Page{newTaskPage("page-2", "Plan offsite", "Not started", "Low")},
+			}, nil
+		},
+	}
+
+	lp := NewListPage(NewListPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: mockClient,
+		DatabaseID:   "db-tasks",
+		Database: config.DatabaseConfig{
+			StatusProperty: "Stage",
+			Sort:           []string{"-Due"},
+			Filter:         `Stage != "Done"`,
+		},
+	})
+
+	lp.Update(lp.fetchPagesCmd()())
+	require.Len(t, lp.pageList, 1)
+	assert.Equal(t, "In progress", lp.pageList[0].Status)
+
+	lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	lp.Update(lp.loadMoreCmd()())
+	require.Len(t, lp.pageList, 2)
+
+	// Loading more continues the same filtered, sorted query
+	require.Len(t, requests, 2)
+	assert.Equal(t, notionapi.Cursor("cursor-2"), requests[1].StartCursor)
+	assert.Equal(t, requests[0].Sorts, requests[1].Sorts)
+	assert.Equal(t, requests[0].Filter, requests[1].Filter)
+}
+
+func TestListPage_DatabaseSettingsError(t *testing.T) {
+	t.Parallel()
+
+	lp := NewListPage(NewListPageInput{
+		NotionClient: &MockNotionClient{
+			GetDatabaseFunc: func(ctx context.Context, id string) (*notionapi.Database, error) {
+				return nil, errors.New("object not found")
+			},
+		},
+		DatabaseID: "db-tasks",
+		Database:   config.DatabaseConfig{Sort: []string{"Due"}},
+	})
+
+	msg := lp.fetchPagesCmd()().(pagesLoadedMsg)
+	require.Error(t, msg.err)
+	assert.Contains(t, msg.err.Error(), "database settings")
+}
+
+func TestSearchPage_SearchDatabase_Settings(t *testing.T) {
+	t.Parallel()
+
+	var request *notionapi.DatabaseQueryRequest
+	mockClient := &MockNotionClient{
+		GetDatabaseFunc: func(ctx context.Context, id string) (*notionapi.Database, error) {
+			return tasksDatabase(), nil
+		},
+		QueryDatabaseFunc: func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+			request = req
+			return &notionapi.DatabaseQueryResponse{
+				Results: []notionapi.Page{
+					newTaskPage("page-1", "Write report", "In progress", "High"),
+					newTaskPage("page-2", "Plan offsite", "Blocked", "Low"),
+				},
+			}, nil
+		},
+	}
+
+	sp := NewSearchPage(NewSearchPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: mockClient,
+		DatabaseID:   "db-tasks",
+		Database: config.DatabaseConfig{
+			StatusProperty: "Stage",
+			Properties:     []string{"Priority"},
+			Filter:         `Stage != "Done"`,
+		},
+		Mode: SearchModeDatabase,
+	})
+
+	msg := sp.searchDatabase(context.Background(), "blocked", "db-tasks")
+	require.NoError(t, msg.err)
+	require.Len(t, msg.results, 1)
+	assert.Equal(t, "Stage: Blocked", msg.results[0].Snippet)
+
+	msg = sp.searchDatabase(context.Background(), "low", "db-tasks")
+	require.Len(t, msg.results, 1)
+	assert.Equal(t, "Priority: Low", msg.results[0].Snippet)
+
+	require.NotNil(t, request)
+	assert.NotNil(t, request.Filter)
+}
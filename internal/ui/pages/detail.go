@@ -4,14 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jomei/notionapi"
 
 	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/logging"
+	"github.com/Panandika/notion-tui/internal/notion"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // ViewerInterface is imported from components package.
@@ -45,6 +53,8 @@ type DetailPage struct {
 	height       int
 	notionClient NotionClient
 	cache        *cache.PageCache
+	index        *index.Index
+	keys         *keymap.KeyMap
 }
 
 // NewDetailPageInput contains the parameters for creating a DetailPage.
@@ -54,7 +64,10 @@ type NewDetailPageInput struct {
 	Viewer       ViewerInterface
 	NotionClient NotionClient
 	Cache        *cache.PageCache
+	Index        *index.Index // optional; receives page titles and content for local search
 	PageID       string
+	Keys         *keymap.KeyMap // optional; the default bindings when nil
+	Theme        *theme.Theme   // optional; the default theme when nil
 }
 
 // NewDetailPage creates a new DetailPage instance.
@@ -63,7 +76,8 @@ func NewDetailPage(input NewDetailPageInput) DetailPage {
 	statusBar.SetWidth(input.Width)
 	statusBar.SetMode(components.ModeBrowse)
 	statusBar.SetSyncStatus(components.StatusSynced)
-	statusBar.SetHelpText("r: refresh | e: edit | esc: back | ?: help")
+	statusBar.SetTheme(theme.OrDefault(input.Theme))
+	statusBar.SetHelpText(detailHelpText(keymap.OrDefault(input.Keys)))
 
 	viewerHeight := input.Height - 1 // Reserve 1 line for status bar
 	if input.Viewer != nil {
@@ -79,9 +93,43 @@ func NewDetailPage(input NewDetailPageInput) DetailPage {
 		height:       input.Height,
 		notionClient: input.NotionClient,
 		cache:        input.Cache,
+		index:        input.Index,
+		keys:         keymap.OrDefault(input.Keys),
 	}
 }
 
+// detailHelpText returns the status bar help of the page view.
+func detailHelpText(keys *keymap.KeyMap) string {
+	return fmt.Sprintf("%s: refresh | %s: edit | %s: back | %s: help",
+		keymap.Label(keys.Detail.Refresh), keymap.Label(keys.Detail.Edit),
+		keymap.Label(keys.Global.Back), keymap.Label(keys.Global.Help))
+}
+
+// SetKeys sets the bindings the page and its viewer respond to and shows
+// them in the status bar. Nil restores the default bindings.
+func (dp *DetailPage) SetKeys(k *keymap.KeyMap) {
+	dp.keys = keymap.OrDefault(k)
+	dp.statusBar.SetHelpText(detailHelpText(dp.keys))
+	if viewer, ok := dp.viewer.(interface{ SetKeys(*keymap.KeyMap) }); ok {
+		viewer.SetKeys(dp.keys)
+	}
+}
+
+// SetTheme restyles the page for a theme. Loaded content is rendered again
+// when the viewer supports themes.
+func (dp *DetailPage) SetTheme(t theme.Theme) tea.Cmd {
+	dp.statusBar.SetTheme(t)
+	themed, ok := dp.viewer.(interface{ SetTheme(theme.Theme) })
+	if !ok {
+		return nil
+	}
+	themed.SetTheme(t)
+	if dp.loading || dp.err != nil {
+		return nil
+	}
+	return dp.viewer.SetBlocks(dp.blocks)
+}
+
 // Init initializes the DetailPage and loads the page content.
 func (dp *DetailPage) Init() tea.Cmd {
 	// Call viewer init immediately if available
@@ -115,18 +163,18 @@ func (dp *DetailPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return dp, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
+		switch {
+		case key.Matches(msg, dp.keys.Detail.Refresh):
 			// Refresh from API (bypass cache)
 			return dp, dp.Refresh()
 
-		case "e":
+		case key.Matches(msg, dp.keys.Detail.Edit):
 			// Navigate to edit mode
 			return dp, func() tea.Msg {
 				return navigationMsg{action: "edit", pageID: dp.pageID}
 			}
 
-		case "esc":
+		case key.Matches(msg, dp.keys.Global.Back):
 			// Navigate back (use exported BackNavigationMsg)
 			return dp, func() tea.Msg {
 				return BackNavigationMsg{}
@@ -166,7 +214,7 @@ func (dp *DetailPage) View() string {
 	}
 
 	if dp.err != nil {
-		errorText := fmt.Sprintf("Error: %v\nPress ESC to go back", dp.err)
+		errorText := fmt.Sprintf("Error: %v\nPress %s to go back", dp.err, strings.ToUpper(keymap.Label(dp.keys.Global.Back)))
 		statusContent := dp.statusBar.View()
 		return lipgloss.JoinVertical(lipgloss.Left, errorText, statusContent)
 	}
@@ -205,7 +253,7 @@ func (dp *DetailPage) Refresh() tea.Cmd {
 // fetchPageCmd loads page data, trying cache first then falling back to API.
 func (dp *DetailPage) fetchPageCmd() tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "detail")
 
 		// Try cache first
 		if dp.cache != nil {
@@ -218,11 +266,18 @@ func (dp *DetailPage) fetchPageCmd() tea.Cmd {
 					if err := json.Unmarshal(cachedBytes, &response); err == nil {
 						blocks = response.Results
 
-						// Also fetch page metadata (not cached)
-						page, err := dp.notionClient.GetPage(ctx, dp.pageID)
-						if err != nil {
-							return pageLoadedMsg{err: fmt.Errorf("fetch page metadata: %w", err)}
+						// Use prefetched page metadata when available
+						page, ok := dp.cachedPage(ctx)
+						if !ok {
+							page, err = dp.notionClient.GetPage(ctx, dp.pageID)
+							if err != nil {
+								return pageLoadedMsg{err: fmt.Errorf("fetch page metadata: %w", err)}
+							}
+							dp.cachePage(ctx, page)
 						}
+						dp.indexPage(page, nil)
+						slog.DebugContext(ctx, "page loaded", logging.KeyPageID, dp.pageID,
+							"source", "cache", "blocks", len(blocks))
 
 						return pageLoadedMsg{
 							page:   page,
@@ -247,7 +302,8 @@ func (dp *DetailPage) fetchPageFromAPICmd() tea.Cmd {
 
 // fetchPageFromAPI performs the actual API calls to fetch page and blocks.
 func (dp *DetailPage) fetchPageFromAPI() tea.Msg {
-	ctx := context.Background()
+	ctx := notion.WithView(context.Background(), "detail")
+	start := time.Now()
 
 	// Fetch page metadata
 	page, err := dp.notionClient.GetPage(ctx, dp.pageID)
@@ -261,24 +317,91 @@ func (dp *DetailPage) fetchPageFromAPI() tea.Msg {
 		return pageLoadedMsg{err: fmt.Errorf("fetch blocks: %w", err)}
 	}
 
-	// Cache the blocks
+	// Cache the blocks; the index follows successful cache writes as an observer.
+	cached := false
 	if dp.cache != nil {
-		if err := dp.cache.Set(ctx, cache.SetInput{
+		err := dp.cache.Set(ctx, cache.SetInput{
 			PageID: dp.pageID,
 			Data:   children,
-			TTL:    time.Hour,
-		}); err != nil {
-			// Log error but don't fail the operation
-			// In production, this would use structured logging
+			TTL:    cache.DefaultPageTTL,
+		})
+		// The page is shown either way; a failed write only costs a refetch
+		if err != nil {
+			slog.WarnContext(ctx, "cache page blocks", logging.KeyPageID, dp.pageID, "error", err)
 		}
+		cached = err == nil
+		dp.cachePage(ctx, page)
 	}
 
+	if cached {
+		dp.indexPage(page, nil)
+	} else {
+		dp.indexPage(page, children.Results)
+	}
+	slog.DebugContext(ctx, "page loaded", logging.KeyPageID, dp.pageID,
+		"source", "api", "blocks", len(children.Results), "took", time.Since(start))
+
 	return pageLoadedMsg{
 		page:   page,
 		blocks: children.Results,
 	}
 }
 
+// cachedPage returns the page metadata from the cache, if fresh.
+func (dp *DetailPage) cachedPage(ctx context.Context) (*notionapi.Page, bool) {
+	cached, err := dp.cache.Get(ctx, cache.MetaKey(dp.pageID))
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return nil, false
+	}
+
+	page, err := notion.DecodePage(data)
+	if err != nil {
+		return nil, false
+	}
+	return page, true
+}
+
+// cachePage stores page metadata so the next visit can skip GetPage.
+func (dp *DetailPage) cachePage(ctx context.Context, page *notionapi.Page) {
+	if dp.cache == nil || page == nil {
+		return
+	}
+	// Metadata caching is an optimization only, so errors are just logged
+	err := dp.cache.Set(ctx, cache.SetInput{
+		PageID: cache.MetaKey(dp.pageID),
+		Data:   page,
+		TTL:    cache.DefaultPageTTL,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "cache page metadata", logging.KeyPageID, dp.pageID, "error", err)
+	}
+}
+
+// indexPage records the page title and parent in the local search index.
+// Content is indexed from blocks when given; otherwise it arrives via the cache.
+func (dp *DetailPage) indexPage(page *notionapi.Page, blocks []notionapi.Block) {
+	if dp.index == nil || page == nil {
+		return
+	}
+
+	dp.index.SetTitle(index.SetTitleInput{
+		ID:       dp.pageID,
+		Title:    extractTitle(page),
+		ParentID: notion.PageParentID(page),
+	})
+
+	if blocks != nil {
+		if content, err := notion.ConvertBlocksToMarkdown(blocks); err == nil {
+			dp.index.SetContent(dp.pageID, content)
+		}
+	}
+}
+
 // PageID returns the current page ID.
 func (dp *DetailPage) PageID() string {
 	return dp.pageID
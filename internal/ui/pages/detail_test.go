@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/index"
 	"github.com/Panandika/notion-tui/internal/testhelpers"
 )
 
@@ -271,6 +272,46 @@ func TestDetailPageCacheHit(t *testing.T) {
 	assert.Equal(t, 1, mockClient.GetPageCallCount())
 }
 
+func TestDetailPageCacheHitWithPrefetchedMeta(t *testing.T) {
+	t.Parallel()
+
+	pageID := "page-warm"
+	testCache := mustCreateCache(t)
+	ctx := context.Background()
+	require.NoError(t, testCache.Set(ctx, cache.SetInput{
+		PageID: pageID,
+		Data:   testhelpers.NewGetChildrenResponse(testhelpers.NewTestBlockList(2)),
+		TTL:    time.Hour,
+	}))
+	require.NoError(t, testCache.Set(ctx, cache.SetInput{
+		PageID: cache.MetaKey(pageID),
+		Data:   testhelpers.NewTestPage(pageID, "Warm Page"),
+		TTL:    time.Hour,
+	}))
+
+	mockClient := testhelpers.NewMockNotionClient()
+	dp := NewDetailPage(NewDetailPageInput{
+		Width:        80,
+		Height:       24,
+		Viewer:       newMockViewer(),
+		NotionClient: mockClient,
+		Cache:        testCache,
+		PageID:       pageID,
+	})
+
+	msg := dp.fetchPageCmd()()
+
+	loadedMsg, ok := msg.(pageLoadedMsg)
+	require.True(t, ok)
+	assert.NoError(t, loadedMsg.err)
+	require.NotNil(t, loadedMsg.page)
+	assert.Equal(t, 2, len(loadedMsg.blocks))
+
+	// Fully warmed pages open without any API call
+	assert.Equal(t, 0, mockClient.GetPageCallCount())
+	assert.Equal(t, 0, mockClient.GetBlocksCallCount())
+}
+
 func TestDetailPageCacheMiss(t *testing.T) {
 	t.Parallel()
 
@@ -639,3 +680,58 @@ func mustCreateCache(t *testing.T) *cache.PageCache {
 	require.NoError(t, err)
 	return c
 }
+
+func TestDetailPageIndexesLoadedPage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		withCache bool
+	}{
+		{name: "content indexed directly without cache", withCache: false},
+		{name: "content indexed through cache writes", withCache: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testPage := testhelpers.NewTestPage("page-indexed", "Indexed Page")
+			testPage.Parent.DatabaseID = "db-1"
+
+			mockClient := testhelpers.NewMockNotionClient()
+			mockClient.PageToReturn = testPage
+			mockClient.BlocksToReturn = testhelpers.NewGetChildrenResponse([]notionapi.Block{
+				testhelpers.NewParagraphBlock("Searchable paragraph about gardening"),
+			})
+
+			searchIndex := index.NewIndex(index.NewIndexInput{})
+			var pageCache *cache.PageCache
+			if tt.withCache {
+				var err error
+				pageCache, err = cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+				require.NoError(t, err)
+				pageCache.AddObserver(searchIndex)
+			}
+
+			dp := NewDetailPage(NewDetailPageInput{
+				Width:        80,
+				Height:       24,
+				Viewer:       newMockViewer(),
+				NotionClient: mockClient,
+				Cache:        pageCache,
+				Index:        searchIndex,
+				PageID:       "page-indexed",
+			})
+
+			msg := dp.fetchPageCmd()()
+			require.NoError(t, msg.(pageLoadedMsg).err)
+
+			hits := searchIndex.Search(index.SearchInput{Query: "gardening", ParentID: "db-1"})
+			require.Len(t, hits, 1)
+			assert.Equal(t, "page-indexed", hits[0].ID)
+			assert.Equal(t, "Indexed Page", hits[0].Title)
+			assert.Equal(t, "content", hits[0].MatchType)
+		})
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jomei/notionapi"
 
+	"github.com/Panandika/notion-tui/internal/notion"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // blockLoadedMsg is sent when a block has been loaded from the API.
@@ -68,6 +72,8 @@ type EditPage struct {
 	retryAttempt     int
 	maxRetries       int
 	pendingBlockType string // For block type transformation requests
+	keys             *keymap.KeyMap
+	theme            theme.Theme
 }
 
 // NewEditPageInput contains parameters for creating a new EditPage.
@@ -77,6 +83,8 @@ type NewEditPageInput struct {
 	NotionClient NotionClient
 	PageID       string
 	BlockID      string
+	Keys         *keymap.KeyMap // optional; the default bindings when nil
+	Theme        *theme.Theme   // optional; the default theme when nil
 }
 
 // NewEditPage creates a new EditPage instance with the given configuration.
@@ -85,6 +93,7 @@ func NewEditPage(input NewEditPageInput) EditPage {
 	statusBar.SetWidth(input.Width)
 	statusBar.SetMode("Loading")
 	statusBar.SetSyncStatus(components.StatusSynced)
+	statusBar.SetTheme(theme.OrDefault(input.Theme))
 
 	return EditPage{
 		pageID:       input.PageID,
@@ -97,9 +106,64 @@ func NewEditPage(input NewEditPageInput) EditPage {
 		maxRetries:   3,
 		showModal:    false,
 		showError:    false,
+		keys:         keymap.OrDefault(input.Keys),
+		theme:        theme.OrDefault(input.Theme),
 	}
 }
 
+// SetKeys sets the bindings the page, its editor and any open modal respond
+// to. Nil restores the default bindings.
+func (ep *EditPage) SetKeys(k *keymap.KeyMap) {
+	ep.keys = keymap.OrDefault(k)
+	ep.editor.SetKeys(ep.keys)
+	if ep.modal != nil {
+		ep.modal.SetKeys(ep.keys)
+	}
+	if !ep.loading && !ep.saving && ep.editor.BlockID() != "" {
+		ep.statusBar.SetHelpText(ep.editHelpText())
+	}
+}
+
+// SetTheme restyles the page, its editor and any open modal or error for a
+// theme.
+func (ep *EditPage) SetTheme(t theme.Theme) tea.Cmd {
+	ep.theme = t
+	ep.statusBar.SetTheme(t)
+	ep.editor.SetTheme(t)
+	if ep.modal != nil {
+		ep.modal.SetStyles(components.NewModalStyles(t))
+	}
+	if ep.errorView != nil {
+		ep.errorView.SetStyles(components.NewErrorViewStyles(t))
+	}
+	return nil
+}
+
+// editHelpText returns the status bar help while editing.
+func (ep *EditPage) editHelpText() string {
+	return fmt.Sprintf("%s: Save | %s: Refresh | %s: Cancel",
+		keymap.Label(ep.keys.Edit.Save), keymap.Label(ep.keys.Edit.Refresh), keymap.Label(ep.keys.Edit.Cancel))
+}
+
+// unsavedChangesModal returns the modal asking what to do with unsaved
+// changes before leaving the editor.
+func (ep *EditPage) unsavedChangesModal() *components.Modal {
+	modal := components.NewModal(components.NewModalInput{
+		Title:   "Unsaved Changes",
+		Message: "You have unsaved changes. What do you want to do?",
+		Actions: []components.ModalAction{
+			{Label: "Save", Key: "s", Value: "save"},
+			{Label: "Discard", Key: "d", Value: "discard"},
+			{Label: "Cancel", Key: "c", Value: "cancel"},
+		},
+		Width:  ep.width,
+		Height: ep.height,
+		Keys:   ep.keys,
+		Theme:  &ep.theme,
+	})
+	return &modal
+}
+
 // Init initializes the EditPage and loads the block content.
 func (ep *EditPage) Init() tea.Cmd {
 	return ep.loadBlockCmd()
@@ -145,8 +209,8 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if ep.showError && ep.errorView != nil {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, ep.keys.Edit.Retry):
 				// Retry save
 				if ep.errorView.IsRetryable() {
 					ep.showError = false
@@ -156,7 +220,7 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ep.statusBar.SetMode("Saving...")
 					return ep, ep.saveCmd()
 				}
-			case "d", "esc":
+			case key.Matches(msg, ep.keys.Edit.Dismiss, ep.keys.Edit.Cancel):
 				// Dismiss error
 				ep.showError = false
 				ep.errorView = nil
@@ -195,6 +259,7 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Width:      ep.width,
 				Height:     ep.height,
 				ShowBorder: true,
+				Theme:      &ep.theme,
 			})
 			return ep, nil
 		}
@@ -212,10 +277,12 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Content:   msg.text,
 			Width:     ep.width - 4,
 			Height:    editorHeight,
+			Keys:      ep.keys,
+			Theme:     &ep.theme,
 		})
 
 		ep.statusBar.SetMode("Editing")
-		ep.statusBar.SetHelpText("Ctrl+S: Save | Ctrl+R: Refresh | Esc: Cancel")
+		ep.statusBar.SetHelpText(ep.editHelpText())
 		return ep, ep.editor.Init()
 
 	case blockRefreshedMsg:
@@ -228,6 +295,7 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Width:      ep.width,
 				Height:     ep.height,
 				ShowBorder: true,
+				Theme:      &ep.theme,
 			})
 			return ep, nil
 		}
@@ -278,6 +346,7 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Width:      ep.width,
 				Height:     ep.height,
 				ShowBorder: true,
+				Theme:      &ep.theme,
 			})
 			ep.statusBar.SetMode("Error")
 			ep.statusBar.SetSyncStatus(components.StatusError)
@@ -331,26 +400,15 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !ep.loading && ep.editor.IsDirty() {
 			// Show confirmation modal
 			ep.showModal = true
-			modal := components.NewModal(components.NewModalInput{
-				Title:   "Unsaved Changes",
-				Message: "You have unsaved changes. What do you want to do?",
-				Actions: []components.ModalAction{
-					{Label: "Save", Key: "s", Value: "save"},
-					{Label: "Discard", Key: "d", Value: "discard"},
-					{Label: "Cancel", Key: "c", Value: "cancel"},
-				},
-				Width:  ep.width,
-				Height: ep.height,
-			})
-			ep.modal = &modal
+			ep.modal = ep.unsavedChangesModal()
 			return ep, nil
 		}
 		return ep, tea.Quit
 
 	case tea.KeyMsg:
 		// Handle keys at page level before delegating to editor
-		switch msg.String() {
-		case "ctrl+s":
+		switch {
+		case key.Matches(msg, ep.keys.Edit.Save):
 			if !ep.loading && !ep.saving && !ep.showModal && !ep.showError {
 				ep.saving = true
 				ep.saved = false
@@ -361,7 +419,7 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return ep, nil
 
-		case "ctrl+r":
+		case key.Matches(msg, ep.keys.Edit.Refresh):
 			// Refresh block from Notion (discard local changes)
 			if !ep.loading && !ep.saving && !ep.showModal && !ep.showError {
 				ep.statusBar.SetMode("Refreshing...")
@@ -369,23 +427,12 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return ep, nil
 
-		case "esc":
+		case key.Matches(msg, ep.keys.Edit.Cancel):
 			// Check dirty state before exiting
 			if !ep.loading && ep.editor.IsDirty() && !ep.showModal && !ep.showError {
 				// Show confirmation modal
 				ep.showModal = true
-				modal := components.NewModal(components.NewModalInput{
-					Title:   "Unsaved Changes",
-					Message: "You have unsaved changes. What do you want to do?",
-					Actions: []components.ModalAction{
-						{Label: "Save", Key: "s", Value: "save"},
-						{Label: "Discard", Key: "d", Value: "discard"},
-						{Label: "Cancel", Key: "c", Value: "cancel"},
-					},
-					Width:  ep.width,
-					Height: ep.height,
-				})
-				ep.modal = &modal
+				ep.modal = ep.unsavedChangesModal()
 				return ep, nil
 			} else if !ep.showModal && !ep.showError {
 				return ep, tea.Quit
@@ -393,21 +440,21 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return ep, nil
 
 		// Block type transformation shortcuts
-		case "ctrl+1":
+		case key.Matches(msg, ep.keys.Edit.Heading1):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeHeading1))
-		case "ctrl+2":
+		case key.Matches(msg, ep.keys.Edit.Heading2):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeHeading2))
-		case "ctrl+3":
+		case key.Matches(msg, ep.keys.Edit.Heading3):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeHeading3))
-		case "ctrl+p":
+		case key.Matches(msg, ep.keys.Edit.Paragraph):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeParagraph))
-		case "ctrl+l":
+		case key.Matches(msg, ep.keys.Edit.BulletedList):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeBulletedListItem))
-		case "ctrl+o":
+		case key.Matches(msg, ep.keys.Edit.NumberedList):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeNumberedListItem))
-		case "ctrl+q":
+		case key.Matches(msg, ep.keys.Edit.Quote):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeQuote))
-		case "ctrl+k":
+		case key.Matches(msg, ep.keys.Edit.Code):
 			return ep, ep.transformBlockType(string(notionapi.BlockTypeCode))
 		}
 	}
@@ -420,10 +467,10 @@ func (ep *EditPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update status bar based on dirty state
 		if ep.editor.IsDirty() && !ep.saving {
 			ep.statusBar.SetMode("Modified *")
-			ep.statusBar.SetHelpText("Ctrl+S: Save | Ctrl+R: Refresh | Esc: Cancel")
+			ep.statusBar.SetHelpText(ep.editHelpText())
 		} else if !ep.saving && !ep.saved {
 			ep.statusBar.SetMode("Editing")
-			ep.statusBar.SetHelpText("Ctrl+S: Save | Ctrl+R: Refresh | Esc: Cancel")
+			ep.statusBar.SetHelpText(ep.editHelpText())
 		}
 
 		return ep, cmd
@@ -502,7 +549,7 @@ func (ep *EditPage) Save() tea.Cmd {
 // loadBlockCmd returns a command that loads a block from the API.
 func (ep *EditPage) loadBlockCmd() tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "edit")
 		block, err := ep.notionClient.GetBlock(ctx, ep.blockID)
 		if err != nil {
 			return blockLoadedMsg{err: fmt.Errorf("load block: %w", err)}
@@ -523,7 +570,7 @@ func (ep *EditPage) loadBlockCmd() tea.Cmd {
 // refreshBlockCmd returns a command that refreshes a block from the API.
 func (ep *EditPage) refreshBlockCmd() tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "edit")
 		block, err := ep.notionClient.GetBlock(ctx, ep.blockID)
 		if err != nil {
 			return blockRefreshedMsg{err: fmt.Errorf("refresh block: %w", err)}
@@ -545,7 +592,7 @@ func (ep *EditPage) refreshBlockCmd() tea.Cmd {
 func (ep *EditPage) saveCmd() tea.Cmd {
 	retryAttempt := ep.retryAttempt
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "edit")
 
 		newText := ep.editor.GetText()
 
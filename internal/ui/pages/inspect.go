@@ -0,0 +1,82 @@
+package pages
+
+import (
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+)
+
+// Inspectable is implemented by pages whose Notion objects the inspector
+// can show.
+type Inspectable interface {
+	// InspectItems returns the objects on the page as Notion returned them
+	// and the index of the selected one.
+	InspectItems() ([]components.InspectorItem, int)
+}
+
+// InspectItems returns the loaded rows of the database, starting at the
+// row selected in the sidebar.
+func (lp *ListPage) InspectItems() ([]components.InspectorItem, int) {
+	selectedID := lp.sidebar.SelectedID()
+	selected := 0
+	items := make([]components.InspectorItem, 0, len(lp.pageList))
+	for i, page := range lp.pageList {
+		if page.ID == selectedID {
+			selected = i
+		}
+		var raw any = page
+		if row, ok := lp.rows[page.ID]; ok {
+			raw = row
+		}
+		items = append(items, components.InspectorItem{
+			Kind:      "database row",
+			ID:        page.ID,
+			Label:     page.Title,
+			Raw:       raw,
+			CacheKeys: pageCacheKeys(page.ID),
+		})
+	}
+	return items, selected
+}
+
+// InspectItems returns the page followed by its top-level blocks, starting
+// at the page.
+func (dp *DetailPage) InspectItems() ([]components.InspectorItem, int) {
+	if dp.page == nil {
+		return nil, 0
+	}
+
+	items := make([]components.InspectorItem, 0, len(dp.blocks)+1)
+	items = append(items, components.InspectorItem{
+		Kind:      "page",
+		ID:        dp.pageID,
+		Label:     extractTitle(dp.page),
+		Raw:       dp.page,
+		CacheKeys: pageCacheKeys(dp.pageID),
+	})
+	for _, block := range dp.blocks {
+		// The viewer shows what conversion gives, errors included
+		markdown, err := notion.ConvertBlocksToMarkdown([]notionapi.Block{block})
+		if err != nil {
+			markdown = "convert block: " + err.Error()
+		}
+		items = append(items, components.InspectorItem{
+			Kind:     "block",
+			ID:       string(block.GetID()),
+			Label:    string(block.GetType()),
+			Raw:      block,
+			Markdown: markdown,
+			// Blocks are cached with the rest of the page's block list
+			CacheKeys: []string{dp.pageID},
+		})
+	}
+	return items, 0
+}
+
+// pageCacheKeys returns the cache entries of a page: its metadata and its
+// block list.
+func pageCacheKeys(pageID string) []string {
+	return []string{cache.MetaKey(pageID), pageID}
+}
@@ -0,0 +1,69 @@
+package pages
+
+import (
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+func TestListPage_InspectItems(t *testing.T) {
+	t.Parallel()
+
+	lp := NewListPage(NewListPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: &MockNotionClient{},
+		DatabaseID:   "test-db",
+	})
+	lp.Update(pagesLoadedMsg{
+		pages: []Page{
+			newTestPage("page-1", "Page 1", "Draft"),
+			newTestPage("page-2", "Page 2", "Published"),
+		},
+		rows: []notionapi.Page{{Object: notionapi.ObjectTypePage, ID: "page-1"}},
+	})
+
+	items, selected := lp.InspectItems()
+	require.Len(t, items, 2)
+	assert.Equal(t, 0, selected)
+	assert.Equal(t, "database row", items[0].Kind)
+	assert.Equal(t, "Page 1", items[0].Label)
+	assert.IsType(t, notionapi.Page{}, items[0].Raw, "rows keep the object Notion returned")
+	assert.IsType(t, Page{}, items[1].Raw, "rows without the raw object show the list entry")
+	assert.Equal(t, []string{cache.MetaKey("page-2"), "page-2"}, items[1].CacheKeys)
+}
+
+func TestDetailPage_InspectItems(t *testing.T) {
+	t.Parallel()
+
+	dp := NewDetailPage(NewDetailPageInput{
+		Width:        80,
+		Height:       24,
+		Viewer:       newMockViewer(),
+		NotionClient: testhelpers.NewMockNotionClient(),
+		PageID:       "page-1",
+	})
+
+	items, _ := dp.InspectItems()
+	assert.Empty(t, items, "nothing to inspect before the page loads")
+
+	page := testhelpers.NewTestPage("page-1", "Roadmap")
+	dp.Update(pageLoadedMsg{page: page, blocks: testhelpers.NewTestBlockList(2)})
+
+	items, selected := dp.InspectItems()
+	require.Len(t, items, 3)
+	assert.Equal(t, 0, selected)
+	assert.Equal(t, "page", items[0].Kind)
+	assert.Equal(t, "Roadmap", items[0].Label)
+	assert.Same(t, page, items[0].Raw)
+	for _, item := range items[1:] {
+		assert.Equal(t, "block", item.Kind)
+		assert.NotEmpty(t, item.Markdown)
+		assert.Equal(t, []string{"page-1"}, item.CacheKeys)
+	}
+}
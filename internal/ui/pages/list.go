@@ -3,22 +3,33 @@ package pages
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jomei/notionapi"
 
 	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/prefetch"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // Page represents a Notion page in the UI.
 type Page struct {
-	ID        string
-	Title     string
-	Status    string
-	UpdatedAt time.Time
+	ID         string
+	Title      string
+	Status     string
+	UpdatedAt  time.Time
+	Date       string         // the database's date property, if configured
+	Properties []PageProperty // the database's visible properties that are set
 }
 
 // NewPage creates a new Page instance.
@@ -51,6 +62,8 @@ type pagesLoadedMsg struct {
 	pages      []Page
 	hasMore    bool
 	nextCursor string
+	query      *notionapi.DatabaseQueryRequest // the filter and sorts used, for loading more
+	rows       []notionapi.Page                // as Notion returned them, for the inspector
 	err        error
 }
 
@@ -70,7 +83,15 @@ type ListPage struct {
 	height       int
 	notionClient NotionClient
 	cache        *cache.PageCache
+	index        *index.Index
+	prefetcher   *prefetch.Prefetcher
 	databaseID   string
+	settings     config.DatabaseConfig
+	view         string // one of config.Views
+	query        *notionapi.DatabaseQueryRequest
+	rows         map[string]notionapi.Page // loaded rows by ID, for the inspector
+	keys         *keymap.KeyMap
+	theme        theme.Theme
 }
 
 // NewListPageInput contains the parameters for creating a new ListPage.
@@ -79,7 +100,12 @@ type NewListPageInput struct {
 	Height       int
 	NotionClient NotionClient
 	Cache        *cache.PageCache
+	Index        *index.Index         // optional; receives page titles for local search
+	Prefetcher   *prefetch.Prefetcher // optional; warms the cache for rows on screen
 	DatabaseID   string
+	Database     config.DatabaseConfig // optional; the database's properties, filter and sorts
+	Keys         *keymap.KeyMap        // optional; the default bindings when nil
+	Theme        *theme.Theme          // optional; the default theme when nil
 }
 
 // NewListPage creates a new ListPage instance.
@@ -96,11 +122,10 @@ func NewListPage(input NewListPageInput) ListPage {
 	statusBar.SetWidth(input.Width)
 	statusBar.SetMode(components.ModeBrowse)
 	statusBar.SetSyncStatus(components.StatusSynced)
-	statusBar.SetHelpText("/: search | r: refresh | ?: help")
 
 	spinner := components.NewSpinner("Loading pages...")
 
-	return ListPage{
+	lp := ListPage{
 		sidebar:      sidebar,
 		statusBar:    statusBar,
 		spinner:      spinner,
@@ -115,8 +140,40 @@ func NewListPage(input NewListPageInput) ListPage {
 		height:       input.Height,
 		notionClient: input.NotionClient,
 		cache:        input.Cache,
+		index:        input.Index,
+		prefetcher:   input.Prefetcher,
 		databaseID:   input.DatabaseID,
+		settings:     input.Database,
+		view:         input.Database.DefaultView(),
 	}
+	lp.SetKeys(input.Keys)
+	lp.SetTheme(theme.OrDefault(input.Theme))
+	return lp
+}
+
+// SetKeys sets the bindings the page and its sidebar respond to and shows
+// them in the status bar, unless it shows an error. Nil restores the
+// default bindings.
+func (lp *ListPage) SetKeys(k *keymap.KeyMap) {
+	lp.keys = keymap.OrDefault(k)
+	lp.sidebar.SetKeys(lp.keys)
+	switch {
+	case lp.err != nil:
+	case lp.loading:
+		lp.statusBar.SetHelpText(fmt.Sprintf("/: search | %s: refresh | %s: help",
+			keymap.Label(lp.keys.List.Refresh), keymap.Label(lp.keys.Global.Help)))
+	default:
+		lp.statusBar.SetHelpText(lp.pagesHelpText())
+	}
+}
+
+// SetTheme restyles the page for a theme.
+func (lp *ListPage) SetTheme(t theme.Theme) tea.Cmd {
+	lp.theme = t
+	lp.sidebar.SetTheme(t)
+	lp.statusBar.SetTheme(t)
+	lp.spinner.SetTheme(t)
+	return nil
 }
 
 // Init fetches pages from the database on initialization.
@@ -148,18 +205,25 @@ func (lp *ListPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			lp.pageList = append(lp.pageList, msg.pages...)
 		} else {
 			lp.pageList = msg.pages
+			lp.rows = nil
+		}
+		if lp.rows == nil {
+			lp.rows = make(map[string]notionapi.Page, len(msg.rows))
+		}
+		for _, row := range msg.rows {
+			lp.rows[string(row.ID)] = row
 		}
 
 		lp.hasMore = msg.hasMore
 		lp.nextCursor = msg.nextCursor
+		if !wasLoadingMore {
+			lp.query = msg.query
+		}
 		lp.updateSidebarItems()
+		lp.prefetchOnScreen()
 		lp.statusBar.SetSyncStatus(components.StatusSynced)
 
-		helpText := fmt.Sprintf("%d pages | r: refresh", len(lp.pageList))
-		if lp.hasMore {
-			helpText += " | m: load more"
-		}
-		lp.statusBar.SetHelpText(helpText)
+		lp.statusBar.SetHelpText(lp.pagesHelpText())
 		return lp, nil
 
 	case components.ItemSelectedMsg:
@@ -170,8 +234,8 @@ func (lp *ListPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
+		switch {
+		case key.Matches(msg, lp.keys.List.Refresh):
 			// Refresh page list
 			lp.loading = true
 			lp.hasMore = false
@@ -180,7 +244,7 @@ func (lp *ListPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			lp.statusBar.SetHelpText("Refreshing...")
 			return lp, lp.fetchPagesCmd()
 
-		case "m":
+		case key.Matches(msg, lp.keys.List.LoadMore):
 			// Load more pages if available
 			if lp.hasMore && !lp.loadingMore {
 				lp.loadingMore = true
@@ -189,6 +253,11 @@ func (lp *ListPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				lp.statusBar.SetHelpText("Loading more pages...")
 				return lp, lp.loadMoreCmd()
 			}
+
+		case key.Matches(msg, lp.keys.List.View):
+			lp.view = nextView(lp.view)
+			lp.statusBar.SetHelpText(lp.pagesHelpText())
+			return lp, nil
 		}
 
 	case tea.WindowSizeMsg:
@@ -212,6 +281,11 @@ func (lp *ListPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	lp.sidebar, sidebarCmd = lp.sidebar.Update(msg)
 	cmds = append(cmds, sidebarCmd)
 
+	// Scrolling or filtering may have changed which rows are on screen
+	if _, ok := msg.(tea.KeyMsg); ok {
+		lp.prefetchOnScreen()
+	}
+
 	// Update status bar based on sidebar search state
 	lp.updateStatusBarFromSidebar()
 
@@ -242,7 +316,7 @@ func (lp *ListPage) View() string {
 			Width(lp.width).
 			Height(lp.height-2).
 			Align(lipgloss.Center, lipgloss.Center).
-			Foreground(lipgloss.Color("#EF4444"))
+			Foreground(lp.theme.Error)
 
 		main := errorStyle.Render(fmt.Sprintf("Error loading pages:\n%v", lp.err))
 		status := lp.statusBar.View()
@@ -250,14 +324,24 @@ func (lp *ListPage) View() string {
 		return lipgloss.JoinVertical(lipgloss.Left, main, status)
 	}
 
-	// Main content placeholder (sidebar is handled by root)
-	mainStyle := lipgloss.NewStyle().
-		Width(lp.width).
-		Height(lp.height-2).
-		Align(lipgloss.Center, lipgloss.Center).
-		Foreground(lipgloss.Color("#6B7280"))
-
-	main := mainStyle.Render("Select a page from the sidebar")
+	var main string
+	switch lp.view {
+	case config.ViewTable:
+		main = lp.renderTable(lp.width)
+	case config.ViewBoard:
+		main = lp.renderBoard(lp.width, lp.height-2)
+	case config.ViewCalendar:
+		main = lp.renderCalendar(lp.height - 2)
+	default:
+		// The list itself is the sidebar, drawn by root
+		main = lipgloss.NewStyle().
+			Width(lp.width).
+			Height(lp.height-2).
+			Align(lipgloss.Center, lipgloss.Center).
+			Foreground(lp.theme.Muted).
+			Render("Select a page from the sidebar")
+	}
+	main = lipgloss.NewStyle().Height(lp.height - 2).MaxHeight(lp.height - 2).MaxWidth(lp.width).Render(main)
 	status := lp.statusBar.View()
 
 	return lipgloss.JoinVertical(lipgloss.Left, main, status)
@@ -267,7 +351,7 @@ func (lp *ListPage) View() string {
 func (lp *ListPage) SetPages(pages []Page) {
 	lp.pageList = pages
 	lp.updateSidebarItems()
-	lp.statusBar.SetHelpText(fmt.Sprintf("%d pages | r: refresh", len(lp.pageList)))
+	lp.statusBar.SetHelpText(lp.pagesHelpText())
 }
 
 // SelectedPage returns the currently selected page or nil if none selected.
@@ -286,7 +370,7 @@ func (lp *ListPage) Refresh() tea.Cmd {
 // fetchPagesCmd returns a command that fetches pages from the database.
 func (lp *ListPage) fetchPagesCmd() tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "list")
 
 		if lp.notionClient == nil {
 			return pagesLoadedMsg{
@@ -294,7 +378,14 @@ func (lp *ListPage) fetchPagesCmd() tea.Cmd {
 			}
 		}
 
-		resp, err := lp.notionClient.QueryDatabase(ctx, lp.databaseID, nil)
+		query, err := databaseQuery(ctx, lp.notionClient, lp.databaseID, lp.settings, time.Now())
+		if err != nil {
+			return pagesLoadedMsg{
+				err: fmt.Errorf("database settings: %w", err),
+			}
+		}
+
+		resp, err := lp.notionClient.QueryDatabase(ctx, lp.databaseID, query)
 		if err != nil {
 			return pagesLoadedMsg{
 				err: fmt.Errorf("fetch pages: %w", err),
@@ -303,30 +394,27 @@ func (lp *ListPage) fetchPagesCmd() tea.Cmd {
 
 		pages := make([]Page, 0, len(resp.Results))
 		for _, p := range resp.Results {
-			title := extractTitle(&p)
-			status := extractStatus(&p)
-
-			page := NewPage(
-				string(p.ID),
-				title,
-				status,
-				p.LastEditedTime,
-			)
-			pages = append(pages, page)
+			pages = append(pages, pageFromNotion(&p, lp.settings))
 		}
+		lp.indexTitles(pages)
+		slog.DebugContext(ctx, "database pages loaded", "database_id", lp.databaseID,
+			"pages", len(pages), "has_more", resp.HasMore)
 
 		return pagesLoadedMsg{
 			pages:      pages,
 			hasMore:    resp.HasMore,
 			nextCursor: string(resp.NextCursor),
+			query:      query,
+			rows:       resp.Results,
 		}
 	}
 }
 
 // loadMoreCmd returns a command that fetches the next page of results.
 func (lp *ListPage) loadMoreCmd() tea.Cmd {
+	query := lp.query
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "list")
 
 		if lp.notionClient == nil {
 			return pagesLoadedMsg{
@@ -340,10 +428,8 @@ func (lp *ListPage) loadMoreCmd() tea.Cmd {
 			}
 		}
 
-		// Create pagination request with the next cursor
-		req := &notionapi.DatabaseQueryRequest{
-			StartCursor: notionapi.Cursor(lp.nextCursor),
-		}
+		// Continue the same filtered and sorted query from the next cursor
+		req := nextPageQuery(query, lp.nextCursor)
 
 		resp, err := lp.notionClient.QueryDatabase(ctx, lp.databaseID, req)
 		if err != nil {
@@ -354,34 +440,49 @@ func (lp *ListPage) loadMoreCmd() tea.Cmd {
 
 		pages := make([]Page, 0, len(resp.Results))
 		for _, p := range resp.Results {
-			title := extractTitle(&p)
-			status := extractStatus(&p)
-
-			page := NewPage(
-				string(p.ID),
-				title,
-				status,
-				p.LastEditedTime,
-			)
-			pages = append(pages, page)
+			pages = append(pages, pageFromNotion(&p, lp.settings))
 		}
+		lp.indexTitles(pages)
+		slog.DebugContext(ctx, "more database pages loaded", "database_id", lp.databaseID,
+			"pages", len(pages), "has_more", resp.HasMore)
 
 		return pagesLoadedMsg{
 			pages:      pages,
 			hasMore:    resp.HasMore,
 			nextCursor: string(resp.NextCursor),
+			query:      query,
+			rows:       resp.Results,
 		}
 	}
 }
 
+// prefetchOnScreen asks the prefetcher to warm the rows currently on screen.
+func (lp *ListPage) prefetchOnScreen() {
+	if lp.prefetcher == nil {
+		return
+	}
+	lp.prefetcher.QueueVisible(lp.sidebar.OnScreenIDs())
+}
+
+// indexTitles records page titles in the local search index.
+func (lp *ListPage) indexTitles(pages []Page) {
+	if lp.index == nil {
+		return
+	}
+	for _, page := range pages {
+		lp.index.SetTitle(index.SetTitleInput{
+			ID:       page.ID,
+			Title:    page.Title,
+			ParentID: lp.databaseID,
+		})
+	}
+}
+
 // updateSidebarItems converts the page list to sidebar items.
 func (lp *ListPage) updateSidebarItems() {
 	items := make([]components.Item, 0, len(lp.pageList))
 	for _, page := range lp.pageList {
-		desc := fmt.Sprintf("Updated: %s", formatTime(page.UpdatedAt))
-		if page.Status != "" {
-			desc = fmt.Sprintf("%s | %s", page.Status, desc)
-		}
+		desc := pageDescription(page)
 
 		item := components.NewItem(page.Title, desc, page.ID)
 		items = append(items, item)
@@ -406,25 +507,27 @@ func extractTitle(page *notionapi.Page) string {
 	return "Untitled"
 }
 
-// extractStatus extracts the status from a Notion page if available.
+// extractStatus extracts the status from a Notion page's default status
+// property if available.
 func extractStatus(page *notionapi.Page) string {
-	if page == nil {
-		return ""
-	}
+	return extractStatusFrom(page, "")
+}
 
-	// Try to find status property
-	for name, prop := range page.Properties {
-		if name == "Status" || name == "status" {
-			if statusProp, ok := prop.(*notionapi.StatusProperty); ok && statusProp.Status.Name != "" {
-				return statusProp.Status.Name
-			}
-			if selectProp, ok := prop.(*notionapi.SelectProperty); ok && selectProp.Select.Name != "" {
-				return selectProp.Select.Name
-			}
-		}
+// pageDescription is the line shown under a page's title: its status, date
+// and visible properties, then when it was last edited.
+func pageDescription(page Page) string {
+	var parts []string
+	if page.Status != "" {
+		parts = append(parts, page.Status)
 	}
-
-	return ""
+	if page.Date != "" {
+		parts = append(parts, page.Date)
+	}
+	for _, prop := range page.Properties {
+		parts = append(parts, fmt.Sprintf("%s: %s", prop.Name, prop.Value))
+	}
+	parts = append(parts, fmt.Sprintf("Updated: %s", formatTime(page.UpdatedAt)))
+	return strings.Join(parts, " | ")
 }
 
 // formatTime formats a time as a relative string.
@@ -489,10 +592,16 @@ func (lp *ListPage) updateStatusBarFromSidebar() {
 		lp.statusBar.SetHelpText(helpText)
 	} else if !lp.loading && !lp.loadingMore {
 		// Restore normal help text
-		helpText := fmt.Sprintf("%d pages | /: search | r: refresh", len(lp.pageList))
-		if lp.hasMore {
-			helpText += " | m: load more"
-		}
-		lp.statusBar.SetHelpText(helpText)
+		lp.statusBar.SetHelpText(lp.pagesHelpText())
+	}
+}
+
+// pagesHelpText returns the status bar help for a loaded page list.
+func (lp *ListPage) pagesHelpText() string {
+	helpText := fmt.Sprintf("%d pages | /: search | %s: refresh", len(lp.pageList), keymap.Label(lp.keys.List.Refresh))
+	if lp.hasMore {
+		helpText += fmt.Sprintf(" | %s: load more", keymap.Label(lp.keys.List.LoadMore))
 	}
+	helpText += fmt.Sprintf(" | %s: view (%s)", keymap.Label(lp.keys.List.View), lp.view)
+	return helpText
 }
@@ -3,20 +3,26 @@ package pages
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jomei/notionapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
 	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/prefetch"
 	"github.com/Panandika/notion-tui/internal/ui/components"
 )
 
 // MockNotionClient is a mock implementation of the NotionClient interface.
 type MockNotionClient struct {
+	GetDatabaseFunc   func(ctx context.Context, id string) (*notionapi.Database, error)
 	QueryDatabaseFunc func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
 	GetPageFunc       func(ctx context.Context, id string) (*notionapi.Page, error)
 	GetBlocksFunc     func(ctx context.Context, id string, pagination *notionapi.Pagination) (*notionapi.GetChildrenResponse, error)
@@ -28,6 +34,13 @@ type MockNotionClient struct {
 	SearchFunc        func(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error)
 }
 
+func (m *MockNotionClient) GetDatabase(ctx context.Context, id string) (*notionapi.Database, error) {
+	if m.GetDatabaseFunc != nil {
+		return m.GetDatabaseFunc(ctx, id)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func (m *MockNotionClient) QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
 	if m.QueryDatabaseFunc != nil {
 		return m.QueryDatabaseFunc(ctx, id, req)
@@ -415,6 +428,100 @@ func TestListPage_View(t *testing.T) {
 	}
 }
 
+func TestListPage_DatabaseViews(t *testing.T) {
+	t.Parallel()
+
+	listPages := []Page{
+		{ID: "page-1", Title: "Plan launch", Status: "Todo", Date: "2024-03-20", UpdatedAt: time.Now()},
+		{ID: "page-2", Title: "Write docs", Status: "Done", Date: "2024-03-15T09:00:00Z", UpdatedAt: time.Now(),
+			Properties: []PageProperty{{Name: "Owner", Value: "Ada"}}},
+		{ID: "page-3", Title: "Someday", UpdatedAt: time.Now()},
+	}
+	settings := config.DatabaseConfig{DateProperty: "Due", Properties: []string{"Owner"}}
+
+	tests := []struct {
+		view string
+		want []string
+	}{
+		{view: config.ViewList, want: []string{"Select a page from the sidebar"}},
+		{view: config.ViewTable, want: []string{"Title", "Status", "Due", "Owner", "Updated", "Plan launch", "2024-03-20", "Ada"}},
+		{view: config.ViewBoard, want: []string{"Todo (1)", "Done (1)", "No status (1)", "Someday"}},
+		{view: config.ViewCalendar, want: []string{"Fri Mar 15, 2024", "Wed Mar 20, 2024", "No date"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.view, func(t *testing.T) {
+			t.Parallel()
+
+			settings := settings
+			settings.View = tt.view
+			lp := NewListPage(NewListPageInput{
+				Width:        120,
+				Height:       30,
+				NotionClient: &MockNotionClient{},
+				DatabaseID:   "test-db",
+				Database:     settings,
+			})
+			lp.loading = false
+			lp.SetPages(listPages)
+
+			view := lp.View()
+			for _, want := range tt.want {
+				assert.Contains(t, view, want)
+			}
+		})
+	}
+}
+
+func TestListPage_CalendarOrdersDays(t *testing.T) {
+	t.Parallel()
+
+	lp := NewListPage(NewListPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: &MockNotionClient{},
+		DatabaseID:   "test-db",
+		Database:     config.DatabaseConfig{DateProperty: "Due", View: config.ViewCalendar},
+	})
+	lp.loading = false
+	lp.SetPages([]Page{
+		{ID: "page-1", Title: "Undated"},
+		{ID: "page-2", Title: "Later", Date: "2024-04-01"},
+		{ID: "page-3", Title: "Sooner", Date: "2024-03-01/2024-03-05"},
+	})
+
+	view := lp.View()
+	sooner := strings.Index(view, "Sooner")
+	later := strings.Index(view, "Later")
+	undated := strings.Index(view, "Undated")
+	require.True(t, sooner >= 0 && later >= 0 && undated >= 0, view)
+	assert.Less(t, sooner, later)
+	assert.Less(t, later, undated)
+}
+
+func TestListPage_ViewKeyCyclesViews(t *testing.T) {
+	t.Parallel()
+
+	lp := NewListPage(NewListPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: &MockNotionClient{},
+		DatabaseID:   "test-db",
+		Database:     config.DatabaseConfig{View: "Board"},
+	})
+	lp.loading = false
+	assert.Equal(t, config.ViewBoard, lp.view, "the configured view is the default")
+
+	var seen []string
+	for range config.Views {
+		model, _ := lp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+		lp = *model.(*ListPage)
+		seen = append(seen, lp.view)
+	}
+	assert.Equal(t, []string{config.ViewCalendar, config.ViewList, config.ViewTable, config.ViewBoard}, seen)
+	assert.Contains(t, lp.statusBar.View(), "view (board)")
+}
+
 func TestListPage_SetPages(t *testing.T) {
 	t.Parallel()
 
@@ -541,6 +648,66 @@ func TestListPage_FetchPagesCmd_Error(t *testing.T) {
 	}
 }
 
+func TestListPage_FetchPagesCmd_IndexesTitles(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockNotionClient{
+		QueryDatabaseFunc: func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+			return &notionapi.DatabaseQueryResponse{
+				Results: []notionapi.Page{
+					newTestNotionPage("page-1", "Quarterly Roadmap", "Draft"),
+					newTestNotionPage("page-2", "Team Offsite", "Published"),
+				},
+			}, nil
+		},
+	}
+
+	searchIndex := index.NewIndex(index.NewIndexInput{})
+	lp := NewListPage(NewListPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: mockClient,
+		Index:        searchIndex,
+		DatabaseID:   "test-db",
+	})
+
+	msg := lp.fetchPagesCmd()()
+	require.NoError(t, msg.(pagesLoadedMsg).err)
+
+	assert.Equal(t, 2, searchIndex.Len())
+	hits := searchIndex.Search(index.SearchInput{Query: "roadmap", ParentID: "test-db"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "page-1", hits[0].ID)
+	assert.Equal(t, "title", hits[0].MatchType)
+}
+
+func TestListPage_PagesLoaded_QueuesOnScreenPrefetch(t *testing.T) {
+	t.Parallel()
+
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+	prefetcher, err := prefetch.NewPrefetcher(prefetch.NewPrefetcherInput{
+		Fetcher: &MockNotionClient{},
+		Cache:   pc,
+	})
+	require.NoError(t, err)
+
+	lp := NewListPage(NewListPageInput{
+		Width:        80,
+		Height:       24,
+		NotionClient: &MockNotionClient{},
+		Prefetcher:   prefetcher,
+		DatabaseID:   "test-db",
+	})
+
+	lp.Update(pagesLoadedMsg{pages: []Page{
+		NewPage("page-1", "First", "Draft", time.Now()),
+		NewPage("page-2", "Second", "Draft", time.Now()),
+	}})
+
+	assert.Equal(t, 2, prefetcher.Stats().Queued)
+}
+
 func TestExtractTitle(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,198 @@
+package pages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/Panandika/notion-tui/internal/config"
+)
+
+// nextView returns the view after current in config.Views, wrapping around.
+func nextView(current string) string {
+	i := slices.Index(config.Views, current)
+	return config.Views[(i+1)%len(config.Views)]
+}
+
+// renderTable lays the pages out in rows with a column for the status, the
+// date, each visible property and the last edit. The status column is left
+// out when no page has one, the date column when no date property is set.
+func (lp *ListPage) renderTable(width int) string {
+	headers := []string{"Title"}
+	withStatus := slices.ContainsFunc(lp.pageList, func(p Page) bool { return p.Status != "" })
+	if withStatus {
+		headers = append(headers, "Status")
+	}
+	if lp.settings.DateProperty != "" {
+		headers = append(headers, lp.settings.DateProperty)
+	}
+	headers = append(headers, lp.settings.Properties...)
+	headers = append(headers, "Updated")
+
+	selected := lp.sidebar.SelectedID()
+	selectedRow := -1
+	rows := make([][]string, 0, len(lp.pageList))
+	for i, page := range lp.pageList {
+		row := []string{page.Title}
+		if withStatus {
+			row = append(row, page.Status)
+		}
+		if lp.settings.DateProperty != "" {
+			row = append(row, page.Date)
+		}
+		for _, name := range lp.settings.Properties {
+			row = append(row, propertyValue(page, name))
+		}
+		row = append(row, formatTime(page.UpdatedAt))
+		rows = append(rows, row)
+		if page.ID == selected {
+			selectedRow = i
+		}
+	}
+
+	cell := lipgloss.NewStyle().Padding(0, 1)
+	t := table.New().
+		Headers(headers...).
+		Rows(rows...).
+		Width(width).
+		BorderStyle(lipgloss.NewStyle().Foreground(lp.theme.Border)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return cell.Bold(true).Foreground(lp.theme.Primary)
+			case row == selectedRow:
+				return cell.Foreground(lp.theme.Accent)
+			default:
+				return cell
+			}
+		})
+	return t.Render()
+}
+
+// renderBoard groups the pages into a column per status, in the order the
+// statuses first appear. Pages without a status share the last column.
+func (lp *ListPage) renderBoard(width, height int) string {
+	var statuses []string
+	groups := make(map[string][]Page)
+	for _, page := range lp.pageList {
+		if _, ok := groups[page.Status]; !ok && page.Status != "" {
+			statuses = append(statuses, page.Status)
+		}
+		groups[page.Status] = append(groups[page.Status], page)
+	}
+	if len(groups[""]) > 0 {
+		statuses = append(statuses, "")
+	}
+	if len(statuses) == 0 {
+		return lipgloss.NewStyle().Foreground(lp.theme.Muted).Render("No pages")
+	}
+
+	colWidth := max(width/len(statuses)-1, 8)
+	selected := lp.sidebar.SelectedID()
+	columns := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		heading := status
+		if heading == "" {
+			heading = "No status"
+		}
+		lines := []string{
+			lipgloss.NewStyle().Bold(true).Foreground(lp.theme.Primary).Render(fmt.Sprintf("%s (%d)", heading, len(groups[status]))),
+		}
+		for _, page := range groups[status] {
+			lines = append(lines, lp.cardLine(page, selected))
+		}
+		columns = append(columns, lipgloss.NewStyle().
+			Width(colWidth).
+			MaxHeight(height).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderRight(true).
+			BorderForeground(lp.theme.Border).
+			Render(strings.Join(lines, "\n")))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+// renderCalendar groups the pages by day, earliest first, using the date
+// property or, when the database has none, the day each page was last
+// edited. Pages without a date are listed last.
+func (lp *ListPage) renderCalendar(height int) string {
+	var days []string
+	groups := make(map[string][]Page)
+	for _, page := range lp.pageList {
+		day := pageDay(page, lp.settings.DateProperty != "")
+		if _, ok := groups[day]; !ok {
+			days = append(days, day)
+		}
+		groups[day] = append(groups[day], page)
+	}
+	slices.SortFunc(days, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "":
+			return 1
+		case b == "":
+			return -1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+
+	selected := lp.sidebar.SelectedID()
+	heading := lipgloss.NewStyle().Bold(true).Foreground(lp.theme.Primary)
+	var lines []string
+	for _, day := range days {
+		label := "No date"
+		if t, err := time.Parse(time.DateOnly, day); err == nil {
+			label = t.Format("Mon Jan 2, 2006")
+		}
+		lines = append(lines, heading.Render(label))
+		for _, page := range groups[day] {
+			lines = append(lines, lp.cardLine(page, selected))
+		}
+	}
+	if len(lines) == 0 {
+		return lipgloss.NewStyle().Foreground(lp.theme.Muted).Render("No pages")
+	}
+	return lipgloss.NewStyle().MaxHeight(height).Render(strings.Join(lines, "\n"))
+}
+
+// cardLine renders a page title on a board or calendar, marking the page
+// selected in the sidebar.
+func (lp *ListPage) cardLine(page Page, selected string) string {
+	if page.ID == selected {
+		return lipgloss.NewStyle().Foreground(lp.theme.Accent).Render("> " + page.Title)
+	}
+	return "  " + page.Title
+}
+
+// pageDay returns the day a page falls on in the calendar as YYYY-MM-DD, or
+// "" when it has no date.
+func pageDay(page Page, useDate bool) string {
+	if !useDate {
+		if page.UpdatedAt.IsZero() {
+			return ""
+		}
+		return page.UpdatedAt.Local().Format(time.DateOnly)
+	}
+	// Dates read as YYYY-MM-DD, an RFC 3339 time or a start/end range
+	if len(page.Date) < len(time.DateOnly) {
+		return ""
+	}
+	return page.Date[:len(time.DateOnly)]
+}
+
+// propertyValue returns the value of a visible property, or "" when the page
+// leaves it unset.
+func propertyValue(page Page, name string) string {
+	for _, prop := range page.Properties {
+		if prop.Name == name {
+			return prop.Value
+		}
+	}
+	return ""
+}
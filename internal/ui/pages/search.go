@@ -3,16 +3,24 @@ package pages
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/logging"
 	"github.com/Panandika/notion-tui/internal/notion"
 	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // SearchMode defines the search scope.
@@ -104,11 +112,14 @@ type SearchPage struct {
 	height       int
 	notionClient NotionClient
 	cache        *cache.PageCache
+	index        *index.Index
 	databaseID   string
+	settings     config.DatabaseConfig
 	styles       SearchPageStyles
 	mode         SearchMode // database or workspace
 	hasMore      bool       // pagination: more results available
 	nextCursor   string     // pagination: cursor for next page
+	keys         *keymap.KeyMap
 }
 
 // SearchPageStyles holds the styles for the search page.
@@ -123,26 +134,31 @@ type SearchPageStyles struct {
 
 // DefaultSearchPageStyles returns the default styles for the search page.
 func DefaultSearchPageStyles() SearchPageStyles {
+	return NewSearchPageStyles(theme.Default())
+}
+
+// NewSearchPageStyles returns the search page styles for a theme.
+func NewSearchPageStyles(t theme.Theme) SearchPageStyles {
 	return SearchPageStyles{
 		Container: lipgloss.NewStyle().
 			Padding(1, 2),
 		InputLabel: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7C3AED")).
+			Foreground(t.Primary).
 			Bold(true).
 			MarginBottom(1),
 		ResultsTitle: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(t.Accent).
 			Bold(true).
 			MarginTop(1).
 			MarginBottom(1),
 		NoResults: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(t.Muted).
 			Italic(true),
 		Error: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
+			Foreground(t.Error).
 			Bold(true),
 		Highlight: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FBBF24")).
+			Foreground(t.Warning).
 			Bold(true),
 	}
 }
@@ -153,8 +169,12 @@ type NewSearchPageInput struct {
 	Height       int
 	NotionClient NotionClient
 	Cache        *cache.PageCache
+	Index        *index.Index // optional; enables ranked full-text and offline search
 	DatabaseID   string
-	Mode         SearchMode // Default: SearchModeWorkspace
+	Database     config.DatabaseConfig // optional; the database's properties, filter and sorts
+	Mode         SearchMode            // Default: SearchModeWorkspace
+	Keys         *keymap.KeyMap        // optional; the default bindings when nil
+	Theme        *theme.Theme          // optional; the default theme when nil
 }
 
 // NewSearchPage creates a new SearchPage instance.
@@ -177,10 +197,7 @@ func NewSearchPage(input NewSearchPageInput) SearchPage {
 	ti.Width = input.Width - 10
 
 	// Create results list
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true
-
-	resultsList := list.New([]list.Item{}, delegate, input.Width-4, input.Height-10)
+	resultsList := list.New([]list.Item{}, list.NewDefaultDelegate(), input.Width-4, input.Height-10)
 	resultsList.Title = "Search Results"
 	resultsList.SetShowStatusBar(false)
 	resultsList.SetShowHelp(false)
@@ -192,15 +209,9 @@ func NewSearchPage(input NewSearchPageInput) SearchPage {
 	statusBar.SetMode(components.ModeBrowse)
 	statusBar.SetSyncStatus(components.StatusSynced)
 
-	helpText := "Type to search | ↓: results | Ctrl+T: switch mode | ESC: back"
-	statusBar.SetHelpText(helpText)
-
 	spinner := components.NewSpinner("Searching...")
 
-	styles := DefaultSearchPageStyles()
-	resultsList.Styles.Title = styles.ResultsTitle
-
-	return SearchPage{
+	sp := SearchPage{
 		input:        ti,
 		resultsList:  resultsList,
 		statusBar:    statusBar,
@@ -213,12 +224,56 @@ func NewSearchPage(input NewSearchPageInput) SearchPage {
 		height:       input.Height,
 		notionClient: input.NotionClient,
 		cache:        input.Cache,
+		index:        input.Index,
 		databaseID:   input.DatabaseID,
-		styles:       styles,
+		settings:     input.Database,
 		mode:         mode,
 		hasMore:      false,
 		nextCursor:   "",
 	}
+	sp.SetKeys(input.Keys)
+	sp.SetTheme(theme.OrDefault(input.Theme))
+	return sp
+}
+
+// SetKeys sets the bindings the page responds to and shows them in the
+// status bar for the part that has focus. Nil restores the default bindings.
+func (sp *SearchPage) SetKeys(k *keymap.KeyMap) {
+	sp.keys = keymap.OrDefault(k)
+	sp.resultsList.KeyMap.CursorUp = sp.keys.List.Up
+	sp.resultsList.KeyMap.CursorDown = sp.keys.List.Down
+	if sp.input.Focused() {
+		sp.statusBar.SetHelpText(inputHelpText(sp.keys))
+	} else {
+		sp.statusBar.SetHelpText(resultsHelpText(sp.keys))
+	}
+}
+
+// SetTheme restyles the page for a theme.
+func (sp *SearchPage) SetTheme(t theme.Theme) tea.Cmd {
+	sp.styles = NewSearchPageStyles(t)
+	sp.input.PromptStyle = lipgloss.NewStyle().Foreground(t.Primary)
+	sp.input.TextStyle = lipgloss.NewStyle().Foreground(t.Text)
+	sp.input.PlaceholderStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	sp.input.Cursor.Style = lipgloss.NewStyle().Foreground(t.Primary)
+	components.StyleList(&sp.resultsList, t)
+	sp.resultsList.Styles.Title = sp.styles.ResultsTitle
+	sp.statusBar.SetTheme(t)
+	sp.spinner.SetTheme(t)
+	return nil
+}
+
+// inputHelpText returns the status bar help while the search input has focus.
+func inputHelpText(keys *keymap.KeyMap) string {
+	return fmt.Sprintf("Type to search | %s: results | %s: switch mode | %s: back",
+		keymap.Label(keys.Search.Results), keymap.Label(keys.Search.ToggleMode), keymap.Label(keys.Global.Back))
+}
+
+// resultsHelpText returns the status bar help while the results list has focus.
+func resultsHelpText(keys *keymap.KeyMap) string {
+	return fmt.Sprintf("%s: input | %s: select | %s: switch mode | %s: back",
+		keymap.Label(keys.Search.Input), keymap.Label(keys.Search.Select),
+		keymap.Label(keys.Search.ToggleMode), keymap.Label(keys.Global.Back))
 }
 
 // Init initializes the search page component.
@@ -256,17 +311,17 @@ func (sp *SearchPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if sp.mode == SearchModeDatabase {
 			modeLabel = "database"
 		}
-		helpText := fmt.Sprintf("%d results (%s) | ↑: input | Enter: select | Ctrl+T: switch mode | ESC: back", len(sp.results), modeLabel)
+		helpText := fmt.Sprintf("%d results (%s) | %s", len(sp.results), modeLabel, resultsHelpText(sp.keys))
 		if sp.hasMore {
-			helpText = fmt.Sprintf("%d+ results (%s) | ↑: input | Enter: select | Ctrl+T: switch mode | ESC: back", len(sp.results), modeLabel)
+			helpText = fmt.Sprintf("%d+ results (%s) | %s", len(sp.results), modeLabel, resultsHelpText(sp.keys))
 		}
 		sp.statusBar.SetHelpText(helpText)
 		sp.statusBar.SetSyncStatus(components.StatusSynced)
 		return sp, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, sp.keys.Search.Select):
 			// If search input is focused and has text, perform search
 			if sp.input.Focused() && sp.input.Value() != "" {
 				sp.searching = true
@@ -287,28 +342,28 @@ func (sp *SearchPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "down", "j":
+		case key.Matches(msg, sp.keys.Search.Results):
 			// Move from input to results list when pressing down
 			if sp.input.Focused() && len(sp.results) > 0 {
 				sp.input.Blur()
-				sp.statusBar.SetHelpText("↑: input | Enter: select | Ctrl+T: switch mode | ESC: back")
+				sp.statusBar.SetHelpText(resultsHelpText(sp.keys))
 				return sp, nil
 			}
 
-		case "up", "k":
+		case key.Matches(msg, sp.keys.Search.Input):
 			// Move from results list back to input when at top
 			if !sp.input.Focused() && sp.resultsList.Index() == 0 {
 				sp.input.Focus()
-				sp.statusBar.SetHelpText("Type to search | ↓: results | Ctrl+T: switch mode | ESC: back")
+				sp.statusBar.SetHelpText(inputHelpText(sp.keys))
 				return sp, nil
 			}
 
-		case "ctrl+t":
+		case key.Matches(msg, sp.keys.Search.ToggleMode):
 			// Explicit mode toggle
 			sp.toggleMode()
 			return sp, nil
 
-		case "esc":
+		case key.Matches(msg, sp.keys.Global.Back):
 			// If filtering in results list, clear filter
 			if sp.resultsList.SettingFilter() {
 				sp.resultsList.ResetFilter()
@@ -317,7 +372,7 @@ func (sp *SearchPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// If in results list (input not focused), go back to input
 			if !sp.input.Focused() {
 				sp.input.Focus()
-				sp.statusBar.SetHelpText("Type to search | ↓: results | Ctrl+T: switch mode | ESC: back")
+				sp.statusBar.SetHelpText(inputHelpText(sp.keys))
 				return sp, nil
 			}
 			// If input is focused, request back navigation
@@ -378,7 +433,7 @@ func (sp *SearchPage) toggleMode() {
 	if sp.mode == SearchModeDatabase {
 		modeLabel = "database"
 	}
-	sp.statusBar.SetHelpText(fmt.Sprintf("Mode: %s | Type to search | Ctrl+T: switch mode | ESC: back", modeLabel))
+	sp.statusBar.SetHelpText(fmt.Sprintf("Mode: %s | %s", modeLabel, inputHelpText(sp.keys)))
 }
 
 // View renders the search page.
@@ -440,21 +495,100 @@ func (sp *SearchPage) searchCmd() tea.Cmd {
 	databaseID := sp.databaseID
 
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx := notion.WithView(context.Background(), "search")
 
-		if sp.notionClient == nil {
+		if sp.notionClient == nil && sp.index == nil {
 			return searchResultsMsg{
 				err: fmt.Errorf("notion client not initialized"),
 			}
 		}
 
+		parentID := ""
+		if mode == SearchModeDatabase {
+			parentID = databaseID
+		}
+		var local []SearchResult
+		if mode == SearchModeWorkspace || databaseID != "" {
+			local = sp.searchLocal(query, parentID)
+		}
+
+		if sp.notionClient == nil {
+			return searchResultsMsg{results: local, query: query}
+		}
+
 		// Use workspace search or database search based on mode
+		var remote searchResultsMsg
 		if mode == SearchModeWorkspace {
-			return sp.searchWorkspace(ctx, query)
+			remote = sp.searchWorkspace(ctx, query)
+		} else {
+			remote = sp.searchDatabase(ctx, query, databaseID)
+		}
+		slog.DebugContext(ctx, "search", logging.KeyQuery, query, "mode", string(mode),
+			"local", len(local), "remote", len(remote.results), "error", remote.err)
+
+		return mergeSearchResults(query, local, remote)
+	}
+}
+
+// searchLocal performs a ranked full-text search over the local index.
+// An empty parentID searches every indexed page.
+func (sp *SearchPage) searchLocal(query, parentID string) []SearchResult {
+	if sp.index == nil {
+		return nil
+	}
+
+	hits := sp.index.Search(index.SearchInput{
+		Query:    query,
+		ParentID: parentID,
+	})
+
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		title := hit.Title
+		if title == "" {
+			title = "Untitled"
+		}
+		results = append(results, SearchResult{
+			PageID:     hit.ID,
+			Title:      title,
+			Snippet:    hit.Snippet,
+			MatchType:  hit.MatchType,
+			ObjectType: "page",
+		})
+	}
+	return results
+}
+
+// mergeSearchResults combines local index hits with remote results.
+// Local hits come first because they are ranked by full-text relevance; remote
+// results for pages already present are dropped. When the remote search fails
+// but local hits exist, the local hits are returned so search works offline.
+func mergeSearchResults(query string, local []SearchResult, remote searchResultsMsg) searchResultsMsg {
+	if remote.err != nil {
+		if len(local) > 0 {
+			return searchResultsMsg{results: local, query: query}
 		}
+		return remote
+	}
+	if len(local) == 0 {
+		return remote
+	}
 
-		return sp.searchDatabase(ctx, query, databaseID)
+	seen := make(map[string]bool, len(local))
+	for _, r := range local {
+		seen[notion.NormalizeID(r.PageID)] = true
+	}
+
+	merged := append([]SearchResult{}, local...)
+	for _, r := range remote.results {
+		if seen[notion.NormalizeID(r.PageID)] {
+			continue
+		}
+		merged = append(merged, r)
 	}
+
+	remote.results = merged
+	return remote
 }
 
 // searchWorkspace performs a workspace-wide search using the Notion Search API.
@@ -505,7 +639,16 @@ func (sp *SearchPage) searchDatabase(ctx context.Context, query, databaseID stri
 		}
 	}
 
-	resp, err := sp.notionClient.QueryDatabase(ctx, databaseID, nil)
+	// Only the rows the database's filter lets through are searched, in its
+	// configured order
+	req, err := databaseQuery(ctx, sp.notionClient, databaseID, sp.settings, time.Now())
+	if err != nil {
+		return searchResultsMsg{
+			err: fmt.Errorf("database settings: %w", err),
+		}
+	}
+
+	resp, err := sp.notionClient.QueryDatabase(ctx, databaseID, req)
 	if err != nil {
 		return searchResultsMsg{
 			err: fmt.Errorf("fetch pages: %w", err),
@@ -516,7 +659,8 @@ func (sp *SearchPage) searchDatabase(ctx context.Context, query, databaseID stri
 	queryLower := strings.ToLower(query)
 
 	for _, p := range resp.Results {
-		title := extractTitle(&p)
+		page := pageFromNotion(&p, sp.settings)
+		title := page.Title
 		titleLower := strings.ToLower(title)
 
 		// Match on title
@@ -532,16 +676,26 @@ func (sp *SearchPage) searchDatabase(ctx context.Context, query, databaseID stri
 			continue
 		}
 
-		// Match on status property
-		status := extractStatus(&p)
-		if status != "" && strings.Contains(strings.ToLower(status), queryLower) {
-			results = append(results, SearchResult{
-				PageID:     string(p.ID),
-				Title:      title,
-				Snippet:    fmt.Sprintf("Status: %s", status),
-				MatchType:  "property",
-				ObjectType: "page",
-			})
+		// Match on the status and visible properties
+		props := page.Properties
+		if page.Status != "" {
+			name := sp.settings.StatusProperty
+			if name == "" {
+				name = config.DefaultStatusProperty
+			}
+			props = append([]PageProperty{{Name: name, Value: page.Status}}, props...)
+		}
+		for _, prop := range props {
+			if strings.Contains(strings.ToLower(prop.Value), queryLower) {
+				results = append(results, SearchResult{
+					PageID:     string(p.ID),
+					Title:      title,
+					Snippet:    fmt.Sprintf("%s: %s", prop.Name, prop.Value),
+					MatchType:  "property",
+					ObjectType: "page",
+				})
+				break
+			}
 		}
 	}
 
@@ -553,40 +707,7 @@ func (sp *SearchPage) searchDatabase(ctx context.Context, query, databaseID stri
 
 // generateSnippet creates a highlighted snippet showing the match.
 func (sp *SearchPage) generateSnippet(text, query string) string {
-	queryLower := strings.ToLower(query)
-	textLower := strings.ToLower(text)
-
-	index := strings.Index(textLower, queryLower)
-	if index == -1 {
-		// Fallback if not found
-		if len(text) > 50 {
-			return text[:50] + "..."
-		}
-		return text
-	}
-
-	// Calculate snippet window
-	start := index - 20
-	if start < 0 {
-		start = 0
-	}
-
-	end := index + len(query) + 30
-	if end > len(text) {
-		end = len(text)
-	}
-
-	snippet := text[start:end]
-
-	// Add ellipsis
-	if start > 0 {
-		snippet = "..." + snippet
-	}
-	if end < len(text) {
-		snippet = snippet + "..."
-	}
-
-	return snippet
+	return index.Snippet(text, []string{query})
 }
 
 // updateResultsList converts search results to list items.
@@ -8,6 +8,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jomei/notionapi"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/notion"
 )
 
 func TestNewSearchPage(t *testing.T) {
@@ -607,3 +610,116 @@ func TestSearchPage_ClientInterface(t *testing.T) {
 
 	assert.NotNil(t, page.notionClient)
 }
+
+func newSearchTestIndex() *index.Index {
+	ix := index.NewIndex(index.NewIndexInput{})
+	ix.Put(index.Document{
+		ID:       "page-1",
+		Title:    "Launch Plan",
+		ParentID: "test-db",
+		Content:  "Checklist for the product launch and the press release.",
+	})
+	ix.Put(index.Document{
+		ID:       "page-2",
+		Title:    "Retro",
+		ParentID: "other-db",
+		Content:  "What went well during the launch week.",
+	})
+	return ix
+}
+
+func TestSearchPage_SearchCmd_LocalIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      NotionClient
+		mode        SearchMode
+		query       string
+		wantIDs     []string
+		wantErr     bool
+		wantSnippet string
+	}{
+		{
+			name:    "offline workspace search uses index only",
+			client:  nil,
+			mode:    SearchModeWorkspace,
+			query:   "launch",
+			wantIDs: []string{"page-1", "page-2"},
+		},
+		{
+			name:    "database mode restricts to current database",
+			client:  nil,
+			mode:    SearchModeDatabase,
+			query:   "launch",
+			wantIDs: []string{"page-1"},
+		},
+		{
+			name: "API failure falls back to local results",
+			client: &MockNotionClient{
+				SearchFunc: func(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error) {
+					return nil, errors.New("network unreachable")
+				},
+			},
+			mode:        SearchModeWorkspace,
+			query:       `"press release"`,
+			wantIDs:     []string{"page-1"},
+			wantSnippet: "press release",
+		},
+		{
+			name: "API results are merged without duplicates",
+			client: &MockNotionClient{
+				SearchFunc: func(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error) {
+					return &notion.SearchResponse{
+						Results: []notion.SearchResult{
+							{ID: "page-1", Title: "Launch Plan", ObjectType: "page"},
+							{ID: "page-9", Title: "Launch Archive", ObjectType: "page"},
+						},
+					}, nil
+				},
+			},
+			mode:    SearchModeWorkspace,
+			query:   "launch",
+			wantIDs: []string{"page-1", "page-2", "page-9"},
+		},
+		{
+			name: "API failure without local hits reports error",
+			client: &MockNotionClient{
+				SearchFunc: func(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error) {
+					return nil, errors.New("network unreachable")
+				},
+			},
+			mode:    SearchModeWorkspace,
+			query:   "nonexistent",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewSearchPage(NewSearchPageInput{
+				Width:        80,
+				Height:       40,
+				NotionClient: tt.client,
+				Index:        newSearchTestIndex(),
+				DatabaseID:   "test-db",
+				Mode:         tt.mode,
+			})
+			page.input.SetValue(tt.query)
+
+			resultsMsg := page.searchCmd()().(searchResultsMsg)
+			if tt.wantErr {
+				assert.Error(t, resultsMsg.err)
+				return
+			}
+
+			assert.NoError(t, resultsMsg.err)
+			ids := make([]string, 0, len(resultsMsg.results))
+			for _, r := range resultsMsg.results {
+				ids = append(ids, r.PageID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			if tt.wantSnippet != "" {
+				assert.Contains(t, resultsMsg.results[0].Snippet, tt.wantSnippet)
+			}
+		})
+	}
+}
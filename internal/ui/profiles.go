@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/pages"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// profileActionPrefix starts the palette action of a profile command; the
+// profile's name follows it, or nothing for the settings outside profiles.
+const profileActionPrefix = "profile:"
+
+// noProfileTitle is the palette title of the settings outside profiles.
+const noProfileTitle = "Profile: (none)"
+
+// ProfileLoader loads the config with the named profile active, or with
+// none when the name is empty, and opens its cache. It returns a
+// ProfileSwitchedMsg, or a ProfileSwitchFailedMsg when the profile cannot
+// be used. It runs outside the UI goroutine.
+type ProfileLoader func(name string) tea.Msg
+
+// ProfileSwitchedMsg carries a profile opened from the palette, with the
+// cache, key bindings and theme built from its config.
+type ProfileSwitchedMsg struct {
+	Config *config.Config
+	Cache  *cache.PageCache // optional; opened from the config when nil
+	Keys   *keymap.KeyMap
+	Theme  theme.Theme
+}
+
+// ProfileSwitchFailedMsg reports a profile that could not be opened. The
+// profile in use stays active.
+type ProfileSwitchFailedMsg struct {
+	Profile string
+	Err     error
+}
+
+// addProfileCommands adds a palette command for each profile not offered
+// yet. Nothing is added when profiles cannot be switched.
+func addProfileCommands(p *components.CommandPalette, load ProfileLoader, names []string) {
+	if load == nil || len(names) == 0 {
+		return
+	}
+	for _, name := range names {
+		title := "Profile: " + name
+		if !hasCommand(p, title) {
+			p.AddCommand(title, fmt.Sprintf("Switch to the %s profile", name),
+				profileActionPrefix+name, func() tea.Cmd { return nil })
+		}
+	}
+	if !hasCommand(p, noProfileTitle) {
+		p.AddCommand(noProfileTitle, "Switch to the settings outside profiles",
+			profileActionPrefix, func() tea.Cmd { return nil })
+	}
+}
+
+// switchProfile starts loading the named profile.
+func (m *AppModel) switchProfile(name string) tea.Cmd {
+	if m.loadProfile == nil {
+		return nil
+	}
+	if name == m.config.Profile {
+		m.statusBar.SetHelpText(fmt.Sprintf("Profile %s is already active", profileLabel(name)))
+		return nil
+	}
+
+	m.statusBar.SetSyncStatus(components.StatusSyncing)
+	m.statusBar.SetHelpText(fmt.Sprintf("Switching to profile %s...", profileLabel(name)))
+	load := m.loadProfile
+	return func() tea.Msg {
+		return load(name)
+	}
+}
+
+// handleProfileSwitched replaces the services of the old profile with those
+// of the new one and reopens the TUI on its dashboard. Pages of the old
+// workspace are dropped.
+func (m *AppModel) handleProfileSwitched(msg ProfileSwitchedMsg) tea.Cmd {
+	// The old search index is saved now. The old cache stays open until Close:
+	// commands started under the old profile may still be running against it,
+	// and its file lock keeps it consistent with the new one meanwhile, as
+	// with a command line run beside the TUI.
+	closeErr := m.retireServices()
+	m.retired = append(m.retired, m.services())
+
+	svc := openServices(msg.Config, msg.Cache)
+	m.notionClient = svc.notionClient
+	m.cache = svc.cache
+	m.index = svc.index
+	m.prefetcher = svc.prefetcher
+	m.config = msg.Config
+
+	m.setKeys(msg.Keys)
+	m.configTheme = msg.Theme
+	addConfiguredThemeCommand(&m.cmdPalette, msg.Theme)
+	themeCmd := m.applyTheme(msg.Theme)
+	m.inspector = newInspector(svc, m.keys, m.theme)
+	m.inspector.SetSize(m.width, m.height)
+	m.stats = newStatsPanel(svc, m.keys, m.theme)
+	m.stats.SetSize(m.width, m.height)
+
+	nav := NewNavigator(NewNavigatorInput{
+		InitialPage: PageDashboard,
+		MaxHistory:  DefaultMaxHistory,
+	})
+	m.navigator = &nav
+	m.currentPage = nav.CurrentPage()
+	m.currentDBID = msg.Config.GetDatabaseID()
+	m.start = StartTarget{}
+	m.selectedPage = nil
+	m.pageList = []pages.Page{}
+	m.mode = ViewModeBrowse
+	m.sidebarFocus = false
+	m.treeView.SetFocused(false)
+	m.treeView.SetLoading(true)
+	m.pages = make(map[PageID]tea.Model)
+	m.initializePages()
+
+	m.statusBar.SetMode(components.ModeBrowse)
+	m.statusBar.SetSyncStatus(components.StatusSynced)
+	m.statusBar.SetProfile(msg.Config.Profile)
+	addProfileCommands(&m.cmdPalette, m.loadProfile, msg.Config.ProfileNames())
+
+	toastCmd := m.toast.Show("Profile: "+profileLabel(msg.Config.Profile),
+		components.ToastInfo, components.DefaultToastDuration)
+	if closeErr != nil {
+		toastCmd = m.toast.Show(fmt.Sprintf("Profile %s opened; the previous one was not closed cleanly: %v",
+			profileLabel(msg.Config.Profile), closeErr), components.ToastError, components.DefaultToastDuration)
+	}
+
+	return tea.Batch(
+		themeCmd,
+		m.pages[m.currentPage].Init(),
+		m.fetchWorkspaceTreeCmd(),
+		m.startPrefetchCmd(),
+		toastCmd,
+	)
+}
+
+// handleProfileSwitchFailed reports a profile that could not be opened.
+func (m *AppModel) handleProfileSwitchFailed(msg ProfileSwitchFailedMsg) tea.Cmd {
+	m.statusBar.SetSyncStatus(components.StatusSynced)
+	return m.toast.Show(fmt.Sprintf("Profile %s not opened: %v", profileLabel(msg.Profile), msg.Err),
+		components.ToastError, components.DefaultToastDuration)
+}
+
+// profileLabel names a profile for messages.
+func profileLabel(name string) string {
+	if name == "" {
+		return "(none)"
+	}
+	return name
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+func TestProfileCommands(t *testing.T) {
+	cfg := &config.Config{
+		NotionToken: "test_token",
+		CacheDir:    t.TempDir(),
+		Profiles:    map[string]config.ProfileConfig{"work": {}, "personal": {}},
+	}
+
+	tests := []struct {
+		name         string
+		loader       ProfileLoader
+		wantCommands bool
+	}{
+		{name: "no loader"},
+		{name: "loader", loader: func(string) tea.Msg { return nil }, wantCommands: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(NewModelInput{Config: cfg, LoadProfile: tt.loader})
+
+			assert.Equal(t, tt.wantCommands, hasCommand(&model.cmdPalette, "Profile: work"))
+			assert.Equal(t, tt.wantCommands, hasCommand(&model.cmdPalette, "Profile: personal"))
+			assert.Equal(t, tt.wantCommands, hasCommand(&model.cmdPalette, noProfileTitle))
+		})
+	}
+}
+
+func TestSwitchProfile(t *testing.T) {
+	work := &config.Config{
+		NotionToken: "work_token",
+		CacheDir:    t.TempDir(),
+		Databases:   []config.DatabaseConfig{{ID: "db-work", Name: "Roadmap"}},
+		Profile:     "work",
+		Profiles:    map[string]config.ProfileConfig{"work": {}},
+	}
+	light, _ := theme.Builtin(theme.Light)
+
+	var loaded string
+	model := NewModel(NewModelInput{
+		Config: &config.Config{
+			NotionToken: "test_token",
+			CacheDir:    t.TempDir(),
+			Profiles:    map[string]config.ProfileConfig{"work": {}},
+		},
+		LoadProfile: func(name string) tea.Msg {
+			loaded = name
+			return ProfileSwitchedMsg{Config: work, Theme: light}
+		},
+	})
+	model.initializePages()
+	model.currentPage = PageWorkspaceSearch
+
+	cmd := model.handleCommandExecution(components.CommandExecutedMsg{ActionType: "profile:work"})
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(ProfileSwitchedMsg)
+	require.True(t, ok)
+	assert.Equal(t, "work", loaded)
+
+	require.NotNil(t, model.handleProfileSwitched(msg))
+
+	assert.Same(t, work, model.config)
+	assert.Equal(t, "db-work", model.currentDBID)
+	assert.Equal(t, PageDashboard, model.currentPage)
+	assert.Equal(t, "work", model.statusBar.Profile())
+	assert.Equal(t, theme.Light, model.theme.Name)
+	assert.Contains(t, model.pages[PageDashboard].View(), "Open Roadmap")
+	assert.Contains(t, model.pages, PageList)
+	assert.Equal(t, "Profile: work", model.toast.Message())
+	require.NoError(t, model.Close())
+
+	// The active profile is not loaded again
+	assert.Nil(t, model.handleCommandExecution(components.CommandExecutedMsg{ActionType: "profile:work"}))
+}
+
+func TestSwitchProfileClosesOldCacheOnClose(t *testing.T) {
+	ctx := context.Background()
+	old, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir()})
+	require.NoError(t, err)
+	model := NewModel(NewModelInput{
+		Config: &config.Config{NotionToken: "test_token", CacheDir: old.Dir()},
+		Cache:  old,
+	})
+
+	work := &config.Config{NotionToken: "work_token", CacheDir: t.TempDir(), Profile: "work"}
+	require.NotNil(t, model.handleProfileSwitched(ProfileSwitchedMsg{Config: work}))
+	require.NotSame(t, old, model.cache)
+
+	// A command started under the old profile can still use its cache
+	require.NoError(t, old.Set(ctx, cache.SetInput{PageID: "page-1", Data: "late write", TTL: time.Hour}))
+
+	require.NoError(t, model.Close())
+	assert.Error(t, old.Set(ctx, cache.SetInput{PageID: "page-2", Data: "after close", TTL: time.Hour}))
+}
+
+func TestSwitchProfileFailed(t *testing.T) {
+	cfg := &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()}
+	model := NewModel(NewModelInput{Config: cfg})
+
+	model.handleProfileSwitchFailed(ProfileSwitchFailedMsg{Profile: "work", Err: errors.New("notion_token is required")})
+
+	assert.Same(t, cfg, model.config)
+	assert.Equal(t, components.ToastError, model.toast.Level())
+	assert.Contains(t, model.toast.Message(), "Profile work not opened")
+	assert.Contains(t, model.toast.Message(), "notion_token is required")
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/pages"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// ConfigReloadedMsg carries the config read again after its file changed,
+// with the key bindings and theme built from it. Sending it to the program
+// applies the config in place.
+type ConfigReloadedMsg struct {
+	Config *config.Config
+	Keys   *keymap.KeyMap
+	Theme  theme.Theme
+}
+
+// ConfigReloadFailedMsg reports a changed config file that could not be
+// applied. The config in use stays active.
+type ConfigReloadFailedMsg struct {
+	Err error
+}
+
+// rebindable is implemented by pages whose key bindings can change while
+// they are shown.
+type rebindable interface {
+	SetKeys(k *keymap.KeyMap)
+}
+
+// handleConfigReloaded applies a reloaded config: the dashboard menu, the
+// database list, the key bindings and, when the configured theme changed,
+// the theme. Settings read only on start, like the token and the cache,
+// keep their old values.
+func (m *AppModel) handleConfigReloaded(msg ConfigReloadedMsg) tea.Cmd {
+	m.config = msg.Config
+	m.setKeys(msg.Keys)
+	addProfileCommands(&m.cmdPalette, m.loadProfile, msg.Config.ProfileNames())
+
+	if dashboard, ok := m.pages[PageDashboard].(*pages.DashboardPage); ok {
+		dashboard.SetConfig(msg.Config)
+	}
+	if dbList, ok := m.pages[PageDatabaseList].(*pages.DatabaseListPage); ok {
+		dbList.SetDatabases(msg.Config.Databases, m.currentDBID)
+	}
+
+	var cmds []tea.Cmd
+	// A theme picked from the palette is kept unless the file changed it
+	if msg.Theme != m.configTheme {
+		m.configTheme = msg.Theme
+		addConfiguredThemeCommand(&m.cmdPalette, msg.Theme)
+		cmds = append(cmds, m.applyTheme(msg.Theme))
+	}
+
+	cmds = append(cmds, m.toast.Show("Config reloaded", components.ToastInfo, components.DefaultToastDuration))
+	return tea.Batch(cmds...)
+}
+
+// handleConfigReloadFailed reports a config that could not be reloaded.
+func (m *AppModel) handleConfigReloadFailed(msg ConfigReloadFailedMsg) tea.Cmd {
+	return m.toast.Show(fmt.Sprintf("Config not reloaded: %v", msg.Err),
+		components.ToastError, components.DefaultToastDuration)
+}
+
+// setKeys rebinds the global components and every open page. Pages created
+// later pick the bindings up from the model.
+func (m *AppModel) setKeys(k *keymap.KeyMap) {
+	m.keys = keymap.OrDefault(k)
+	m.treeView.SetKeys(m.keys)
+	m.cmdPalette.SetKeys(m.keys)
+	m.inspector.SetKeys(m.keys)
+	m.stats.SetKeys(m.keys)
+	if !m.showHelp {
+		m.statusBar.SetHelpText(fmt.Sprintf("%s for help", keymap.Label(m.keys.Global.Help)))
+	}
+	for _, page := range m.pages {
+		if p, ok := page.(rebindable); ok {
+			p.SetKeys(m.keys)
+		}
+	}
+}
+
+// hasCommand reports whether the palette already offers a command.
+func hasCommand(p *components.CommandPalette, title string) bool {
+	for _, c := range p.Commands() {
+		if c.Title() == title {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+func TestHandleConfigReloaded(t *testing.T) {
+	cfg := &config.Config{
+		NotionToken: "test_token",
+		CacheDir:    t.TempDir(),
+		Databases:   []config.DatabaseConfig{{ID: "db-1", Name: "Notes"}},
+	}
+	model := NewModel(NewModelInput{Config: cfg})
+	model.initializePages()
+
+	reloaded := *cfg
+	reloaded.Databases = []config.DatabaseConfig{
+		{ID: "db-1", Name: "Notes"},
+		{ID: "db-2", Name: "Tasks"},
+	}
+	keys, err := keymap.New(keymap.NewInput{Preset: keymap.PresetVim})
+	require.NoError(t, err)
+	light, _ := theme.Builtin(theme.Light)
+
+	cmd := model.handleConfigReloaded(ConfigReloadedMsg{Config: &reloaded, Keys: keys, Theme: light})
+	require.NotNil(t, cmd)
+
+	assert.Same(t, &reloaded, model.config)
+	assert.Same(t, keys, model.keys)
+	assert.Equal(t, theme.Light, model.theme.Name)
+	assert.Contains(t, model.pages[PageDashboard].View(), "Open Tasks")
+	assert.True(t, model.toast.Visible())
+	assert.Equal(t, components.ToastInfo, model.toast.Level())
+	assert.Equal(t, "Config reloaded", model.toast.Message())
+}
+
+func TestHandleConfigReloadedKeepsPickedTheme(t *testing.T) {
+	cfg := &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()}
+	model := NewModel(NewModelInput{Config: cfg})
+	model.initializePages()
+	model.switchTheme(theme.HighContrast)
+
+	// The file's theme did not change, so the one picked from the palette stays
+	model.handleConfigReloaded(ConfigReloadedMsg{Config: cfg, Theme: model.configTheme})
+
+	assert.Equal(t, theme.HighContrast, model.theme.Name)
+}
+
+func TestHandleConfigReloadFailed(t *testing.T) {
+	cfg := &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()}
+	model := NewModel(NewModelInput{Config: cfg})
+	model.initializePages()
+
+	cmd := model.handleConfigReloadFailed(ConfigReloadFailedMsg{Err: errors.New("invalid key binding")})
+	require.NotNil(t, cmd)
+
+	assert.Same(t, cfg, model.config)
+	assert.True(t, model.toast.Visible())
+	assert.Equal(t, components.ToastError, model.toast.Level())
+	assert.Contains(t, model.toast.Message(), "invalid key binding")
+}
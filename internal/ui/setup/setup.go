@@ -0,0 +1,561 @@
+// Package setup implements the first-run wizard that writes a config file:
+// it verifies a pasted token, lists the databases shared with the
+// integration and lets the user pick the ones to add.
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// IntegrationsURL is where integrations and their tokens are created.
+const IntegrationsURL = "https://www.notion.so/my-integrations"
+
+// requestTimeout bounds each call to Notion.
+const requestTimeout = 30 * time.Second
+
+// maxSearchPages bounds how many pages of databases are listed.
+const maxSearchPages = 10
+
+// Client is the part of the Notion client the wizard uses.
+type Client interface {
+	Me(ctx context.Context) (*notionapi.User, error)
+	Search(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error)
+}
+
+// step is a screen of the wizard.
+type step int
+
+const (
+	stepToken step = iota
+	stepVerifying
+	stepLoading
+	stepSelect
+	stepEdit
+	stepWriting
+	stepDone
+)
+
+// editKey opens the name and icon of the selected database for editing.
+var editKey = key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "rename"))
+
+// tokenVerifiedMsg reports the result of checking the token.
+type tokenVerifiedMsg struct {
+	workspace string
+	err       error
+}
+
+// databasesLoadedMsg carries the databases shared with the integration.
+type databasesLoadedMsg struct {
+	databases []notion.SearchResult
+	err       error
+}
+
+// configWrittenMsg reports the result of writing the config file.
+type configWrittenMsg struct {
+	err error
+}
+
+// choice is a database the user can add.
+type choice struct {
+	id       string
+	name     string
+	icon     string
+	selected bool
+}
+
+// Styles holds the styles for the wizard.
+type Styles struct {
+	Title    lipgloss.Style
+	Text     lipgloss.Style
+	Muted    lipgloss.Style
+	Error    lipgloss.Style
+	Success  lipgloss.Style
+	Cursor   lipgloss.Style
+	Selected lipgloss.Style
+}
+
+// DefaultStyles returns the default styles for the wizard.
+func DefaultStyles() Styles {
+	return NewStyles(theme.Default())
+}
+
+// NewStyles returns the wizard styles for a theme.
+func NewStyles(t theme.Theme) Styles {
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Foreground(t.Primary).
+			Bold(true).
+			MarginBottom(1),
+		Text: lipgloss.NewStyle().
+			Foreground(t.Text),
+		Muted: lipgloss.NewStyle().
+			Foreground(t.Muted),
+		Error: lipgloss.NewStyle().
+			Foreground(t.Error),
+		Success: lipgloss.NewStyle().
+			Foreground(t.Success).
+			Bold(true),
+		Cursor: lipgloss.NewStyle().
+			Foreground(t.Accent).
+			Bold(true),
+		Selected: lipgloss.NewStyle().
+			Foreground(t.Success),
+	}
+}
+
+// NewModelInput contains the parameters for creating a new Model.
+type NewModelInput struct {
+	ConfigFile string                    // where the config is written
+	Overwrite  bool                      // replace an existing ConfigFile
+	NewClient  func(token string) Client // creates a client for a pasted token
+	Keys       *keymap.KeyMap            // optional; the default bindings when nil
+	Theme      *theme.Theme              // optional; the default theme when nil
+}
+
+// Model is the setup wizard. Run it as a Bubble Tea program; Completed
+// reports whether it wrote the config file.
+type Model struct {
+	step       step
+	configFile string
+	overwrite  bool
+	newClient  func(token string) Client
+
+	tokenInput textinput.Model
+	nameInput  textinput.Model
+	iconInput  textinput.Model
+	spinner    components.Spinner
+
+	token     string
+	workspace string
+	choices   []choice
+	cursor    int
+	err       error
+	completed bool
+
+	keys   *keymap.KeyMap
+	styles Styles
+}
+
+// NewModel creates the wizard on its token screen.
+func NewModel(input NewModelInput) Model {
+	t := theme.OrDefault(input.Theme)
+
+	tokenInput := textinput.New()
+	tokenInput.Placeholder = "ntn_... or secret_..."
+	tokenInput.EchoMode = textinput.EchoPassword
+	tokenInput.EchoCharacter = '•'
+	tokenInput.Width = 50
+	tokenInput.Focus()
+
+	nameInput := textinput.New()
+	nameInput.Prompt = "Name: "
+	nameInput.Width = 40
+
+	iconInput := textinput.New()
+	iconInput.Prompt = "Icon: "
+	iconInput.Placeholder = "an emoji, optional"
+	iconInput.Width = 10
+
+	spinner := components.NewSpinner("")
+	spinner.SetTheme(t)
+
+	return Model{
+		step:       stepToken,
+		configFile: input.ConfigFile,
+		overwrite:  input.Overwrite,
+		newClient:  input.NewClient,
+		tokenInput: tokenInput,
+		nameInput:  nameInput,
+		iconInput:  iconInput,
+		spinner:    spinner,
+		keys:       keymap.OrDefault(input.Keys),
+		styles:     NewStyles(t),
+	}
+}
+
+// Init starts the cursor blinking in the token input.
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Completed reports whether the config file was written.
+func (m Model) Completed() bool {
+	return m.completed
+}
+
+// Databases returns the databases chosen so far, in the order shown.
+func (m Model) Databases() []config.DatabaseConfig {
+	var databases []config.DatabaseConfig
+	for _, c := range m.choices {
+		if c.selected {
+			databases = append(databases, config.DatabaseConfig{ID: c.id, Name: c.name, Icon: c.icon})
+		}
+	}
+	return databases
+}
+
+// Update handles input and the results of calls to Notion.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// ctrl+c leaves from any screen; q would be typed into the inputs
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.handleKey(msg)
+
+	case tokenVerifiedMsg:
+		if msg.err != nil {
+			m.step = stepToken
+			m.err = msg.err
+			m.tokenInput.Focus()
+			return m, textinput.Blink
+		}
+		m.workspace = msg.workspace
+		m.step = stepLoading
+		return m, m.loadDatabases()
+
+	case databasesLoadedMsg:
+		m.step = stepSelect
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.setChoices(msg.databases)
+		return m, nil
+
+	case configWrittenMsg:
+		if msg.err != nil {
+			m.step = stepSelect
+			m.err = msg.err
+			return m, nil
+		}
+		m.step = stepDone
+		m.completed = true
+		return m, nil
+	}
+
+	if m.step == stepVerifying || m.step == stepLoading || m.step == stepWriting {
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// handleKey handles a key on the current screen.
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.step {
+	case stepToken:
+		if msg.Type == tea.KeyEnter {
+			return m.verifyToken()
+		}
+		var cmd tea.Cmd
+		m.tokenInput, cmd = m.tokenInput.Update(msg)
+		return m, cmd
+
+	case stepSelect:
+		return m.handleSelectKey(msg)
+
+	case stepEdit:
+		return m.handleEditKey(msg)
+
+	case stepDone:
+		if msg.Type == tea.KeyEnter || key.Matches(msg, m.keys.Global.Quit) {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// handleSelectKey moves through and toggles the databases, or writes the
+// config with the ones selected.
+func (m Model) handleSelectKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Global.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.List.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.List.Down):
+		if m.cursor < len(m.choices)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.List.Toggle):
+		if m.cursor < len(m.choices) {
+			m.choices[m.cursor].selected = !m.choices[m.cursor].selected
+		}
+	case key.Matches(msg, editKey):
+		if m.cursor < len(m.choices) {
+			m.step = stepEdit
+			m.nameInput.SetValue(m.choices[m.cursor].name)
+			m.iconInput.SetValue(m.choices[m.cursor].icon)
+			m.iconInput.Blur()
+			m.nameInput.Focus()
+			return m, textinput.Blink
+		}
+	case key.Matches(msg, m.keys.List.Refresh):
+		m.err = nil
+		m.step = stepLoading
+		return m, m.loadDatabases()
+	case key.Matches(msg, m.keys.List.Select):
+		m.err = nil
+		m.step = stepWriting
+		return m, tea.Batch(m.spinner.Init(), m.writeConfig())
+	}
+	return m, nil
+}
+
+// handleEditKey edits the name and icon of a database. Tab moves between
+// them, enter keeps the change and the back key drops it.
+func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Global.Back):
+		m.step = stepSelect
+		return m, nil
+	case msg.Type == tea.KeyTab:
+		if m.nameInput.Focused() {
+			m.nameInput.Blur()
+			m.iconInput.Focus()
+		} else {
+			m.iconInput.Blur()
+			m.nameInput.Focus()
+		}
+		return m, textinput.Blink
+	case msg.Type == tea.KeyEnter:
+		name := strings.TrimSpace(m.nameInput.Value())
+		if name == "" {
+			m.err = errors.New("the name cannot be empty")
+			return m, nil
+		}
+		m.err = nil
+		m.choices[m.cursor].name = name
+		m.choices[m.cursor].icon = strings.TrimSpace(m.iconInput.Value())
+		m.step = stepSelect
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.nameInput.Focused() {
+		m.nameInput, cmd = m.nameInput.Update(msg)
+	} else {
+		m.iconInput, cmd = m.iconInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// verifyToken asks Notion who the pasted token belongs to.
+func (m Model) verifyToken() (tea.Model, tea.Cmd) {
+	token := strings.TrimSpace(m.tokenInput.Value())
+	if token == "" {
+		m.err = errors.New("paste the token first")
+		return m, nil
+	}
+
+	m.token = token
+	m.err = nil
+	m.step = stepVerifying
+	m.tokenInput.Blur()
+
+	client := m.newClient(token)
+	return m, tea.Batch(m.spinner.Init(), func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		user, err := client.Me(ctx)
+		if err != nil {
+			if notion.ClassifyError(err) == notion.ErrorClassAuth {
+				return tokenVerifiedMsg{err: errors.New("Notion rejected the token; copy the Internal Integration Secret again")}
+			}
+			return tokenVerifiedMsg{err: fmt.Errorf("verify token: %w", err)}
+		}
+		workspace := user.Name
+		if user.Bot != nil && user.Bot.WorkspaceName != "" {
+			workspace = user.Bot.WorkspaceName
+		}
+		return tokenVerifiedMsg{workspace: workspace}
+	})
+}
+
+// loadDatabases lists every database shared with the integration.
+func (m Model) loadDatabases() tea.Cmd {
+	client := m.newClient(m.token)
+	return tea.Batch(m.spinner.Init(), func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		var databases []notion.SearchResult
+		cursor := ""
+		for range maxSearchPages {
+			resp, err := client.Search(ctx, notion.SearchInput{
+				Filter:      "database",
+				PageSize:    100,
+				StartCursor: cursor,
+			})
+			if err != nil {
+				return databasesLoadedMsg{err: fmt.Errorf("list databases: %w", err)}
+			}
+			databases = append(databases, resp.Results...)
+			if !resp.HasMore {
+				break
+			}
+			cursor = resp.NextCursor
+		}
+		return databasesLoadedMsg{databases: databases}
+	})
+}
+
+// setChoices offers the databases found, all selected. Names and icons
+// edited before a refresh are kept.
+func (m *Model) setChoices(databases []notion.SearchResult) {
+	previous := make(map[string]choice, len(m.choices))
+	for _, c := range m.choices {
+		previous[c.id] = c
+	}
+
+	m.choices = make([]choice, 0, len(databases))
+	for _, db := range databases {
+		c, ok := previous[db.ID]
+		if !ok {
+			c = choice{id: db.ID, name: db.Title, icon: db.Icon, selected: true}
+		}
+		m.choices = append(m.choices, c)
+	}
+	if m.cursor >= len(m.choices) {
+		m.cursor = max(len(m.choices)-1, 0)
+	}
+}
+
+// writeConfig writes the token and the selected databases.
+func (m Model) writeConfig() tea.Cmd {
+	path := m.configFile
+	input := config.WriteFileInput{
+		Token:     m.token,
+		Databases: m.Databases(),
+		Overwrite: m.overwrite,
+	}
+	return func() tea.Msg {
+		return configWrittenMsg{err: config.WriteFile(path, input)}
+	}
+}
+
+// View renders the current screen.
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Render("notion-tui setup"))
+	b.WriteString("\n")
+
+	switch m.step {
+	case stepToken:
+		b.WriteString(m.styles.Text.Render("1. Create an internal integration at " + IntegrationsURL))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Text.Render("2. Copy its Internal Integration Secret and paste it below"))
+		b.WriteString("\n\n")
+		b.WriteString(m.tokenInput.View())
+		b.WriteString("\n")
+		m.writeError(&b)
+		b.WriteString("\n")
+		b.WriteString(m.styles.Muted.Render("enter: verify • ctrl+c: quit"))
+
+	case stepVerifying:
+		b.WriteString(m.spinner.View() + " Checking the token with Notion...")
+
+	case stepLoading:
+		b.WriteString(m.spinner.View() + " Looking for databases shared with the integration...")
+
+	case stepSelect:
+		m.viewSelect(&b)
+
+	case stepEdit:
+		b.WriteString(m.styles.Text.Render("Rename the database as shown in notion-tui"))
+		b.WriteString("\n\n")
+		b.WriteString(m.nameInput.View())
+		b.WriteString("\n")
+		b.WriteString(m.iconInput.View())
+		b.WriteString("\n")
+		m.writeError(&b)
+		b.WriteString("\n")
+		b.WriteString(m.styles.Muted.Render(fmt.Sprintf("tab: switch field • enter: keep • %s: cancel",
+			keymap.Label(m.keys.Global.Back))))
+
+	case stepWriting:
+		b.WriteString(m.spinner.View() + " Writing " + m.configFile + "...")
+
+	case stepDone:
+		b.WriteString(m.styles.Success.Render("Config written to " + m.configFile))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.Muted.Render("enter: continue"))
+	}
+
+	return b.String()
+}
+
+// viewSelect renders the list of databases, or how to share one when the
+// integration sees none.
+func (m Model) viewSelect(b *strings.Builder) {
+	if m.workspace != "" {
+		b.WriteString(m.styles.Success.Render("Connected to " + m.workspace))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.choices) == 0 {
+		b.WriteString(m.styles.Text.Render("No databases are shared with the integration yet. For each database:"))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Text.Render("  open it in Notion, click ••• in the top right, then Connections,"))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Text.Render("  and add the integration."))
+		b.WriteString("\n\n")
+		m.writeError(b)
+		b.WriteString(m.styles.Muted.Render(fmt.Sprintf("%s: look again • %s: finish without databases (workspace search still works) • %s: quit",
+			keymap.Label(m.keys.List.Refresh), keymap.Label(m.keys.List.Select), keymap.Label(m.keys.Global.Quit))))
+		return
+	}
+
+	b.WriteString(m.styles.Text.Render("Pick the databases to add. The first one opens by default."))
+	b.WriteString("\n\n")
+	for i, c := range m.choices {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = m.styles.Cursor.Render("> ")
+		}
+		check := "[ ]"
+		if c.selected {
+			check = m.styles.Selected.Render("[x]")
+		}
+		icon := c.icon
+		if icon == "" {
+			icon = "📄"
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s %s\n", cursor, check, icon, c.name))
+	}
+	b.WriteString("\n")
+	m.writeError(b)
+	b.WriteString(m.styles.Muted.Render(fmt.Sprintf("%s: toggle • e: rename • %s: look again • %s: save • %s: quit",
+		keymap.Label(m.keys.List.Toggle), keymap.Label(m.keys.List.Refresh),
+		keymap.Label(m.keys.List.Select), keymap.Label(m.keys.Global.Quit))))
+}
+
+// writeError renders the last error, if any, on its own line.
+func (m Model) writeError(b *strings.Builder) {
+	if m.err != nil {
+		b.WriteString(m.styles.Error.Render(m.err.Error()))
+		b.WriteString("\n")
+	}
+}
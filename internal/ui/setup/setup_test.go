@@ -0,0 +1,204 @@
+package setup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+// fakeClient answers the wizard's API calls.
+type fakeClient struct {
+	meErr error
+	pages [][]notion.SearchResult // one page of results per Search call
+}
+
+func (f *fakeClient) Me(ctx context.Context) (*notionapi.User, error) {
+	if f.meErr != nil {
+		return nil, f.meErr
+	}
+	return &notionapi.User{Name: "Notion TUI", Bot: &notionapi.Bot{WorkspaceName: "Acme"}}, nil
+}
+
+func (f *fakeClient) Search(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error) {
+	page := 0
+	if input.StartCursor != "" {
+		page = 1
+	}
+	if page >= len(f.pages) {
+		return &notion.SearchResponse{}, nil
+	}
+	resp := &notion.SearchResponse{Results: f.pages[page]}
+	if page+1 < len(f.pages) {
+		resp.HasMore = true
+		resp.NextCursor = "next"
+	}
+	return resp, nil
+}
+
+// run executes cmd, and any commands it batches, returning the first
+// message of the wizard's own types.
+func run(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if found := run(t, c); found != nil {
+				return found
+			}
+		}
+	case tokenVerifiedMsg, databasesLoadedMsg, configWrittenMsg:
+		return msg
+	}
+	return nil
+}
+
+// send sends msg to the wizard, dropping the commands it returns.
+func send(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+// sendAndWait sends msg to the wizard and feeds back the results of the
+// calls to Notion it starts, until there are none left.
+func sendAndWait(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	for msg != nil {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		msg = run(t, cmd)
+	}
+	return m
+}
+
+func typeText(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func newTestModel(t *testing.T, client *fakeClient) (Model, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notion-tui", "config.yaml")
+	m := NewModel(NewModelInput{
+		ConfigFile: path,
+		NewClient:  func(token string) Client { return client },
+	})
+	return m, path
+}
+
+func TestWizardWritesConfig(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{pages: [][]notion.SearchResult{
+		{{ID: "db-tasks", Title: "Tasks", Icon: "✅"}},
+		{{ID: "db-notes", Title: "Notes"}},
+	}}
+	m, path := newTestModel(t, client)
+
+	m = typeText(t, m, "secret_abc")
+	assert.NotContains(t, m.View(), "secret_abc", "the token is masked")
+
+	m = sendAndWait(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, stepSelect, m.step)
+	assert.Contains(t, m.View(), "Connected to Acme")
+	assert.Contains(t, m.View(), "Tasks")
+	assert.Contains(t, m.View(), "Notes", "every page of results is listed")
+
+	// Rename the second database and drop the first
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	require.Equal(t, stepEdit, m.step)
+	m.nameInput.SetValue("")
+	m = typeText(t, m, "Journal")
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	m = typeText(t, m, "📓")
+	m = sendAndWait(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, stepSelect, m.step)
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyUp})
+	m = send(t, m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Equal(t, []config.DatabaseConfig{{ID: "db-notes", Name: "Journal", Icon: "📓"}}, m.Databases())
+
+	m = sendAndWait(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, stepDone, m.step)
+	assert.True(t, m.Completed())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "notion_token: secret_abc")
+	assert.Contains(t, string(data), "id: db-notes")
+	assert.Contains(t, string(data), "name: Journal")
+	assert.NotContains(t, string(data), "db-tasks")
+}
+
+func TestWizardRejectedToken(t *testing.T) {
+	t.Parallel()
+
+	m, path := newTestModel(t, &fakeClient{meErr: &notionapi.Error{Status: 401}})
+
+	m = typeText(t, m, "secret_bad")
+	m = sendAndWait(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, stepToken, m.step)
+	assert.Contains(t, m.View(), "Notion rejected the token")
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWizardEmptyToken(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestModel(t, &fakeClient{})
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, stepToken, m.step)
+	assert.Contains(t, m.View(), "paste the token first")
+}
+
+func TestWizardNoDatabases(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{}
+	m, _ := newTestModel(t, client)
+
+	m = typeText(t, m, "secret_abc")
+	m = sendAndWait(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, stepSelect, m.step)
+	assert.Contains(t, m.View(), "Connections")
+
+	// Sharing a database and looking again lists it
+	client.pages = [][]notion.SearchResult{{{ID: "db-tasks", Title: "Tasks"}}}
+	m = sendAndWait(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	require.Equal(t, stepSelect, m.step)
+	assert.Equal(t, []config.DatabaseConfig{{ID: "db-tasks", Name: "Tasks"}}, m.Databases())
+}
+
+func TestWizardQuit(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestModel(t, &fakeClient{})
+
+	// q is typed into the token, ctrl+c leaves
+	m = typeText(t, m, "q")
+	assert.Equal(t, "q", m.tokenInput.Value())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.False(t, m.Completed())
+}
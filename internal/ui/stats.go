@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// statsActionType is the palette action that opens the stats panel.
+const statsActionType = "stats"
+
+// addStatsCommand adds the palette command that opens the stats panel.
+func addStatsCommand(p *components.CommandPalette) {
+	p.AddCommand("API Stats", "Show API requests, latency, rate limiting and cache hits of this session",
+		statsActionType, func() tea.Cmd { return nil })
+}
+
+// newStatsPanel creates the stats panel for the services of a profile.
+func newStatsPanel(svc services, keys *keymap.KeyMap, t theme.Theme) components.StatsPanel {
+	input := components.NewStatsPanelInput{
+		API:   svc.notionClient.Stats,
+		Keys:  keys,
+		Theme: &t,
+	}
+	if svc.cache != nil {
+		input.Cache = svc.cache.Stats
+	}
+	return components.NewStatsPanel(input)
+}
+
+// openStats opens the stats panel.
+func (m *AppModel) openStats() tea.Cmd {
+	m.stats.SetSize(m.width, m.height)
+	return m.stats.Open()
+}
+
+// sessionStats is a line of the stats file: the aggregates of one profile's
+// session, from opening its services to closing them.
+type sessionStats struct {
+	Profile string            `json:"profile,omitempty"`
+	Ended   time.Time         `json:"ended"`
+	API     notion.APIStats   `json:"api"`
+	Cache   *cache.CacheStats `json:"cache,omitempty"` // nil when the cache was not in use
+}
+
+// writeStats appends the session's aggregates to the stats file, if one is
+// configured.
+func (m AppModel) writeStats() error {
+	if m.config == nil || m.config.StatsFile == "" || m.notionClient == nil {
+		return nil
+	}
+
+	line := sessionStats{
+		Profile: m.config.Profile,
+		Ended:   time.Now(),
+		API:     m.notionClient.Stats(),
+	}
+	if m.cache != nil {
+		stats := m.cache.Stats()
+		line.Cache = &stats
+	}
+	return appendStats(m.config.StatsFile, line)
+}
+
+// appendStats writes s as a JSON line at the end of path.
+func appendStats(path string, s sessionStats) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode stats: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create stats directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("open stats file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write stats file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close stats file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/index"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+)
+
+func TestOpenStats(t *testing.T) {
+	model := NewModel(NewModelInput{
+		Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()},
+	})
+	model.initializePages()
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updated.(AppModel)
+
+	cmd := model.handleCommandExecution(components.CommandExecutedMsg{ActionType: statsActionType})
+	assert.NotNil(t, cmd, "the panel refreshes while open")
+	assert.True(t, model.stats.IsOpen())
+	assert.Contains(t, model.View(), "API Stats")
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(AppModel)
+	assert.False(t, model.stats.IsOpen())
+}
+
+func TestCloseWritesStats(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state", "stats.jsonl")
+	cfg := &config.Config{NotionToken: "test_token", CacheDir: dir, StatsFile: path, Profile: "work"}
+
+	// Each closed session adds a line
+	for i := 0; i < 2; i++ {
+		model := NewModel(NewModelInput{Config: cfg})
+		require.NoError(t, model.Close())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var line sessionStats
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, "work", line.Profile)
+	assert.False(t, line.API.Since.IsZero())
+	assert.NotNil(t, line.Cache)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestCloseWithoutStatsFile(t *testing.T) {
+	dir := t.TempDir()
+	model := NewModel(NewModelInput{Config: &config.Config{NotionToken: "test_token", CacheDir: dir}})
+	require.NoError(t, model.Close())
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCloseWritesStatsWhenIndexSaveFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stats.jsonl")
+	model := NewModel(NewModelInput{
+		Config: &config.Config{NotionToken: "test_token", CacheDir: dir, StatsFile: path},
+	})
+	require.NotNil(t, model.index)
+	model.index.Put(index.Document{ID: "page-1", Title: "Roadmap"})
+
+	// A file in place of the index directory makes the save fail
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "index")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index"), nil, 0600))
+
+	err := model.Close()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "save search index")
+
+	_, statErr := os.Stat(path)
+	assert.NoError(t, statErr, "stats are written even though the index was not saved")
+}
@@ -2,31 +2,15 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Panandika/notion-tui/internal/ui/theme"
 )
 
 // Theme defines the color scheme for the application.
-type Theme struct {
-	Primary   lipgloss.Color
-	Secondary lipgloss.Color
-	Accent    lipgloss.Color
-	Text      lipgloss.Color
-	Muted     lipgloss.Color
-	Error     lipgloss.Color
-	Success   lipgloss.Color
-	Warning   lipgloss.Color
-}
+type Theme = theme.Theme
 
 // darkTheme is the default dark theme for the application.
-var darkTheme = Theme{
-	Primary:   lipgloss.Color("#7C3AED"),
-	Secondary: lipgloss.Color("#374151"),
-	Accent:    lipgloss.Color("#10B981"),
-	Text:      lipgloss.Color("#F3F4F6"),
-	Muted:     lipgloss.Color("#6B7280"),
-	Error:     lipgloss.Color("#EF4444"),
-	Success:   lipgloss.Color("#10B981"),
-	Warning:   lipgloss.Color("#F59E0B"),
-}
+var darkTheme = theme.Default()
 
 // Styles holds all lipgloss styles for the application.
 type Styles struct {
@@ -0,0 +1,53 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// GlamourStyle returns a glamour JSON style for rendering page content with
+// the theme's colors. It starts from the theme's markdown style and colors
+// headings, links, code and quotes from the theme's roles.
+func (t Theme) GlamourStyle() ([]byte, error) {
+	var cfg ansi.StyleConfig
+	switch t.Markdown {
+	case MarkdownLight:
+		cfg = styles.LightStyleConfig
+	case MarkdownNone:
+		cfg = styles.NoTTYStyleConfig
+	default:
+		cfg = styles.DarkStyleConfig
+	}
+
+	// Every field set below gets a fresh pointer, so the shared base
+	// styles are never changed
+	color := func(c string) *string {
+		if c == "" {
+			return nil
+		}
+		return &c
+	}
+	if t.Markdown != MarkdownNone {
+		cfg.Document.Color = color(string(t.Text))
+		cfg.Heading.Color = color(string(t.Primary))
+		cfg.H1.Color = color(string(t.Primary))
+		cfg.H1.BackgroundColor = nil
+		cfg.Link.Color = color(string(t.Info))
+		cfg.LinkText.Color = color(string(t.Accent))
+		cfg.Code.Color = color(string(t.Warning))
+		cfg.Code.BackgroundColor = color(string(t.Secondary))
+		cfg.BlockQuote.Color = color(string(t.Muted))
+		cfg.HorizontalRule.Color = color(string(t.Subtle))
+		cfg.Item.Color = color(string(t.Text))
+		cfg.Enumeration.Color = color(string(t.Accent))
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("encode glamour style: %w", err)
+	}
+	return data, nil
+}
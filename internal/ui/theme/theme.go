@@ -0,0 +1,268 @@
+// Package theme holds the colors every style of the TUI derives from. A
+// theme starts from one of the built-in palettes and may be customized from
+// the config file or a theme file. Markdown is rendered with a glamour style
+// generated from the same colors, so page content matches the chrome.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"go.yaml.in/yaml/v3"
+)
+
+// Names of the built-in themes.
+const (
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+	NoColor      = "no-color"
+	Custom       = "custom" // the name of a built-in theme changed by colors
+)
+
+// Names lists the built-in themes, in the order the palette offers them.
+var Names = []string{Dark, Light, HighContrast, NoColor}
+
+// Glamour styles markdown rendering starts from.
+const (
+	MarkdownDark  = "dark"
+	MarkdownLight = "light"
+	MarkdownNone  = "notty"
+)
+
+// Theme is a named set of colors. Components derive their styles from the
+// roles rather than from literal colors. An empty color means the
+// terminal's own.
+type Theme struct {
+	Name string
+
+	Primary   lipgloss.Color // titles, focused borders and the palette
+	Secondary lipgloss.Color // status bar and selected-line backgrounds
+	Accent    lipgloss.Color // selected items and actions
+	Text      lipgloss.Color // body text
+	Muted     lipgloss.Color // help and secondary text
+	Error     lipgloss.Color
+	Success   lipgloss.Color
+	Warning   lipgloss.Color
+	Subtle    lipgloss.Color // tree guides and background whitespace
+	Border    lipgloss.Color // unfocused borders
+	Info      lipgloss.Color // page icons and links
+
+	// Markdown is the glamour style page content starts from before the
+	// theme's colors are applied: MarkdownDark, MarkdownLight or MarkdownNone.
+	Markdown string
+}
+
+// Builtin returns the built-in theme with the given name.
+func Builtin(name string) (Theme, bool) {
+	switch name {
+	case Dark:
+		return Theme{
+			Name:      Dark,
+			Primary:   lipgloss.Color("#7C3AED"),
+			Secondary: lipgloss.Color("#374151"),
+			Accent:    lipgloss.Color("#10B981"),
+			Text:      lipgloss.Color("#F3F4F6"),
+			Muted:     lipgloss.Color("#6B7280"),
+			Error:     lipgloss.Color("#EF4444"),
+			Success:   lipgloss.Color("#10B981"),
+			Warning:   lipgloss.Color("#F59E0B"),
+			Subtle:    lipgloss.Color("#4B5563"),
+			Border:    lipgloss.Color("#374151"),
+			Info:      lipgloss.Color("#60A5FA"),
+			Markdown:  MarkdownDark,
+		}, true
+	case Light:
+		return Theme{
+			Name:      Light,
+			Primary:   lipgloss.Color("#6D28D9"),
+			Secondary: lipgloss.Color("#E5E7EB"),
+			Accent:    lipgloss.Color("#047857"),
+			Text:      lipgloss.Color("#111827"),
+			Muted:     lipgloss.Color("#6B7280"),
+			Error:     lipgloss.Color("#B91C1C"),
+			Success:   lipgloss.Color("#047857"),
+			Warning:   lipgloss.Color("#B45309"),
+			Subtle:    lipgloss.Color("#D1D5DB"),
+			Border:    lipgloss.Color("#9CA3AF"),
+			Info:      lipgloss.Color("#1D4ED8"),
+			Markdown:  MarkdownLight,
+		}, true
+	case HighContrast:
+		return Theme{
+			Name:      HighContrast,
+			Primary:   lipgloss.Color("#FFFF00"),
+			Secondary: lipgloss.Color("#000000"),
+			Accent:    lipgloss.Color("#00FFFF"),
+			Text:      lipgloss.Color("#FFFFFF"),
+			Muted:     lipgloss.Color("#D0D0D0"),
+			Error:     lipgloss.Color("#FF5555"),
+			Success:   lipgloss.Color("#00FF00"),
+			Warning:   lipgloss.Color("#FFFF00"),
+			Subtle:    lipgloss.Color("#A0A0A0"),
+			Border:    lipgloss.Color("#FFFFFF"),
+			Info:      lipgloss.Color("#00FFFF"),
+			Markdown:  MarkdownDark,
+		}, true
+	case NoColor:
+		return Theme{Name: NoColor, Markdown: MarkdownNone}, true
+	default:
+		return Theme{}, false
+	}
+}
+
+// Default returns the dark theme.
+func Default() Theme {
+	t, _ := Builtin(Dark)
+	return t
+}
+
+// OrDefault returns *t, or the default theme when t is nil.
+func OrDefault(t *Theme) Theme {
+	if t == nil {
+		return Default()
+	}
+	return *t
+}
+
+// LoadInput contains the parameters for loading a theme.
+type LoadInput struct {
+	Name string // a built-in theme; Dark when empty
+	// File is a YAML theme file, read after Name. It may name the built-in
+	// theme it starts from and override its markdown style and colors.
+	File string
+	// Colors override colors by role, after the file.
+	Colors map[string]string
+	// NoColor forces the no-color theme, as when NO_COLOR is set.
+	NoColor bool
+}
+
+// fileTheme is the layout of a theme file.
+type fileTheme struct {
+	Name     string            `yaml:"name"`
+	Markdown string            `yaml:"markdown"`
+	Colors   map[string]string `yaml:"colors"`
+}
+
+// Load builds a theme from a built-in theme, a theme file and color
+// overrides. Unknown themes, roles and markdown styles are errors, as are
+// colors that are neither hex (#RGB or #RRGGBB) nor an ANSI number.
+func Load(input LoadInput) (Theme, error) {
+	if input.NoColor {
+		t, _ := Builtin(NoColor)
+		return t, nil
+	}
+
+	name := input.Name
+	var file fileTheme
+	if input.File != "" {
+		data, err := os.ReadFile(input.File)
+		if err != nil {
+			return Theme{}, fmt.Errorf("read theme file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return Theme{}, fmt.Errorf("parse theme file %s: %w", input.File, err)
+		}
+		if file.Name != "" {
+			name = file.Name
+		}
+	}
+	if name == "" {
+		name = Dark
+	}
+
+	t, ok := Builtin(name)
+	if !ok {
+		return Theme{}, fmt.Errorf("theme: unknown theme %q (want one of %s)", name, strings.Join(Names, ", "))
+	}
+
+	if file.Markdown != "" {
+		switch file.Markdown {
+		case MarkdownDark, MarkdownLight, MarkdownNone:
+			t.Markdown = file.Markdown
+		default:
+			return Theme{}, fmt.Errorf("theme file %s: unknown markdown style %q (want %s, %s or %s)",
+				input.File, file.Markdown, MarkdownDark, MarkdownLight, MarkdownNone)
+		}
+	}
+	if err := t.apply(file.Colors); err != nil {
+		return Theme{}, fmt.Errorf("theme file %s: %w", input.File, err)
+	}
+	if err := t.apply(input.Colors); err != nil {
+		return Theme{}, fmt.Errorf("theme: %w", err)
+	}
+	return t, nil
+}
+
+// roles returns the colors of a theme by their config names.
+func (t *Theme) roles() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"primary":   &t.Primary,
+		"secondary": &t.Secondary,
+		"accent":    &t.Accent,
+		"text":      &t.Text,
+		"muted":     &t.Muted,
+		"error":     &t.Error,
+		"success":   &t.Success,
+		"warning":   &t.Warning,
+		"subtle":    &t.Subtle,
+		"border":    &t.Border,
+		"info":      &t.Info,
+	}
+}
+
+// Roles lists the color roles a theme file or the config may set.
+func Roles() []string {
+	var t Theme
+	roles := make([]string, 0, len(t.roles()))
+	for role := range t.roles() {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// apply overrides colors by role. A theme changed this way is named Custom.
+func (t *Theme) apply(colors map[string]string) error {
+	if len(colors) == 0 {
+		return nil
+	}
+
+	roles := t.roles()
+	names := make([]string, 0, len(colors))
+	for role := range colors {
+		names = append(names, role)
+	}
+	sort.Strings(names)
+
+	for _, role := range names {
+		color, ok := roles[strings.ToLower(role)]
+		if !ok {
+			return fmt.Errorf("unknown color %q (want one of %s)", role, strings.Join(Roles(), ", "))
+		}
+		value := strings.TrimSpace(colors[role])
+		if !validColor(value) {
+			return fmt.Errorf("colors.%s: invalid color %q (want #RRGGBB, #RGB or an ANSI number 0-255)", role, value)
+		}
+		*color = lipgloss.Color(value)
+	}
+	t.Name = Custom
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether s is a color lipgloss understands. Empty means
+// the terminal's own color.
+func validColor(s string) bool {
+	if s == "" || hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
@@ -0,0 +1,156 @@
+package theme
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltin(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range Names {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			theme, ok := Builtin(name)
+			require.True(t, ok)
+			assert.Equal(t, name, theme.Name)
+			for role, color := range theme.roles() {
+				assert.True(t, validColor(string(*color)), "%s = %q", role, *color)
+			}
+		})
+	}
+
+	_, ok := Builtin("solarized")
+	assert.False(t, ok)
+	assert.Equal(t, Dark, OrDefault(nil).Name)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	lightFile := writeFile("light.yaml", "name: light\nmarkdown: dark\ncolors:\n  primary: \"#FF5F87\"\n")
+	badColorFile := writeFile("bad.yaml", "colors:\n  accent: teal\n")
+
+	tests := []struct {
+		name      string
+		input     LoadInput
+		wantErr   string
+		wantTheme func(t *testing.T, theme Theme)
+	}{
+		{
+			name:  "dark by default",
+			input: LoadInput{},
+			wantTheme: func(t *testing.T, theme Theme) {
+				assert.Equal(t, Default(), theme)
+			},
+		},
+		{
+			name:  "built-in theme",
+			input: LoadInput{Name: HighContrast},
+			wantTheme: func(t *testing.T, theme Theme) {
+				assert.Equal(t, HighContrast, theme.Name)
+			},
+		},
+		{
+			name:  "NO_COLOR wins",
+			input: LoadInput{Name: Light, Colors: map[string]string{"primary": "#FFFFFF"}, NoColor: true},
+			wantTheme: func(t *testing.T, theme Theme) {
+				assert.Equal(t, NoColor, theme.Name)
+				assert.Equal(t, lipgloss.Color(""), theme.Primary)
+			},
+		},
+		{
+			name:  "theme file then config colors",
+			input: LoadInput{Name: Dark, File: lightFile, Colors: map[string]string{"Accent": "33"}},
+			wantTheme: func(t *testing.T, theme Theme) {
+				light, _ := Builtin(Light)
+				assert.Equal(t, Custom, theme.Name)
+				assert.Equal(t, lipgloss.Color("#FF5F87"), theme.Primary)
+				assert.Equal(t, lipgloss.Color("33"), theme.Accent)
+				assert.Equal(t, light.Text, theme.Text)
+				assert.Equal(t, MarkdownDark, theme.Markdown)
+			},
+		},
+		{
+			name:    "unknown theme",
+			input:   LoadInput{Name: "solarized"},
+			wantErr: `theme: unknown theme "solarized"`,
+		},
+		{
+			name:    "unknown role",
+			input:   LoadInput{Colors: map[string]string{"background": "#000"}},
+			wantErr: `theme: unknown color "background"`,
+		},
+		{
+			name:    "invalid color in file",
+			input:   LoadInput{File: badColorFile},
+			wantErr: `colors.accent: invalid color "teal"`,
+		},
+		{
+			name:    "missing file",
+			input:   LoadInput{File: filepath.Join(dir, "missing.yaml")},
+			wantErr: "read theme file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			theme, err := Load(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.wantTheme(t, theme)
+		})
+	}
+}
+
+func TestGlamourStyle(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range Names {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			theme, _ := Builtin(name)
+			style, err := theme.GlamourStyle()
+			require.NoError(t, err)
+
+			var decoded struct {
+				Heading struct {
+					Color *string `json:"color"`
+				} `json:"heading"`
+			}
+			require.NoError(t, json.Unmarshal(style, &decoded))
+			if name == NoColor {
+				assert.Nil(t, decoded.Heading.Color)
+			} else {
+				require.NotNil(t, decoded.Heading.Color)
+				assert.Equal(t, string(theme.Primary), *decoded.Heading.Color)
+			}
+
+			renderer, err := glamour.NewTermRenderer(glamour.WithStylesFromJSONBytes(style))
+			require.NoError(t, err)
+			out, err := renderer.Render("# Title\n\nSome `code` and a [link](https://example.com).")
+			require.NoError(t, err)
+			assert.Contains(t, out, "Title")
+		})
+	}
+}
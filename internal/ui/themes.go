@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+// themeActionPrefix starts the palette action of a theme command; the
+// theme's name follows it.
+const themeActionPrefix = "theme:"
+
+// themeTitles are the palette titles of the built-in themes.
+var themeTitles = map[string]string{
+	theme.Dark:         "Dark",
+	theme.Light:        "Light",
+	theme.HighContrast: "High Contrast",
+	theme.NoColor:      "No Color",
+}
+
+// configuredThemeTitle is the palette title of the theme from the config
+// file when it is not a built-in one.
+const configuredThemeTitle = "Theme: Configured"
+
+// themed is implemented by pages that can be restyled while shown. The
+// returned command re-renders content drawn with the old colors.
+type themed interface {
+	SetTheme(t theme.Theme) tea.Cmd
+}
+
+// addThemeCommands adds a palette command for each built-in theme, and one
+// for the configured theme when it was customized.
+func addThemeCommands(p *components.CommandPalette, configured theme.Theme) {
+	for _, name := range theme.Names {
+		p.AddCommand("Theme: "+themeTitles[name], fmt.Sprintf("Switch to the %s theme", name),
+			themeActionPrefix+name, func() tea.Cmd { return nil })
+	}
+	addConfiguredThemeCommand(p, configured)
+}
+
+// addConfiguredThemeCommand adds the palette command for the configured
+// theme when it was customized.
+func addConfiguredThemeCommand(p *components.CommandPalette, configured theme.Theme) {
+	if _, ok := theme.Builtin(configured.Name); !ok && !hasCommand(p, configuredThemeTitle) {
+		p.AddCommand(configuredThemeTitle, "Switch back to the theme from the config file",
+			themeActionPrefix+configured.Name, func() tea.Cmd { return nil })
+	}
+}
+
+// switchTheme applies the named theme: a built-in one, or the configured
+// theme when the name is its own.
+func (m *AppModel) switchTheme(name string) tea.Cmd {
+	t, ok := theme.Builtin(name)
+	if !ok {
+		if name != m.configTheme.Name {
+			m.statusBar.SetHelpText(fmt.Sprintf("Unknown theme %q", name))
+			return nil
+		}
+		t = m.configTheme
+	}
+
+	cmd := m.applyTheme(t)
+	m.statusBar.SetHelpText("Theme: " + t.Name)
+	return cmd
+}
+
+// applyTheme restyles the global components and every open page. Pages
+// created later pick the theme up from the model.
+func (m *AppModel) applyTheme(t theme.Theme) tea.Cmd {
+	m.theme = t
+	m.treeView.SetTheme(t)
+	m.statusBar.SetTheme(t)
+	m.cmdPalette.SetTheme(t)
+	m.toast.SetTheme(t)
+	m.inspector.SetTheme(t)
+	m.stats.SetTheme(t)
+
+	var cmds []tea.Cmd
+	for _, page := range m.pages {
+		if p, ok := page.(themed); ok {
+			cmds = append(cmds, p.SetTheme(t))
+		}
+	}
+	return tea.Batch(cmds...)
+}
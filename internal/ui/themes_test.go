@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Panandika/notion-tui/internal/config"
+	"github.com/Panandika/notion-tui/internal/ui/components"
+	"github.com/Panandika/notion-tui/internal/ui/theme"
+)
+
+func TestSwitchTheme(t *testing.T) {
+	custom := theme.Default()
+	custom.Name = theme.Custom
+	custom.Primary = "#FF5F87"
+
+	tests := []struct {
+		name      string
+		action    string
+		wantTheme string
+		wantText  string
+	}{
+		{name: "built-in theme", action: "theme:light", wantTheme: theme.Light, wantText: "Theme: light"},
+		{name: "configured theme", action: "theme:custom", wantTheme: theme.Custom, wantText: "Theme: custom"},
+		{name: "unknown theme", action: "theme:solarized", wantTheme: theme.Dark, wantText: `Unknown theme "solarized"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(NewModelInput{
+				Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()},
+				Theme:  &custom,
+			})
+			model.initializePages()
+			model.theme, _ = theme.Builtin(theme.Dark)
+
+			model.handleCommandExecution(components.CommandExecutedMsg{ActionType: tt.action})
+
+			assert.Equal(t, tt.wantText, model.statusBar.HelpText())
+			assert.Equal(t, tt.wantTheme, model.theme.Name)
+		})
+	}
+}
+
+func TestThemeCommands(t *testing.T) {
+	tests := []struct {
+		name           string
+		theme          *theme.Theme
+		wantConfigured bool
+	}{
+		{name: "built-in theme", theme: nil},
+		{name: "custom theme", theme: &theme.Theme{Name: theme.Custom}, wantConfigured: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(NewModelInput{
+				Config: &config.Config{NotionToken: "test_token", CacheDir: t.TempDir()},
+				Theme:  tt.theme,
+			})
+
+			titles := make(map[string]bool)
+			for _, cmd := range model.cmdPalette.Commands() {
+				titles[cmd.Title()] = true
+			}
+			for _, title := range themeTitles {
+				assert.True(t, titles["Theme: "+title], title)
+			}
+			assert.Equal(t, tt.wantConfigured, titles["Theme: Configured"])
+		})
+	}
+}
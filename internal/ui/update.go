@@ -1,20 +1,23 @@
 package ui
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jomei/notionapi"
 
+	"github.com/Panandika/notion-tui/internal/ui/keymap"
 	"github.com/Panandika/notion-tui/internal/ui/pages"
 )
 
 // HandleGlobalKeys processes global keyboard shortcuts that work across all pages.
 // Returns (handled bool, cmd tea.Cmd) where handled indicates if the key was processed.
-// Global keys include quit (Ctrl+C, q) and help (?).
+// Global keys include quit (Ctrl+C, q) and help (?) with the default bindings.
 func HandleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
+	keys := keymap.Default().Global
+	switch {
+	case key.Matches(msg, keys.Quit):
 		return true, tea.Quit
-	case "?":
+	case key.Matches(msg, keys.Help):
 		// TODO: Show help overlay
 		return true, nil
 	default:
@@ -22,20 +25,22 @@ func HandleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 	}
 }
 
-// HandleNavigationKeys processes navigation keys (up, down, k, j).
+// HandleNavigationKeys processes navigation keys (up, down, k, j) with the
+// default bindings.
 // Returns updated cursor position and a boolean indicating if key was handled.
 // Does not move cursor beyond bounds (0 to len(pages)-1).
 func HandleNavigationKeys(msg tea.KeyMsg, cursor int, pageCount int) (int, bool) {
 	newCursor := cursor
 	handled := false
 
-	switch msg.String() {
-	case "up", "k":
+	keys := keymap.Default().List
+	switch {
+	case key.Matches(msg, keys.Up):
 		if cursor > 0 {
 			newCursor = cursor - 1
 		}
 		handled = true
-	case "down", "j":
+	case key.Matches(msg, keys.Down):
 		if cursor < pageCount-1 {
 			newCursor = cursor + 1
 		}
@@ -0,0 +1,98 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Panandika/notion-tui/internal/fsutil"
+)
+
+// state is what the watcher has seen, persisted between runs so a restarted
+// watch picks up where it stopped instead of replaying or missing changes.
+type state struct {
+	// Databases maps a normalized database ID to the newest last-edited
+	// time seen in it. A database without a cursor has not been baselined.
+	Databases map[string]time.Time `json:"databases"`
+	// Workspace is the cursor of the workspace feed; zero until baselined.
+	Workspace time.Time             `json:"workspace,omitempty"`
+	Pages     map[string]*pageState `json:"pages"` // keyed by normalized page ID
+
+	path  string
+	codec Codec
+}
+
+// Codec transforms the state file on its way to and from disk, for example to
+// encrypt it alongside an encrypted cache. *cache.PageCache implements it.
+type Codec interface {
+	Seal(name string, data []byte) ([]byte, error)
+	Unseal(name string, data []byte) ([]byte, error)
+}
+
+// pageState is the last seen version of a page.
+type pageState struct {
+	LastEdited time.Time         `json:"last_edited_time"`
+	Title      string            `json:"title"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// loadState reads the state file, opening it with codec when one is given. A
+// missing file starts an empty state; a corrupt one is an error, since every
+// page would look new.
+func loadState(path string, codec Codec) (*state, error) {
+	s := &state{path: path, codec: codec}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("read watch state: %w", err)
+		}
+		if err == nil && codec != nil {
+			if data, err = codec.Unseal(filepath.Base(path), data); err != nil {
+				return nil, fmt.Errorf("open watch state %s: %w", path, err)
+			}
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, s); err != nil {
+				return nil, fmt.Errorf("parse watch state %s: %w", path, err)
+			}
+		}
+	}
+
+	if s.Databases == nil {
+		s.Databases = make(map[string]time.Time)
+	}
+	if s.Pages == nil {
+		s.Pages = make(map[string]*pageState)
+	}
+	return s, nil
+}
+
+// save writes the state to a temporary file and renames it into place, so an
+// interrupted write never leaves a corrupt state behind.
+func (s *state) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode watch state: %w", err)
+	}
+	if s.codec != nil {
+		if data, err = s.codec.Seal(filepath.Base(s.path), data); err != nil {
+			return fmt.Errorf("seal watch state: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create watch state directory: %w", err)
+	}
+
+	if err := fsutil.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("write watch state: %w", err)
+	}
+	return nil
+}
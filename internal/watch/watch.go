@@ -0,0 +1,327 @@
+// Package watch turns polling of Notion into a feed of change events. Each
+// poll queries the watched databases for pages edited since the last one,
+// and optionally searches the workspace the same way, then compares what it
+// finds with the pages it saw before. A state file keeps the cursors and the
+// last seen version of every page, so a restarted watch resumes where it
+// stopped.
+//
+// Notion reports edit times to the minute, so pages edited in the minute of
+// the cursor are fetched again on the next poll; a page that looks the same
+// as before is not reported twice.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/Panandika/notion-tui/internal/notion"
+)
+
+const (
+	// queryPageSize is the largest page size accepted by the Notion API.
+	queryPageSize = 100
+	// maxSearchPages bounds the workspace search of one poll, in case a
+	// large import edited more pages than a watch should report at once.
+	maxSearchPages = 10
+)
+
+// Client is the subset of the Notion client used to watch for changes.
+type Client interface {
+	QueryDatabase(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error)
+	Search(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error)
+}
+
+// EventType is the kind of change an event reports.
+type EventType string
+
+const (
+	EventPageCreated     EventType = "page_created"     // a page appeared
+	EventPageUpdated     EventType = "page_updated"     // a page was edited without changing its properties
+	EventPropertyChanged EventType = "property_changed" // one or more properties changed
+)
+
+// EventTypes lists every event type, in the order they are documented.
+var EventTypes = []EventType{EventPageCreated, EventPageUpdated, EventPropertyChanged}
+
+// Event is a change found by a poll.
+type Event struct {
+	Type       EventType         `json:"type"`
+	PageID     string            `json:"page_id"`
+	Title      string            `json:"title"`
+	URL        string            `json:"url,omitempty"`
+	DatabaseID string            `json:"database_id,omitempty"`
+	LastEdited time.Time         `json:"last_edited_time"`
+	Changes    map[string]Change `json:"changes,omitempty"` // keyed by property name
+}
+
+// Change is the value of a property before and after an edit, as plain text.
+type Change struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Watcher polls for changes.
+type Watcher struct {
+	client      Client
+	databaseIDs []string
+	workspace   bool
+	state       *state
+}
+
+// NewWatcherInput contains the parameters for creating a Watcher.
+type NewWatcherInput struct {
+	Client      Client
+	StatePath   string   // empty keeps the state in memory only
+	DatabaseIDs []string // databases to query
+	Workspace   bool     // also search the workspace for edited pages
+	Codec       Codec    // optional; the state file is written as is when nil
+}
+
+// NewWatcher loads the state file and returns a Watcher.
+func NewWatcher(input NewWatcherInput) (*Watcher, error) {
+	if input.Client == nil {
+		return nil, errors.New("client is required")
+	}
+	if len(input.DatabaseIDs) == 0 && !input.Workspace {
+		return nil, errors.New("nothing to watch: give a database or watch the workspace")
+	}
+
+	s, err := loadState(input.StatePath, input.Codec)
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{
+		client:      input.Client,
+		databaseIDs: input.DatabaseIDs,
+		workspace:   input.Workspace,
+		state:       s,
+	}, nil
+}
+
+// Poll checks every source once and returns the changes since the previous
+// poll, oldest first within each source. The first poll of a source only
+// records what is there and reports nothing. A source that fails does not
+// stop the others; its error is returned with the events that were found,
+// and it is retried from the same cursor next time.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	var events []Event
+	var errs []error
+
+	for _, id := range w.databaseIDs {
+		found, err := w.pollDatabase(ctx, id)
+		events = append(events, found...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("poll database %s: %w", id, err))
+		}
+	}
+	if w.workspace {
+		found, err := w.pollWorkspace(ctx)
+		events = append(events, found...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("poll workspace: %w", err))
+		}
+	}
+
+	if err := w.state.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return events, errors.Join(errs...)
+}
+
+// pollDatabase reports the pages of a database edited since its cursor. The
+// first poll reads the whole database, so later property changes have a
+// value to compare against.
+func (w *Watcher) pollDatabase(ctx context.Context, id string) ([]Event, error) {
+	key := notion.NormalizeID(id)
+	cursor, baselined := w.state.Databases[key]
+
+	req := &notionapi.DatabaseQueryRequest{
+		Sorts:    []notionapi.SortObject{{Timestamp: notionapi.TimestampLastEdited, Direction: notionapi.SortOrderASC}},
+		PageSize: queryPageSize,
+	}
+	if baselined {
+		since := notionapi.Date(cursor)
+		req.Filter = &notionapi.TimestampFilter{
+			Timestamp:      notionapi.TimestampLastEdited,
+			LastEditedTime: &notionapi.DateFilterCondition{OnOrAfter: &since},
+		}
+	}
+
+	// Pages are only recorded once the query finished, so a failed poll
+	// is repeated in full rather than losing the pages it did not reach.
+	var pages []notionapi.Page
+	for {
+		resp, err := w.client.QueryDatabase(ctx, id, req)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			break
+		}
+		req.StartCursor = resp.NextCursor
+	}
+
+	var events []Event
+	next := cursor
+	for i := range pages {
+		page := &pages[i]
+		event, ok := w.observe(cursor, page.LastEditedTime, page.CreatedTime, string(page.ID), snapshot(page))
+		if ok && baselined {
+			event.URL = page.URL
+			event.DatabaseID = id
+			events = append(events, event)
+		}
+		if page.LastEditedTime.After(next) {
+			next = page.LastEditedTime
+		}
+	}
+	if !baselined && next.IsZero() {
+		next = time.Now().UTC().Truncate(time.Minute)
+	}
+	w.state.Databases[key] = next
+	return events, nil
+}
+
+// pollWorkspace reports pages anywhere in the workspace edited since the
+// workspace cursor, newest results first from search and then reversed.
+// Pages of watched databases are left to pollDatabase, which sees their
+// properties. The first poll only sets the cursor.
+func (w *Watcher) pollWorkspace(ctx context.Context) ([]Event, error) {
+	cursor := w.state.Workspace
+	baselined := !cursor.IsZero()
+
+	watched := make(map[string]bool, len(w.databaseIDs))
+	for _, id := range w.databaseIDs {
+		watched[notion.NormalizeID(id)] = true
+	}
+
+	var results []notion.SearchResult
+	input := notion.SearchInput{Filter: "page", PageSize: queryPageSize, SortLastEdited: true}
+	if !baselined {
+		input.PageSize = 1
+	}
+	for range maxSearchPages {
+		resp, err := w.client.Search(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		done := !resp.HasMore || resp.NextCursor == "" || !baselined
+		for _, result := range resp.Results {
+			if result.LastEdited.Before(cursor) {
+				done = true
+				break
+			}
+			results = append(results, result)
+		}
+		if done {
+			break
+		}
+		input.StartCursor = resp.NextCursor
+	}
+
+	var events []Event
+	next := cursor
+	for i := len(results) - 1; i >= 0; i-- {
+		result := results[i]
+		if result.LastEdited.After(next) {
+			next = result.LastEdited
+		}
+		if !baselined || (result.ParentType == "database_id" && watched[notion.NormalizeID(result.ParentID)]) {
+			continue
+		}
+
+		event, ok := w.observe(cursor, result.LastEdited, result.Created, result.ID, &pageState{
+			LastEdited: result.LastEdited,
+			Title:      result.Title,
+		})
+		if ok {
+			if result.ParentType == "database_id" {
+				event.DatabaseID = result.ParentID
+			}
+			events = append(events, event)
+		}
+	}
+	if next.IsZero() {
+		next = time.Now().UTC().Truncate(time.Minute)
+	}
+	w.state.Workspace = next
+	return events, nil
+}
+
+// observe records the current version of a page and returns the event it
+// amounts to, if any. A page not seen before counts as created when it was
+// created since the cursor, and as updated otherwise, such as a page moved
+// into a database or a workspace page edited for the first time.
+func (w *Watcher) observe(cursor, lastEdited, created time.Time, id string, current *pageState) (Event, bool) {
+	key := notion.NormalizeID(id)
+	previous := w.state.Pages[key]
+	w.state.Pages[key] = current
+
+	event := Event{PageID: id, Title: current.Title, LastEdited: lastEdited}
+	switch {
+	case previous == nil && !created.Before(cursor):
+		event.Type = EventPageCreated
+	case previous == nil:
+		event.Type = EventPageUpdated
+	default:
+		changes := diff(previous, current)
+		switch {
+		case len(changes) > 0:
+			event.Type = EventPropertyChanged
+			event.Changes = changes
+		case lastEdited.After(previous.LastEdited):
+			event.Type = EventPageUpdated
+		default:
+			return Event{}, false
+		}
+	}
+	return event, true
+}
+
+// snapshot records the properties of a database row as plain text. Edit
+// times and editors are left out, since they change with every edit and
+// are reported by the event itself.
+func snapshot(page *notionapi.Page) *pageState {
+	props := make(map[string]string, len(page.Properties))
+	for name, prop := range page.Properties {
+		switch prop.(type) {
+		case *notionapi.LastEditedTimeProperty, *notionapi.LastEditedByProperty:
+			continue
+		}
+		props[name] = notion.PropertyString(prop)
+	}
+	return &pageState{
+		LastEdited: page.LastEditedTime,
+		Title:      notion.PageTitle(page),
+		Properties: props,
+	}
+}
+
+// diff returns the properties whose values differ between two versions of a
+// page. Workspace pages carry no properties, so their title is compared.
+func diff(before, after *pageState) map[string]Change {
+	changes := make(map[string]Change)
+	if before.Properties == nil && after.Properties == nil {
+		if before.Title != after.Title {
+			changes["title"] = Change{Before: before.Title, After: after.Title}
+		}
+		return changes
+	}
+
+	for name, value := range after.Properties {
+		if old := before.Properties[name]; old != value {
+			changes[name] = Change{Before: old, After: value}
+		}
+	}
+	for name, old := range before.Properties {
+		if _, ok := after.Properties[name]; !ok {
+			changes[name] = Change{Before: old}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,234 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Panandika/notion-tui/internal/cache"
+	"github.com/Panandika/notion-tui/internal/notion"
+	"github.com/Panandika/notion-tui/internal/testhelpers"
+)
+
+var t0 = time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+
+// fakeWorkspace is a database and a few loose pages that tests edit between
+// polls. Its client answers queries and searches the way Notion does,
+// including the on_or_after cursor filter.
+type fakeWorkspace struct {
+	rows  map[string]*notionapi.Page
+	loose []notion.SearchResult
+}
+
+func newFakeWorkspace() *fakeWorkspace {
+	return &fakeWorkspace{rows: make(map[string]*notionapi.Page)}
+}
+
+// put adds or replaces a row of the database.
+func (f *fakeWorkspace) put(id, title, status string, created, edited time.Time) {
+	page := testhelpers.NewTestPage(id, title)
+	page.CreatedTime = created
+	page.LastEditedTime = edited
+	page.URL = "https://www.notion.so/" + id
+	page.Properties["Status"] = &notionapi.StatusProperty{Status: notionapi.Status{Name: status}}
+	page.Properties["Edited"] = &notionapi.LastEditedTimeProperty{LastEditedTime: edited}
+	f.rows[id] = page
+}
+
+func (f *fakeWorkspace) client() *testhelpers.MockNotionClient {
+	mockClient := testhelpers.NewMockNotionClient()
+	mockClient.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		var since time.Time
+		if filter, ok := req.Filter.(*notionapi.TimestampFilter); ok {
+			since = time.Time(*filter.LastEditedTime.OnOrAfter)
+		}
+		resp := &notionapi.DatabaseQueryResponse{}
+		for _, page := range f.rows {
+			if !page.LastEditedTime.Before(since) {
+				resp.Results = append(resp.Results, *page)
+			}
+		}
+		sort.Slice(resp.Results, func(i, j int) bool {
+			return resp.Results[i].LastEditedTime.Before(resp.Results[j].LastEditedTime)
+		})
+		return resp, nil
+	}
+	mockClient.SearchFunc = func(ctx context.Context, input notion.SearchInput) (*notion.SearchResponse, error) {
+		results := append([]notion.SearchResult(nil), f.loose...)
+		sort.Slice(results, func(i, j int) bool { return results[i].LastEdited.After(results[j].LastEdited) })
+		if len(results) > input.PageSize {
+			results = results[:input.PageSize]
+		}
+		return &notion.SearchResponse{Results: results}, nil
+	}
+	return mockClient
+}
+
+func TestWatcherDatabase(t *testing.T) {
+	t.Parallel()
+
+	f := newFakeWorkspace()
+	f.put("page-1", "Plan launch", "Todo", t0, t0)
+	f.put("page-2", "Write docs", "Todo", t0, t0)
+	statePath := filepath.Join(t.TempDir(), "watch-state.json")
+
+	newWatcher := func() *Watcher {
+		w, err := NewWatcher(NewWatcherInput{Client: f.client(), StatePath: statePath, DatabaseIDs: []string{"db-1"}})
+		require.NoError(t, err)
+		return w
+	}
+
+	events, err := newWatcher().Poll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, events, "the first poll only records a baseline")
+
+	f.put("page-1", "Plan launch", "Done", t0, t0.Add(2*time.Minute))
+	f.put("page-2", "Write docs", "Todo", t0, t0.Add(3*time.Minute))
+	f.put("page-3", "Announce", "Todo", t0.Add(4*time.Minute), t0.Add(4*time.Minute))
+
+	// A new watcher reads the state file, as after a restart
+	w := newWatcher()
+	events, err = w.Poll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Event{
+		{
+			Type:       EventPropertyChanged,
+			PageID:     "page-1",
+			Title:      "Plan launch",
+			URL:        "https://www.notion.so/page-1",
+			DatabaseID: "db-1",
+			LastEdited: t0.Add(2 * time.Minute),
+			Changes:    map[string]Change{"Status": {Before: "Todo", After: "Done"}},
+		},
+		{
+			Type:       EventPageUpdated,
+			PageID:     "page-2",
+			Title:      "Write docs",
+			URL:        "https://www.notion.so/page-2",
+			DatabaseID: "db-1",
+			LastEdited: t0.Add(3 * time.Minute),
+		},
+		{
+			Type:       EventPageCreated,
+			PageID:     "page-3",
+			Title:      "Announce",
+			URL:        "https://www.notion.so/page-3",
+			DatabaseID: "db-1",
+			LastEdited: t0.Add(4 * time.Minute),
+		},
+	}, events)
+
+	// Pages edited in the cursor's minute come back, but are not repeated
+	events, err = w.Poll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestWatcherWorkspace(t *testing.T) {
+	t.Parallel()
+
+	f := newFakeWorkspace()
+	f.loose = []notion.SearchResult{
+		{ID: "note-1", Title: "Ideas", ObjectType: "page", Created: t0.Add(-time.Hour), LastEdited: t0, ParentType: "workspace"},
+	}
+	w, err := NewWatcher(NewWatcherInput{Client: f.client(), DatabaseIDs: []string{"db-1"}, Workspace: true})
+	require.NoError(t, err)
+
+	events, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	f.loose = []notion.SearchResult{
+		{ID: "note-1", Title: "Better ideas", ObjectType: "page", Created: t0.Add(-time.Hour), LastEdited: t0.Add(time.Minute), ParentType: "workspace"},
+		{ID: "note-2", Title: "Journal", ObjectType: "page", Created: t0.Add(2 * time.Minute), LastEdited: t0.Add(2 * time.Minute), ParentType: "page_id"},
+		{ID: "page-9", Title: "Row", ObjectType: "page", Created: t0.Add(time.Minute), LastEdited: t0.Add(3 * time.Minute), ParentType: "database_id", ParentID: "db-1"},
+	}
+	events, err = w.Poll(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, events, 2, "rows of watched databases are left to the database poll")
+	assert.Equal(t, EventPageUpdated, events[0].Type, "a page never seen before is updated unless it is new")
+	assert.Equal(t, "note-1", events[0].PageID)
+	assert.Equal(t, EventPageCreated, events[1].Type)
+	assert.Equal(t, "note-2", events[1].PageID)
+
+	f.loose[0].Title = "Best ideas"
+	f.loose[0].LastEdited = t0.Add(5 * time.Minute)
+	events, err = w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventPropertyChanged, events[0].Type)
+	assert.Equal(t, map[string]Change{"title": {Before: "Better ideas", After: "Best ideas"}}, events[0].Changes)
+}
+
+func TestWatcherStateSealedWithCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key, err := cache.KeyFromPassphrase("passphrase")
+	require.NoError(t, err)
+	pc, err := cache.NewPageCache(cache.NewPageCacheInput{Dir: t.TempDir(), Key: key})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	f := newFakeWorkspace()
+	f.put("page-1", "Salary review", "Todo", t0, t0)
+	statePath := filepath.Join(pc.Dir(), "watch", "state.json")
+	newWatcher := func() *Watcher {
+		w, err := NewWatcher(NewWatcherInput{Client: f.client(), StatePath: statePath, DatabaseIDs: []string{"db-1"}, Codec: pc})
+		require.NoError(t, err)
+		return w
+	}
+
+	_, err = newWatcher().Poll(ctx)
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "Salary review")
+	assert.NotContains(t, string(raw), "Todo")
+
+	// The state follows the cache to a new key
+	rotated, err := cache.KeyFromPassphrase("rotated")
+	require.NoError(t, err)
+	require.NoError(t, pc.Rotate(ctx, rotated))
+
+	f.put("page-1", "Salary review", "Done", t0, t0.Add(time.Minute))
+	events, err := newWatcher().Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, map[string]Change{"Status": {Before: "Todo", After: "Done"}}, events[0].Changes)
+}
+
+func TestWatcherErrors(t *testing.T) {
+	t.Parallel()
+
+	f := newFakeWorkspace()
+	f.put("page-1", "Plan launch", "Todo", t0, t0)
+	mockClient := f.client()
+	query := mockClient.QueryDatabaseFunc
+	mockClient.QueryDatabaseFunc = func(ctx context.Context, id string, req *notionapi.DatabaseQueryRequest) (*notionapi.DatabaseQueryResponse, error) {
+		if id == "db-broken" {
+			return nil, errors.New("boom")
+		}
+		return query(ctx, id, req)
+	}
+
+	w, err := NewWatcher(NewWatcherInput{Client: mockClient, DatabaseIDs: []string{"db-broken", "db-1"}})
+	require.NoError(t, err)
+	_, err = w.Poll(context.Background())
+	assert.ErrorContains(t, err, "poll database db-broken: boom")
+	assert.Contains(t, w.state.Databases, "db-1", "other databases are still polled")
+	assert.NotContains(t, w.state.Databases, "db-broken")
+
+	_, err = NewWatcher(NewWatcherInput{Client: mockClient})
+	assert.ErrorContains(t, err, "nothing to watch")
+}